@@ -14,7 +14,7 @@
  * limitations under the License.
  */
 
-// Package assert 提供了一些常用的断言函数。
+// Package assert 提供了一些常用的断言函数，断言函数返回断言是否成功。
 package assert
 
 import (
@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 // T testing.T 的简化接口。
@@ -56,19 +58,23 @@ func Check(cases Cases) error {
 }
 
 // True asserts that got is true.
-func True(t T, got bool, msg ...string) {
+func True(t T, got bool, msg ...string) bool {
 	t.Helper()
 	if !got {
 		fail(t, "got false but expect true", msg...)
+		return false
 	}
+	return true
 }
 
 // False asserts that got is false.
-func False(t T, got bool, msg ...string) {
+func False(t T, got bool, msg ...string) bool {
 	t.Helper()
 	if got {
 		fail(t, "got true but expect false", msg...)
+		return false
 	}
+	return true
 }
 
 // isNil 返回 v 的值是否为 nil，但是不会 panic 。
@@ -87,7 +93,7 @@ func isNil(v reflect.Value) bool {
 }
 
 // Nil asserts that got is nil.
-func Nil(t T, got interface{}, msg ...string) {
+func Nil(t T, got interface{}, msg ...string) bool {
 	t.Helper()
 	// 为什么不能使用 got == nil 进行判断呢？因为如果
 	// a := (*int)(nil)        // %T == *int
@@ -96,101 +102,248 @@ func Nil(t T, got interface{}, msg ...string) {
 	if !isNil(reflect.ValueOf(got)) {
 		str := fmt.Sprintf("got (%T) %v but expect nil", got, got)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // NotNil asserts that got is not nil.
-func NotNil(t T, got interface{}, msg ...string) {
+func NotNil(t T, got interface{}, msg ...string) bool {
 	t.Helper()
 	if isNil(reflect.ValueOf(got)) {
 		fail(t, "got nil but expect not nil", msg...)
+		return false
 	}
+	return true
 }
 
 // Equal asserts that got and expect are equal.
-func Equal(t T, got interface{}, expect interface{}, msg ...string) {
+func Equal(t T, got interface{}, expect interface{}, msg ...string) bool {
 	t.Helper()
 	if !reflect.DeepEqual(got, expect) {
+		if canDiff(got, expect) {
+			if s := diff(expect, got); s != "" {
+				str := fmt.Sprintf("got (%T) but expect (%T)", got, expect)
+				fail(t, str, append(msg, "diff:\n"+s)...)
+				return false
+			}
+		}
 		str := fmt.Sprintf("got (%T) %v but expect (%T) %v", got, got, expect, expect)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // NotEqual asserts that got and expect are not equal.
-func NotEqual(t T, got interface{}, expect interface{}, msg ...string) {
+func NotEqual(t T, got interface{}, expect interface{}, msg ...string) bool {
 	t.Helper()
 	if reflect.DeepEqual(got, expect) {
 		str := fmt.Sprintf("expect not (%T) %v", expect, expect)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // Same asserts that got and expect are same.
-func Same(t T, got interface{}, expect interface{}, msg ...string) {
+func Same(t T, got interface{}, expect interface{}, msg ...string) bool {
 	t.Helper()
 	if got != expect {
 		str := fmt.Sprintf("got (%T) %v but expect (%T) %v", got, got, expect, expect)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // NotSame asserts that got and expect are not same.
-func NotSame(t T, got interface{}, expect interface{}, msg ...string) {
+func NotSame(t T, got interface{}, expect interface{}, msg ...string) bool {
 	t.Helper()
 	if got == expect {
 		str := fmt.Sprintf("expect not (%T) %v", expect, expect)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // Panic asserts that function fn() would panic. It fails if the panic
 // message does not match the regular expression.
-func Panic(t T, fn func(), expr string, msg ...string) {
+func Panic(t T, fn func(), expr string, msg ...string) bool {
 	// TODO 使用 util.Panic(err).When(err != nil) 时堆栈信息不对
 	t.Helper()
-	defer func() {
-		if r := recover(); r == nil {
-			fail(t, "did not panic", msg...)
-		} else {
-			var str string
-			switch v := r.(type) {
-			case error:
-				str = v.Error()
-			case string:
-				str = v
-			default:
-				str = fmt.Sprint(r)
-			}
-			matches(t, str, expr, msg...)
-		}
-	}()
+	panicked, r := doPanic(fn)
+	if !panicked {
+		fail(t, "did not panic", msg...)
+		return false
+	}
+	var str string
+	switch v := r.(type) {
+	case error:
+		str = v.Error()
+	case string:
+		str = v
+	default:
+		str = fmt.Sprint(r)
+	}
+	return matches(t, str, expr, msg...)
+}
+
+// doPanic 执行 fn 并返回 fn 是否 panic 以及 panic 的值。
+func doPanic(fn func()) (panicked bool, r interface{}) {
+	panicked = true
+	defer func() { r = recover() }()
 	fn()
+	panicked = false
+	return
+}
+
+// Panics asserts that function fn() would panic.
+func Panics(t T, fn func(), msg ...string) bool {
+	t.Helper()
+	if panicked, _ := doPanic(fn); !panicked {
+		fail(t, "did not panic", msg...)
+		return false
+	}
+	return true
+}
+
+// NotPanics asserts that function fn() would not panic.
+func NotPanics(t T, fn func(), msg ...string) bool {
+	t.Helper()
+	if panicked, r := doPanic(fn); panicked {
+		str := fmt.Sprintf("got panic (%T) %v but expect not panic", r, r)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// PanicsWithValue asserts that function fn() would panic with the
+// expected value.
+func PanicsWithValue(t T, fn func(), expect interface{}, msg ...string) bool {
+	t.Helper()
+	panicked, r := doPanic(fn)
+	if !panicked {
+		fail(t, "did not panic", msg...)
+		return false
+	}
+	if !reflect.DeepEqual(r, expect) {
+		str := fmt.Sprintf("got panic (%T) %v but expect (%T) %v", r, r, expect, expect)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// Eventually asserts that cond() returns true within timeout, cond() is
+// checked every interval.
+func Eventually(t T, cond func() bool, timeout, interval time.Duration, msg ...string) bool {
+	t.Helper()
+	if !poll(cond, timeout, interval) {
+		str := fmt.Sprintf("condition not satisfied in %s", timeout)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// Never asserts that cond() never returns true within timeout, cond() is
+// checked every interval.
+func Never(t T, cond func() bool, timeout, interval time.Duration, msg ...string) bool {
+	t.Helper()
+	if poll(cond, timeout, interval) {
+		str := fmt.Sprintf("condition satisfied in %s but expect never", timeout)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// poll 每隔 interval 检查一次 cond() ，在 timeout 内返回 true 时返回 true 。
+func poll(cond func() bool, timeout, interval time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond() {
+			return true
+		}
+		d := time.Until(deadline)
+		if d <= 0 {
+			return false
+		}
+		if d > interval {
+			d = interval
+		}
+		time.Sleep(d)
+	}
 }
 
 // Matches asserts that a got value matches a given regular expression.
-func Matches(t T, got string, expr string, msg ...string) {
+func Matches(t T, got string, expr string, msg ...string) bool {
 	t.Helper()
-	matches(t, got, expr, msg...)
+	return matches(t, got, expr, msg...)
 }
 
 // Error asserts that a got error string matches a given regular expression.
-func Error(t T, got error, expr string, msg ...string) {
+func Error(t T, got error, expr string, msg ...string) bool {
 	t.Helper()
 	if got == nil {
 		fail(t, "expect not nil error", msg...)
-		return
+		return false
+	}
+	return matches(t, got.Error(), expr, msg...)
+}
+
+// ErrorIs asserts that target is in got's error chain.
+func ErrorIs(t T, got error, target error, msg ...string) bool {
+	t.Helper()
+	if !errors.Is(got, target) {
+		str := fmt.Sprintf("got (%T) %v but expect error is (%T) %v", got, got, target, target)
+		fail(t, str, msg...)
+		return false
 	}
-	matches(t, got.Error(), expr, msg...)
+	return true
 }
 
-func matches(t T, got string, expr string, msg ...string) {
+// ErrorAs asserts that an error in got's error chain can be assigned to
+// target, which must be a non-nil pointer.
+func ErrorAs(t T, got error, target interface{}, msg ...string) bool {
+	t.Helper()
+	if !errors.As(got, target) {
+		str := fmt.Sprintf("got (%T) %v but expect error as (%s)", got, got, reflect.TypeOf(target).Elem())
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// ErrorContains asserts that a got error string contains substr.
+func ErrorContains(t T, got error, substr string, msg ...string) bool {
+	t.Helper()
+	if got == nil {
+		fail(t, "expect not nil error", msg...)
+		return false
+	}
+	if !strings.Contains(got.Error(), substr) {
+		str := fmt.Sprintf("got %q which does not contain %q", got.Error(), substr)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+func matches(t T, got string, expr string, msg ...string) bool {
 	t.Helper()
 	if ok, err := regexp.MatchString(expr, got); err != nil {
 		fail(t, "invalid pattern", msg...)
+		return false
 	} else if !ok {
 		str := fmt.Sprintf("got %q which does not match %q", got, expr)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 func fail(t T, str string, msg ...string) {
@@ -201,7 +354,7 @@ func fail(t T, str string, msg ...string) {
 }
 
 // TypeOf asserts that got and expect are same type.
-func TypeOf(t T, got interface{}, expect interface{}, msg ...string) {
+func TypeOf(t T, got interface{}, expect interface{}, msg ...string) bool {
 	t.Helper()
 
 	e2 := reflect.TypeOf(expect)
@@ -215,11 +368,13 @@ func TypeOf(t T, got interface{}, expect interface{}, msg ...string) {
 	if !e1.AssignableTo(e2) {
 		str := fmt.Sprintf("got type (%s) but expect type (%s)", e1, e2)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // Implements asserts that got implements expect.
-func Implements(t T, got interface{}, expect interface{}, msg ...string) {
+func Implements(t T, got interface{}, expect interface{}, msg ...string) bool {
 	t.Helper()
 
 	e2 := reflect.TypeOf(expect)
@@ -228,7 +383,7 @@ func Implements(t T, got interface{}, expect interface{}, msg ...string) {
 			e2 = e2.Elem()
 		} else {
 			fail(t, "expect should be interface", msg...)
-			return
+			return false
 		}
 	}
 
@@ -236,22 +391,116 @@ func Implements(t T, got interface{}, expect interface{}, msg ...string) {
 	if !e1.Implements(e2) {
 		str := fmt.Sprintf("got type (%s) but expect type (%s)", e1, e2)
 		fail(t, str, msg...)
+		return false
 	}
+	return true
 }
 
 // JsonEqual asserts that got and expect are equal.
-func JsonEqual(t T, got string, expect string, msg ...string) {
+//
+// Deprecated: 使用 JSONEqual 。
+func JsonEqual(t T, got string, expect string, msg ...string) bool {
+	t.Helper()
+	return JSONEqual(t, got, expect, msg...)
+}
+
+// JSONEqual asserts that got and expect are structurally equal JSON
+// strings, key order and whitespace are ignored.
+func JSONEqual(t T, got string, expect string, msg ...string) bool {
 	t.Helper()
-	var gotJson interface{}
-	if err := json.Unmarshal([]byte(got), &gotJson); err != nil {
-		fail(t, err.Error(), msg...)
+	gotJSON, expectJSON, ok := unmarshalJSON(t, got, expect, msg...)
+	if !ok {
+		return false
 	}
-	var expectJson interface{}
-	if err := json.Unmarshal([]byte(expect), &expectJson); err != nil {
-		fail(t, err.Error(), msg...)
+	if !reflect.DeepEqual(gotJSON, expectJSON) {
+		if canDiff(gotJSON, expectJSON) {
+			if s := diff(expectJSON, gotJSON); s != "" {
+				fail(t, "got json not equal to expect json", append(msg, "diff:\n"+s)...)
+				return false
+			}
+		}
+		str := fmt.Sprintf("got json %s but expect json %s", got, expect)
+		fail(t, str, msg...)
+		return false
 	}
-	if !reflect.DeepEqual(gotJson, expectJson) {
-		str := fmt.Sprintf("got (%T) %v but expect (%T) %v", got, got, expect, expect)
+	return true
+}
+
+// JSONSubset asserts that got contains every field of expect. Objects in
+// got may have extra keys, arrays must have the same length and scalars
+// must be equal.
+func JSONSubset(t T, got string, expect string, msg ...string) bool {
+	t.Helper()
+	gotJSON, expectJSON, ok := unmarshalJSON(t, got, expect, msg...)
+	if !ok {
+		return false
+	}
+	if str := jsonSubset("$", gotJSON, expectJSON); str != "" {
 		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+func unmarshalJSON(t T, got, expect string, msg ...string) (gotJSON, expectJSON interface{}, ok bool) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(got), &gotJSON); err != nil {
+		fail(t, "invalid got json: "+err.Error(), msg...)
+		return nil, nil, false
+	}
+	if err := json.Unmarshal([]byte(expect), &expectJSON); err != nil {
+		fail(t, "invalid expect json: "+err.Error(), msg...)
+		return nil, nil, false
+	}
+	return gotJSON, expectJSON, true
+}
+
+// jsonSubset 返回 got 不包含 expect 的第一处位置，包含时返回空字符串。
+func jsonSubset(path string, got, expect interface{}) string {
+	switch e := expect.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: got %s but expect object", path, jsonString(got))
+		}
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, ok := g[k]
+			if !ok {
+				return fmt.Sprintf("%s: missing key %q", path, k)
+			}
+			if str := jsonSubset(path+"."+k, v, e[k]); str != "" {
+				return str
+			}
+		}
+		return ""
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: got %s but expect array", path, jsonString(got))
+		}
+		if len(g) != len(e) {
+			return fmt.Sprintf("%s: got array length %d but expect %d", path, len(g), len(e))
+		}
+		for i := range e {
+			if str := jsonSubset(fmt.Sprintf("%s[%d]", path, i), g[i], e[i]); str != "" {
+				return str
+			}
+		}
+		return ""
+	default:
+		if !reflect.DeepEqual(got, expect) {
+			return fmt.Sprintf("%s: got %s but expect %s", path, jsonString(got), jsonString(expect))
+		}
+		return ""
 	}
 }
+
+func jsonString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-spring/spring-base/assert"
 	"github.com/golang/mock/gomock"
@@ -186,6 +187,50 @@ func TestEqual(t *testing.T) {
 		g.EXPECT().Fail()
 		assert.Equal(g, 0, "0", "param (index=0)")
 	}
+
+	type S struct {
+		A int
+		B []string
+		M map[string]int
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{`got (assert_test.S) but expect (assert_test.S); param (index=0); diff:
+--- expect
++++ got
+  assert_test.S{
+    A: 1,
+    B: []string{
+-     "c",
++     "b",
+    },
+    M: map[string]int{
+      "x": 1,
+  ...`})
+		g.EXPECT().Fail()
+		got := S{A: 1, B: []string{"b"}, M: map[string]int{"x": 1}}
+		expect := S{A: 1, B: []string{"c"}, M: map[string]int{"x": 1}}
+		assert.Equal(g, got, expect, "param (index=0)")
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{`got (map[string]interface {}) but expect (map[string]interface {}); diff:
+--- expect
++++ got
+  map[string]interface {}{
+-   "a": []int{
+-     1,
+-   },
++   "a": nil,
+    "b": "2",
+  }`})
+		g.EXPECT().Fail()
+		assert.Equal(g, map[string]interface{}{"a": nil, "b": "2"}, map[string]interface{}{"a": []int{1}, "b": "2"})
+	}
 }
 
 func TestNotEqual(t *testing.T) {
@@ -316,6 +361,74 @@ func TestPanic(t *testing.T) {
 	}
 }
 
+func TestPanics(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.Panics(g, func() { panic(errors.New("error")) })
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"did not panic; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.Panics(g, func() {}, "param (index=0)")
+	}
+}
+
+func TestNotPanics(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.NotPanics(g, func() {})
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"got panic (string) error but expect not panic; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.NotPanics(g, func() { panic("error") }, "param (index=0)")
+	}
+}
+
+func TestPanicsWithValue(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.PanicsWithValue(g, func() { panic([]int{1, 2}) }, []int{1, 2})
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"did not panic"})
+		g.EXPECT().Fail()
+		assert.PanicsWithValue(g, func() {}, "error")
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"got panic (int) 3 but expect (string) 3; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.PanicsWithValue(g, func() { panic(3) }, "3", "param (index=0)")
+	}
+}
+
 func TestMatches(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
@@ -443,3 +556,199 @@ func TestImplements(t *testing.T) {
 		assert.Implements(g, new(int), (*int)(nil))
 	}
 }
+
+func TestJSONEqual(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.JSONEqual(g, `{"a":1,"b":[1,2]}`, `{ "b": [1, 2], "a": 1 }`)
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"invalid got json: unexpected end of JSON input; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.JSONEqual(g, `{`, `{}`, "param (index=0)")
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{`got json not equal to expect json; diff:
+--- expect
++++ got
+  map[string]interface {}{
+-   "a": 2,
++   "a": 1,
+    "b": "c",
+  }`})
+		g.EXPECT().Fail()
+		assert.JSONEqual(g, `{"a":1,"b":"c"}`, `{"b":"c","a":2}`)
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{`got json 1 but expect json "1"`})
+		g.EXPECT().Fail()
+		assert.JSONEqual(g, `1`, `"1"`)
+	}
+}
+
+func TestJSONSubset(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	got := `{"a":1,"b":{"c":[{"d":"e","f":true}],"g":null}}`
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.JSONSubset(g, got, `{"b":{"c":[{"f":true}]}}`)
+	}
+
+	testcases := []struct {
+		expect string
+		log    string
+	}{
+		{`{"x":1}`, `$: missing key "x"`},
+		{`{"b":{"c":[{},{}]}}`, `$.b.c: got array length 1 but expect 2`},
+		{`{"b":{"c":[{"d":"x"}]}}`, `$.b.c[0].d: got "e" but expect "x"`},
+		{`{"a":{"b":1}}`, `$.a: got 1 but expect object`},
+		{`{"b":[]}`, `$.b: got {"c":[{"d":"e","f":true}],"g":null} but expect array`},
+	}
+	for _, c := range testcases {
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{c.log})
+		g.EXPECT().Fail()
+		assert.JSONSubset(g, got, c.expect)
+	}
+}
+
+func TestEventually(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		n := 0
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.Eventually(g, func() bool { n++; return n == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, n, 3)
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"condition not satisfied in 10ms; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.Eventually(g, func() bool { return false }, 10*time.Millisecond, time.Millisecond, "param (index=0)")
+	}
+}
+
+func TestNever(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		n := 0
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.Never(g, func() bool { n++; return false }, 10*time.Millisecond, time.Millisecond)
+		assert.True(t, n > 1)
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"condition satisfied in 1s but expect never"})
+		g.EXPECT().Fail()
+		assert.Never(g, func() bool { return true }, time.Second, time.Millisecond)
+	}
+}
+
+type testError struct{ code int }
+
+func (e *testError) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+func TestErrorIs(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	errNotFound := errors.New("not found")
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.ErrorIs(g, fmt.Errorf("query: %w", errNotFound), errNotFound)
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"got (*errors.errorString) timeout but expect error is (*errors.errorString) not found; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.ErrorIs(g, errors.New("timeout"), errNotFound, "param (index=0)")
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		var e *testError
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.ErrorAs(g, fmt.Errorf("query: %w", &testError{code: 3}), &e)
+		assert.Equal(t, e.code, 3)
+	}
+
+	{
+		var e *testError
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"got (<nil>) <nil> but expect error as (*assert_test.testError)"})
+		g.EXPECT().Fail()
+		assert.ErrorAs(g, nil, &e)
+	}
+}
+
+func TestErrorContains(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.ErrorContains(g, errors.New("there's an error (*)"), "an error (*)")
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"expect not nil error"})
+		g.EXPECT().Fail()
+		assert.ErrorContains(g, nil, "an error")
+	}
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{"got \"there's no error\" which does not contain \"an error\"; param (index=0)"})
+		g.EXPECT().Fail()
+		assert.ErrorContains(g, errors.New("there's no error"), "an error", "param (index=0)")
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Len asserts that got has the expected length, got can be a string,
+// slice, array, map or chan.
+func Len(t T, got interface{}, length int, msg ...string) bool {
+	t.Helper()
+	v := reflect.ValueOf(got)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+	default:
+		str := fmt.Sprintf("got (%T) %v which has no length", got, got)
+		fail(t, str, msg...)
+		return false
+	}
+	if v.Len() != length {
+		str := fmt.Sprintf("got length %d but expect %d", v.Len(), length)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// Contains asserts that got contains elem. got can be a string (elem is a
+// substring), slice, array (elem is an element) or map (elem is a key).
+func Contains(t T, got interface{}, elem interface{}, msg ...string) bool {
+	t.Helper()
+	found, ok := contains(got, elem)
+	if !ok {
+		str := fmt.Sprintf("got (%T) %v which can not contain elements", got, got)
+		fail(t, str, msg...)
+		return false
+	}
+	if !found {
+		str := fmt.Sprintf("got (%T) %v which does not contain (%T) %v", got, got, elem, elem)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// NotContains asserts that got does not contain elem.
+func NotContains(t T, got interface{}, elem interface{}, msg ...string) bool {
+	t.Helper()
+	found, ok := contains(got, elem)
+	if !ok {
+		str := fmt.Sprintf("got (%T) %v which can not contain elements", got, got)
+		fail(t, str, msg...)
+		return false
+	}
+	if found {
+		str := fmt.Sprintf("got (%T) %v which contains (%T) %v", got, got, elem, elem)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// ElementsMatch asserts that got and expect have the same elements
+// ignoring the order, duplicated elements must appear the same times.
+func ElementsMatch(t T, got interface{}, expect interface{}, msg ...string) bool {
+	t.Helper()
+	g, e, ok := listValues(t, got, expect, msg...)
+	if !ok {
+		return false
+	}
+	missing, extra := listDiff(e, g)
+	if len(missing) > 0 || len(extra) > 0 {
+		str := fmt.Sprintf("got %v but expect elements %v, missing %v, extra %v", got, expect, missing, extra)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// SubsetOf asserts that every element of got is in expect. got and expect
+// can be both slices (or arrays) or both maps, map entries are compared by
+// key and value.
+func SubsetOf(t T, got interface{}, expect interface{}, msg ...string) bool {
+	t.Helper()
+	gv, ev := reflect.ValueOf(got), reflect.ValueOf(expect)
+	if gv.Kind() == reflect.Map && ev.Kind() == reflect.Map {
+		for _, k := range gv.MapKeys() {
+			v := ev.MapIndex(k)
+			if !v.IsValid() || !reflect.DeepEqual(v.Interface(), gv.MapIndex(k).Interface()) {
+				str := fmt.Sprintf("got %v which is not subset of %v, entry %v:%v not found", got, expect, k, gv.MapIndex(k))
+				fail(t, str, msg...)
+				return false
+			}
+		}
+		return true
+	}
+	g, e, ok := listValues(t, got, expect, msg...)
+	if !ok {
+		return false
+	}
+	if missing, _ := listDiff(g, e); len(missing) > 0 {
+		str := fmt.Sprintf("got %v which is not subset of %v, elements %v not found", got, expect, missing)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
+
+// contains 返回 list 是否包含 elem ，list 不支持包含关系时 ok 为 false 。
+func contains(list interface{}, elem interface{}) (found, ok bool) {
+	v := reflect.ValueOf(list)
+	switch v.Kind() {
+	case reflect.String:
+		s, ok := elem.(string)
+		if !ok {
+			return false, true
+		}
+		return strings.Contains(v.String(), s), true
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if reflect.DeepEqual(v.Index(i).Interface(), elem) {
+				return true, true
+			}
+		}
+		return false, true
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if reflect.DeepEqual(k.Interface(), elem) {
+				return true, true
+			}
+		}
+		return false, true
+	}
+	return false, false
+}
+
+// listValues 将 got 和 expect 转换为 []interface{} ，二者必须都是切片或者数组。
+func listValues(t T, got, expect interface{}, msg ...string) (g, e []interface{}, ok bool) {
+	t.Helper()
+	toList := func(i interface{}) ([]interface{}, bool) {
+		v := reflect.ValueOf(i)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, false
+		}
+		r := make([]interface{}, v.Len())
+		for j := range r {
+			r[j] = v.Index(j).Interface()
+		}
+		return r, true
+	}
+	if g, ok = toList(got); !ok {
+		str := fmt.Sprintf("got (%T) %v which is not a slice or array", got, got)
+		fail(t, str, msg...)
+		return nil, nil, false
+	}
+	if e, ok = toList(expect); !ok {
+		str := fmt.Sprintf("expect (%T) %v which is not a slice or array", expect, expect)
+		fail(t, str, msg...)
+		return nil, nil, false
+	}
+	return g, e, true
+}
+
+// listDiff 返回在 a 中但不在 b 中的元素 (missing) 以及在 b 中但不在 a 中的元素
+// (extra)，重复的元素按照出现的次数计算。
+func listDiff(a, b []interface{}) (missing, extra []interface{}) {
+	used := make([]bool, len(b))
+	for _, x := range a {
+		found := false
+		for j, y := range b {
+			if !used[j] && reflect.DeepEqual(x, y) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, x)
+		}
+	}
+	for j, y := range b {
+		if !used[j] {
+			extra = append(extra, y)
+		}
+	}
+	return
+}
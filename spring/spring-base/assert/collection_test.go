@@ -0,0 +1,97 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCollection(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.Len(g, "abc", 3)
+		assert.Len(g, []int{1, 2}, 2)
+		assert.Len(g, map[string]int{"a": 1}, 1)
+		assert.Contains(g, "abc", "bc")
+		assert.Contains(g, []int{1, 2}, 2)
+		assert.Contains(g, [2]string{"a", "b"}, "a")
+		assert.Contains(g, map[string]int{"a": 1}, "a")
+		assert.NotContains(g, []int{1, 2}, 3)
+		assert.NotContains(g, []int{1, 2}, int64(1))
+		assert.ElementsMatch(g, []int{1, 2, 2, 3}, []int{2, 3, 2, 1})
+		assert.SubsetOf(g, []string{"b"}, []string{"a", "b"})
+		assert.SubsetOf(g, map[string]int{"a": 1}, map[string]int{"a": 1, "b": 2})
+	}
+
+	testcases := []struct {
+		fn  func(g assert.T) bool
+		log string
+	}{
+		{
+			fn:  func(g assert.T) bool { return assert.Len(g, []int{1}, 2, "param (index=0)") },
+			log: "got length 1 but expect 2; param (index=0)",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.Len(g, 3, 1) },
+			log: "got (int) 3 which has no length",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.Contains(g, []int{1, 2}, 3) },
+			log: "got ([]int) [1 2] which does not contain (int) 3",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.Contains(g, 3, 3) },
+			log: "got (int) 3 which can not contain elements",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.NotContains(g, "abc", "b") },
+			log: "got (string) abc which contains (string) b",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.ElementsMatch(g, []int{1, 1, 2}, []int{1, 2, 3}) },
+			log: "got [1 1 2] but expect elements [1 2 3], missing [3], extra [1]",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.ElementsMatch(g, "a", []string{"a"}) },
+			log: "got (string) a which is not a slice or array",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.SubsetOf(g, []string{"a", "c"}, []string{"a", "b"}) },
+			log: "got [a c] which is not subset of [a b], elements [c] not found",
+		},
+		{
+			fn:  func(g assert.T) bool { return assert.SubsetOf(g, map[string]int{"a": 2}, map[string]int{"a": 1}) },
+			log: "got map[a:2] which is not subset of map[a:1], entry a:2 not found",
+		},
+	}
+
+	for _, c := range testcases {
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{c.log})
+		g.EXPECT().Fail()
+		assert.False(t, c.fn(g))
+	}
+}
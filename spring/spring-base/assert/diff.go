@@ -0,0 +1,291 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// diffContext 差异输出时变化行前后保留的上下文行数。
+const diffContext = 3
+
+// maxDiffLines 参与比较的最大行数，超过时不再输出差异。
+const maxDiffLines = 2000
+
+// canDiff 返回 got 和 expect 是否适合输出逐行差异，只有类型相同的结构体、
+// map 、切片和数组 (或者指向它们的指针) 才输出差异。
+func canDiff(got, expect interface{}) bool {
+	t := reflect.TypeOf(got)
+	if t == nil || t != reflect.TypeOf(expect) {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// diff 返回 expect 和 got 的逐行差异，相同时返回空字符串。
+func diff(expect, got interface{}) string {
+	a := strings.Split(dump(expect), "\n")
+	b := strings.Split(dump(got), "\n")
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return ""
+	}
+	ops := diffLines(a, b)
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString("--- expect\n+++ got\n")
+	last := -1
+	for i, op := range ops {
+		if op.kind == ' ' && !nearChange(ops, i) {
+			continue
+		}
+		if i != last+1 {
+			buf.WriteString("  ...\n")
+		}
+		last = i
+		buf.WriteByte(op.kind)
+		buf.WriteByte(' ')
+		buf.WriteString(op.line)
+		buf.WriteByte('\n')
+	}
+	if last != len(ops)-1 {
+		buf.WriteString("  ...\n")
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// nearChange 返回第 i 行前后 diffContext 行内是否存在变化行。
+func nearChange(ops []diffOp, i int) bool {
+	for j := i - diffContext; j <= i+diffContext; j++ {
+		if j >= 0 && j < len(ops) && ops[j].kind != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+type diffOp struct {
+	kind byte // ' ' 、'-' 或者 '+'
+	line string
+}
+
+// diffLines 使用最长公共子序列算法计算 a 到 b 的逐行差异。
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// dump 将 v 输出为多行的 Go 语法风格的字符串，map 的键是有序的。
+func dump(v interface{}) string {
+	d := dumper{visited: make(map[uintptr]bool)}
+	d.dump(reflect.ValueOf(v), 0)
+	return d.buf.String()
+}
+
+type dumper struct {
+	buf     strings.Builder
+	visited map[uintptr]bool
+}
+
+func (d *dumper) indent(n int) {
+	d.buf.WriteString(strings.Repeat("  ", n))
+}
+
+func (d *dumper) dump(v reflect.Value, depth int) {
+
+	if !v.IsValid() {
+		d.buf.WriteString("nil")
+		return
+	}
+
+	if v.CanInterface() {
+		switch i := v.Interface().(type) {
+		case error:
+			if v.Kind() != reflect.Ptr || !v.IsNil() {
+				d.buf.WriteString(strconv.Quote(i.Error()))
+				return
+			}
+		case fmt.Stringer:
+			if v.Kind() != reflect.Ptr || !v.IsNil() {
+				d.buf.WriteString(strconv.Quote(i.String()))
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			d.buf.WriteString("nil")
+			return
+		}
+		if d.visited[v.Pointer()] {
+			fmt.Fprintf(&d.buf, "<cycle %s>", v.Type())
+			return
+		}
+		d.visited[v.Pointer()] = true
+		defer delete(d.visited, v.Pointer())
+		d.buf.WriteByte('&')
+		d.dump(v.Elem(), depth)
+	case reflect.Interface:
+		if v.IsNil() {
+			d.buf.WriteString("nil")
+			return
+		}
+		d.dump(v.Elem(), depth)
+	case reflect.Struct:
+		d.buf.WriteString(v.Type().String())
+		if v.NumField() == 0 {
+			d.buf.WriteString("{}")
+			return
+		}
+		d.buf.WriteString("{\n")
+		for i := 0; i < v.NumField(); i++ {
+			d.indent(depth + 1)
+			d.buf.WriteString(v.Type().Field(i).Name)
+			d.buf.WriteString(": ")
+			d.dump(v.Field(i), depth+1)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteByte('}')
+	case reflect.Map:
+		d.buf.WriteString(v.Type().String())
+		if v.IsNil() {
+			d.buf.WriteString("(nil)")
+			return
+		}
+		if v.Len() == 0 {
+			d.buf.WriteString("{}")
+			return
+		}
+		type entry struct{ key, val string }
+		var entries []entry
+		for _, k := range v.MapKeys() {
+			kd := dumper{visited: d.visited}
+			kd.dump(k, depth+1)
+			vd := dumper{visited: d.visited}
+			vd.dump(v.MapIndex(k), depth+1)
+			entries = append(entries, entry{kd.buf.String(), vd.buf.String()})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+		d.buf.WriteString("{\n")
+		for _, e := range entries {
+			d.indent(depth + 1)
+			d.buf.WriteString(e.key)
+			d.buf.WriteString(": ")
+			d.buf.WriteString(e.val)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		d.buf.WriteString(v.Type().String())
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			d.buf.WriteString("(nil)")
+			return
+		}
+		if v.Len() == 0 {
+			d.buf.WriteString("{}")
+			return
+		}
+		d.buf.WriteString("{\n")
+		for i := 0; i < v.Len(); i++ {
+			d.indent(depth + 1)
+			d.dump(v.Index(i), depth+1)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteByte('}')
+	case reflect.String:
+		d.buf.WriteString(strconv.Quote(v.String()))
+	case reflect.Bool:
+		d.buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		d.buf.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprint(&d.buf, v.Complex())
+	default: // Chan, Func, UnsafePointer
+		if v.IsNil() {
+			fmt.Fprintf(&d.buf, "%s(nil)", v.Type())
+		} else {
+			fmt.Fprintf(&d.buf, "%s(%#x)", v.Type(), v.Pointer())
+		}
+	}
+}
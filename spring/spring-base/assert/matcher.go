@@ -0,0 +1,92 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Matcher 自定义的匹配器，返回值 string 描述匹配的条件，例如 "is a valid
+// session"，匹配失败时也可以返回失败的原因。
+type Matcher interface {
+	Match(v interface{}) (bool, string)
+}
+
+// MatcherFunc 函数形式的 Matcher 。
+type MatcherFunc func(v interface{}) (bool, string)
+
+func (f MatcherFunc) Match(v interface{}) (bool, string) {
+	return f(v)
+}
+
+// NewMatcher 使用条件描述和判断函数创建 Matcher 。
+func NewMatcher(desc string, fn func(v interface{}) bool) Matcher {
+	return MatcherFunc(func(v interface{}) (bool, string) {
+		return fn(v), desc
+	})
+}
+
+// And 返回所有 Matcher 都匹配时才匹配的 Matcher ，匹配失败时返回第一个失败的
+// Matcher 的描述。
+func And(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(v interface{}) (bool, string) {
+		var desc []string
+		for _, m := range matchers {
+			ok, s := m.Match(v)
+			if !ok {
+				return false, s
+			}
+			desc = append(desc, s)
+		}
+		return true, strings.Join(desc, " and ")
+	})
+}
+
+// Or 返回任一 Matcher 匹配时就匹配的 Matcher 。
+func Or(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(v interface{}) (bool, string) {
+		var desc []string
+		for _, m := range matchers {
+			ok, s := m.Match(v)
+			if ok {
+				return true, s
+			}
+			desc = append(desc, s)
+		}
+		return false, strings.Join(desc, " or ")
+	})
+}
+
+// Not 返回和 m 匹配结果相反的 Matcher 。
+func Not(m Matcher) Matcher {
+	return MatcherFunc(func(v interface{}) (bool, string) {
+		ok, s := m.Match(v)
+		return !ok, "not (" + s + ")"
+	})
+}
+
+// That asserts that got matches the matcher.
+func That(t T, got interface{}, m Matcher, msg ...string) bool {
+	t.Helper()
+	if ok, s := m.Match(got); !ok {
+		str := fmt.Sprintf("got (%T) %v which does not match: %s", got, got, s)
+		fail(t, str, msg...)
+		return false
+	}
+	return true
+}
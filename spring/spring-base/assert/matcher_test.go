@@ -0,0 +1,80 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func TestThat(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	positive := assert.NewMatcher("is positive", func(v interface{}) bool {
+		i, ok := v.(int)
+		return ok && i > 0
+	})
+
+	even := assert.NewMatcher("is even", func(v interface{}) bool {
+		i, ok := v.(int)
+		return ok && i%2 == 0
+	})
+
+	validSession := assert.MatcherFunc(func(v interface{}) (bool, string) {
+		s, ok := v.(string)
+		if !ok {
+			return false, "is not a string"
+		}
+		if !strings.HasPrefix(s, "session-") {
+			return false, "has no session- prefix"
+		}
+		return true, "is a valid session"
+	})
+
+	{
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		assert.That(g, 2, assert.And(positive, even))
+		assert.That(g, 3, assert.Or(even, positive))
+		assert.That(g, -3, assert.Not(assert.Or(positive, even)))
+		assert.That(g, "session-1", validSession)
+	}
+
+	testcases := []struct {
+		got interface{}
+		m   assert.Matcher
+		log string
+	}{
+		{3, assert.And(positive, even), "got (int) 3 which does not match: is even"},
+		{-3, assert.Or(positive, even), "got (int) -3 which does not match: is positive or is even"},
+		{2, assert.Not(assert.And(positive, even)), "got (int) 2 which does not match: not (is positive and is even)"},
+		{"s-1", validSession, "got (string) s-1 which does not match: has no session- prefix"},
+	}
+
+	for _, c := range testcases {
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Log([]interface{}{c.log})
+		g.EXPECT().Fail()
+		assert.That(g, c.got, c.m)
+	}
+}
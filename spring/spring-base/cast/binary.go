@@ -0,0 +1,64 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+)
+
+// ToBase64 使用标准 base64 编码将二进制数据转换为字符串。
+func ToBase64(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// FromBase64 将标准 base64 编码的字符串还原为二进制数据。
+func FromBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// NewBase64Encoder 返回以 base64 编码写入 w 的 io.WriteCloser 对象，写入完成后
+// 必须调用 Close 方法才能将最后不足一组的数据写入 w 。
+func NewBase64Encoder(w io.Writer) io.WriteCloser {
+	return base64.NewEncoder(base64.StdEncoding, w)
+}
+
+// NewBase64Decoder 返回从 r 读取 base64 编码数据并还原的 io.Reader 对象。
+func NewBase64Decoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.StdEncoding, r)
+}
+
+// ToHex 将二进制数据转换为小写的十六进制字符串。
+func ToHex(b []byte) string {
+	return hex.EncodeToString(b)
+}
+
+// FromHex 将十六进制字符串还原为二进制数据，大小写均可。
+func FromHex(s string) ([]byte, error) {
+	return hex.DecodeString(s)
+}
+
+// NewHexEncoder 返回以十六进制编码写入 w 的 io.Writer 对象。
+func NewHexEncoder(w io.Writer) io.Writer {
+	return hex.NewEncoder(w)
+}
+
+// NewHexDecoder 返回从 r 读取十六进制数据并还原的 io.Reader 对象。
+func NewHexDecoder(r io.Reader) io.Reader {
+	return hex.NewDecoder(r)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cast"
+)
+
+func TestBase64(t *testing.T) {
+
+	data := []byte("\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n")
+	s := cast.ToBase64(data)
+	assert.Equal(t, s, "AMAKCQC+bQaJWigACg==")
+	b, err := cast.FromBase64(s)
+	assert.Nil(t, err)
+	assert.Equal(t, b, data)
+
+	_, err = cast.FromBase64("AMAKCQC+bQaJWigACg=")
+	assert.Error(t, err, "illegal base64 data at input byte 19")
+
+	var buf bytes.Buffer
+	w := cast.NewBase64Encoder(&buf)
+	for _, c := range data {
+		_, err = w.Write([]byte{c})
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, w.Close())
+	assert.Equal(t, buf.String(), s)
+
+	b, err = ioutil.ReadAll(cast.NewBase64Decoder(strings.NewReader(s)))
+	assert.Nil(t, err)
+	assert.Equal(t, b, data)
+}
+
+func TestHex(t *testing.T) {
+
+	data := []byte("\x00\xc0\n\t")
+	s := cast.ToHex(data)
+	assert.Equal(t, s, "00c00a09")
+	b, err := cast.FromHex("00C00A09")
+	assert.Nil(t, err)
+	assert.Equal(t, b, data)
+
+	_, err = cast.FromHex("0")
+	assert.Error(t, err, "encoding/hex: odd length hex string")
+
+	var buf bytes.Buffer
+	_, err = cast.NewHexEncoder(&buf).Write(data)
+	assert.Nil(t, err)
+	assert.Equal(t, buf.String(), s)
+
+	b, err = ioutil.ReadAll(cast.NewHexDecoder(strings.NewReader(s)))
+	assert.Nil(t, err)
+	assert.Equal(t, b, data)
+}
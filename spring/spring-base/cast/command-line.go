@@ -19,8 +19,7 @@ package cast
 import (
 	"bytes"
 	"errors"
-	"strconv"
-	"unicode"
+	"fmt"
 	"unicode/utf8"
 )
 
@@ -44,107 +43,210 @@ func QuoteCount(s string) int {
 	return 0
 }
 
-// ToCommandLine 将数据转换为命令行格式，可用于 redis 参数格式化。
+// ToCommandLine 将数据转换为命令行格式，可用于 redis 参数格式化。参数为空或者
+// 包含空白、引号、反斜杠、控制字符以及非法的 unicode 字符时使用双引号包裹，引号
+// 内的特殊字符转义为 \n、\" 或者 \xHH 的形式，保证 ParseCommandLine 能够还原。
 func ToCommandLine(data ...interface{}) string {
 	var buf bytes.Buffer
 	for i, arg := range data {
-		switch s := arg.(type) {
-		case string:
-			if c := QuoteCount(s); c > 1 {
-				s = strconv.Quote(s)
-			}
-			buf.WriteString(s)
-		default:
-			buf.WriteString(ToString(arg))
-		}
-		if i < len(data)-1 {
+		if i > 0 {
 			buf.WriteByte(' ')
 		}
+		s, ok := arg.(string)
+		if !ok {
+			s = ToString(arg)
+		}
+		if needQuote(s) {
+			writeQuoted(&buf, s)
+		} else {
+			buf.WriteString(s)
+		}
 	}
 	return buf.String()
 }
 
-// ParseCommandLine 将命令行格式的数据转换为字符串数组。
+// needQuote 返回命令行参数是否需要使用双引号包裹。
+func needQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b <= ' ' || b == '"' || b == '\'' || b == '\\' || b == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// writeQuoted 使用双引号包裹参数，并对其中的特殊字符进行转义。
+func writeQuoted(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b >= utf8.RuneSelf {
+			c, size := utf8.DecodeRuneInString(s[i:])
+			if c != utf8.RuneError || size > 1 {
+				buf.WriteString(s[i : i+size])
+				i += size
+				continue
+			}
+		}
+		switch b {
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\a':
+			buf.WriteString(`\a`)
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+		default:
+			if b < ' ' || b >= 0x7f {
+				buf.WriteString(`\x`)
+				buf.WriteByte(hex[b>>4])
+				buf.WriteByte(hex[b&0xf])
+			} else {
+				buf.WriteByte(b)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
+
+// ParseCommandLine 将命令行格式的数据转换为字符串数组。双引号内支持 \n、\"、
+// \xHH 等转义，单引号内只支持 \' 转义，引号可以和普通字符相连组成一个参数。
 func ParseCommandLine(data string) ([]string, error) {
+	return parseCommandLine(data, false)
+}
+
+// ParseCommandLineStrict 与 ParseCommandLine 相同，但是遇到未知的转义字符、
+// 非法的 \x 转义以及闭合引号后面紧跟其他字符等不规范的输入时返回错误。
+func ParseCommandLineStrict(data string) ([]string, error) {
+	return parseCommandLine(data, true)
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\n', '\r', '\t', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+func parseCommandLine(data string, strict bool) ([]string, error) {
 	var (
 		ret []string
 		buf bytes.Buffer
 	)
 	for i := 0; ; {
-		for i < len(data) && unicode.IsSpace(rune(data[i])) {
+		for i < len(data) && isSpace(data[i]) {
 			i++
 		}
 		if i >= len(data) {
 			return ret, nil
 		}
 		buf.Reset()
-		var (
-			done          bool
-			inQuote       bool
-			inSingleQuote bool
-		)
-		for ; !done; i++ {
-			if i >= len(data) && (inQuote || inSingleQuote) {
-				return nil, errors.New("invalid syntax")
+		for i < len(data) && !isSpace(data[i]) {
+			var (
+				n   int
+				err error
+			)
+			switch c := data[i]; c {
+			case '"':
+				n, err = parseDoubleQuoted(&buf, data[i+1:], strict)
+			case '\'':
+				n, err = parseSingleQuoted(&buf, data[i+1:])
+			default:
+				buf.WriteByte(c)
+				i++
+				continue
 			}
-			if c := data[i]; inQuote {
-				if c == '\\' && i < len(data)-3 && data[i+1] == 'x' && IsHexDigit(data[i+2]) && IsHexDigit(data[i+3]) {
-					b1 := HexDigitToInt(data[i+2]) * 16
-					b2 := HexDigitToInt(data[i+3])
-					b := byte(b1 + b2)
-					buf.WriteByte(b)
-					i += 3
-				} else if c == '\\' && i < len(data)-1 {
-					i++
-					switch c = data[i]; c {
-					case 'n':
-						c = '\n'
-					case 'r':
-						c = '\r'
-					case 't':
-						c = '\t'
-					case 'b':
-						c = '\b'
-					case 'a':
-						c = '\a'
-					}
-					buf.WriteByte(c)
-				} else if c == '"' {
-					done = true
-				} else {
-					buf.WriteByte(c)
-				}
-			} else if inSingleQuote {
-				if c == '\\' && i < len(data)-1 && data[i+1] == '\'' {
-					i++
-					buf.WriteByte('\'')
-				} else if c == '\'' {
-					done = true
-				} else {
-					buf.WriteByte(c)
-				}
-			} else {
-				switch c {
-				case ' ':
-					fallthrough
-				case '\n':
-					fallthrough
-				case '\r':
-					fallthrough
-				case '\t':
-					done = true
-				case '"':
-					inQuote = true
-				case '\'':
-					inSingleQuote = true
-				default:
-					buf.WriteByte(c)
-				}
-				if i == len(data)-1 {
-					done = true
-				}
+			if err != nil {
+				return nil, err
+			}
+			i += n + 1
+			if strict && i < len(data) && !isSpace(data[i]) {
+				return nil, fmt.Errorf("closing quote must be followed by a space at %d", i)
 			}
 		}
 		ret = append(ret, buf.String())
 	}
 }
+
+// parseDoubleQuoted 解析双引号内的数据，返回包括闭合引号在内的长度。
+func parseDoubleQuoted(buf *bytes.Buffer, s string, strict bool) (int, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' {
+			return i + 1, nil
+		}
+		if c != '\\' {
+			buf.WriteByte(c)
+			continue
+		}
+		if i++; i >= len(s) {
+			break
+		}
+		switch c = s[i]; c {
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'a':
+			buf.WriteByte('\a')
+		case '"', '\'', '\\':
+			buf.WriteByte(c)
+		case 'x':
+			if i+2 < len(s) && IsHexDigit(s[i+1]) && IsHexDigit(s[i+2]) {
+				buf.WriteByte(byte(HexDigitToInt(s[i+1])<<4 | HexDigitToInt(s[i+2])))
+				i += 2
+			} else if strict {
+				return 0, errors.New("invalid hex escape")
+			} else {
+				buf.WriteByte(c)
+			}
+		default:
+			if strict {
+				return 0, fmt.Errorf("invalid escape \\%c", c)
+			}
+			buf.WriteByte(c)
+		}
+	}
+	return 0, errors.New("unbalanced quotes")
+}
+
+// parseSingleQuoted 解析单引号内的数据，返回包括闭合引号在内的长度。
+func parseSingleQuoted(buf *bytes.Buffer, s string) (int, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' {
+			return i + 1, nil
+		}
+		if c == '\\' && i+1 < len(s) && s[i+1] == '\'' {
+			i++
+		}
+		buf.WriteByte(s[i])
+	}
+	return 0, errors.New("unbalanced quotes")
+}
@@ -45,3 +45,83 @@ func TestCommandLine(t *testing.T) {
 		"\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n",
 	})
 }
+
+func TestCommandLineRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"hello world",
+		`say "hi"`,
+		"it's",
+		`C:\path`,
+		"line1\nline2\r\n",
+		"中文 参数",
+		"\x7f\x00\xff",
+	}
+	args := make([]interface{}, len(inputs))
+	for i, s := range inputs {
+		args[i] = s
+	}
+	data := cast.ToCommandLine(args...)
+	assert.Equal(t, data, `"" "hello world" "say \"hi\"" "it's" "C:\\path" "line1\nline2\r\n" "中文 参数" "\x7f\x00\xff"`)
+	outputs, err := cast.ParseCommandLineStrict(data)
+	assert.Nil(t, err)
+	assert.Equal(t, outputs, inputs)
+}
+
+func TestParseCommandLine(t *testing.T) {
+
+	testcases := []struct {
+		data   string
+		expect []string
+		err    string
+	}{
+		{
+			data:   "  SET a  b\t",
+			expect: []string{"SET", "a", "b"},
+		},
+		{
+			data:   `SET 'a b' 'it\'s'`,
+			expect: []string{"SET", "a b", "it's"},
+		},
+		{
+			data:   `SET a"b c"d`,
+			expect: []string{"SET", "ab cd"},
+			err:    "closing quote must be followed by a space at 10",
+		},
+		{
+			data:   `SET "\q"`,
+			expect: []string{"SET", "q"},
+			err:    `invalid escape \\q`,
+		},
+		{
+			data:   `SET "\xZZ"`,
+			expect: []string{"SET", "xZZ"},
+			err:    "invalid hex escape",
+		},
+		{
+			data: `SET "a`,
+			err:  "unbalanced quotes",
+		},
+		{
+			data: `SET 'a`,
+			err:  "unbalanced quotes",
+		},
+	}
+
+	for _, c := range testcases {
+		outputs, err := cast.ParseCommandLine(c.data)
+		if c.expect == nil {
+			assert.Error(t, err, c.err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, outputs, c.expect)
+		}
+		outputs, err = cast.ParseCommandLineStrict(c.data)
+		if c.err != "" {
+			assert.Error(t, err, c.err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, outputs, c.expect)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// ToSliceOf 将 slice 或者 array 类型的 i 转换为 []T ，每个元素使用对应的
+// ToXxxE 函数进行转换，例如 ToSliceOf[int]([]string{"1", "2"}) 。i 为 nil 时
+// 返回 nil 。
+func ToSliceOf[T any](i interface{}) ([]T, error) {
+	if i == nil {
+		return nil, nil
+	}
+	ret := make([]T, 0)
+	t := reflect.TypeOf(ret)
+	src := reflect.ValueOf(i)
+	if k := src.Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, fmt.Errorf("unable to cast %#v of type %T to %s", i, i, t)
+	}
+	ret = make([]T, src.Len())
+	v := reflect.ValueOf(ret)
+	for j := 0; j < src.Len(); j++ {
+		e, err := convert(src.Index(j).Interface(), t.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", j, err)
+		}
+		v.Index(j).Set(e)
+	}
+	return ret, nil
+}
+
+// ToMapOf 将 map 类型的 i 转换为 map[K]V ，每个键和值使用对应的 ToXxxE 函数
+// 进行转换，例如 ToMapOf[string, int](m) 。i 为 nil 时返回 nil 。
+func ToMapOf[K comparable, V any](i interface{}) (map[K]V, error) {
+	if i == nil {
+		return nil, nil
+	}
+	ret := make(map[K]V)
+	t := reflect.TypeOf(ret)
+	src := reflect.ValueOf(i)
+	if src.Kind() != reflect.Map {
+		return nil, fmt.Errorf("unable to cast %#v of type %T to %s", i, i, t)
+	}
+	v := reflect.ValueOf(ret)
+	iter := src.MapRange()
+	for iter.Next() {
+		key, err := convert(iter.Key().Interface(), t.Key())
+		if err != nil {
+			return nil, fmt.Errorf("key %v: %w", iter.Key(), err)
+		}
+		val, err := convert(iter.Value().Interface(), t.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("key %v: %w", iter.Key(), err)
+		}
+		v.SetMapIndex(key, val)
+	}
+	return ret, nil
+}
+
+// convert 将 i 转换为 t 类型的值。
+func convert(i interface{}, t reflect.Type) (reflect.Value, error) {
+
+	if i != nil && reflect.TypeOf(i) == t {
+		return reflect.ValueOf(i), nil
+	}
+
+	switch t {
+	case durationType:
+		d, err := ToDurationE(i)
+		return reflect.ValueOf(d), err
+	case timeType:
+		d, err := ToTimeE(i)
+		return reflect.ValueOf(d), err
+	}
+
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := ToBoolE(i)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := ToInt64E(i)
+		if err != nil {
+			return v, err
+		}
+		if v.OverflowInt(n) {
+			return v, fmt.Errorf("%d overflows %s", n, t)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := ToUint64E(i)
+		if err != nil {
+			return v, err
+		}
+		if v.OverflowUint(n) {
+			return v, fmt.Errorf("%d overflows %s", n, t)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := ToFloat64E(i)
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		s, err := ToStringE(i)
+		if err != nil {
+			return v, err
+		}
+		v.SetString(s)
+	case reflect.Interface:
+		if i != nil {
+			if !reflect.TypeOf(i).Implements(t) {
+				return v, fmt.Errorf("%T does not implement %s", i, t)
+			}
+			v.Set(reflect.ValueOf(i))
+		}
+	default:
+		if i == nil {
+			return v, nil
+		}
+		iv := reflect.ValueOf(i)
+		if !iv.Type().ConvertibleTo(t) {
+			return v, fmt.Errorf("unable to cast %#v of type %T to %s", i, i, t)
+		}
+		v.Set(iv.Convert(t))
+	}
+	return v, nil
+}
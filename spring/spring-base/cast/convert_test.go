@@ -0,0 +1,78 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cast"
+)
+
+func TestToSliceOf(t *testing.T) {
+
+	ints, err := cast.ToSliceOf[int]([]interface{}{1, "2", 3.0, true})
+	assert.Nil(t, err)
+	assert.Equal(t, ints, []int{1, 2, 3, 1})
+
+	strs, err := cast.ToSliceOf[string]([3]int{1, 2, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, strs, []string{"1", "2", "3"})
+
+	durations, err := cast.ToSliceOf[time.Duration]([]string{"1s", "2m"})
+	assert.Nil(t, err)
+	assert.Equal(t, durations, []time.Duration{time.Second, 2 * time.Minute})
+
+	values, err := cast.ToSliceOf[interface{}]([]interface{}{1, nil})
+	assert.Nil(t, err)
+	assert.Equal(t, values, []interface{}{1, nil})
+
+	_, err = cast.ToSliceOf[int8]([]int{1, 300})
+	assert.Error(t, err, "index 1: 300 overflows int8")
+
+	_, err = cast.ToSliceOf[int]([]string{"a"})
+	assert.Error(t, err, "index 0: strconv.ParseInt: parsing \"a\": invalid syntax")
+
+	_, err = cast.ToSliceOf[int]("abc")
+	assert.Error(t, err, "unable to cast \"abc\" of type string to \\[\\]int")
+
+	ints, err = cast.ToSliceOf[int](nil)
+	assert.Nil(t, err)
+	assert.Nil(t, ints)
+}
+
+func TestToMapOf(t *testing.T) {
+
+	m, err := cast.ToMapOf[string, int](map[interface{}]interface{}{"a": "1", 2: 2.0})
+	assert.Nil(t, err)
+	assert.Equal(t, m, map[string]int{"a": 1, "2": 2})
+
+	bools, err := cast.ToMapOf[int, bool](map[string]string{"1": "true", "2": "false"})
+	assert.Nil(t, err)
+	assert.Equal(t, bools, map[int]bool{1: true, 2: false})
+
+	_, err = cast.ToMapOf[int, bool](map[string]string{"a": "true"})
+	assert.Error(t, err, "key a: strconv.ParseInt: parsing \"a\": invalid syntax")
+
+	m, err = cast.ToMapOf[string, int](nil)
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+
+	_, err = cast.ToMapOf[int, bool]([]int{1})
+	assert.Error(t, err, "unable to cast \\[\\]int\\{1\\} of type \\[\\]int to map\\[int\\]bool")
+}
@@ -17,29 +17,100 @@
 package cast
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
+	"io"
 	"strconv"
+	"strings"
 )
 
 // ToCSV 将数据转换为 CSV 格式，可用于 redis 结果格式化。
 func ToCSV(data ...interface{}) string {
-	var buf bytes.Buffer
+	return string(appendCSV(nil, data...))
+}
+
+func appendCSV(b []byte, data ...interface{}) []byte {
 	for i, arg := range data {
 		switch s := arg.(type) {
 		case string:
 			if c := QuoteCount(s); c == 1 {
 				s = strconv.Quote(s)
 			}
-			buf.WriteString(strconv.Quote(s))
+			b = strconv.AppendQuote(b, s)
 		default:
-			buf.WriteString(strconv.Quote(ToString(arg)))
+			b = strconv.AppendQuote(b, ToString(arg))
 		}
 		if i < len(data)-1 {
-			buf.WriteByte(',')
+			b = append(b, ',')
+		}
+	}
+	return b
+}
+
+// CSVWriter 以流的方式写入 CSV 格式的数据，每条记录占一行，记录的格式和 ToCSV
+// 相同，适用于编码很大的结果集而不必在内存中拼接完整的字符串。
+type CSVWriter struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+// NewCSVWriter 返回写入 w 的 CSVWriter 对象。
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: bufio.NewWriter(w)}
+}
+
+// Write 写入一条记录，数据保存在缓冲区中，需要调用 Flush 才能确保写入 w 。
+func (w *CSVWriter) Write(record ...interface{}) error {
+	w.buf = appendCSV(w.buf[:0], record...)
+	w.buf = append(w.buf, '\n')
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// Flush 将缓冲区中的数据写入 w 。
+func (w *CSVWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// CSVReader 以流的方式读取 CSV 格式的数据，是 CSVWriter 的逆过程，空行会被忽略。
+type CSVReader struct {
+	r *bufio.Reader
+}
+
+// NewCSVReader 返回从 r 读取数据的 CSVReader 对象。
+func NewCSVReader(r io.Reader) *CSVReader {
+	return &CSVReader{r: bufio.NewReader(r)}
+}
+
+// Read 读取一条记录，没有更多记录时返回 io.EOF 。
+func (r *CSVReader) Read() ([]string, error) {
+	for {
+		line, err := r.r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" {
+			continue
+		}
+		return ParseCSV(line)
+	}
+}
+
+// ReadAll 读取剩余的全部记录。
+func (r *CSVReader) ReadAll() ([][]string, error) {
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
 		}
+		records = append(records, record)
 	}
-	return buf.String()
 }
 
 // ParseCSV 将 CSV 格式的数据转换为字符串数组。
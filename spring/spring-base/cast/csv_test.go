@@ -17,6 +17,9 @@
 package cast_test
 
 import (
+	"bytes"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/go-spring/spring-base/assert"
@@ -45,3 +48,34 @@ func TestCSV(t *testing.T) {
 		"\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n",
 	})
 }
+
+func TestCSVStream(t *testing.T) {
+
+	var buf bytes.Buffer
+	w := cast.NewCSVWriter(&buf)
+	assert.Nil(t, w.Write("id", "name"))
+	assert.Nil(t, w.Write(1, "a,b\nc"))
+	assert.Nil(t, w.Write(2, "\x00\xc0"))
+	assert.Nil(t, w.Flush())
+
+	data := buf.String()
+	assert.Equal(t, data, cast.ToCSV("id", "name")+"\n"+
+		cast.ToCSV(1, "a,b\nc")+"\n"+cast.ToCSV(2, "\x00\xc0")+"\n")
+
+	r := cast.NewCSVReader(strings.NewReader(data + "\n"))
+	record, err := r.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, record, []string{"id", "name"})
+	records, err := r.ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, records, [][]string{
+		{"1", "a,b\nc"},
+		{"2", "\x00\xc0"},
+	})
+	_, err = r.Read()
+	assert.Equal(t, err, io.EOF)
+
+	r = cast.NewCSVReader(strings.NewReader(`"a","b`))
+	_, err = r.Read()
+	assert.Error(t, err, "invalid syntax")
+}
@@ -25,28 +25,74 @@ import (
 
 const rootKey = "$"
 
+// FlatOption 设置 Flat 和 FlatSlice 的展开方式。
+type FlatOption func(*flatOptions)
+
+type flatOptions struct {
+	nullAs  string
+	nullSet bool
+	emptyAs string
+}
+
+// NullAs 设置 json 的 null 值展开后的字符串，默认为空字符串，即不区分 null 和
+// ""。不存在的元素不会生成 key，因此总是可以和 null 以及 "" 区分开来。
+func NullAs(s string) FlatOption {
+	return func(opts *flatOptions) {
+		opts.nullAs = s
+		opts.nullSet = true
+	}
+}
+
+// EmptyAs 设置空字符串展开后的字符串，默认为空字符串。
+func EmptyAs(s string) FlatOption {
+	return func(opts *flatOptions) {
+		opts.emptyAs = s
+	}
+}
+
+func newFlatOptions(opts []FlatOption) *flatOptions {
+	o := &flatOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
 // Flat 将 json 字符串解析成一维映射表，如 {"a":{"b":"c"}} 解析成 a.b=c 映射表。
-func Flat(data []byte) map[string]string {
+func Flat(data []byte, opts ...FlatOption) map[string]string {
+	o := newFlatOptions(opts)
 	result := make(map[string]string)
-	if !flatPrefix(rootKey, data, result) {
+	if !flatPrefix(rootKey, data, result, o) {
 		result[rootKey] = string(data)
 	}
 	return result
 }
 
-func FlatSlice(data []string) map[string]string {
+// FlatSlice 将字符串数组解析成一维映射表，每个元素按照 json 格式展开，如
+// ["a","{\"b\":\"c\"}"] 解析成 [0]=a 和 [1].b=c 映射表。
+func FlatSlice(data []string, opts ...FlatOption) map[string]string {
+	o := newFlatOptions(opts)
 	result := make(map[string]string)
 	for i, v := range data {
 		k := rootKey + fmt.Sprintf("[%d]", i)
-		if !flatPrefix(k, []byte(v), result) {
+		if !flatPrefix(k, []byte(v), result, o) {
 			result[k] = v
 		}
 	}
 	return result
 }
 
-func flatPrefix(prefix string, data []byte, result map[string]string) bool {
-	switch trimData := bytes.TrimSpace(data); trimData[0] {
+func flatPrefix(prefix string, data []byte, result map[string]string, opts *flatOptions) bool {
+	trimData := bytes.TrimSpace(data)
+	if len(trimData) == 0 {
+		if len(data) == 0 {
+			result[prefix] = opts.emptyAs
+		} else {
+			result[prefix] = string(data)
+		}
+		return true
+	}
+	switch trimData[0] {
 	case '{':
 		var m map[string]json.RawMessage
 		if json.Unmarshal(trimData, &m) != nil || len(m) == 0 {
@@ -54,7 +100,7 @@ func flatPrefix(prefix string, data []byte, result map[string]string) bool {
 		}
 		for k, v := range m {
 			k = prefix + "." + k
-			if !flatPrefix(k, v, result) {
+			if !flatPrefix(k, v, result, opts) {
 				result[k] = string(v)
 			}
 		}
@@ -66,32 +112,40 @@ func flatPrefix(prefix string, data []byte, result map[string]string) bool {
 		}
 		for i, v := range s {
 			k := prefix + fmt.Sprintf("[%d]", i)
-			if !flatPrefix(k, v, result) {
+			if !flatPrefix(k, v, result, opts) {
 				result[k] = string(v)
 			}
 		}
 		return true
-	default:
-		var s string
-		if json.Unmarshal(data, &s) != nil {
-			result[prefix] = string(data)
+	case 'n':
+		if string(trimData) == "null" {
+			result[prefix] = opts.nullAs
 			return true
 		}
-		trimStr := strings.TrimSpace(s)
-		if len(trimStr) == 0 {
+	}
+	var s string
+	if json.Unmarshal(data, &s) != nil {
+		result[prefix] = string(data)
+		return true
+	}
+	trimStr := strings.TrimSpace(s)
+	if len(trimStr) == 0 {
+		if len(s) == 0 {
+			result[prefix] = opts.emptyAs
+		} else {
 			result[prefix] = s
-			return true
 		}
-		switch trimStr[0] {
-		case '{', '[', '"':
-			k := prefix + ".\"\""
-			if !flatPrefix(k, []byte(trimStr), result) {
-				result[prefix] = s
-			}
-			return true
-		default:
+		return true
+	}
+	switch trimStr[0] {
+	case '{', '[', '"':
+		k := prefix + ".\"\""
+		if !flatPrefix(k, []byte(trimStr), result, opts) {
 			result[prefix] = s
-			return true
 		}
+		return true
+	default:
+		result[prefix] = s
+		return true
 	}
 }
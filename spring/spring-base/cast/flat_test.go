@@ -113,3 +113,37 @@ func TestFlatSlice(t *testing.T) {
 		"$[2].e[0]": "f",
 	})
 }
+
+func TestFlatSliceNull(t *testing.T) {
+	data := []string{"", "null", "\"\"", "[null,\"\",\"a\"]", "{\"a\":null}", " "}
+
+	m := cast.FlatSlice(data)
+	assert.Equal(t, m, map[string]string{
+		"$[0]":    "",
+		"$[1]":    "",
+		"$[2]":    "",
+		"$[3][0]": "",
+		"$[3][1]": "",
+		"$[3][2]": "a",
+		"$[4].a":  "",
+		"$[5]":    " ",
+	})
+
+	m = cast.FlatSlice(data, cast.NullAs("::null::"), cast.EmptyAs("::empty::"))
+	assert.Equal(t, m, map[string]string{
+		"$[0]":    "::empty::",
+		"$[1]":    "::null::",
+		"$[2]":    "::empty::",
+		"$[3][0]": "::null::",
+		"$[3][1]": "::empty::",
+		"$[3][2]": "a",
+		"$[4].a":  "::null::",
+		"$[5]":    " ",
+	})
+
+	m = cast.Flat([]byte(`{"a":null,"b":""}`), cast.NullAs("::null::"))
+	assert.Equal(t, m, map[string]string{
+		"$.a": "::null::",
+		"$.b": "",
+	})
+}
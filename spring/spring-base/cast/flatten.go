@@ -0,0 +1,356 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flatten 将嵌套的结构体、map、slice 展开成一维映射表，key 的形式如 a.b[0].c，
+// 结构体字段优先使用 json 标签的名称，匿名嵌入的结构体字段直接展开在当前层级。
+// nil 值展开为 NullAs 设置的字符串，空的 map 和 slice 分别展开为 {} 和 [] 。
+func Flatten(v interface{}, opts ...FlatOption) map[string]string {
+	o := newFlatOptions(opts)
+	result := make(map[string]string)
+	flatten("", reflect.ValueOf(v), result, o)
+	return result
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func flatten(prefix string, v reflect.Value, result map[string]string, opts *flatOptions) {
+
+	if !v.IsValid() {
+		result[prefix] = opts.nullAs
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			result[prefix] = opts.nullAs
+			return
+		}
+		flatten(prefix, v.Elem(), result, opts)
+		return
+	case reflect.Map:
+		if v.IsNil() {
+			result[prefix] = opts.nullAs
+			return
+		}
+		if v.Len() == 0 {
+			result[prefix] = "{}"
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			k := ToString(iter.Key().Interface())
+			flatten(joinKey(prefix, k), iter.Value(), result, opts)
+		}
+		return
+	case reflect.Slice:
+		if v.IsNil() {
+			result[prefix] = opts.nullAs
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		fallthrough
+	case reflect.Array:
+		if v.Len() == 0 {
+			result[prefix] = "[]"
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			flatten(prefix+"["+strconv.Itoa(i)+"]", v.Index(i), result, opts)
+		}
+		return
+	case reflect.Struct:
+		if v.Type() == timeType {
+			break
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			name, ok := fieldName(f)
+			if !ok {
+				continue
+			}
+			if f.Anonymous && name == "" {
+				flatten(prefix, v.Field(i), result, opts)
+				continue
+			}
+			flatten(joinKey(prefix, name), v.Field(i), result, opts)
+		}
+		return
+	}
+
+	var s string
+	switch x := v.Interface().(type) {
+	case time.Time:
+		s = x.Format(time.RFC3339Nano)
+	case []byte:
+		s = string(x)
+	default:
+		s = ToString(x)
+	}
+	if s == "" {
+		s = opts.emptyAs
+	}
+	result[prefix] = s
+}
+
+// fieldName 返回字段展开后的名称，匿名嵌入且没有 json 标签的结构体返回空字符串，
+// 标签为 "-" 的字段返回 false 。
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag != "" {
+		return tag, true
+	}
+	if f.Anonymous {
+		t := f.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct {
+			return "", true
+		}
+	}
+	return f.Name, true
+}
+
+// Unflatten 是 Flatten 的逆过程，将一维映射表还原到 out 指向的对象中，out 必须
+// 是非空指针。只有设置了 NullAs 时，等于该值的 key 才会被还原为 nil 。因为 key
+// 使用 . 分隔，所以 map 的键不能包含 . 和 [ 字符。
+func Unflatten(m map[string]string, out interface{}, opts ...FlatOption) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("out should be a non-nil pointer but %T", out)
+	}
+	return unflatten(m, "", v.Elem(), newFlatOptions(opts))
+}
+
+// hasChildren 返回映射表中是否存在 prefix 的子 key 。
+func hasChildren(m map[string]string, prefix string) bool {
+	for k := range m {
+		if prefix == "" {
+			return true
+		}
+		if strings.HasPrefix(k, prefix) && len(k) > len(prefix) {
+			if c := k[len(prefix)]; c == '.' || c == '[' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func unflatten(m map[string]string, prefix string, v reflect.Value, opts *flatOptions) error {
+
+	val, exact := m[prefix]
+	if exact && opts.nullSet && val == opts.nullAs {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !exact && !hasChildren(m, prefix) {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unflatten(m, prefix, v.Elem(), opts)
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return fmt.Errorf("%s: unsupported type %s", prefix, v.Type())
+		}
+		if exact {
+			v.Set(reflect.ValueOf(val))
+			return nil
+		}
+		if !hasChildren(m, prefix) {
+			return nil
+		}
+		var e reflect.Value
+		if _, ok := m[prefix+"[0]"]; ok || hasChildren(m, prefix+"[0]") {
+			e = reflect.New(reflect.TypeOf([]interface{}{})).Elem()
+		} else {
+			e = reflect.New(reflect.TypeOf(map[string]interface{}{})).Elem()
+		}
+		if err := unflatten(m, prefix, e, opts); err != nil {
+			return err
+		}
+		v.Set(e)
+		return nil
+	case reflect.Map:
+		if exact && val == "{}" {
+			v.Set(reflect.MakeMap(v.Type()))
+			return nil
+		}
+		keys := childKeys(m, prefix)
+		if len(keys) == 0 {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		t := v.Type()
+		for _, k := range keys {
+			key, err := convert(k, t.Key())
+			if err != nil {
+				return fmt.Errorf("%s: %w", joinKey(prefix, k), err)
+			}
+			e := reflect.New(t.Elem()).Elem()
+			if err = unflatten(m, joinKey(prefix, k), e, opts); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, e)
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		n := sliceLen(m, prefix)
+		if n == 0 {
+			if exact && val == "[]" {
+				v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+			}
+			return nil
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := unflatten(m, prefix+"["+strconv.Itoa(i)+"]", v.Index(i), opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		if v.Type() == timeType {
+			break
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			name, ok := fieldName(f)
+			if !ok {
+				continue
+			}
+			key := prefix
+			if !f.Anonymous || name != "" {
+				key = joinKey(prefix, name)
+			}
+			if err := unflatten(m, key, v.Field(i), opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !exact {
+		return nil
+	}
+	if val == opts.emptyAs {
+		val = ""
+	}
+	if v.Kind() == reflect.Slice {
+		v.SetBytes([]byte(val))
+		return nil
+	}
+	e, err := convert(val, v.Type())
+	if err != nil {
+		return fmt.Errorf("%s: %w", prefix, err)
+	}
+	v.Set(e)
+	return nil
+}
+
+// childKeys 返回 prefix 下一级的所有 key，按照字典序排列。
+func childKeys(m map[string]string, prefix string) []string {
+	set := make(map[string]struct{})
+	for k := range m {
+		var rest string
+		if prefix == "" {
+			rest = k
+		} else if strings.HasPrefix(k, prefix+".") {
+			rest = k[len(prefix)+1:]
+		} else {
+			continue
+		}
+		if i := strings.IndexAny(rest, ".["); i >= 0 {
+			rest = rest[:i]
+		}
+		if rest != "" {
+			set[rest] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sliceLen 根据 prefix[i] 形式的 key 计算 slice 的长度。
+func sliceLen(m map[string]string, prefix string) int {
+	n := 0
+	for k := range m {
+		if !strings.HasPrefix(k, prefix+"[") {
+			continue
+		}
+		rest := k[len(prefix)+1:]
+		i := strings.IndexByte(rest, ']')
+		if i < 0 {
+			continue
+		}
+		if index, err := strconv.Atoi(rest[:i]); err == nil && index >= n {
+			n = index + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cast"
+)
+
+type flattenBase struct {
+	ID int `json:"id"`
+}
+
+type flattenItem struct {
+	Name  string
+	Price float64 `json:"price,omitempty"`
+}
+
+type flattenOrder struct {
+	flattenBase
+	User    *string
+	Items   []flattenItem     `json:"items"`
+	Tags    map[string]string `json:"tags"`
+	Extra   interface{}       `json:"extra"`
+	Data    []byte            `json:"data"`
+	Empty   []int             `json:"empty"`
+	Created time.Time         `json:"created"`
+	Timeout time.Duration     `json:"timeout"`
+	Ignore  string            `json:"-"`
+	secret  string
+}
+
+func TestFlatten(t *testing.T) {
+
+	order := flattenOrder{
+		flattenBase: flattenBase{ID: 1},
+		Items: []flattenItem{
+			{Name: "a", Price: 1.5},
+			{Name: "", Price: 2},
+		},
+		Tags:    map[string]string{"k": "v"},
+		Extra:   []interface{}{"x", map[string]interface{}{"y": "z"}},
+		Data:    []byte("abc"),
+		Empty:   []int{},
+		Created: time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC),
+		Timeout: time.Second,
+		Ignore:  "ignore",
+		secret:  "secret",
+	}
+
+	m := cast.Flatten(order, cast.NullAs("::null::"), cast.EmptyAs("::empty::"))
+	assert.Equal(t, m, map[string]string{
+		"id":             "1",
+		"User":           "::null::",
+		"items[0].Name":  "a",
+		"items[0].price": "1.5",
+		"items[1].Name":  "::empty::",
+		"items[1].price": "2",
+		"tags.k":         "v",
+		"extra[0]":       "x",
+		"extra[1].y":     "z",
+		"data":           "abc",
+		"empty":          "[]",
+		"created":        "2021-09-01T00:00:00Z",
+		"timeout":        "1s",
+	})
+
+	var got flattenOrder
+	err := cast.Unflatten(m, &got, cast.NullAs("::null::"), cast.EmptyAs("::empty::"))
+	assert.Nil(t, err)
+	order.Ignore = ""
+	order.secret = ""
+	assert.Equal(t, got, order)
+
+	assert.Equal(t, cast.Flatten([]int{1, 2}), map[string]string{"[0]": "1", "[1]": "2"})
+	assert.Equal(t, cast.Flatten(3), map[string]string{"": "3"})
+
+	var s []int
+	err = cast.Unflatten(map[string]string{"[0]": "1", "[2]": "3"}, &s)
+	assert.Nil(t, err)
+	assert.Equal(t, s, []int{1, 0, 3})
+
+	var mi map[int]bool
+	err = cast.Unflatten(map[string]string{"1": "true", "x": "false"}, &mi)
+	assert.Error(t, err, "x: strconv.ParseInt: parsing \"x\": invalid syntax")
+
+	err = cast.Unflatten(nil, got)
+	assert.Error(t, err, "out should be a non-nil pointer but cast_test.flattenOrder")
+}
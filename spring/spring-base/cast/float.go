@@ -18,6 +18,7 @@ package cast
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -27,6 +28,18 @@ func ToFloat32(i interface{}) float32 {
 	return float32(v)
 }
 
+// ToFloat32E casts an interface{} to a float32. 超出 float32 的范围时返回错误。
+func ToFloat32E(i interface{}) (float32, error) {
+	v, err := ToFloat64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if math.Abs(v) > math.MaxFloat32 && !math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%v overflows float32", v)
+	}
+	return float32(v), nil
+}
+
 // ToFloat64 casts an interface{} to a float64. 在类型明确的情况下推荐使用标准库函数。
 func ToFloat64(i interface{}) float64 {
 	v, _ := ToFloat64E(i)
@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/go-spring/spring-base/assert"
 	"github.com/go-spring/spring-base/cast"
 )
 
@@ -46,3 +47,13 @@ func BenchmarkToFloat(b *testing.B) {
 		})
 	})
 }
+
+func TestToFloat32E(t *testing.T) {
+
+	f, err := cast.ToFloat32E("1.5")
+	assert.Nil(t, err)
+	assert.Equal(t, f, float32(1.5))
+
+	_, err = cast.ToFloat32E(1e40)
+	assert.Error(t, err, "1e\\+40 overflows float32")
+}
@@ -27,24 +27,72 @@ func ToInt(i interface{}) int {
 	return int(v)
 }
 
+// ToIntE casts an interface{} to an int. 超出 int 的范围时返回错误。
+func ToIntE(i interface{}) (int, error) {
+	v, err := ToInt64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(v)) != v {
+		return 0, fmt.Errorf("%d overflows int", v)
+	}
+	return int(v), nil
+}
+
 // ToInt8 casts an interface{} to an int8. 在类型明确的情况下推荐使用标准库函数。
 func ToInt8(i interface{}) int8 {
 	v, _ := ToInt64E(i)
 	return int8(v)
 }
 
+// ToInt8E casts an interface{} to an int8. 超出 int8 的范围时返回错误。
+func ToInt8E(i interface{}) (int8, error) {
+	v, err := ToInt64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int8(v)) != v {
+		return 0, fmt.Errorf("%d overflows int8", v)
+	}
+	return int8(v), nil
+}
+
 // ToInt16 casts an interface{} to an int16. 在类型明确的情况下推荐使用标准库函数。
 func ToInt16(i interface{}) int16 {
 	v, _ := ToInt64E(i)
 	return int16(v)
 }
 
+// ToInt16E casts an interface{} to an int16. 超出 int16 的范围时返回错误。
+func ToInt16E(i interface{}) (int16, error) {
+	v, err := ToInt64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int16(v)) != v {
+		return 0, fmt.Errorf("%d overflows int16", v)
+	}
+	return int16(v), nil
+}
+
 // ToInt32 casts an interface{} to an int32. 在类型明确的情况下推荐使用标准库函数。
 func ToInt32(i interface{}) int32 {
 	v, _ := ToInt64E(i)
 	return int32(v)
 }
 
+// ToInt32E casts an interface{} to an int32. 超出 int32 的范围时返回错误。
+func ToInt32E(i interface{}) (int32, error) {
+	v, err := ToInt64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int32(v)) != v {
+		return 0, fmt.Errorf("%d overflows int32", v)
+	}
+	return int32(v), nil
+}
+
 // ToInt64 casts an interface{} to an int64. 在类型明确的情况下推荐使用标准库函数。
 func ToInt64(i interface{}) int64 {
 	v, _ := ToInt64E(i)
@@ -128,24 +176,72 @@ func ToUint(i interface{}) uint {
 	return uint(v)
 }
 
+// ToUintE casts an interface{} to an uint. 超出 uint 的范围时返回错误。
+func ToUintE(i interface{}) (uint, error) {
+	v, err := ToUint64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(uint(v)) != v {
+		return 0, fmt.Errorf("%d overflows uint", v)
+	}
+	return uint(v), nil
+}
+
 // ToUint8 casts an interface{} to an uint8. 在类型明确的情况下推荐使用标准库函数。
 func ToUint8(i interface{}) uint8 {
 	v, _ := ToUint64E(i)
 	return uint8(v)
 }
 
+// ToUint8E casts an interface{} to an uint8. 超出 uint8 的范围时返回错误。
+func ToUint8E(i interface{}) (uint8, error) {
+	v, err := ToUint64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(uint8(v)) != v {
+		return 0, fmt.Errorf("%d overflows uint8", v)
+	}
+	return uint8(v), nil
+}
+
 // ToUint16 casts an interface{} to an uint16. 在类型明确的情况下推荐使用标准库函数。
 func ToUint16(i interface{}) uint16 {
 	v, _ := ToUint64E(i)
 	return uint16(v)
 }
 
+// ToUint16E casts an interface{} to an uint16. 超出 uint16 的范围时返回错误。
+func ToUint16E(i interface{}) (uint16, error) {
+	v, err := ToUint64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(uint16(v)) != v {
+		return 0, fmt.Errorf("%d overflows uint16", v)
+	}
+	return uint16(v), nil
+}
+
 // ToUint32 casts an interface{} to an uint32. 在类型明确的情况下推荐使用标准库函数。
 func ToUint32(i interface{}) uint32 {
 	v, _ := ToUint64E(i)
 	return uint32(v)
 }
 
+// ToUint32E casts an interface{} to an uint32. 超出 uint32 的范围时返回错误。
+func ToUint32E(i interface{}) (uint32, error) {
+	v, err := ToUint64E(i)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(uint32(v)) != v {
+		return 0, fmt.Errorf("%d overflows uint32", v)
+	}
+	return uint32(v), nil
+}
+
 // ToUint64 casts an interface{} to an uint64. 在类型明确的情况下推荐使用标准库函数。
 func ToUint64(i interface{}) uint64 {
 	v, _ := ToUint64E(i)
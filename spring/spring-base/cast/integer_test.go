@@ -105,3 +105,37 @@ func TestToInt(t *testing.T) {
 		assert.Equal(t, v, testcase.expect, fmt.Sprintf("index %d", i))
 	}
 }
+
+func TestToIntE(t *testing.T) {
+
+	i8, err := cast.ToInt8E("127")
+	assert.Nil(t, err)
+	assert.Equal(t, i8, int8(127))
+
+	_, err = cast.ToInt8E("128")
+	assert.Error(t, err, "128 overflows int8")
+
+	_, err = cast.ToInt16E(-40000)
+	assert.Error(t, err, "-40000 overflows int16")
+
+	i, err := cast.ToIntE("0x10")
+	assert.Nil(t, err)
+	assert.Equal(t, i, 16)
+
+	_, err = cast.ToInt32E("abc")
+	assert.Error(t, err, "strconv.ParseInt: parsing \"abc\": invalid syntax")
+
+	u8, err := cast.ToUint8E(255)
+	assert.Nil(t, err)
+	assert.Equal(t, u8, uint8(255))
+
+	_, err = cast.ToUint8E(256)
+	assert.Error(t, err, "256 overflows uint8")
+
+	_, err = cast.ToUint32E(uint64(1) << 32)
+	assert.Error(t, err, "4294967296 overflows uint32")
+
+	u, err := cast.ToUintE("3")
+	assert.Nil(t, err)
+	assert.Equal(t, u, uint(3))
+}
@@ -18,6 +18,7 @@ package cast
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -30,6 +31,14 @@ const (
 	Hour        = "h"  // 小时
 )
 
+// DefaultTimeLayouts 未指定格式时，字符串依次尝试使用这些格式转换为时间。
+var DefaultTimeLayouts = []string{
+	"2006-01-02 15:04:05 -0700",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
 var unitMap = map[string]int64{
 	"ns": int64(time.Nanosecond),
 	"μs": int64(time.Microsecond),
@@ -39,7 +48,8 @@ var unitMap = map[string]int64{
 	"h":  int64(time.Hour),
 }
 
-// ToDuration casts an interface{} to a time.Duration.
+// ToDuration casts an interface{} to a time.Duration. 字符串可以是 "1h30m" 这样
+// 的格式，也可以是整数或者浮点数，数值的单位由 unit 指定，默认为纳秒。
 func ToDuration(i interface{}, unit ...string) time.Duration {
 	v, _ := ToDurationE(i, unit...)
 	return v
@@ -99,16 +109,32 @@ func ToDurationE(i interface{}, unit ...string) (time.Duration, error) {
 	case *float64:
 		return parseFloatDuration(*s, unit...), nil
 	case string:
-		return time.ParseDuration(s)
+		return parseStringDuration(s, unit...)
 	case *string:
-		return time.ParseDuration(*s)
+		return parseStringDuration(*s, unit...)
 	case time.Duration:
 		return s, nil
+	case *time.Duration:
+		return *s, nil
 	default:
 		return 0, fmt.Errorf("unable to cast %#v of type %T to time.Duration", i, i)
 	}
 }
 
+func parseStringDuration(v string, unit ...string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err == nil {
+		return d, nil
+	}
+	if n, e := strconv.ParseInt(v, 10, 64); e == nil {
+		return parseIntDuration(n, unit...), nil
+	}
+	if f, e := strconv.ParseFloat(v, 64); e == nil {
+		return parseFloatDuration(f, unit...), nil
+	}
+	return 0, err
+}
+
 func parseIntDuration(v int64, unit ...string) time.Duration {
 	unitN := int64(time.Nanosecond)
 	if len(unit) > 0 {
@@ -125,7 +151,9 @@ func parseFloatDuration(v float64, unit ...string) time.Duration {
 	return time.Duration(v * float64(unitN))
 }
 
-// ToTime casts an interface{} to a time.Time.
+// ToTime casts an interface{} to a time.Time. 数值被当作 unix 时间戳，单位由 arg
+// 指定，默认为纳秒；字符串可以是 "1h30m" 这样的时长 (相对于 unix 零点)、整数时间戳
+// 或者格式化的时间，依次尝试 arg 中不是单位的格式，未指定格式时使用 DefaultTimeLayouts 。
 func ToTime(i interface{}, arg ...string) time.Time {
 	v, _ := ToTimeE(i, arg...)
 	return v
@@ -203,11 +231,37 @@ func parseFormatTime(v string, arg ...string) (time.Time, error) {
 		return time.Unix(int64(d/time.Second), int64(d%time.Second)), nil
 	}
 
-	layout := "2006-01-02 15:04:05 -0700"
-	if len(arg) > 0 {
-		layout = arg[0]
+	var (
+		unit    []string
+		layouts []string
+	)
+	for _, s := range arg {
+		if _, ok := unitMap[s]; ok {
+			unit = []string{s}
+		} else if s != "" {
+			layouts = append(layouts, s)
+		}
+	}
+
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return parseIntTimestamp(n, unit...), nil
+	}
+
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	if len(layouts) == 1 {
+		return time.Time{}, err
 	}
-	return time.Parse(layout, v)
+	return time.Time{}, fmt.Errorf("unable to cast %q to Time", v)
 }
 
 func parseIntTimestamp(v int64, arg ...string) time.Time {
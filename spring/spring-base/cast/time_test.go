@@ -126,3 +126,58 @@ func TestToTime(t *testing.T) {
 		}
 	})
 }
+
+func TestToTimeString(t *testing.T) {
+
+	testcases := []struct {
+		value  string
+		arg    []string
+		expect time.Time
+	}{
+		{"2021-09-01T08:00:00+08:00", nil, time.Unix(1630454400, 0)},
+		{"2021-09-01T00:00:00.5Z", nil, time.Unix(1630454400, 5e8)},
+		{"2021-09-01 00:00:00", nil, time.Unix(1630454400, 0)},
+		{"2021-09-01", nil, time.Unix(1630454400, 0)},
+		{"1630454400", []string{cast.Second}, time.Unix(1630454400, 0)},
+		{"1630454400000", []string{cast.Millisecond}, time.Unix(1630454400, 0)},
+		{"01/09/2021", []string{"2006-01-02", "02/01/2006"}, time.Unix(1630454400, 0)},
+		{"1h30m", nil, time.Unix(5400, 0)},
+	}
+
+	for i, testcase := range testcases {
+		v, err := cast.ToTimeE(testcase.value, testcase.arg...)
+		assert.Nil(t, err, fmt.Sprintf("index %d", i))
+		assert.True(t, v.Equal(testcase.expect), fmt.Sprintf("index %d", i))
+	}
+
+	_, err := cast.ToTimeE("abc")
+	assert.Error(t, err, "unable to cast \"abc\" to Time")
+
+	_, err = cast.ToTimeE("abc", "2006-01-02")
+	assert.Error(t, err, "cannot parse \"abc\" as \"2006\"")
+}
+
+func TestToDuration(t *testing.T) {
+
+	testcases := []struct {
+		value  interface{}
+		unit   []string
+		expect time.Duration
+	}{
+		{"1h30m", nil, 90 * time.Minute},
+		{"100", nil, 100},
+		{"100", []string{cast.Millisecond}, 100 * time.Millisecond},
+		{"1.5", []string{cast.Second}, 1500 * time.Millisecond},
+		{3, []string{cast.Second}, 3 * time.Second},
+		{time.Second, nil, time.Second},
+	}
+
+	for i, testcase := range testcases {
+		v, err := cast.ToDurationE(testcase.value, testcase.unit...)
+		assert.Nil(t, err, fmt.Sprintf("index %d", i))
+		assert.Equal(t, v, testcase.expect, fmt.Sprintf("index %d", i))
+	}
+
+	_, err := cast.ToDurationE("abc")
+	assert.Error(t, err, "time: invalid duration")
+}
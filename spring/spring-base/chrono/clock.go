@@ -0,0 +1,252 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/knife"
+)
+
+const clockKey = "::clock::"
+
+// Clock 时钟接口，时间相关的代码通过 Clock 获取时间和创建定时器，以便在单元测试
+// 和流量回放时使用 MockClock 控制时间的流逝。
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer 对应 time.Timer 。
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker 对应 time.Ticker 。
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// RealClock 使用系统时间的时钟。
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()               { t.t.Stop() }
+
+// SetClock 为 context.Context 对象设置时钟。
+func SetClock(ctx context.Context, c Clock) error {
+	return knife.Set(ctx, clockKey, c)
+}
+
+// GetClock 获取 context.Context 对象的时钟，未设置时返回 RealClock 。
+func GetClock(ctx context.Context) Clock {
+	if ctx == nil {
+		return RealClock
+	}
+	if v, ok := knife.Get(ctx, clockKey); ok {
+		if c, ok := v.(Clock); ok {
+			return c
+		}
+	}
+	return RealClock
+}
+
+// Sleep 使用 context.Context 对象的时钟休眠。
+func Sleep(ctx context.Context, d time.Duration) {
+	GetClock(ctx).Sleep(d)
+}
+
+// After 使用 context.Context 对象的时钟等待 d 时间之后返回当前时间。
+func After(ctx context.Context, d time.Duration) <-chan time.Time {
+	return GetClock(ctx).After(d)
+}
+
+// MockClock 可以控制的时钟，时间只在调用 Advance 或者 Set 时改变，到期的定时器
+// 按照到期时间的先后顺序触发。和标准库一样，定时器的通道容量为 1，来不及接收的
+// 触发会被丢弃。
+type MockClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*mockTimer
+}
+
+// NewMockClock 返回以 t 为当前时间的 MockClock 对象。
+func NewMockClock(t time.Time) *MockClock {
+	return &MockClock{now: t}
+}
+
+// Now 返回当前时间。
+func (c *MockClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Sleep 阻塞直到时钟被推进 d 时间。
+func (c *MockClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// After 返回时钟被推进 d 时间后触发的通道。
+func (c *MockClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer 创建 d 时间后触发一次的定时器。
+func (c *MockClock) NewTimer(d time.Duration) Timer {
+	return c.newTimer(d, 0)
+}
+
+// NewTicker 创建每隔 d 时间触发一次的定时器。
+func (c *MockClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return mockTicker{c.newTimer(d, d)}
+}
+
+func (c *MockClock) newTimer(d time.Duration, period time.Duration) *mockTimer {
+	c.mutex.Lock()
+	t := &mockTimer{
+		clock:  c,
+		c:      make(chan time.Time, 1),
+		period: period,
+	}
+	c.start(t, d)
+	c.mutex.Unlock()
+	c.fire()
+	return t
+}
+
+// start 启动定时器，需要在锁内调用。
+func (c *MockClock) start(t *mockTimer, d time.Duration) bool {
+	active := c.stop(t)
+	t.when = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	return active
+}
+
+// stop 停止定时器，需要在锁内调用。
+func (c *MockClock) stop(t *mockTimer) bool {
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advance 将时钟推进 d 时间，并触发期间到期的定时器。
+func (c *MockClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	c.now = c.now.Add(d)
+	c.mutex.Unlock()
+	c.fire()
+}
+
+// Set 将时钟设置为 t，并触发到期的定时器，时间不能回退。
+func (c *MockClock) Set(t time.Time) {
+	c.mutex.Lock()
+	if t.After(c.now) {
+		c.now = t
+	}
+	c.mutex.Unlock()
+	c.fire()
+}
+
+// fire 按照到期时间的先后顺序触发所有到期的定时器。
+func (c *MockClock) fire() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].when.Before(c.timers[j].when)
+		})
+		if len(c.timers) == 0 || c.timers[0].when.After(c.now) {
+			return
+		}
+		t := c.timers[0]
+		select {
+		case t.c <- t.when:
+		default:
+		}
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			c.timers = c.timers[1:]
+		}
+	}
+}
+
+type mockTimer struct {
+	clock  *MockClock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+}
+
+func (t *mockTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *mockTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	return t.clock.stop(t)
+}
+
+func (t *mockTimer) Reset(d time.Duration) bool {
+	t.clock.mutex.Lock()
+	active := t.clock.start(t, d)
+	t.clock.mutex.Unlock()
+	t.clock.fire()
+	return active
+}
+
+type mockTicker struct {
+	*mockTimer
+}
+
+func (t mockTicker) Stop() {
+	t.mockTimer.Stop()
+}
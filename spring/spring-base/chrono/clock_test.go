@@ -0,0 +1,96 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/chrono"
+	"github.com/go-spring/spring-base/knife"
+)
+
+func TestMockClock(t *testing.T) {
+
+	start := time.Unix(100, 0)
+	c := chrono.NewMockClock(start)
+
+	ctx, _ := knife.New(context.Background())
+	assert.Equal(t, chrono.GetClock(ctx), chrono.RealClock)
+	err := chrono.SetClock(ctx, c)
+	assert.Nil(t, err)
+	assert.Equal(t, chrono.Now(ctx), start)
+
+	t.Run("timer", func(t *testing.T) {
+		timer := c.NewTimer(time.Second)
+		c.Advance(999 * time.Millisecond)
+		select {
+		case <-timer.C():
+			t.Fatal("timer fired too early")
+		default:
+		}
+		c.Advance(time.Millisecond)
+		assert.Equal(t, <-timer.C(), start.Add(time.Second))
+		assert.False(t, timer.Stop())
+		assert.False(t, timer.Reset(time.Second))
+		assert.True(t, timer.Stop())
+		c.Advance(time.Second)
+		select {
+		case <-timer.C():
+			t.Fatal("stopped timer fired")
+		default:
+		}
+	})
+
+	t.Run("ticker", func(t *testing.T) {
+		now := c.Now()
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+		for i := 1; i <= 3; i++ {
+			c.Advance(time.Second)
+			assert.Equal(t, <-ticker.C(), now.Add(time.Duration(i)*time.Second))
+		}
+		c.Advance(3 * time.Second)
+		assert.Equal(t, <-ticker.C(), now.Add(4*time.Second))
+		select {
+		case <-ticker.C():
+			t.Fatal("ticks should be dropped")
+		default:
+		}
+	})
+
+	t.Run("sleep", func(t *testing.T) {
+		now := c.Now()
+		ch := c.After(time.Minute)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ch
+			assert.Equal(t, c.Now(), now.Add(time.Hour))
+		}()
+		c.Set(now.Add(time.Hour))
+		wg.Wait()
+		chrono.Sleep(ctx, 0)
+	})
+
+	chrono.ResetTime(ctx)
+	assert.Equal(t, chrono.GetClock(ctx), chrono.RealClock)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/atomic"
+)
+
+// DefaultCoarseGranularity CoarseNow 默认的更新间隔。
+const DefaultCoarseGranularity = time.Millisecond
+
+var coarse struct {
+	once        sync.Once
+	now         atomic.Int64
+	granularity atomic.Duration
+}
+
+// CoarseNow 返回粗粒度的当前时间，该时间由后台协程按照固定的间隔更新，误差不
+// 超过更新间隔，适用于日志、录制等每秒调用上百万次且对精度要求不高的场景。后台
+// 协程在第一次调用时启动。注意返回的时间不携带单调时钟读数。
+func CoarseNow() time.Time {
+	coarse.once.Do(startCoarse)
+	return time.Unix(0, coarse.now.Load())
+}
+
+// SetCoarseGranularity 设置 CoarseNow 的更新间隔，下一次更新之后生效。
+func SetCoarseGranularity(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive granularity for CoarseNow")
+	}
+	coarse.granularity.Store(d)
+}
+
+func startCoarse() {
+	if coarse.granularity.Load() <= 0 {
+		coarse.granularity.Store(DefaultCoarseGranularity)
+	}
+	coarse.now.Store(time.Now().UnixNano())
+	go func() {
+		for {
+			time.Sleep(coarse.granularity.Load())
+			coarse.now.Store(time.Now().UnixNano())
+		}
+	}()
+}
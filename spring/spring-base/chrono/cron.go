@@ -0,0 +1,251 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 描述任务的调度计划。
+type Schedule interface {
+	// Next 返回 t 之后 (不包括 t) 下一次执行的时间，不存在时返回零值。
+	Next(t time.Time) time.Time
+}
+
+// ParseCron 解析 cron 表达式，支持 5 个字段 (分 时 日 月 周) 和 6 个字段 (秒 分
+// 时 日 月 周) 两种格式，每个字段支持 *、?、a、a-b、*/n、a-b/n、a/n 以及它们用
+// 逗号分隔的组合，月和周支持 JAN、MON 这样的英文缩写，周的 0 和 7 都表示周日。此外
+// 还支持 @yearly、@monthly、@weekly、@daily、@hourly 以及 @every <duration> 。
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(expr[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("invalid cron expression %q: interval less than 1s", expr)
+		}
+		return everySchedule(d), nil
+	}
+	if s, ok := cronDescriptors[expr]; ok {
+		expr = s
+	}
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields but %d", expr, len(fields))
+	}
+	s := &cronSchedule{}
+	var err error
+	masks := []*uint64{&s.second, &s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		if *masks[i], err = parseCronField(f, cronBounds[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+	s.domStar = fields[3] == "*" || fields[3] == "?"
+	s.dowStar = fields[5] == "*" || fields[5] == "?"
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+type cronBound struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronBounds = []cronBound{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// parseCronField 将字段解析为位掩码，第 i 位为 1 表示取值 i 。
+func parseCronField(field string, b cronBound) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", part[i+1:], b.name)
+			}
+			step, part = n, part[:i]
+		}
+		var start, end int
+		switch {
+		case part == "*" || part == "?":
+			start, end = b.min, b.max
+		case strings.IndexByte(part, '-') > 0:
+			i := strings.IndexByte(part, '-')
+			var err error
+			if start, err = parseCronValue(part[:i], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(part[i+1:], b); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(part, b); err != nil {
+				return 0, err
+			}
+			end = start
+			if step > 1 {
+				end = b.max
+			}
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q in %s", part, b.name)
+		}
+		for v := start; v <= end; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func parseCronValue(s string, b cronBound) (int, error) {
+	if v, ok := b.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < b.min || v > b.max {
+		return 0, fmt.Errorf("invalid value %q in %s, expected %d-%d", s, b.name, b.min, b.max)
+	}
+	return v, nil
+}
+
+type everySchedule time.Duration
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s) - time.Duration(t.Nanosecond()))
+}
+
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+}
+
+func has(mask uint64, v int) bool {
+	return mask&(1<<uint(v)) != 0
+}
+
+// dayMatches 和标准的 cron 一样，日和周都有限制时满足其一即可。
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+
+	loc := t.Location()
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+
+	// 从大到小逐级匹配，低一级的字段变化导致进位时重新从月份开始匹配。
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for added := false; !has(s.month, int(t.Month())); {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for added := false; !s.dayMatches(t); {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for added := false; !has(s.hour, t.Hour()); {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for added := false; !has(s.minute, t.Minute()); {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for !has(s.second, t.Second()) {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
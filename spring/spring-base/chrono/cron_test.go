@@ -0,0 +1,82 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/chrono"
+)
+
+func TestParseCron(t *testing.T) {
+
+	const layout = "2006-01-02 15:04:05 Mon"
+	parse := func(s string) time.Time {
+		v, err := time.ParseInLocation(layout, s, time.UTC)
+		assert.Nil(t, err)
+		return v
+	}
+
+	testcases := []struct {
+		expr   string
+		from   string
+		expect string
+	}{
+		{"* * * * *", "2021-09-01 10:00:00 Wed", "2021-09-01 10:01:00 Wed"},
+		{"*/15 * * * * *", "2021-09-01 10:00:00 Wed", "2021-09-01 10:00:15 Wed"},
+		{"0 30 9 * * MON-FRI", "2021-09-03 10:00:00 Fri", "2021-09-06 09:30:00 Mon"},
+		{"0 0 1,15 * *", "2021-09-01 00:00:00 Wed", "2021-09-15 00:00:00 Wed"},
+		{"0 0 1 JAN ?", "2021-09-01 00:00:00 Wed", "2022-01-01 00:00:00 Sat"},
+		{"0 0 29 2 *", "2021-03-01 00:00:00 Mon", "2024-02-29 00:00:00 Thu"},
+		{"0 12 13 * 5", "2021-09-01 00:00:00 Wed", "2021-09-03 12:00:00 Fri"},
+		{"0 0 * * 7", "2021-09-01 00:00:00 Wed", "2021-09-05 00:00:00 Sun"},
+		{"5-10/5 0 0 * * *", "2021-09-01 00:00:05 Wed", "2021-09-01 00:00:10 Wed"},
+		{"@daily", "2021-09-01 10:00:00 Wed", "2021-09-02 00:00:00 Thu"},
+		{"@weekly", "2021-09-01 10:00:00 Wed", "2021-09-05 00:00:00 Sun"},
+		{"@every 90s", "2021-09-01 10:00:00 Wed", "2021-09-01 10:01:30 Wed"},
+	}
+
+	for _, c := range testcases {
+		s, err := chrono.ParseCron(c.expr)
+		assert.Nil(t, err, c.expr)
+		next := s.Next(parse(c.from))
+		assert.Equal(t, next.Format(layout), c.expect, c.expr)
+	}
+
+	s, err := chrono.ParseCron("0 0 30 2 *")
+	assert.Nil(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+
+	errors := []struct {
+		expr string
+		err  string
+	}{
+		{"* * * *", "expected 5 or 6 fields but 4"},
+		{"60 * * * *", "invalid value \"60\" in minute, expected 0-59"},
+		{"* * * 13 *", "invalid value \"13\" in month, expected 1-12"},
+		{"*/0 * * * *", "invalid step \"0\" in minute"},
+		{"* 5-1 * * *", "invalid range \"5-1\" in hour"},
+		{"@every 1ms", "interval less than 1s"},
+		{"@every abc", "time: invalid duration"},
+	}
+	for _, c := range errors {
+		_, err = chrono.ParseCron(c.expr)
+		assert.Error(t, err, c.err, c.expr)
+	}
+}
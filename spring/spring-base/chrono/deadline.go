@@ -0,0 +1,100 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithTimeout 等价于 WithDeadline(ctx, GetClock(ctx).Now().Add(d)) 。
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(ctx, GetClock(ctx).Now().Add(d))
+}
+
+// WithDeadline 创建一个在 deadline 时刻超时的子 context.Context 对象，和
+// context.WithDeadline 不同的是超时由 ctx 绑定的时钟驱动，使用 MockClock
+// 时只有推进时钟才会超时，从而保证测试和回放时取消行为是可复现的。
+func WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+
+	clock := GetClock(ctx)
+	if clock == RealClock {
+		return context.WithDeadline(ctx, deadline)
+	}
+
+	if cur, ok := ctx.Deadline(); ok && cur.Before(deadline) {
+		return context.WithCancel(ctx)
+	}
+
+	c := &deadlineCtx{
+		Context:  ctx,
+		deadline: deadline,
+		done:     make(chan struct{}),
+	}
+
+	d := deadline.Sub(clock.Now())
+	if d <= 0 {
+		c.cancel(context.DeadlineExceeded)
+		return c, func() { c.cancel(context.Canceled) }
+	}
+
+	timer := clock.NewTimer(d)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			c.cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+			c.cancel(ctx.Err())
+		case <-c.done:
+		}
+	}()
+	return c, func() { c.cancel(context.Canceled) }
+}
+
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+	mutex    sync.Mutex
+	err      error
+}
+
+func (c *deadlineCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *deadlineCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *deadlineCtx) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.err
+}
+
+func (c *deadlineCtx) cancel(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/chrono"
+	"github.com/go-spring/spring-base/knife"
+)
+
+func TestWithTimeout(t *testing.T) {
+
+	t.Run("real", func(t *testing.T) {
+		ctx, cancel := chrono.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		assert.Equal(t, ctx.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		ctx, _ := knife.New(context.Background())
+		c := chrono.NewMockClock(time.Unix(100, 0))
+		assert.Nil(t, chrono.SetClock(ctx, c))
+
+		child, cancel := chrono.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		deadline, ok := child.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, deadline, time.Unix(160, 0))
+
+		c.Advance(59 * time.Second)
+		select {
+		case <-child.Done():
+			t.Fatal("should not be done")
+		case <-time.After(10 * time.Millisecond):
+		}
+		assert.Nil(t, child.Err())
+
+		c.Advance(time.Second)
+		<-child.Done()
+		assert.Equal(t, child.Err(), context.DeadlineExceeded)
+		assert.Same(t, chrono.GetClock(child), c)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, _ := knife.New(context.Background())
+		assert.Nil(t, chrono.SetClock(ctx, chrono.NewMockClock(time.Unix(100, 0))))
+
+		child, cancel := chrono.WithDeadline(ctx, time.Unix(200, 0))
+		cancel()
+		<-child.Done()
+		assert.Equal(t, child.Err(), context.Canceled)
+
+		child, cancel = chrono.WithDeadline(ctx, time.Unix(50, 0))
+		defer cancel()
+		<-child.Done()
+		assert.Equal(t, child.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, _ := knife.New(parent)
+		assert.Nil(t, chrono.SetClock(ctx, chrono.NewMockClock(time.Unix(100, 0))))
+
+		child, cancel := chrono.WithTimeout(ctx, time.Hour)
+		defer cancel()
+		cancelParent()
+		<-child.Done()
+		assert.Equal(t, child.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ZoneProperty 设置默认时区的属性。
+const ZoneProperty = "spring.time.zone"
+
+// 预定义的布局名称。
+const (
+	LayoutDate        = "date"        // 2006-01-02
+	LayoutTime        = "time"        // 15:04:05
+	LayoutDateTime    = "datetime"    // 2006-01-02 15:04:05
+	LayoutDateTimeMs  = "datetime-ms" // 2006-01-02 15:04:05.000
+	LayoutRFC3339     = "rfc3339"
+	LayoutRFC3339Nano = "rfc3339-nano"
+)
+
+var location atomic.Value
+
+var layouts = struct {
+	sync.RWMutex
+	m map[string]string
+}{
+	m: map[string]string{
+		LayoutDate:        "2006-01-02",
+		LayoutTime:        "15:04:05",
+		LayoutDateTime:    "2006-01-02 15:04:05",
+		LayoutDateTimeMs:  "2006-01-02 15:04:05.000",
+		LayoutRFC3339:     time.RFC3339,
+		LayoutRFC3339Nano: time.RFC3339Nano,
+	},
+}
+
+// GetLocation 返回默认时区，未设置时返回 time.Local 。
+func GetLocation() *time.Location {
+	if loc, ok := location.Load().(*time.Location); ok {
+		return loc
+	}
+	return time.Local
+}
+
+// SetLocation 设置默认时区，loc 为 nil 时恢复为 time.Local 。
+func SetLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.Local
+	}
+	location.Store(loc)
+}
+
+// LoadLocation 按照名称加载并设置默认时区，例如 Asia/Shanghai 、UTC 。
+func LoadLocation(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	SetLocation(loc)
+	return nil
+}
+
+// RegisterLayout 注册命名的时间布局，已存在的名称会被覆盖。
+func RegisterLayout(name string, layout string) {
+	layouts.Lock()
+	defer layouts.Unlock()
+	layouts.m[name] = layout
+}
+
+// Layout 返回名称对应的时间布局，未注册的名称原样作为布局返回。
+func Layout(name string) string {
+	layouts.RLock()
+	defer layouts.RUnlock()
+	if layout, ok := layouts.m[name]; ok {
+		return layout
+	}
+	return name
+}
+
+// Format 使用命名的布局在默认时区下格式化 t ，t 为零值时使用 context.Context
+// 对象的当前时间。
+func Format(ctx context.Context, t time.Time, layoutName string) string {
+	if t.IsZero() {
+		t = Now(ctx)
+	}
+	return t.In(GetLocation()).Format(Layout(layoutName))
+}
+
+// Parse 使用命名的布局在默认时区下解析时间字符串。
+func Parse(s string, layoutName string) (time.Time, error) {
+	return time.ParseInLocation(Layout(layoutName), s, GetLocation())
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/chrono"
+	"github.com/go-spring/spring-base/knife"
+)
+
+func TestFormat(t *testing.T) {
+
+	assert.Nil(t, chrono.LoadLocation("Asia/Shanghai"))
+	defer chrono.SetLocation(nil)
+
+	tm := time.Date(2022, 3, 4, 16, 5, 6, 7000000, time.UTC)
+	assert.Equal(t, chrono.Format(nil, tm, chrono.LayoutDate), "2022-03-05")
+	assert.Equal(t, chrono.Format(nil, tm, chrono.LayoutDateTimeMs), "2022-03-05 00:05:06.007")
+	assert.Equal(t, chrono.Format(nil, tm, chrono.LayoutRFC3339), "2022-03-05T00:05:06+08:00")
+	assert.Equal(t, chrono.Format(nil, tm, "2006/01/02"), "2022/03/05")
+
+	chrono.RegisterLayout("compact", "20060102150405")
+	assert.Equal(t, chrono.Format(nil, tm, "compact"), "20220305000506")
+
+	ctx, _ := knife.New(context.Background())
+	assert.Nil(t, chrono.Freeze(ctx, tm))
+	assert.Equal(t, chrono.Format(ctx, time.Time{}, chrono.LayoutTime), "00:05:06")
+
+	p, err := chrono.Parse("2022-03-05 00:05:06", chrono.LayoutDateTime)
+	assert.Nil(t, err)
+	assert.True(t, p.Equal(tm.Truncate(time.Second)))
+
+	assert.Error(t, chrono.LoadLocation("Nowhere/City"), "unknown time zone Nowhere/City")
+	assert.Equal(t, chrono.GetLocation().String(), "Asia/Shanghai")
+}
@@ -46,9 +46,55 @@ func (t *baseTime) Get() time.Time {
 	return t.base.Add(time.Since(t.from))
 }
 
+type offsetTime struct {
+	base   TimeNow
+	offset time.Duration
+}
+
+func (t *offsetTime) Get() time.Time {
+	return t.base.Get().Add(t.offset)
+}
+
+type clockTime struct {
+	clock Clock
+}
+
+func (t *clockTime) Get() time.Time {
+	return t.clock.Now()
+}
+
+// current 返回 context.Context 对象当前使用的时间源。
+func current(ctx context.Context) TimeNow {
+	if v, ok := knife.Get(ctx, nowKey); ok {
+		if t, ok := v.(TimeNow); ok {
+			return t
+		}
+	}
+	return &clockTime{clock: GetClock(ctx)}
+}
+
+// replace 替换 context.Context 对象的时间源。
+func replace(ctx context.Context, t TimeNow) error {
+	knife.Delete(ctx, nowKey)
+	return knife.Set(ctx, nowKey, t)
+}
+
+// Freeze 将 context.Context 对象的时间冻结在 t，只影响该 context.Context 对象，
+// 可以重复调用以改变冻结的时间。
+func Freeze(ctx context.Context, t time.Time) error {
+	return replace(ctx, &fixedTime{fixed: t})
+}
+
+// Travel 将 context.Context 对象的时间偏移 offset，偏移叠加在当前的时间源之上，
+// 例如冻结之后再偏移得到的仍然是固定的时间。
+func Travel(ctx context.Context, offset time.Duration) error {
+	return replace(ctx, &offsetTime{base: current(ctx), offset: offset})
+}
+
 // ResetTime 恢复正常时间。
 func ResetTime(ctx context.Context) {
 	knife.Delete(ctx, nowKey)
+	knife.Delete(ctx, clockKey)
 }
 
 // SetFixedTime 设置固定时间。
@@ -61,22 +107,39 @@ func SetBaseTime(ctx context.Context, t time.Time) error {
 	return knife.Set(ctx, nowKey, &baseTime{base: t, from: time.Now()})
 }
 
-// Now 获取当前时间。
+// Now 获取当前时间，优先使用 SetFixedTime 等设置的时间，其次使用 SetClock 设置的时钟。
 func Now(ctx context.Context) time.Time {
 	if ctx == nil {
 		return time.Now()
 	}
 	v, ok := knife.Get(ctx, nowKey)
 	if !ok {
-		return time.Now()
+		return GetClock(ctx).Now()
 	}
 	t, ok := v.(TimeNow)
 	if !ok {
-		return time.Now()
+		return GetClock(ctx).Now()
 	}
 	return t.Get()
 }
 
+// Since 返回从 t 到 context.Context 对象当前时间经过的时间，没有设置时间源时
+// 和 time.Since 一样使用单调时钟，不受系统时间调整的影响。
+func Since(ctx context.Context, t time.Time) time.Duration {
+	return Now(ctx).Sub(t)
+}
+
+// Until 返回从 context.Context 对象当前时间到 t 的时间。
+func Until(ctx context.Context, t time.Time) time.Duration {
+	return t.Sub(Now(ctx))
+}
+
+// Elapsed 返回从纳秒时间戳 start 到 context.Context 对象当前时间经过的时间，
+// 适用于 fastdev.Action.Timestamp 这样的时间戳。
+func Elapsed(ctx context.Context, start int64) time.Duration {
+	return time.Duration(Now(ctx).UnixNano() - start)
+}
+
 // MilliSeconds 返回 time.Time 的毫秒时间。
 func MilliSeconds(t time.Time) int64 {
 	return t.UnixNano() / 1e6
@@ -49,3 +49,59 @@ func TestNow(t *testing.T) {
 
 	assert.True(t, chrono.Now(ctx).Sub(trueNow) < 3*time.Second)
 }
+
+func TestFreezeAndTravel(t *testing.T) {
+
+	err := chrono.Freeze(context.Background(), time.Unix(100, 0))
+	assert.Error(t, err, "knife uninitialized")
+
+	ctx, _ := knife.New(context.Background())
+	other, _ := knife.New(context.Background())
+
+	err = chrono.Freeze(ctx, time.Unix(100, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, chrono.Now(ctx), time.Unix(100, 0))
+	assert.True(t, time.Since(chrono.Now(other)) < time.Second)
+
+	err = chrono.Freeze(ctx, time.Unix(200, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, chrono.Now(ctx), time.Unix(200, 0))
+
+	err = chrono.Travel(ctx, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, chrono.Now(ctx), time.Unix(200, 0).Add(time.Hour))
+
+	chrono.ResetTime(ctx)
+	err = chrono.Travel(ctx, -24*time.Hour)
+	assert.Nil(t, err)
+	d := time.Since(chrono.Now(ctx)) - 24*time.Hour
+	assert.True(t, d >= 0 && d < time.Second)
+
+	c := chrono.NewMockClock(time.Unix(300, 0))
+	chrono.ResetTime(ctx)
+	assert.Nil(t, chrono.SetClock(ctx, c))
+	assert.Nil(t, chrono.Travel(ctx, time.Minute))
+	c.Advance(time.Second)
+	assert.Equal(t, chrono.Now(ctx), time.Unix(361, 0))
+}
+
+func TestSince(t *testing.T) {
+
+	start := time.Now()
+	d := chrono.Since(nil, start)
+	assert.True(t, d >= 0 && d < time.Second)
+
+	ctx, _ := knife.New(context.Background())
+	c := chrono.NewMockClock(time.Unix(100, 0))
+	assert.Nil(t, chrono.SetClock(ctx, c))
+
+	start = chrono.Now(ctx)
+	c.Advance(time.Minute)
+	assert.Equal(t, chrono.Since(ctx, start), time.Minute)
+	assert.Equal(t, chrono.Until(ctx, start.Add(time.Hour)), 59*time.Minute)
+	assert.Equal(t, chrono.Elapsed(ctx, start.UnixNano()), time.Minute)
+
+	assert.Nil(t, chrono.Freeze(ctx, time.Unix(200, 0)))
+	c.Advance(time.Minute)
+	assert.Equal(t, chrono.Since(ctx, time.Unix(100, 0)), 100*time.Second)
+}
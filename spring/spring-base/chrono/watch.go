@@ -0,0 +1,72 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WatchHook 秒表停止时的回调函数。
+type WatchHook func(ctx context.Context, name string, elapsed time.Duration)
+
+var watchHooks struct {
+	mutex sync.RWMutex
+	hooks []WatchHook
+}
+
+// OnWatchStop 注册秒表停止时的回调函数，例如录制模式下 recorder 包通过它将
+// 耗时记录到会话中。
+func OnWatchStop(hook WatchHook) {
+	watchHooks.mutex.Lock()
+	defer watchHooks.mutex.Unlock()
+	watchHooks.hooks = append(watchHooks.hooks, hook)
+}
+
+// Watch 秒表，使用 context.Context 对象的时间计时，因此可以被 MockClock 等控制。
+type Watch struct {
+	ctx   context.Context
+	name  string
+	start time.Time
+}
+
+// StartWatch 启动一个名为 name 的秒表。
+func StartWatch(ctx context.Context, name string) *Watch {
+	return &Watch{ctx: ctx, name: name, start: Now(ctx)}
+}
+
+// Name 返回秒表的名称。
+func (w *Watch) Name() string {
+	return w.name
+}
+
+// Elapsed 返回秒表启动以来经过的时间。
+func (w *Watch) Elapsed() time.Duration {
+	return Since(w.ctx, w.start)
+}
+
+// Stop 停止秒表，返回经过的时间并调用所有的 WatchHook 。
+func (w *Watch) Stop() time.Duration {
+	d := w.Elapsed()
+	watchHooks.mutex.RLock()
+	defer watchHooks.mutex.RUnlock()
+	for _, hook := range watchHooks.hooks {
+		hook(w.ctx, w.name, d)
+	}
+	return d
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chrono_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/chrono"
+	"github.com/go-spring/spring-base/knife"
+)
+
+func TestWatch(t *testing.T) {
+
+	ctx, _ := knife.New(context.Background())
+	c := chrono.NewMockClock(time.Unix(100, 0))
+	assert.Nil(t, chrono.SetClock(ctx, c))
+
+	var stopped []string
+	chrono.OnWatchStop(func(ctx context.Context, name string, elapsed time.Duration) {
+		stopped = append(stopped, name+"="+elapsed.String())
+	})
+
+	w := chrono.StartWatch(ctx, "redis")
+	assert.Equal(t, w.Name(), "redis")
+	c.Advance(15 * time.Millisecond)
+	assert.Equal(t, w.Elapsed(), 15*time.Millisecond)
+	c.Advance(5 * time.Millisecond)
+	assert.Equal(t, w.Stop(), 20*time.Millisecond)
+	assert.Equal(t, stopped, []string{"redis=20ms"})
+}
@@ -20,9 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
 
+	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-base/code"
 	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-base/util"
@@ -58,15 +58,31 @@ func (param *BindParam) BindTag(tag string) error {
 	return nil
 }
 
+// BindValue 将 param.Key 对应的属性值绑定到 v 上，v 可以是值类型，也可以是指向值
+// 类型的指针。绑定失败时返回的错误包含绑定对象的路径和属性名。
 func BindValue(p *Properties, v reflect.Value, param BindParam) error {
 
-	if !util.IsValueType(param.Type) {
+	if !util.IsValueType(param.Type) && !isValuePtr(param.Type) {
 		return util.Errorf(code.FileLine(), "%s 属性绑定的目标必须是值类型", param.Path)
 	}
 
 	log.Tracef("::<>:: %#v", param)
 
+	if strings.Contains(param.Key, "${") {
+		key, err := resolveString(p, param.Key)
+		if err != nil {
+			return err
+		}
+		param.Key = key
+	}
+
+	if r, ok := ToRefreshable(v); ok {
+		return r.Refresh(p, param)
+	}
+
 	switch v.Kind() {
+	case reflect.Ptr:
+		return bindPtr(p, v, param)
 	case reflect.Map:
 		return bindMap(p, v, param)
 	case reflect.Array:
@@ -84,14 +100,14 @@ func BindValue(p *Properties, v reflect.Value, param BindParam) error {
 
 	val, err := resolve(p, param)
 	if err != nil {
-		return util.Wrapf(err, code.FileLine(), "type %q bind error", param.Type)
+		return util.Wrapf(err, code.FileLine(), "bind %s error", param.Path)
 	}
 
 	if fn != nil {
 		fnValue := reflect.ValueOf(fn)
 		out := fnValue.Call([]reflect.Value{reflect.ValueOf(val)})
 		if !out[1].IsNil() {
-			return out[1].Interface().(error)
+			return bindError(param, out[1].Interface().(error))
 		}
 		v.Set(out[0])
 		return nil
@@ -100,38 +116,44 @@ func BindValue(p *Properties, v reflect.Value, param BindParam) error {
 	switch v.Kind() {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		var u uint64
-		if u, err = strconv.ParseUint(val, 0, 0); err == nil {
-			v.SetUint(u)
-			return nil
+		if u, err = cast.ToUint64E(val); err == nil {
+			if err = checkOverflow(v.OverflowUint(u), val, v); err == nil {
+				v.SetUint(u)
+				return nil
+			}
 		}
-		return util.Errorf(code.FileLine(), "%+v %w", param, err)
+		return bindError(param, err)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		var i int64
-		if i, err = strconv.ParseInt(val, 0, 0); err == nil {
-			v.SetInt(i)
-			return nil
+		if i, err = cast.ToInt64E(val); err == nil {
+			if err = checkOverflow(v.OverflowInt(i), val, v); err == nil {
+				v.SetInt(i)
+				return nil
+			}
 		}
-		return util.Errorf(code.FileLine(), "%+v %w", param, err)
+		return bindError(param, err)
 	case reflect.Float32, reflect.Float64:
 		var f float64
-		if f, err = strconv.ParseFloat(val, 64); err == nil {
-			v.SetFloat(f)
-			return nil
+		if f, err = cast.ToFloat64E(val); err == nil {
+			if err = checkOverflow(v.OverflowFloat(f), val, v); err == nil {
+				v.SetFloat(f)
+				return nil
+			}
 		}
-		return util.Errorf(code.FileLine(), "%+v %w", param, err)
+		return bindError(param, err)
 	case reflect.Bool:
 		var b bool
-		if b, err = strconv.ParseBool(val); err == nil {
+		if b, err = cast.ToBoolE(val); err == nil {
 			v.SetBool(b)
 			return nil
 		}
-		return util.Errorf(code.FileLine(), "%+v %w", param, err)
+		return bindError(param, err)
 	case reflect.String:
 		v.SetString(val)
 		return nil
 	}
 
-	return util.Errorf(code.FileLine(), "unsupported bind type %q", param.Type.String())
+	return util.Errorf(code.FileLine(), "%s unsupported bind type %q", param.Path, param.Type.String())
 }
 
 func getSliceValue(p *Properties, et reflect.Type, param BindParam) (*Properties, error) {
@@ -156,7 +178,7 @@ func getSliceValue(p *Properties, et reflect.Type, param BindParam) (*Properties
 
 	if wantDef {
 		if !param.hasDef {
-			return nil, ErrNotExist
+			return nil, fmt.Errorf("%s property %q %w", code.FileLine(), param.Key, ErrNotExist)
 		}
 		if param.def == "" {
 			return nil, nil
@@ -195,6 +217,9 @@ func bindArray(p *Properties, v reflect.Value, param BindParam) error {
 			Key:  fmt.Sprintf("%s[%d]", param.Key, i),
 			Path: fmt.Sprintf("%s[%d]", param.Path, i),
 		}
+		if !p.Has(subParam.Key) {
+			break
+		}
 		err = BindValue(p, v.Index(i), subParam)
 		if errors.Is(err, ErrNotExist) {
 			break
@@ -221,6 +246,10 @@ func bindSlice(p *Properties, v reflect.Value, param BindParam) error {
 			Key:  fmt.Sprintf("%s[%d]", param.Key, i),
 			Path: fmt.Sprintf("%s[%d]", param.Path, i),
 		}
+		// 元素的字段都有默认值时不会返回 ErrNotExist ，因此需要判断元素是否存在。
+		if !p.Has(subParam.Key) {
+			break
+		}
 		e := reflect.New(et).Elem()
 		err = BindValue(p, e, subParam)
 		if errors.Is(err, ErrNotExist) {
@@ -254,7 +283,7 @@ func bindMap(p *Properties, v reflect.Value, param BindParam) error {
 		for i, s := range keyPath {
 			vt, ok := t[s]
 			if !ok {
-				return util.Errorf(code.FileLine(), "property %q %w", param.Key, ErrNotExist)
+				return fmt.Errorf("%s property %q %w", code.FileLine(), param.Key, ErrNotExist)
 			}
 			if _, ok = vt.(struct{}); ok {
 				oldKey := strings.Join(keyPath[:i+1], ".")
@@ -262,7 +291,12 @@ func bindMap(p *Properties, v reflect.Value, param BindParam) error {
 			}
 			t = vt.(map[string]interface{})
 		}
-		for k := range t {
+		for k, vt := range t {
+			// 元素为简单类型时，嵌套的 key 展开成 a.b 的形式。
+			if sub, ok := vt.(map[string]interface{}); ok && util.IsPrimitiveValueType(param.Type.Elem()) {
+				leafKeys(k, sub, &keys)
+				continue
+			}
 			keys = append(keys, k)
 		}
 	}
@@ -278,7 +312,7 @@ func bindMap(p *Properties, v reflect.Value, param BindParam) error {
 		subParam := BindParam{
 			Type: et,
 			Key:  subKey,
-			Path: param.Path,
+			Path: fmt.Sprintf("%s[%s]", param.Path, key),
 		}
 		err := BindValue(p, e, subParam)
 		if err != nil {
@@ -353,18 +387,31 @@ func validTag(tag string) bool {
 	return strings.HasPrefix(tag, "${") && strings.HasSuffix(tag, "}")
 }
 
-// parseTag 解析 ${key:=def} 格式的字符串，然后返回 key 和 def 的值。
+// parseTag 解析 ${key:=def} 或者 ${key:def} 格式的字符串，然后返回 key 和 def
+// 的值，key 中可以嵌套属性引用，例如 ${${env}.db.host:localhost} 。
 func parseTag(tag string) (key string, def string, hasDef bool) {
-	ss := strings.SplitN(tag[2:len(tag)-1], ":=", 2)
-	if len(ss) > 1 {
-		hasDef = true
-		def = ss[1]
+	s := tag[2 : len(tag)-1]
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+		case s[i] == ':' && depth == 0:
+			return s[:i], strings.TrimPrefix(s[i+1:], "="), true
+		}
 	}
-	key = ss[0]
-	return
+	return s, "", false
 }
 
 func resolveString(p *Properties, s string) (string, error) {
+	return resolveRefs(p, s, nil)
+}
+
+// resolveRefs 解析字符串中的属性引用，refs 是正在解析的属性引用链，用于检测循环引用。
+func resolveRefs(p *Properties, s string, refs []string) (string, error) {
 
 	n := len(s)
 	count := 0
@@ -373,9 +420,9 @@ func resolveString(p *Properties, s string) (string, error) {
 
 	for i := 0; i < len(s); i++ {
 		switch s[i] {
-		case '$':
+		case '$', '#':
 			if i < n-1 {
-				if s[i+1] == '{' {
+				if s[i+1] == '{' && (s[i] == '$' || count > 0) {
 					if count == 0 {
 						start = i
 					}
@@ -408,12 +455,12 @@ func resolveString(p *Properties, s string) (string, error) {
 		return "", err
 	}
 
-	s1, err := resolve(p, param)
+	s1, err := resolveKey(p, param, refs)
 	if err != nil {
 		return "", err
 	}
 
-	s2, err := resolveString(p, s[end+1:])
+	s2, err := resolveRefs(p, s[end+1:], refs)
 	if err != nil {
 		return "", err
 	}
@@ -424,11 +471,82 @@ func resolveString(p *Properties, s string) (string, error) {
 // resolve 解析 ${key:=def} 字符串，返回 key 对应的属性值，如果没有找到则返回
 // def 值，如果 def 存在引用则递归解析直到获取最终的属性值。
 func resolve(p *Properties, param BindParam) (string, error) {
-	if val, ok := p.m[param.Key]; ok {
-		return resolveString(p, val)
+	return resolveKey(p, param, nil)
+}
+
+// resolveKey 解析 param 对应的属性值，key 中嵌套的属性引用首先被解析，属性值中
+// 的属性引用递归解析，出现循环引用时返回包含完整引用链的错误。
+func resolveKey(p *Properties, param BindParam, refs []string) (string, error) {
+	key, err := resolveRefs(p, param.Key, refs)
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref == key {
+			chain := strings.Join(append(refs, key), " -> ")
+			return "", util.Errorf(code.FileLine(), "property %q has circular reference: %s", key, chain)
+		}
+	}
+	if val, ok := p.lookup(key); ok {
+		return resolveRefs(p, val, append(refs[:len(refs):len(refs)], key))
 	}
 	if param.hasDef {
-		return resolveString(p, param.def)
+		return resolveDefault(p, param.def, refs)
+	}
+	if len(refs) > 0 {
+		chain := strings.Join(refs, " -> ")
+		return "", fmt.Errorf("%s property %q %w, referenced by %s", code.FileLine(), key, ErrNotExist, chain)
+	}
+	return "", fmt.Errorf("%s property %q %w", code.FileLine(), key, ErrNotExist)
+}
+
+// checkOverflow 属性值超出目标类型的范围时返回错误，而不是静默截断。
+func checkOverflow(overflow bool, val string, v reflect.Value) error {
+	if overflow {
+		return fmt.Errorf("%s overflows %s", val, v.Type())
+	}
+	return nil
+}
+
+// isValuePtr 返回 t 是否为指向值类型的指针。
+func isValuePtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && util.IsValueType(t.Elem())
+}
+
+// bindPtr 绑定指向值类型的指针。属性不存在并且没有默认值时保持原来的值，否则创建
+// 新的对象进行绑定，因此指针字段可以用来表示可选的配置。
+func bindPtr(p *Properties, v reflect.Value, param BindParam) error {
+
+	if param.Key != "" && !param.hasDef && !p.Has(param.Key) {
+		return nil
+	}
+
+	e := reflect.New(param.Type.Elem())
+	if !v.IsNil() {
+		e.Elem().Set(v.Elem())
+	}
+
+	subParam := param
+	subParam.Type = param.Type.Elem()
+	if err := BindValue(p, e.Elem(), subParam); err != nil {
+		return err
+	}
+	v.Set(e)
+	return nil
+}
+
+// leafKeys 收集属性树中 t 节点下所有叶子结点的路径，路径以 prefix 开头。
+func leafKeys(prefix string, t map[string]interface{}, keys *[]string) {
+	for k, v := range t {
+		if sub, ok := v.(map[string]interface{}); ok {
+			leafKeys(prefix+"."+k, sub, keys)
+			continue
+		}
+		*keys = append(*keys, prefix+"."+k)
 	}
-	return "", util.Errorf(code.FileLine(), "property %q %w", param.Key, ErrNotExist)
+}
+
+// bindError 返回包含绑定对象的路径和属性名的错误。
+func bindError(param BindParam, err error) error {
+	return util.Wrapf(err, code.FileLine(), "bind %s error: property %q", param.Path, param.Key)
 }
@@ -35,8 +35,10 @@ import (
 // 构存储数据，属性的 key 可以是 a.b.c 或者 a[0].b 两种形式，a.b.c 表示从 map
 // 结构中获取属性值，a[0].b 表示从切片结构中获取属性值，并且 key 是大小写敏感的。
 type Properties struct {
-	m map[string]string      // 一维，存储 key 和 value。
-	t map[string]interface{} // 树形，存储 key 的节点路由。
+	m   map[string]string      // 一维，存储 key 和 value。
+	t   map[string]interface{} // 树形，存储 key 的节点路由。
+	env *RelaxedEnv            // 属性不存在时按照宽松规则查找的环境变量。
+	rnd *RandomValues          // 提供 random.* 形式的随机属性。
 }
 
 // New 返回一个空的属性列表。
@@ -117,6 +119,30 @@ func (p *Properties) Bytes(b []byte, ext string) error {
 	return nil
 }
 
+// Copy 返回属性列表的副本，包括设置的宽松匹配的环境变量和随机属性。
+func (p *Properties) Copy() *Properties {
+	r := New()
+	for k, v := range p.m {
+		r.m[k] = v
+	}
+	r.t = copyTree(p.t)
+	r.env = p.env
+	r.rnd = p.rnd
+	return r
+}
+
+func copyTree(t map[string]interface{}) map[string]interface{} {
+	r := make(map[string]interface{}, len(t))
+	for k, v := range t {
+		if m, ok := v.(map[string]interface{}); ok {
+			r[k] = copyTree(m)
+		} else {
+			r[k] = v
+		}
+	}
+	return r
+}
+
 // Keys 返回所有属性 key 的列表。
 func (p *Properties) Keys() []string {
 	keys := make([]string, 0, len(p.m))
@@ -184,8 +210,44 @@ func (p *Properties) checkKey(key string, collection bool) error {
 	return nil
 }
 
+// SetRelaxedEnv 设置按照宽松规则查找的环境变量，属性列表中不存在的属性会继续在环
+// 境变量中查找，例如 SPRING_DATASOURCE_MAX_OPEN 可以提供 spring.datasource.max-open
+// 属性。这些属性不会出现在 Keys 方法的返回值中。
+func (p *Properties) SetRelaxedEnv(env *RelaxedEnv) {
+	p.env = env
+}
+
+// SetRandomValues 设置提供 random.* 形式随机属性的对象，属性列表中存在的属性优先。
+// 多个属性列表共享同一个 RandomValues 对象时同一个 key 得到相同的随机值。
+func (p *Properties) SetRandomValues(r *RandomValues) {
+	p.rnd = r
+}
+
+// lookup 返回 key 对应的属性值，属性不存在时依次在宽松匹配的环境变量和随机属性中
+// 查找。
+func (p *Properties) lookup(key string) (string, bool) {
+	if val, ok := p.m[key]; ok {
+		return val, true
+	}
+	if val, ok := p.env.Lookup(key); ok {
+		return val, true
+	}
+	return p.rnd.Lookup(key)
+}
+
 // Has 返回属性 key 是否存在。
 func (p *Properties) Has(key string) bool {
+	if p.has(key) {
+		return true
+	}
+	_, ok := p.env.Lookup(key)
+	if !ok {
+		_, ok = p.rnd.Lookup(key)
+	}
+	return ok
+}
+
+func (p *Properties) has(key string) bool {
 
 	var (
 		ok bool
@@ -236,7 +298,7 @@ func Def(v string) GetOption {
 // Get 方法的返回值是否为 nil 来判断 key 对应的属性值是否存在。
 func (p *Properties) Get(key string, opts ...GetOption) string {
 
-	if val, ok := p.m[key]; ok {
+	if val, ok := p.lookup(key); ok {
 		return val
 	}
 
@@ -251,7 +313,8 @@ func (p *Properties) Get(key string, opts ...GetOption) string {
 // 值。Set 方法除了支持 string 类型的属性值，还支持 int、uint、bool 等其他基础
 // 数据类型的属性值。特殊情况下，Set 方法也支持 slice 、map 与基础数据类型组合构
 // 成的属性值，其处理方式是将组合结构层层展开，可以将组合结构看成一棵树，那么叶子结
-// 点的路径就是属性的 key，叶子结点的值就是属性的值。
+// 点的路径就是属性的 key，叶子结点的值就是属性的值。ENC(...) 形式的属性值在设置
+// 时使用 SetDecryptor 设置的解密器进行解密。
 func (p *Properties) Set(key string, val interface{}) error {
 	switch v := reflect.ValueOf(val); v.Kind() {
 	case reflect.Map:
@@ -276,8 +339,11 @@ func (p *Properties) Set(key string, val interface{}) error {
 			return p.checkKey(key, true)
 		}
 		if util.IsPrimitiveValueType(v.Type().Elem()) {
-			ss := cast.ToStringSlice(val)
-			err := p.Set(key, strings.Join(ss, ","))
+			ss, err := cast.ToSliceOf[string](val)
+			if err != nil {
+				return err
+			}
+			err = p.Set(key, strings.Join(ss, ","))
 			if err != nil {
 				return err
 			}
@@ -295,7 +361,11 @@ func (p *Properties) Set(key string, val interface{}) error {
 			}
 		}
 	default:
-		p.m[key] = cast.ToString(val)
+		s, err := decrypt(cast.ToString(val))
+		if err != nil {
+			return fmt.Errorf("decrypt property %q error: %w", key, err)
+		}
+		p.m[key] = s
 		return p.checkKey(key, false)
 	}
 	return nil
@@ -329,8 +399,10 @@ func Tag(tag string) BindOption {
 // Bind 将 key 对应的属性值绑定到某个数据类型的实例上。i 必须是一个指针，只有这
 // 样才能将修改传递出去。Bind 方法使用 tag 字符串对数据实例进行属性绑定，其语法
 // 为 value:"${a:=b}"，其中 value 表示属性绑定，${} 表示属性引用，a 表示属性
-// 的名称，:=b 表示为属性设置默认值。而且 tag 字符串还支持在默认值中进行嵌套引用
-// ，即 ${a:=${b}}。当然，还有两点需要特别说明：
+// 的名称，:=b 表示为属性设置默认值，也可以简写为 ${a:b}。而且 tag 字符串还支持在
+// 默认值中进行嵌套引用，即 ${a:=${b}}，以及在属性名中进行嵌套引用，即
+// ${${env}.db.host}，属性值之间的循环引用会返回包含完整引用链的错误。当然，还有两
+// 点需要特别说明：
 // 一是对 array、slice、map、struct 这些复合类型不能设置非空默认值，因为如果
 // 默认值太长会影响阅读体验，而且解析起来也并不容易；
 // 二是可以省略属性名而只有默认值，即 ${:=b}，原因是某些情况下属性名可能没想好或
@@ -365,5 +437,8 @@ func (p *Properties) Bind(i interface{}, opts ...BindOption) error {
 	if err := param.BindTag(arg.tag); err != nil {
 		return err
 	}
-	return BindValue(p, v, param)
+	if err := BindValue(p, v, param); err != nil {
+		return err
+	}
+	return Validate(v, param)
 }
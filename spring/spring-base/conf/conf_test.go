@@ -17,9 +17,14 @@
 package conf_test
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"runtime"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -340,6 +345,66 @@ func TestProperties_ReadYaml(t *testing.T) {
 	})
 }
 
+func TestProperties_ReadHCL(t *testing.T) {
+
+	str := `
+# 应用配置
+name = "go-spring"
+port = 8080
+ratio = 0.5
+debug = true
+tags = ["a", "b", // 注释
+  "c"]
+
+/* 数据库配置 */
+database "master" {
+  host = "127.0.0.1"
+  options = { timeout = 3, retry = false }
+}
+
+database "slave" {
+  host = "127.0.0.2"
+}
+`
+	p, err := conf.Bytes([]byte(str), ".hcl")
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("name"), "go-spring")
+	assert.Equal(t, p.Get("port"), "8080")
+	assert.Equal(t, p.Get("ratio"), "0.5")
+	assert.Equal(t, p.Get("debug"), "true")
+	assert.Equal(t, p.Get("tags[0]"), "a")
+	assert.Equal(t, p.Get("tags[2]"), "c")
+	assert.Equal(t, p.Get("database.master.host"), "127.0.0.1")
+	assert.Equal(t, p.Get("database.master.options.timeout"), "3")
+	assert.Equal(t, p.Get("database.master.options.retry"), "false")
+	assert.Equal(t, p.Get("database.slave.host"), "127.0.0.2")
+
+	_, err = conf.Bytes([]byte("name = \"go-spring"), ".hcl")
+	assert.Error(t, err, "hcl: line 1: unterminated string")
+}
+
+func TestProperties_ReadDotenv(t *testing.T) {
+
+	str := `
+# 数据库配置
+DB_HOST=127.0.0.1
+export DB_PORT=3306 # 端口
+DB_PASSWORD='p@ss#word'
+GREETING="hello\nworld"
+spring.application.name=dotenv
+`
+	p, err := conf.Bytes([]byte(str), ".env")
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("db.host"), "127.0.0.1")
+	assert.Equal(t, p.Get("db.port"), "3306")
+	assert.Equal(t, p.Get("db.password"), "p@ss#word")
+	assert.Equal(t, p.Get("greeting"), "hello\nworld")
+	assert.Equal(t, p.Get("spring.application.name"), "dotenv")
+
+	_, err = conf.Bytes([]byte("DB_HOST"), ".env")
+	assert.Error(t, err, "dotenv: line 1: invalid line \"DB_HOST\"")
+}
+
 func TestProperties_Get(t *testing.T) {
 
 	t.Run("base", func(t *testing.T) {
@@ -408,6 +473,22 @@ func TestProperties_Get(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, f2, float32(3))
 
+		err = p.Set("Overflow", 300)
+		assert.Nil(t, err)
+
+		var i8 int8
+		err = p.Bind(&i8, conf.Key("Overflow"))
+		assert.Error(t, err, "300 overflows int8")
+
+		var u8 uint8
+		err = p.Bind(&u8, conf.Key("Overflow"))
+		assert.Error(t, err, "300 overflows uint8")
+
+		var i16 int16
+		err = p.Bind(&i16, conf.Key("Overflow"))
+		assert.Nil(t, err)
+		assert.Equal(t, i16, int16(300))
+
 		v = p.Get("Bool")
 		b := cast.ToBool(v)
 		assert.Equal(t, b, true)
@@ -539,6 +620,57 @@ func TestProperties_Ref(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, s.KeyIsEmpty, "kie")
 	})
+
+	t.Run("colon default", func(t *testing.T) {
+		p := conf.New()
+		var s struct {
+			Host string `value:"${db.host:localhost}"`
+			URL  string `value:"${db.url:http://${db.host:127.0.0.1}:3306}"`
+		}
+		err := p.Bind(&s)
+		assert.Nil(t, err)
+		assert.Equal(t, s.Host, "localhost")
+		assert.Equal(t, s.URL, "http://127.0.0.1:3306")
+	})
+
+	t.Run("nested key", func(t *testing.T) {
+		p := conf.New()
+		assert.Nil(t, p.Set("env", "test"))
+		assert.Nil(t, p.Set("test.db.host", "10.0.0.1"))
+		assert.Nil(t, p.Set("test.db.port", 3306))
+		var s struct {
+			Host string `value:"${${env}.db.host:localhost}"`
+			DB   struct {
+				Host string `value:"${host}"`
+				Port int    `value:"${port}"`
+			} `value:"${${env}.db}"`
+		}
+		err := p.Bind(&s)
+		assert.Nil(t, err)
+		assert.Equal(t, s.Host, "10.0.0.1")
+		assert.Equal(t, s.DB.Host, "10.0.0.1")
+		assert.Equal(t, s.DB.Port, 3306)
+		str, err := p.Resolve("${${env}.db.host}:${${env}.db.port}")
+		assert.Nil(t, err)
+		assert.Equal(t, str, "10.0.0.1:3306")
+	})
+
+	t.Run("circular reference", func(t *testing.T) {
+		p := conf.New()
+		assert.Nil(t, p.Set("a", "${b}"))
+		assert.Nil(t, p.Set("b", "x${c}"))
+		assert.Nil(t, p.Set("c", "${a}"))
+		_, err := p.Resolve("${a}")
+		assert.Error(t, err, `property "a" has circular reference: a -> b -> c -> a`)
+	})
+
+	t.Run("unresolved reference", func(t *testing.T) {
+		p := conf.New()
+		assert.Nil(t, p.Set("a", "${b}"))
+		assert.Nil(t, p.Set("b", "${c}"))
+		_, err := p.Resolve("${a}")
+		assert.Error(t, err, `property "c" not exist, referenced by a -> b`)
+	})
 }
 
 func TestBindMap(t *testing.T) {
@@ -577,7 +709,7 @@ func TestBindMap(t *testing.T) {
 		var r map[string]S
 		p := conf.Map(m)
 		err := p.Bind(&r)
-		assert.Error(t, err, "map\\[string]conf_test.S.M 属性绑定的目标必须是值类型")
+		assert.Error(t, err, "map\\[string]conf_test.S\\[[ab]\\].M 属性绑定的目标必须是值类型")
 	})
 
 	t.Run("", func(t *testing.T) {
@@ -587,7 +719,7 @@ func TestBindMap(t *testing.T) {
 		var r map[string]S
 		p := conf.Map(m)
 		err := p.Bind(&r)
-		assert.Error(t, err, "map\\[string]conf_test.S.M 属性绑定的目标必须是值类型")
+		assert.Error(t, err, "map\\[string]conf_test.S\\[[ab]\\].M 属性绑定的目标必须是值类型")
 	})
 
 	t.Run("", func(t *testing.T) {
@@ -597,7 +729,7 @@ func TestBindMap(t *testing.T) {
 		var r map[string]S
 		p := conf.Map(m)
 		err := p.Bind(&r)
-		assert.Error(t, err, "map\\[string]conf_test.S.M 属性绑定的目标必须是值类型")
+		assert.Error(t, err, "map\\[string]conf_test.S\\[[ab]\\].M 属性绑定的目标必须是值类型")
 	})
 
 	t.Run("", func(t *testing.T) {
@@ -613,10 +745,11 @@ func TestBindMap(t *testing.T) {
 	})
 
 	t.Run("", func(t *testing.T) {
-		p := conf.Map(map[string]interface{}{"a.b1": "ab1"})
+		p := conf.Map(map[string]interface{}{"a.b1": "ab1", "a.b2.c": "abc", "d": "d"})
 		var r map[string]string
 		err := p.Bind(&r)
-		assert.Error(t, err, ".*/bind.go:87 type \"string\" bind error\n.*/bind.go:433 property \"a\" not exist")
+		assert.Nil(t, err)
+		assert.Equal(t, r, map[string]string{"a.b1": "ab1", "a.b2.c": "abc", "d": "d"})
 	})
 
 	t.Run("", func(t *testing.T) {
@@ -721,4 +854,387 @@ func TestProperties_Set(t *testing.T) {
 	assert.Equal(t, p.Get("a"), "a,aa,aaa")
 	assert.Equal(t, p.Get("b"), "1,11,111")
 	assert.Equal(t, p.Get("c"), "1,1.1,1.11")
+
+	err = p.Set("d", []time.Duration{time.Second, time.Minute})
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("d"), "1s,1m0s")
+	var d []time.Duration
+	assert.Nil(t, p.Bind(&d, conf.Key("d")))
+	assert.Equal(t, d, []time.Duration{time.Second, time.Minute})
+}
+
+func TestProperties_BindValidate(t *testing.T) {
+
+	type ServerConfig struct {
+		Host string `value:"${host:=}" validate:"required"`
+		Port int    `value:"${port:=0}" validate:"min=1,max=65535"`
+		Mode string `value:"${mode:=dev}" validate:"oneof=dev prod"`
+	}
+
+	var c ServerConfig
+	p := conf.Map(map[string]interface{}{"host": "localhost", "port": 8080})
+	err := p.Bind(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Port, 8080)
+
+	p = conf.Map(map[string]interface{}{"port": 80000, "mode": "test"})
+	err = p.Bind(&c)
+	assert.Error(t, err, "3 errors occurred:\n\t\\* Host is required \\(property \"host\"\\)\n\t\\* Port must be at most 65535 \\(property \"port\"\\)\n\t\\* Mode must be one of \\[dev prod\\] \\(property \"mode\"\\)")
+
+	type DBServer struct {
+		Host string `value:"${host}"`
+		Port int    `value:"${port}" validate:"max=65535"`
+	}
+
+	type DBConfig struct {
+		Servers []DBServer `value:"${servers}"`
+	}
+
+	var db DBConfig
+	p = conf.Map(map[string]interface{}{
+		"db.servers": []map[string]interface{}{
+			{"host": "a", "port": 3306},
+			{"host": "b", "port": 80000},
+		},
+	})
+	err = p.Bind(&db, conf.Key("db"))
+	assert.Error(t, err, "^Servers\\[1\\].Port must be at most 65535 \\(property \"db.servers\\[1\\].port\"\\)$")
+
+	var e *conf.ValidateError
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.Key, "db.servers[1].port")
+	e.Origin = "application.yaml"
+	assert.Equal(t, e.Error(), "Servers[1].Port must be at most 65535 (property \"db.servers[1].port\" from application.yaml)")
+}
+
+type reverseDecryptor struct{}
+
+func (d reverseDecryptor) Decrypt(s string) (string, error) {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b), nil
+}
+
+func TestProperties_Decrypt(t *testing.T) {
+
+	key := []byte("0123456789abcdef")
+	s, err := conf.AESEncrypt(key, "p@ssw0rd")
+	assert.Nil(t, err)
+	assert.Matches(t, s, `^ENC\(.+\)$`)
+
+	t.Run("no decryptor", func(t *testing.T) {
+		os.Unsetenv(conf.EncryptKeyEnv)
+		_, err = conf.Bytes([]byte("db.password="+s), ".properties")
+		assert.Error(t, err, "decrypt property \"db.password\" error: no decryptor")
+	})
+
+	t.Run("env key", func(t *testing.T) {
+		os.Setenv(conf.EncryptKeyEnv, base64.StdEncoding.EncodeToString(key))
+		defer os.Unsetenv(conf.EncryptKeyEnv)
+		p, err := conf.Bytes([]byte("db.user=root\ndb.password="+s), ".properties")
+		assert.Nil(t, err)
+		assert.Equal(t, p.Get("db.user"), "root")
+		assert.Equal(t, p.Get("db.password"), "p@ssw0rd")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		d, err := conf.NewAESDecryptor([]byte("fedcba9876543210"))
+		assert.Nil(t, err)
+		conf.SetDecryptor(d)
+		defer conf.SetDecryptor(nil)
+		_, err = conf.Bytes([]byte("db.password="+s), ".properties")
+		assert.Error(t, err, "message authentication failed")
+	})
+
+	t.Run("custom decryptor", func(t *testing.T) {
+		conf.SetDecryptor(reverseDecryptor{})
+		defer conf.SetDecryptor(nil)
+		p := conf.New()
+		assert.Nil(t, p.Set("db.password", "ENC(drowssap)"))
+		assert.Equal(t, p.Get("db.password"), "password")
+	})
+}
+
+func TestProperties_RelaxedEnv(t *testing.T) {
+
+	assert.Equal(t, conf.EnvName("spring.datasource.max-open"), "SPRING_DATASOURCE_MAX_OPEN")
+	assert.Equal(t, conf.EnvName("a.b[0].c"), "A_B_0_C")
+
+	env := conf.NewRelaxedEnv([]string{
+		"SPRING_DATASOURCE_MAX_OPEN=10",
+		"server_port=9090",
+		"LOGGING_MAXSIZE=1MB",
+		"A_B_0_C=x",
+		"PATH=/usr/bin",
+	})
+
+	p := conf.Map(map[string]interface{}{
+		"spring.datasource.max-open": "1",
+		"spring.datasource.url":      "mysql://",
+	})
+	p.SetRelaxedEnv(env)
+
+	// 属性列表中已有的属性优先，覆盖已有属性由调用方决定。
+	assert.Equal(t, p.Get("spring.datasource.max-open"), "1")
+	assert.Equal(t, p.Get("spring.datasource.url"), "mysql://")
+	assert.Equal(t, p.Get("server.port"), "9090")
+	assert.Equal(t, p.Get("logging.max-size"), "1MB")
+	assert.Equal(t, p.Get("a.b[0].c"), "x")
+	assert.True(t, p.Has("server.port"))
+	assert.False(t, p.Has("path"))
+	assert.Equal(t, p.Get("path"), "")
+
+	var s struct {
+		Port int    `value:"${server.port:=8080}"`
+		Size string `value:"${logging.max-size}"`
+	}
+	assert.Nil(t, p.Bind(&s))
+	assert.Equal(t, s.Port, 9090)
+	assert.Equal(t, s.Size, "1MB")
+}
+
+func TestProperties_BindNested(t *testing.T) {
+
+	type Limit struct {
+		QPS   int `value:"${qps:=100}"`
+		Burst int `value:"${burst:=10}"`
+	}
+
+	type Server struct {
+		Host string `value:"${host:=localhost}"`
+		Port int    `value:"${port:=80}"`
+	}
+
+	type Base struct {
+		Name string `value:"${name}"`
+	}
+
+	type Config struct {
+		Base
+		Servers  []Server         `value:"${servers}"`
+		Limits   map[string]Limit `value:"${limits}"`
+		Timeout  *time.Duration   `value:"${timeout:=5s}"`
+		Retry    *int             `value:"${retry}"`
+		Fallback *Server          `value:"${fallback}"`
+		Backup   *Server          `value:"${backup}"`
+	}
+
+	p := conf.Map(map[string]interface{}{
+		"app.name":               "demo",
+		"app.servers[0].host":    "a",
+		"app.servers[1].port":    8080,
+		"app.limits.api.qps":     10,
+		"app.limits.admin.burst": 1,
+		"app.fallback.host":      "b",
+	})
+
+	var c Config
+	err := p.Bind(&c, conf.Key("app"))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Name, "demo")
+	assert.Equal(t, c.Servers, []Server{{"a", 80}, {"localhost", 8080}})
+	assert.Equal(t, c.Limits, map[string]Limit{"api": {10, 10}, "admin": {100, 1}})
+	assert.Equal(t, *c.Timeout, 5*time.Second)
+	assert.Nil(t, c.Retry)
+	assert.Equal(t, *c.Fallback, Server{"b", 80})
+	assert.Nil(t, c.Backup)
+
+	p = conf.Map(map[string]interface{}{
+		"app.name":            "demo",
+		"app.servers[0].port": 80,
+		"app.servers[1].port": "abc",
+	})
+	err = p.Bind(&c, conf.Key("app"))
+	assert.Error(t, err, `bind Config.Servers\[1\].Port error: property "app.servers\[1\].port"\nstrconv.ParseInt: parsing "abc": invalid syntax`)
+
+	p = conf.Map(map[string]interface{}{
+		"app.name":            "demo",
+		"app.servers[0].port": 80,
+		"app.limits.api.qps":  "x",
+	})
+	err = p.Bind(&c, conf.Key("app"))
+	assert.Error(t, err, `bind Config.Limits\[api\].QPS error: property "app.limits.api.qps"`)
+
+	p = conf.Map(map[string]interface{}{"app.name": "demo"})
+	err = p.Bind(&c, conf.Key("app"))
+	assert.Error(t, err, `property "app.servers" not exist`)
+}
+
+func TestProperties_RandomValues(t *testing.T) {
+
+	p := conf.New()
+	assert.False(t, p.Has("random.int"))
+
+	rnd := conf.NewRandomValues()
+	p.SetRandomValues(rnd)
+	assert.Nil(t, p.Set("random.fixed", "1"))
+
+	assert.Matches(t, p.Get("random.value"), "^[0-9a-f]{32}$")
+	assert.Matches(t, p.Get("random.uuid"), "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$")
+	assert.Matches(t, p.Get("random.long"), "^[0-9]+$")
+	assert.Equal(t, p.Get("random.fixed"), "1")
+	assert.False(t, p.Has("random.float"))
+	assert.False(t, p.Has("random.int(5,5)"))
+
+	// 同一个 key 只生成一次随机值，共享 RandomValues 的属性列表得到相同的值。
+	id := p.Get("random.uuid")
+	assert.Equal(t, p.Get("random.uuid"), id)
+	p2 := conf.New()
+	p2.SetRandomValues(rnd)
+	assert.Equal(t, p2.Get("random.uuid"), id)
+
+	var s struct {
+		Port  int   `value:"${random.int(1000,2000)}"`
+		Shard int64 `value:"${random.long(8)}"`
+		Seed  int   `value:"${random.int}"`
+	}
+	assert.Nil(t, p.Bind(&s))
+	assert.True(t, s.Port >= 1000 && s.Port < 2000)
+	assert.True(t, s.Shard >= 0 && s.Shard < 8)
+	assert.True(t, s.Seed >= 0)
+	assert.Equal(t, strconv.Itoa(s.Port), p.Get("random.int(1000,2000)"))
+}
+
+func TestProperties_DurationAndDataSize(t *testing.T) {
+
+	for s, expect := range map[string]conf.DataSize{
+		"0":       0,
+		"512":     512,
+		"512B":    512,
+		"16KB":    16 * conf.KiloByte,
+		"16mb":    16 * conf.MegaByte,
+		" 2 GB ":  2 * conf.GigaByte,
+		"1TB":     conf.TeraByte,
+		"1048576": conf.MegaByte,
+	} {
+		d, err := conf.ParseDataSize(s)
+		assert.Nil(t, err)
+		assert.Equal(t, d, expect)
+	}
+	assert.Equal(t, (16 * conf.MegaByte).String(), "16MB")
+	assert.Equal(t, conf.DataSize(1536).String(), "1536B")
+	assert.Equal(t, conf.DataSize(0).String(), "0B")
+
+	_, err := conf.ParseDataSize("16XB")
+	assert.Error(t, err, "invalid data size \"16XB\"")
+	_, err = conf.ParseDataSize("-1KB")
+	assert.Error(t, err, "invalid data size \"-1KB\"")
+	_, err = conf.ParseDataSize("9999999TB")
+	assert.Error(t, err, "data size \"9999999TB\" overflows int64")
+
+	p := conf.Map(map[string]interface{}{
+		"server.timeout": "30s",
+		"server.buffer":  "16MB",
+	})
+
+	var s struct {
+		Timeout time.Duration `value:"${server.timeout}"`
+		Buffer  conf.DataSize `value:"${server.buffer}"`
+		Max     conf.DataSize `value:"${server.max:=1GB}"`
+	}
+	assert.Nil(t, p.Bind(&s))
+	assert.Equal(t, s.Timeout, 30*time.Second)
+	assert.Equal(t, s.Buffer.Bytes(), int64(16*1024*1024))
+	assert.Equal(t, s.Max, conf.GigaByte)
+
+	assert.Nil(t, p.Set("server.timeout", "30 seconds"))
+	err = p.Bind(&s)
+	assert.Error(t, err, "bind .*Timeout error: property \"server.timeout\"\ninvalid duration \"30 seconds\", want a value like 300ms, 30s or 1h30m")
+
+	assert.Nil(t, p.Set("server.timeout", "30s"))
+	assert.Nil(t, p.Set("server.buffer", "16M"))
+	err = p.Bind(&s)
+	assert.Error(t, err, "bind .*Buffer error: property \"server.buffer\"\ninvalid data size \"16M\"")
+}
+
+func TestProperties_Copy(t *testing.T) {
+
+	p := conf.New()
+	assert.Nil(t, p.Set("a.b", "1"))
+	assert.Nil(t, p.Set("a.c", []string{}))
+	p.SetRandomValues(conf.NewRandomValues())
+
+	r := p.Copy()
+	assert.Nil(t, r.Set("a.d", "2"))
+	assert.Equal(t, r.Get("a.b"), "1")
+	assert.True(t, r.Has("a.c"))
+	assert.Equal(t, r.Get("random.uuid"), p.Get("random.uuid"))
+	assert.False(t, p.Has("a.d"))
+
+	assert.Error(t, r.Set("a.b.c", "3"), "property \"a.b\" has a value but want another sub key \"a.b.c\"")
+}
+
+func TestEval(t *testing.T) {
+
+	for expr, want := range map[string]string{
+		"1+2*3":                          "7",
+		"(1+2)*3":                        "9",
+		"7/2":                            "3",
+		"7/2.0":                          "3.5",
+		"7%3":                            "1",
+		"-2*-3":                          "6",
+		"'10'*2":                         "20",
+		"max(1, 5, 3)":                   "5",
+		"min(4, 2.5)":                    "2.5",
+		"cpu":                            strconv.Itoa(runtime.NumCPU()),
+		"env('EXPR_X_NOT_EXIST', 'dev')": "dev",
+	} {
+		v, err := conf.Eval(expr)
+		assert.Nil(t, err)
+		assert.Equal(t, v, want)
+	}
+
+	for expr, err := range map[string]string{
+		"1/0":                     "division by zero",
+		"(1+2":                    "missing '\\)'",
+		"1+":                      "unexpected end of expression",
+		"foo":                     "unknown identifier \"foo\"",
+		"bar(1)":                  "unknown function \"bar\"",
+		"'a'*2":                   "\"a\" is not a number",
+		"1.5%2":                   "operator % requires integers",
+		"1 2":                     "unexpected \"2\" at 2",
+		"env('EXPR_X_NOT_EXIST')": "environment variable \"EXPR_X_NOT_EXIST\" not exist",
+	} {
+		_, e := conf.Eval(expr)
+		assert.Error(t, e, err)
+	}
+}
+
+func TestProperties_ExprDefault(t *testing.T) {
+
+	os.Setenv("EXPR_POOL_FACTOR", "3")
+	defer os.Unsetenv("EXPR_POOL_FACTOR")
+
+	p := conf.New()
+	assert.Nil(t, p.Set("pool.min", 4))
+
+	var s struct {
+		Size    int     `value:"${pool.size:=#{cpu*2}}"`
+		Max     int     `value:"${pool.max:=#{max(${pool.min}, cpu) * env('EXPR_POOL_FACTOR')}}"`
+		Ratio   float64 `value:"${pool.ratio:=#{1/4.0}}"`
+		Min     int     `value:"${pool.min:=#{cpu}}"`
+		Literal string  `value:"${pool.name:=#cpu}"`
+	}
+	assert.Nil(t, p.Bind(&s))
+	cpu := runtime.NumCPU()
+	assert.Equal(t, s.Size, cpu*2)
+	want := cpu
+	if want < 4 {
+		want = 4
+	}
+	assert.Equal(t, s.Max, want*3)
+	assert.Equal(t, s.Ratio, 0.25)
+	assert.Equal(t, s.Min, 4)
+	assert.Equal(t, s.Literal, "#cpu")
+
+	v, err := p.Resolve("size=${pool.size:=#{(cpu+1)*0}}")
+	assert.Nil(t, err)
+	assert.Equal(t, v, "size=0")
+
+	var e struct {
+		Size int `value:"${pool.size:=#{cpu/0}}"`
+	}
+	assert.Error(t, p.Bind(&e), "evaluate #\\{cpu/0\\} error\ndivision by zero")
 }
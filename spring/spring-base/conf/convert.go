@@ -18,6 +18,7 @@ package conf
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"time"
@@ -41,7 +42,16 @@ func init() {
 	})
 
 	// time.Duration 转换函数，支持 "ns", "us" (or "µs"), "ms", "s", "m", "h" 等。
-	Convert(func(s string) (time.Duration, error) { return cast.ToDurationE(s) })
+	Convert(func(s string) (time.Duration, error) {
+		d, err := cast.ToDurationE(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q, want a value like 300ms, 30s or 1h30m", s)
+		}
+		return d, nil
+	})
+
+	// DataSize 转换函数，支持 "B", "KB", "MB", "GB", "TB" 等。
+	Convert(ParseDataSize)
 }
 
 func validConverter(t reflect.Type) bool {
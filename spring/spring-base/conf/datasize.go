@@ -0,0 +1,85 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DataSize 以字节为单位的数据大小，绑定属性时支持 B 、KB 、MB 、GB 和 TB 这些单位，
+// 单位不区分大小写并且按照 1024 进制换算，没有单位时表示字节数，例如 16MB 表示
+// 16777216 字节。
+type DataSize int64
+
+const (
+	Byte     DataSize = 1
+	KiloByte          = 1024 * Byte
+	MegaByte          = 1024 * KiloByte
+	GigaByte          = 1024 * MegaByte
+	TeraByte          = 1024 * GigaByte
+)
+
+var dataSizeUnits = []struct {
+	unit string
+	size DataSize
+}{
+	{"TB", TeraByte},
+	{"GB", GigaByte},
+	{"MB", MegaByte},
+	{"KB", KiloByte},
+	{"B", Byte},
+}
+
+// ParseDataSize 解析 16MB 这种格式的数据大小，数值必须是非负整数。
+func ParseDataSize(s string) (DataSize, error) {
+
+	str := strings.ToUpper(strings.TrimSpace(s))
+	unit := Byte
+	for _, u := range dataSizeUnits {
+		if strings.HasSuffix(str, u.unit) {
+			str, unit = strings.TrimSpace(strings.TrimSuffix(str, u.unit)), u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid data size %q, want a non-negative integer with an optional unit of B, KB, MB, GB or TB", s)
+	}
+	if n > math.MaxInt64/int64(unit) {
+		return 0, fmt.Errorf("data size %q overflows int64", s)
+	}
+	return DataSize(n) * unit, nil
+}
+
+// Bytes 返回字节数。
+func (d DataSize) Bytes() int64 {
+	return int64(d)
+}
+
+// String 返回能够整除的最大单位表示的数据大小，例如 16MB 。
+func (d DataSize) String() string {
+	for _, u := range dataSizeUnits {
+		if d != 0 && d%u.size == 0 {
+			return strconv.FormatInt(int64(d/u.size), 10) + u.unit
+		}
+	}
+	return strconv.FormatInt(int64(d), 10) + "B"
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EncryptKeyEnv 保存默认 AES 解密器密钥的环境变量，密钥需要使用 base64 编码，
+// 解码之后的长度必须是 16、24 或者 32 字节。
+const EncryptKeyEnv = "SPRING_ENCRYPT_KEY"
+
+// Decryptor 属性值解密器，用于解密 ENC(...) 形式的加密属性值，可以通过实现该接口
+// 接入 KMS 等密钥管理服务。
+type Decryptor interface {
+	Decrypt(cipherText string) (string, error)
+}
+
+var (
+	decryptorMutex sync.RWMutex
+	decryptor      Decryptor
+)
+
+// SetDecryptor 设置全局的属性值解密器，为 nil 时使用 EncryptKeyEnv 环境变量中
+// 的密钥创建 AES 解密器。
+func SetDecryptor(d Decryptor) {
+	decryptorMutex.Lock()
+	defer decryptorMutex.Unlock()
+	decryptor = d
+}
+
+func getDecryptor() (Decryptor, error) {
+	decryptorMutex.RLock()
+	d := decryptor
+	decryptorMutex.RUnlock()
+	if d != nil {
+		return d, nil
+	}
+	s, ok := os.LookupEnv(EncryptKeyEnv)
+	if !ok {
+		return nil, fmt.Errorf("no decryptor, set it by conf.SetDecryptor or env %s", EncryptKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("env %s should be base64 encoded: %w", EncryptKeyEnv, err)
+	}
+	return NewAESDecryptor(key)
+}
+
+// isEncrypted 返回属性值是否为 ENC(...) 形式的加密值。
+func isEncrypted(s string) bool {
+	return strings.HasPrefix(s, "ENC(") && strings.HasSuffix(s, ")")
+}
+
+// decrypt 解密 ENC(...) 形式的属性值，其他属性值原样返回。
+func decrypt(s string) (string, error) {
+	if !isEncrypted(s) {
+		return s, nil
+	}
+	d, err := getDecryptor()
+	if err != nil {
+		return "", err
+	}
+	return d.Decrypt(s[len("ENC(") : len(s)-1])
+}
+
+type aesDecryptor struct {
+	aead cipher.AEAD
+}
+
+// NewAESDecryptor 创建 AES-GCM 解密器，密文是随机数和加密结果拼接之后的 base64
+// 编码，可以使用 AESEncrypt 函数生成。
+func NewAESDecryptor(key []byte) (Decryptor, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &aesDecryptor{aead: aead}, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (d *aesDecryptor) Decrypt(cipherText string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return "", err
+	}
+	n := d.aead.NonceSize()
+	if len(b) < n {
+		return "", errors.New("cipher text too short")
+	}
+	plain, err := d.aead.Open(nil, b[:n], b[n:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// AESEncrypt 使用 AES-GCM 加密属性值，返回可以直接写入属性文件的 ENC(...) 形式
+// 的加密值。
+func AESEncrypt(key []byte, plainText string) (string, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	b := aead.Seal(nonce, nonce, []byte(plainText), nil)
+	return "ENC(" + base64.StdEncoding.EncodeToString(b) + ")", nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dotenv 实现了 .env 格式配置文件的解析。
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Read 将 .env 格式的字节数组解析成 map 数据。每行一个 KEY=value 形式的变量，支
+// 持 export 前缀、 # 注释以及单引号和双引号括起来的值，其中单引号中的内容不转义。
+// 变量名按照环境变量的规则转换成属性名，即转成小写并将下划线替换成点号，例如
+// DB_HOST 转换成 db.host 。
+func Read(b []byte) (map[string]interface{}, error) {
+	ret := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("dotenv: line %d: invalid line %q", n, line)
+		}
+		key := strings.TrimSpace(line[:i])
+		val, err := parseValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("dotenv: line %d: %w", n, err)
+		}
+		key = strings.ReplaceAll(strings.ToLower(key), "_", ".")
+		ret[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func parseValue(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	switch s[0] {
+	case '"':
+		end := closingQuote(s, '"')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated value %s", s)
+		}
+		return strconv.Unquote(s[:end+1])
+	case '\'':
+		end := closingQuote(s, '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated value %s", s)
+		}
+		return s[1:end], nil
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i] // 去掉行尾注释
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// closingQuote 返回与开头引号匹配的结束引号的位置，双引号中的转义引号被跳过。
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"strings"
+)
+
+// RelaxedEnv 按照宽松规则匹配属性的环境变量，例如 SPRING_DATASOURCE_MAX_OPEN 和
+// SPRING_DATASOURCE_MAXOPEN 都匹配 spring.datasource.max-open 属性。
+type RelaxedEnv struct {
+	m map[string]string
+}
+
+// NewRelaxedEnv 使用 KEY=VALUE 形式的环境变量创建 RelaxedEnv ，例如 os.Environ()
+// 的返回值，环境变量名不区分大小写。
+func NewRelaxedEnv(environ []string) *RelaxedEnv {
+	m := make(map[string]string)
+	for _, env := range environ {
+		ss := strings.SplitN(env, "=", 2)
+		if len(ss) < 2 {
+			continue
+		}
+		m[strings.ToUpper(ss[0])] = ss[1]
+	}
+	return &RelaxedEnv{m: m}
+}
+
+// Lookup 查找属性 key 对应的环境变量。key 中的 . 、 - 和 [ 转换为 _ ，] 被去掉，
+// 然后转换为大写，也会尝试去掉 - 之后的形式。为了避免 PATH 、HOME 这类环境变量被
+// 误用，只有包含 . 的 key 才会匹配。
+func (e *RelaxedEnv) Lookup(key string) (string, bool) {
+	if e == nil || !strings.Contains(key, ".") {
+		return "", false
+	}
+	for _, name := range []string{EnvName(key), EnvName(strings.ReplaceAll(key, "-", ""))} {
+		if v, ok := e.m[name]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// EnvName 返回属性 key 对应的环境变量名，例如 spring.datasource.max-open 对应
+// SPRING_DATASOURCE_MAX_OPEN ，a.b[0].c 对应 A_B_0_C 。
+func EnvName(key string) string {
+	var sb strings.Builder
+	for _, c := range key {
+		switch c {
+		case '.', '-', '[':
+			sb.WriteByte('_')
+		case ']':
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return strings.ToUpper(sb.String())
+}
@@ -0,0 +1,338 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/go-spring/spring-base/code"
+	"github.com/go-spring/spring-base/util"
+)
+
+// resolveDefault 解析属性的默认值，#{expr} 形式的默认值是一个表达式，其中的
+// 属性引用首先被解析，然后再对表达式求值，例如 ${pool.size:=#{cpu*2}} 。
+func resolveDefault(p *Properties, def string, refs []string) (string, error) {
+	if !strings.HasPrefix(def, "#{") || !strings.HasSuffix(def, "}") {
+		return resolveRefs(p, def, refs)
+	}
+	s, err := resolveRefs(p, def[2:len(def)-1], refs)
+	if err != nil {
+		return "", err
+	}
+	v, err := Eval(s)
+	if err != nil {
+		return "", util.Wrapf(err, code.FileLine(), "evaluate %s error", def)
+	}
+	return v, nil
+}
+
+// Eval 计算表达式的值，支持整数和浮点数的 + - * / % 运算以及括号，两个整数之间
+// 的运算结果仍然是整数。支持的标识符和函数有：cpu 返回 CPU 的核数，env('NAME')
+// 和 env('NAME','def') 返回环境变量的值，min(a,b,...) 和 max(a,b,...) 返回最小值
+// 和最大值。字符串使用单引号或者双引号，参与运算时被转换为数字。
+func Eval(expr string) (string, error) {
+	e := &exprParser{s: expr}
+	v, err := e.parseExpr()
+	if err != nil {
+		return "", err
+	}
+	if e.skipSpace(); e.pos < len(e.s) {
+		return "", fmt.Errorf("unexpected %q at %d", e.s[e.pos:], e.pos)
+	}
+	switch r := v.(type) {
+	case int64:
+		return strconv.FormatInt(r, 10), nil
+	case float64:
+		return strconv.FormatFloat(r, 'f', -1, 64), nil
+	default:
+		return r.(string), nil
+	}
+}
+
+// exprParser 递归下降的表达式解析器，解析的同时完成求值，值的类型为 int64、
+// float64 或者 string 。
+type exprParser struct {
+	s   string
+	pos int
+}
+
+func (e *exprParser) skipSpace() {
+	for e.pos < len(e.s) && (e.s[e.pos] == ' ' || e.s[e.pos] == '\t') {
+		e.pos++
+	}
+}
+
+// peek 跳过空白字符后返回下一个字符，没有字符时返回 0 。
+func (e *exprParser) peek() byte {
+	e.skipSpace()
+	if e.pos < len(e.s) {
+		return e.s[e.pos]
+	}
+	return 0
+}
+
+// parseExpr expr := term (('+'|'-') term)*
+func (e *exprParser) parseExpr() (interface{}, error) {
+	v, err := e.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := e.peek()
+		if op != '+' && op != '-' {
+			return v, nil
+		}
+		e.pos++
+		r, err := e.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if v, err = arith(op, v, r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseTerm term := unary (('*'|'/'|'%') unary)*
+func (e *exprParser) parseTerm() (interface{}, error) {
+	v, err := e.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := e.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return v, nil
+		}
+		e.pos++
+		r, err := e.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if v, err = arith(op, v, r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseUnary unary := '-' unary | primary
+func (e *exprParser) parseUnary() (interface{}, error) {
+	if e.peek() == '-' {
+		e.pos++
+		v, err := e.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return arith('-', int64(0), v)
+	}
+	return e.parsePrimary()
+}
+
+// parsePrimary primary := number | string | ident | ident '(' args ')' | '(' expr ')'
+func (e *exprParser) parsePrimary() (interface{}, error) {
+	c := e.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression %q", e.s)
+	case c == '(':
+		e.pos++
+		v, err := e.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if e.peek() != ')' {
+			return nil, fmt.Errorf("missing ')' at %d", e.pos)
+		}
+		e.pos++
+		return v, nil
+	case c == '\'' || c == '"':
+		end := strings.IndexByte(e.s[e.pos+1:], c)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string at %d", e.pos)
+		}
+		v := e.s[e.pos+1 : e.pos+1+end]
+		e.pos += end + 2
+		return v, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := e.pos
+		for e.pos < len(e.s) && (e.s[e.pos] >= '0' && e.s[e.pos] <= '9' || e.s[e.pos] == '.') {
+			e.pos++
+		}
+		return toNumber(e.s[start:e.pos])
+	case isIdentByte(c):
+		start := e.pos
+		for e.pos < len(e.s) && (isIdentByte(e.s[e.pos]) || e.s[e.pos] >= '0' && e.s[e.pos] <= '9') {
+			e.pos++
+		}
+		name := e.s[start:e.pos]
+		if e.peek() != '(' {
+			return ident(name)
+		}
+		e.pos++
+		args, err := e.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		return call(name, args)
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", c, e.pos)
+}
+
+// parseArgs 解析函数的参数列表，左括号已经被读取。
+func (e *exprParser) parseArgs() ([]interface{}, error) {
+	var args []interface{}
+	if e.peek() == ')' {
+		e.pos++
+		return args, nil
+	}
+	for {
+		v, err := e.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+		switch e.peek() {
+		case ',':
+			e.pos++
+		case ')':
+			e.pos++
+			return args, nil
+		default:
+			return nil, fmt.Errorf("missing ')' at %d", e.pos)
+		}
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func ident(name string) (interface{}, error) {
+	switch name {
+	case "cpu":
+		return int64(runtime.NumCPU()), nil
+	}
+	return nil, fmt.Errorf("unknown identifier %q", name)
+}
+
+func call(name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "env":
+		if len(args) != 1 && len(args) != 2 {
+			return nil, fmt.Errorf("env() takes 1 or 2 arguments but got %d", len(args))
+		}
+		if v, ok := os.LookupEnv(fmt.Sprint(args[0])); ok {
+			return v, nil
+		}
+		if len(args) == 2 {
+			return args[1], nil
+		}
+		return nil, fmt.Errorf("environment variable %q not exist", args[0])
+	case "min", "max":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s() takes at least 1 argument", name)
+		}
+		ret, err := toNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, arg := range args[1:] {
+			v, err := toNumber(arg)
+			if err != nil {
+				return nil, err
+			}
+			f, cur := toFloat(v), toFloat(ret)
+			if name == "min" && f < cur || name == "max" && f > cur {
+				ret = v
+			}
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("unknown function %q", name)
+}
+
+// toNumber 将值转换为 int64 或者 float64 。
+func toNumber(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("%q is not a number", s)
+}
+
+func toFloat(v interface{}) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+// arith 计算 l op r 的值，两个整数之间的运算结果是整数，否则是浮点数。
+func arith(op byte, l, r interface{}) (interface{}, error) {
+	l, err := toNumber(l)
+	if err != nil {
+		return nil, err
+	}
+	if r, err = toNumber(r); err != nil {
+		return nil, err
+	}
+	li, lok := l.(int64)
+	ri, rok := r.(int64)
+	if lok && rok {
+		switch op {
+		case '+':
+			return li + ri, nil
+		case '-':
+			return li - ri, nil
+		case '*':
+			return li * ri, nil
+		}
+		if ri == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if op == '/' {
+			return li / ri, nil
+		}
+		return li % ri, nil
+	}
+	lf, rf := toFloat(l), toFloat(r)
+	switch op {
+	case '+':
+		return lf + rf, nil
+	case '-':
+		return lf - rf, nil
+	case '*':
+		return lf * rf, nil
+	case '/':
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	}
+	return nil, fmt.Errorf("operator %% requires integers")
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package hcl 实现了 HCL 格式配置文件的解析，支持属性赋值、带标签的块、列表、
+// 对象以及 # 、 // 和 /* */ 三种注释，不支持表达式、函数调用和 heredoc 字符串。
+package hcl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Read 将 hcl 格式的字节数组解析成 map 数据。
+func Read(b []byte) (map[string]interface{}, error) {
+	p := &parser{s: string(b), line: 1}
+	m := make(map[string]interface{})
+	if err := p.parseBody(m, false); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type parser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("hcl: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skip 跳过空白和注释，newline 表示是否跳过换行符。
+func (p *parser) skip(newline bool) {
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\n':
+			if !newline {
+				return
+			}
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#' || strings.HasPrefix(p.s[p.pos:], "//"):
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.s[p.pos:], "/*"):
+			end := strings.Index(p.s[p.pos+2:], "*/")
+			if end < 0 {
+				p.pos = len(p.s)
+				return
+			}
+			p.line += strings.Count(p.s[p.pos:p.pos+2+end], "\n")
+			p.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// parseBody 解析属性赋值和块的列表，nested 表示是否为块内部的内容。
+func (p *parser) parseBody(m map[string]interface{}, nested bool) error {
+	for {
+		p.skip(true)
+		c := p.peek()
+		if c == 0 {
+			if nested {
+				return p.errorf("unexpected end of block")
+			}
+			return nil
+		}
+		if c == '}' && nested {
+			p.pos++
+			return nil
+		}
+		key, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+		p.skip(false)
+		switch p.peek() {
+		case '=', ':':
+			p.pos++
+			p.skip(false)
+			v, err := p.parseValue()
+			if err != nil {
+				return err
+			}
+			m[key] = v
+		default:
+			if err = p.parseBlock(m, key); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseBlock 解析 name "label" { ... } 形式的块，标签作为嵌套的 key 。
+func (p *parser) parseBlock(m map[string]interface{}, name string) error {
+	keys := []string{name}
+	for {
+		p.skip(false)
+		if p.peek() == '{' {
+			p.pos++
+			break
+		}
+		label, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, label)
+	}
+	for _, k := range keys {
+		sub, ok := m[k].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[k] = sub
+		}
+		m = sub
+	}
+	return p.parseBody(m, true)
+}
+
+// parseKey 解析标识符或者字符串形式的 key 。
+func (p *parser) parseKey() (string, error) {
+	if p.peek() == '"' {
+		return p.parseString()
+	}
+	start := p.pos
+	for p.pos < len(p.s) && isIdentChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("unexpected character %q", p.peek())
+	}
+	return p.s[start:p.pos], nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '"':
+			p.pos++
+			s, err := strconv.Unquote(p.s[start:p.pos])
+			if err != nil {
+				return "", p.errorf("invalid string %s", p.s[start:p.pos])
+			}
+			return s, nil
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	switch c := p.peek(); c {
+	case '"':
+		return p.parseString()
+	case '[':
+		p.pos++
+		return p.parseList()
+	case '{':
+		p.pos++
+		m := make(map[string]interface{})
+		if err := p.parseObject(m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && (isIdentChar(p.s[p.pos]) || p.s[p.pos] == '+') {
+		p.pos++
+	}
+	s := p.s[start:p.pos]
+	switch {
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "":
+		return nil, p.errorf("unexpected character %q", p.peek())
+	}
+	if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("unsupported value %q", s)
+}
+
+func (p *parser) parseList() ([]interface{}, error) {
+	var ret []interface{}
+	for {
+		p.skip(true)
+		if p.peek() == ']' {
+			p.pos++
+			return ret, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+		p.skip(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected ',' or ']' in list")
+		}
+	}
+}
+
+// parseObject 解析 { key = value, ... } 形式的对象，键值对之间可以使用逗号或者
+// 换行分隔。
+func (p *parser) parseObject(m map[string]interface{}) error {
+	for {
+		p.skip(true)
+		if p.peek() == '}' {
+			p.pos++
+			return nil
+		}
+		key, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+		p.skip(false)
+		if c := p.peek(); c != '=' && c != ':' {
+			return p.errorf("expected '=' after %q", key)
+		}
+		p.pos++
+		p.skip(false)
+		v, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		m[key] = v
+		p.skip(false)
+		if p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
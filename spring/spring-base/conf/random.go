@@ -0,0 +1,125 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// RandomPrefix 随机属性的前缀。
+const RandomPrefix = "random."
+
+// RandomValues 提供 random.* 形式的随机属性，支持以下几种形式：
+// random.value 返回 32 位的十六进制字符串，random.uuid 返回 uuid 字符串，
+// random.int 和 random.long 返回非负的随机整数，random.int(max) 返回 [0,max)
+// 之间的随机整数，random.int(min,max) 返回 [min,max) 之间的随机整数，random.long
+// 也支持同样的范围语法。同一个 key 只生成一次随机值，之后总是返回相同的值。
+type RandomValues struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+// NewRandomValues 返回一个新的 RandomValues 对象。
+func NewRandomValues() *RandomValues {
+	return &RandomValues{m: make(map[string]string)}
+}
+
+// Lookup 返回 key 对应的随机值，key 不是合法的随机属性时返回 false 。
+func (r *RandomValues) Lookup(key string) (string, bool) {
+	if r == nil || !strings.HasPrefix(key, RandomPrefix) {
+		return "", false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.m[key]; ok {
+		return v, true
+	}
+	v, err := randomValue(strings.TrimPrefix(key, RandomPrefix))
+	if err != nil {
+		return "", false
+	}
+	r.m[key] = v
+	return v, true
+}
+
+func randomValue(s string) (string, error) {
+	switch s {
+	case "value":
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(b), nil
+	case "uuid":
+		return uuid.New().String(), nil
+	case "int":
+		return randomInt(0, math.MaxInt32)
+	case "long":
+		return randomInt(0, math.MaxInt64)
+	}
+	for _, typ := range []string{"int", "long"} {
+		if strings.HasPrefix(s, typ+"(") && strings.HasSuffix(s, ")") {
+			min, max, err := randomRange(s[len(typ)+1 : len(s)-1])
+			if err != nil {
+				return "", err
+			}
+			return randomInt(min, max)
+		}
+	}
+	return "", fmt.Errorf("unsupported random property %q", s)
+}
+
+// randomRange 解析 max 或者 min,max 形式的范围。
+func randomRange(s string) (min, max int64, err error) {
+	ss := strings.Split(s, ",")
+	if len(ss) > 2 {
+		return 0, 0, fmt.Errorf("illegal random range %q", s)
+	}
+	for i, str := range ss {
+		n, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		if i == len(ss)-1 {
+			max = n
+		} else {
+			min = n
+		}
+	}
+	return min, max, nil
+}
+
+// randomInt 返回 [min,max) 之间的随机整数。
+func randomInt(min, max int64) (string, error) {
+	if min >= max {
+		return "", fmt.Errorf("illegal random range [%d,%d)", min, max)
+	}
+	n, err := rand.Int(rand.Reader, new(big.Int).Sub(big.NewInt(max), big.NewInt(min)))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(n.Int64()+min, 10), nil
+}
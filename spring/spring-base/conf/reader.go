@@ -17,6 +17,8 @@
 package conf
 
 import (
+	"github.com/go-spring/spring-base/conf/dotenv"
+	"github.com/go-spring/spring-base/conf/hcl"
 	"github.com/go-spring/spring-base/conf/prop"
 	"github.com/go-spring/spring-base/conf/yaml"
 )
@@ -24,6 +26,8 @@ import (
 func init() {
 	NewReader(prop.Read, ".properties")
 	NewReader(yaml.Read, ".yaml", ".yml")
+	NewReader(hcl.Read, ".hcl")
+	NewReader(dotenv.Read, ".env")
 }
 
 var readers = make(map[string]Reader)
@@ -31,7 +35,8 @@ var readers = make(map[string]Reader)
 // Reader 属性列表解析器，将字节数组解析成 map 数据。
 type Reader func(b []byte) (map[string]interface{}, error)
 
-// NewReader 注册属性列表解析器，ext 是解析器支持的文件扩展名。
+// NewReader 注册属性列表解析器，ext 是解析器支持的文件扩展名，也可以用于注册自定
+// 义格式的解析器，已经注册的扩展名会被覆盖。
 func NewReader(r Reader, ext ...string) {
 	for _, s := range ext {
 		readers[s] = r
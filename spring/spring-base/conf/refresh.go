@@ -0,0 +1,37 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"reflect"
+)
+
+// Refreshable 可以就地刷新的属性值，例如 dync 包中的动态属性。绑定属性时不再设置
+// 字段的值，而是调用 Refresh 方法由属性值自己完成解析和更新，因此属性发生变化时
+// 可以在不重新创建对象的情况下更新属性值。
+type Refreshable interface {
+	Refresh(p *Properties, param BindParam) error
+}
+
+// ToRefreshable 返回 v 对应的 Refreshable 对象，v 必须是可寻址的。
+func ToRefreshable(v reflect.Value) (Refreshable, bool) {
+	if !v.CanAddr() || !v.Addr().CanInterface() {
+		return nil, false
+	}
+	r, ok := v.Addr().Interface().(Refreshable)
+	return r, ok
+}
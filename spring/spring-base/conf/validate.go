@@ -0,0 +1,134 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-spring/spring-base/util"
+)
+
+// ValidateError 属性绑定之后的校验错误，Key 是字段绑定的属性名，Origin 是属性的
+// 来源，例如属性文件的路径，由知道属性来源的调用方填写。
+type ValidateError struct {
+	Key    string
+	Origin string
+	Err    error
+}
+
+func (e *ValidateError) Error() string {
+	switch {
+	case e.Key == "":
+		return e.Err.Error()
+	case e.Origin == "":
+		return fmt.Sprintf("%v (property %q)", e.Err, e.Key)
+	default:
+		return fmt.Sprintf("%v (property %q from %s)", e.Err, e.Key, e.Origin)
+	}
+}
+
+func (e *ValidateError) Unwrap() error {
+	return e.Err
+}
+
+// Validate 根据 validate 标签校验已经完成属性绑定的 v ，param 与属性绑定时使用的
+// 相同，返回由 *ValidateError 组成的 *util.Errors ，包含所有字段的校验错误以及字段
+// 绑定的属性名。只有通过属性绑定的字段会被递归校验，其他字段只校验自身的标签。
+func Validate(v reflect.Value, param BindParam) error {
+	var errs util.Errors
+	validateValue(&errs, "", v, param.Key)
+	return errs.ErrorOrNil()
+}
+
+func validateValue(errs *util.Errors, path string, v reflect.Value, key string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			validateValue(errs, path, v.Elem(), key)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(errs, fmt.Sprintf("%s[%d]", path, i), v.Index(i), fmt.Sprintf("%s[%d]", key, i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(errs, fmt.Sprintf("%s[%v]", path, iter.Key()), iter.Value(), fmt.Sprintf("%s.%v", key, iter.Key()))
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return
+		}
+		validateStruct(errs, path, v, key)
+	}
+}
+
+func validateStruct(errs *util.Errors, path string, v reflect.Value, key string) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		fv := v.Field(i)
+		if !fv.CanInterface() {
+			fv = util.PatchValue(fv)
+			if !fv.CanInterface() {
+				continue
+			}
+		}
+
+		fp := f.Name
+		if f.Anonymous {
+			fp = path
+		} else if path != "" {
+			fp = path + "." + f.Name
+		}
+
+		// 和 bindStruct 一样计算字段绑定的属性名。
+		fk, bound := "", true
+		if tag, ok := f.Tag.Lookup("value"); ok {
+			param := BindParam{Key: key, Path: fp}
+			if err := param.BindTag(tag); err != nil {
+				continue
+			}
+			fk = param.Key
+		} else if f.Anonymous {
+			fk = key
+		} else if util.IsValueType(f.Type) {
+			fk = f.Name
+			if key != "" {
+				fk = key + "." + f.Name
+			}
+		} else {
+			bound = false
+		}
+
+		if tag, ok := f.Tag.Lookup("validate"); ok && tag != "-" {
+			var fieldErrs util.Errors
+			next := util.ValidateTag(&fieldErrs, fp, fv, tag)
+			for _, err := range fieldErrs.Errors() {
+				errs.Append(&ValidateError{Key: fk, Err: err})
+			}
+			if !next {
+				continue
+			}
+		}
+
+		if bound {
+			validateValue(errs, fp, fv, fk)
+		}
+	}
+}
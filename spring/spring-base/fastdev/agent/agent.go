@@ -0,0 +1,209 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package agent 提供了通过 unix domain socket 和本地 sidecar 交换会话的能力，
+// 录制时将会话发送给 sidecar 进行存储或上传，回放时从 sidecar 获取会话，从
+// 而将繁重的 I/O 操作从业务进程中剥离出去。
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-base/fastdev"
+)
+
+const sessionPath = "/session/"
+
+// Storage 会话的存储，由 sidecar 实现。
+type Storage interface {
+	Save(sessionID string, data []byte) error
+	Load(sessionID string) ([]byte, error)
+}
+
+// DirStorage 将会话以 <sessionID>.json 文件的形式保存在目录中，携带租户前缀
+// 的会话保存在租户对应的子目录中，即 <tenant>/<sessionID>.json 。
+type DirStorage string
+
+func (dir DirStorage) file(sessionID string) (string, error) {
+	tenant, id := fastdev.SplitTenant(sessionID)
+	if id == "" || strings.ContainsAny(sessionID, `/\`) || strings.HasPrefix(sessionID, ".") {
+		return "", fmt.Errorf("invalid session id %q", sessionID)
+	}
+	return filepath.Join(string(dir), tenant, id+".json"), nil
+}
+
+func (dir DirStorage) Save(sessionID string, data []byte) error {
+	file, err := dir.file(sessionID)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+func (dir DirStorage) Load(sessionID string) ([]byte, error) {
+	file, err := dir.file(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(file)
+}
+
+// Server sidecar 服务端。
+type Server struct {
+	storage Storage
+	server  *http.Server
+}
+
+// NewServer 创建 sidecar 服务端。
+func NewServer(storage Storage) *Server {
+	s := &Server{storage: storage}
+	mux := http.NewServeMux()
+	mux.HandleFunc(sessionPath, s.session)
+	s.server = &http.Server{Handler: mux}
+	return s
+}
+
+// ListenAndServe 监听 socket 文件并提供服务，socket 文件已存在时会先删除。
+func (s *Server) ListenAndServe(socket string) error {
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve 在 l 上提供服务。
+func (s *Server) Serve(l net.Listener) error {
+	err := s.server.Serve(l)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown 关闭服务端。
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) session(w http.ResponseWriter, r *http.Request) {
+	sessionID := strings.TrimPrefix(r.URL.Path, sessionPath)
+	switch r.Method {
+	case http.MethodPost:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = s.storage.Save(sessionID, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case http.MethodGet:
+		data, err := s.storage.Load(sessionID)
+		if os.IsNotExist(err) {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(data)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Client sidecar 客户端，Upload 可以作为 recorder.SetSink 的参数，Fetch 可
+// 以作为 replayer.SetSource 的参数。
+type Client struct {
+	client *http.Client
+}
+
+// NewClient 创建连接到 socket 文件的 sidecar 客户端。
+func NewClient(socket string) *Client {
+	dialer := &net.Dialer{}
+	return &Client{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+func (c *Client) url(sessionID string) string {
+	return "http://agent" + sessionPath + sessionID
+}
+
+// Upload 将会话发送给 sidecar 。
+func (c *Client) Upload(session *fastdev.Session) error {
+	var buf bytes.Buffer
+	if err := fastdev.WriteSession(&buf, session); err != nil {
+		return err
+	}
+	resp, err := c.client.Post(c.url(session.Session), "application/json", &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+// Fetch 从 sidecar 获取会话。
+func (c *Client) Fetch(sessionID string) (*fastdev.RawSession, error) {
+	resp, err := c.client.Get(c.url(sessionID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err = checkResponse(resp); err != nil {
+		return nil, err
+	}
+	return fastdev.ReadRawSession(resp.Body)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	msg := strings.TrimSpace(string(b))
+	if msg == "" {
+		msg = resp.Status
+	}
+	return errors.New(msg)
+}
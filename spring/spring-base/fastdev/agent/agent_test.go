@@ -0,0 +1,137 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/agent"
+	"github.com/go-spring/spring-base/fastdev/recorder"
+	"github.com/go-spring/spring-base/fastdev/replayer"
+	"github.com/go-spring/spring-base/knife"
+)
+
+type protocol struct{}
+
+func (p *protocol) ShouldDiff() bool {
+	return true
+}
+
+func (p *protocol) GetLabel(data string) string {
+	return data
+}
+
+func (p *protocol) FlatRequest(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (p *protocol) FlatResponse(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func init() {
+	fastdev.RegisterProtocol(fastdev.HTTP, &protocol{})
+	fastdev.RegisterProtocol(fastdev.REDIS, &protocol{})
+}
+
+func TestAgent(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "agent")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "agent.sock")
+	server := agent.NewServer(agent.DirStorage(filepath.Join(dir, "sessions")))
+	go func() { _ = server.ListenAndServe(socket) }()
+	defer server.Shutdown(context.Background())
+
+	client := agent.NewClient(socket)
+	for i := 0; i < 100; i++ {
+		if _, err = os.Stat(socket); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, err = client.Fetch("df3b64266ebe4e63a464e135000a07cd")
+	assert.Error(t, err, "session not found")
+
+	_, err = client.Fetch(".hidden")
+	assert.Error(t, err, "invalid session id")
+
+	const sessionID = "df3b64266ebe4e63a464e135000a07cd"
+
+	recorder.SetRecordMode(true)
+	recorder.SetSink(client.Upload)
+	{
+		ctx, _ := knife.New(context.Background())
+		err = recorder.StartRecord(ctx, sessionID)
+		assert.Nil(t, err)
+		action := fastdev.AcquireAction()
+		action.Protocol = fastdev.REDIS
+		action.SetRequest("GET a")
+		action.SetResponse("1")
+		err = recorder.RecordAction(ctx, action)
+		assert.Nil(t, err)
+		inbound := fastdev.AcquireAction()
+		inbound.Protocol = fastdev.HTTP
+		inbound.SetRequest("GET /a")
+		inbound.SetResponse("200 1")
+		err = recorder.RecordInbound(ctx, inbound)
+		assert.Nil(t, err)
+		var session *fastdev.Session
+		session, err = recorder.StopRecord(ctx)
+		assert.Nil(t, err)
+		assert.Nil(t, session)
+	}
+	recorder.SetSink(nil)
+	recorder.SetRecordMode(false)
+
+	replayer.SetReplayMode(true)
+	replayer.SetSource(client.Fetch)
+	{
+		ctx, _ := knife.New(context.Background())
+		err = replayer.SetSessionID(ctx, sessionID)
+		assert.Nil(t, err)
+		var action *replayer.Action
+		action, err = replayer.ReplayAction(ctx, fastdev.REDIS, "GET a")
+		assert.Nil(t, err)
+		assert.Equal(t, action.Response, "1")
+		err = replayer.ReplayInbound(ctx, "200 1")
+		assert.Nil(t, err)
+		var result *replayer.Result
+		result, err = replayer.Report(sessionID)
+		assert.Nil(t, err)
+		assert.True(t, result.Pass)
+		replayer.Delete(sessionID)
+	}
+	replayer.SetSource(nil)
+	replayer.SetReplayMode(false)
+
+	session := &fastdev.RawSession{Session: "order-prod." + sessionID}
+	err = agent.DirStorage(dir).Save(session.Session, []byte(`{}`))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "order-prod", sessionID+".json"))
+	assert.Nil(t, err)
+}
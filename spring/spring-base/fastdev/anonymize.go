@@ -0,0 +1,153 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fastdev
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/go-spring/spring-base/util"
+)
+
+// AnonymizeMode 匿名化的方式。
+type AnonymizeMode int
+
+const (
+	Hash  = AnonymizeMode(iota) // 使用以盐为密钥的 HMAC-SHA256 值替换
+	Token                       // 使用按出现顺序编号的令牌替换
+)
+
+// AnonymizeRule 匿名化规则，所有匹配 Pattern 的内容都会被替换。
+type AnonymizeRule struct {
+	Name    string         // 规则名称，用作替换内容的前缀
+	Pattern *regexp.Regexp // 需要匿名化的内容
+	Mode    AnonymizeMode  // 匿名化的方式
+	Salt    string         // 哈希方式使用的盐，为空时使用 Anonymizer 随机生成的盐
+}
+
+// NewAnonymizeRule 创建匿名化规则。
+func NewAnonymizeRule(name string, expr string, mode AnonymizeMode) (*AnonymizeRule, error) {
+	r, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &AnonymizeRule{Name: name, Pattern: r, Mode: mode}, nil
+}
+
+// 内置的匿名化规则表达式。
+const (
+	EmailPattern  = `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`
+	PhonePattern  = `\b1[3-9][0-9]{9}\b`
+	IDCardPattern = `\b[0-9]{17}[0-9Xx]\b`
+)
+
+// BuiltinAnonymizeRules 返回内置的邮箱、手机号、身份证号匿名化规则。
+func BuiltinAnonymizeRules(mode AnonymizeMode) []*AnonymizeRule {
+	return []*AnonymizeRule{
+		{Name: "email", Pattern: regexp.MustCompile(EmailPattern), Mode: mode},
+		{Name: "phone", Pattern: regexp.MustCompile(PhonePattern), Mode: mode},
+		{Name: "idcard", Pattern: regexp.MustCompile(IDCardPattern), Mode: mode},
+	}
+}
+
+// Anonymizer 对会话进行匿名化，同一个 Anonymizer 对象会将相同的原始内容替换
+// 为相同的结果，因此可以在多个会话之间保持引用的一致性。Anonymizer 对象可以
+// 在多个 goroutine 中并发使用。
+type Anonymizer struct {
+	rules  []*AnonymizeRule
+	mu     sync.Mutex
+	tokens map[string]map[string]string
+	salt   []byte // 规则没有设置盐时使用的随机盐
+}
+
+// NewAnonymizer 创建 Anonymizer 对象。手机号、身份证号等内容的取值空间很小，
+// 没有盐或者盐是公开的时候哈希值可以被穷举还原，因此没有设置盐的规则使用每个
+// Anonymizer 对象随机生成的盐，此时不同 Anonymizer 对象的结果不一致。
+func NewAnonymizer(rules []*AnonymizeRule) *Anonymizer {
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	util.Panic(err).When(err != nil)
+	return &Anonymizer{
+		rules:  rules,
+		tokens: make(map[string]map[string]string),
+		salt:   salt,
+	}
+}
+
+// Anonymize 对会话的上游数据和所有动作数据进行匿名化。
+func (a *Anonymizer) Anonymize(session *RawSession) {
+	if session.Inbound != nil {
+		a.anonymizeAction(session.Inbound)
+	}
+	for _, action := range session.Actions {
+		a.anonymizeAction(action)
+	}
+}
+
+func (a *Anonymizer) anonymizeAction(action *RawAction) {
+	action.Request = a.String(action.Request)
+	action.Response = a.String(action.Response)
+}
+
+// String 按照规则的顺序依次对字符串进行匿名化。
+func (a *Anonymizer) String(s string) string {
+	for _, r := range a.rules {
+		s = r.Pattern.ReplaceAllStringFunc(s, func(v string) string {
+			return a.replace(r, v)
+		})
+	}
+	return s
+}
+
+func (a *Anonymizer) replace(r *AnonymizeRule, v string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	m, ok := a.tokens[r.Name]
+	if !ok {
+		m = make(map[string]string)
+		a.tokens[r.Name] = m
+	}
+	if s, ok := m[v]; ok {
+		return s
+	}
+	var s string
+	switch r.Mode {
+	case Token:
+		s = fmt.Sprintf("%s_%d", r.Name, len(m)+1)
+	default:
+		key := a.salt
+		if r.Salt != "" {
+			key = []byte(r.Salt)
+		}
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(v))
+		s = r.Name + "_" + hex.EncodeToString(h.Sum(nil)[:8])
+	}
+	m[v] = s
+	return s
+}
+
+// Anonymize 使用 rules 对会话进行匿名化，相同的原始内容在会话内会被替换为相
+// 同的结果。需要在多个会话之间保持一致时请使用 Anonymizer 对象。
+func Anonymize(session *RawSession, rules []*AnonymizeRule) {
+	NewAnonymizer(rules).Anonymize(session)
+}
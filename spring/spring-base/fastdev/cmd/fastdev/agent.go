@@ -0,0 +1,36 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/go-spring/spring-base/fastdev/agent"
+)
+
+// runAgent 启动 sidecar，将收到的会话保存到目录中。
+func runAgent(args []string) error {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	socket := fs.String("socket", "/tmp/fastdev.sock", "unix domain socket path")
+	dir := fs.String("dir", "sessions", "session storage dir")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	fmt.Printf("fastdev agent listening on %s, storage dir %s\n", *socket, *dir)
+	return agent.NewServer(agent.DirStorage(*dir)).ListenAndServe(*socket)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-spring/spring-base/fastdev"
+)
+
+// anonymize 使用内置规则和自定义规则对会话文件进行匿名化，所有文件共享同一
+// 个 Anonymizer 对象，以便在会话之间保持引用的一致性。
+func anonymize(args []string) error {
+
+	var rules stringFlags
+	fs := flag.NewFlagSet("anonymize", flag.ContinueOnError)
+	mode := fs.String("mode", "hash", "anonymize mode, hash or token")
+	salt := fs.String("salt", "", "secret salt used by hash mode, random for each run if empty")
+	out := fs.String("out", "", "output dir, overwrite the input files if empty")
+	fs.Var(&rules, "rule", "custom rule in name=regexp form, can be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var m fastdev.AnonymizeMode
+	switch *mode {
+	case "hash":
+		m = fastdev.Hash
+	case "token":
+		m = fastdev.Token
+	default:
+		return fmt.Errorf("invalid mode %s", *mode)
+	}
+
+	list := fastdev.BuiltinAnonymizeRules(m)
+	for _, s := range rules {
+		ss := strings.SplitN(s, "=", 2)
+		if len(ss) != 2 || ss[0] == "" {
+			return fmt.Errorf("invalid rule %s", s)
+		}
+		r, err := fastdev.NewAnonymizeRule(ss[0], ss[1], m)
+		if err != nil {
+			return err
+		}
+		list = append(list, r)
+	}
+	for _, r := range list {
+		r.Salt = *salt
+	}
+
+	if fs.NArg() == 0 {
+		return errors.New("no session file")
+	}
+
+	a := fastdev.NewAnonymizer(list)
+	for _, file := range fs.Args() {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		session, err := fastdev.ToRawSession(string(data))
+		if err != nil {
+			return fmt.Errorf("unmarshal file %s error %s", file, err.Error())
+		}
+		a.Anonymize(session)
+		str, err := session.Pretty()
+		if err != nil {
+			return err
+		}
+		dest := file
+		if *out != "" {
+			if err = os.MkdirAll(*out, os.ModePerm); err != nil {
+				return err
+			}
+			dest = filepath.Join(*out, filepath.Base(file))
+		}
+		if err = ioutil.WriteFile(dest, []byte(str+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// fastdev 命令行工具，用于处理录制的会话文件。
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const help = `command:
+  fastdev anonymize [-mode hash|token] [-salt salt] [-rule name=regexp] [-out dir] file...
+  fastdev index dir
+  fastdev search [-inbound label] [-tag tag] [-action protocol:label] [-since time] [-until time] dir
+  fastdev agent [-socket path] [-dir dir]`
+
+var commands = map[string]func(args []string) error{
+	"agent":     runAgent,
+	"anonymize": anonymize,
+	"index":     index,
+	"search":    search,
+}
+
+// stringFlags 收集可以重复设置的命令行参数。
+type stringFlags []string
+
+func (s *stringFlags) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringFlags) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(help)
+		os.Exit(2)
+	}
+	fn, ok := commands[os.Args[1]]
+	if !ok {
+		var names []string
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("error command %s, available commands: %s\n", os.Args[1], strings.Join(names, ", "))
+		fmt.Println(help)
+		os.Exit(2)
+	}
+	if err := fn(os.Args[2:]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
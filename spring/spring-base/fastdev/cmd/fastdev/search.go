@@ -0,0 +1,77 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/go-spring/spring-base/fastdev"
+)
+
+// index 为会话目录生成索引文件。
+func index(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: fastdev index dir")
+	}
+	return fastdev.BuildIndex(args[0])
+}
+
+// search 使用索引文件检索会话，输出满足条件的会话文件路径。
+func search(args []string) error {
+
+	var q fastdev.Query
+	var tags, actions stringFlags
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	fs.StringVar(&q.Inbound, "inbound", "", "inbound label, * is wildcard")
+	fs.Var(&tags, "tag", "session tag, can be repeated")
+	fs.Var(&actions, "action", "protocol or protocol:label, can be repeated")
+	since := fs.String("since", "", "time lower bound in RFC3339 format")
+	until := fs.String("until", "", "time upper bound in RFC3339 format")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: fastdev search [options] dir")
+	}
+
+	var err error
+	q.Tags, q.Actions = tags, actions
+	if *since != "" {
+		if q.Since, err = time.Parse(time.RFC3339, *since); err != nil {
+			return err
+		}
+	}
+	if *until != "" {
+		if q.Until, err = time.Parse(time.RFC3339, *until); err != nil {
+			return err
+		}
+	}
+
+	dir := fs.Arg(0)
+	entries, err := fastdev.Search(dir, &q)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		fmt.Println(filepath.Join(dir, filepath.FromSlash(e.File)))
+	}
+	return nil
+}
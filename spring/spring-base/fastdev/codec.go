@@ -0,0 +1,831 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fastdev
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-spring/spring-base/fastdev/internal/json"
+)
+
+// 本文件提供了会话的快速序列化方法，其结果和 String 方法的结果完全相同，但
+// 是不使用反射，并且通过追加的方式和对象池复用内存，适合处理很大的会话。
+
+// maxPooledBufferSize 放回对象池的缓冲区的最大容量，偶尔出现的大会话使用的
+// 缓冲区直接丢弃，避免对象池长期占用大块内存。
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 4096)
+		return &b
+	},
+}
+
+func putBuffer(p *[]byte) {
+	if cap(*p) > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(p)
+}
+
+// WriteSession 将会话序列化后写入 w，序列化使用的内存来自对象池。
+func WriteSession(w io.Writer, session *Session) error {
+	p := bufferPool.Get().(*[]byte)
+	defer func() { putBuffer(p) }()
+	*p = AppendSession((*p)[:0], session)
+	_, err := w.Write(*p)
+	return err
+}
+
+// AppendSession 将会话序列化后追加到 dst 中，结果和 Session.String 相同。
+func AppendSession(dst []byte, session *Session) []byte {
+	if session == nil {
+		return append(dst, "null"...)
+	}
+	dst, comma := appendHead(dst, session.Session, session.Timestamp, session.Tags)
+	if session.Inbound != nil {
+		dst = appendKey(dst, comma, "Inbound")
+		dst = appendAction(dst, session.Inbound)
+		comma = true
+	}
+	if len(session.Actions) > 0 {
+		dst = appendKey(dst, comma, "Actions")
+		dst = append(dst, '[')
+		for i, action := range session.Actions {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendAction(dst, action)
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, '}')
+}
+
+func appendAction(dst []byte, action *Action) []byte {
+	if action == nil {
+		return append(dst, "null"...)
+	}
+	dst = append(dst, '{')
+	comma := false
+	if action.Protocol != "" {
+		dst = appendKey(dst, comma, "Protocol")
+		dst = appendString(dst, action.Protocol)
+		comma = true
+	}
+	if action.Timestamp != 0 {
+		dst = appendKey(dst, comma, "Timestamp")
+		dst = strconv.AppendInt(dst, action.Timestamp, 10)
+		comma = true
+	}
+	if action.Request != nil {
+		dst = appendKey(dst, comma, "Request")
+		dst = appendMessage(dst, action.Request())
+		comma = true
+	}
+	if action.Response != nil {
+		dst = appendKey(dst, comma, "Response")
+		dst = appendMessage(dst, action.Response())
+		comma = true
+	}
+	dst = appendMetadata(dst, comma, action.Metadata)
+	return append(dst, '}')
+}
+
+// AppendRawSession 将会话序列化后追加到 dst 中，结果和 RawSession.String 相同。
+func AppendRawSession(dst []byte, session *RawSession) []byte {
+	if session == nil {
+		return append(dst, "null"...)
+	}
+	dst, comma := appendHead(dst, session.Session, session.Timestamp, session.Tags)
+	if session.Inbound != nil {
+		dst = appendKey(dst, comma, "Inbound")
+		dst = appendRawAction(dst, session.Inbound)
+		comma = true
+	}
+	if len(session.Actions) > 0 {
+		dst = appendKey(dst, comma, "Actions")
+		dst = append(dst, '[')
+		for i, action := range session.Actions {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendRawAction(dst, action)
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, '}')
+}
+
+func appendRawAction(dst []byte, action *RawAction) []byte {
+	if action == nil {
+		return append(dst, "null"...)
+	}
+	dst = append(dst, '{')
+	comma := false
+	if action.Protocol != "" {
+		dst = appendKey(dst, comma, "Protocol")
+		dst = appendString(dst, action.Protocol)
+		comma = true
+	}
+	if action.Timestamp != 0 {
+		dst = appendKey(dst, comma, "Timestamp")
+		dst = strconv.AppendInt(dst, action.Timestamp, 10)
+		comma = true
+	}
+	if action.Request != "" {
+		dst = appendKey(dst, comma, "Request")
+		dst = appendMessage(dst, action.Request)
+		comma = true
+	}
+	if action.Response != "" {
+		dst = appendKey(dst, comma, "Response")
+		dst = appendMessage(dst, action.Response)
+		comma = true
+	}
+	dst = appendMetadata(dst, comma, action.Metadata)
+	return append(dst, '}')
+}
+
+// appendMetadata 按照 key 的字典序追加附加信息，和 json 包的 map 序列化结果相同。
+func appendMetadata(dst []byte, comma bool, m map[string]string) []byte {
+	if len(m) == 0 {
+		return dst
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	dst = appendKey(dst, comma, "Metadata")
+	dst = append(dst, '{')
+	for i, k := range keys {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendMapKey(dst, k)
+		dst = append(dst, ':')
+		dst = appendString(dst, m[k])
+	}
+	return append(dst, '}')
+}
+
+func appendHead(dst []byte, session string, timestamp int64, tags []string) ([]byte, bool) {
+	dst = append(dst, '{')
+	comma := false
+	if session != "" {
+		dst = appendKey(dst, comma, "Session")
+		dst = appendString(dst, session)
+		comma = true
+	}
+	if timestamp != 0 {
+		dst = appendKey(dst, comma, "Timestamp")
+		dst = strconv.AppendInt(dst, timestamp, 10)
+		comma = true
+	}
+	if len(tags) > 0 {
+		dst = appendKey(dst, comma, "Tags")
+		dst = append(dst, '[')
+		for i, tag := range tags {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendString(dst, tag)
+		}
+		dst = append(dst, ']')
+		comma = true
+	}
+	return dst, comma
+}
+
+func appendKey(dst []byte, comma bool, key string) []byte {
+	if comma {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, key...)
+	return append(dst, '"', ':')
+}
+
+// appendMessage 按照 EncodeMessage 的规则追加消息，base64 编码直接写入 dst 。
+func appendMessage(dst []byte, s string) []byte {
+	if !hasBase64Prefix(s) {
+		if b, ok := appendText(dst, s); ok {
+			return b
+		}
+	}
+	dst = append(dst, '"')
+	dst = append(dst, Base64Prefix...)
+	n := len(dst)
+	dst = grow(dst, base64.StdEncoding.EncodedLen(len(s)))
+	// 分段拷贝到栈上的数组中进行编码，避免将整个字符串转换为 []byte 。
+	var chunk [3 * 1024]byte
+	for len(s) > 0 {
+		k := copy(chunk[:], s)
+		s = s[k:]
+		size := base64.StdEncoding.EncodedLen(k)
+		dst = dst[:n+size]
+		base64.StdEncoding.Encode(dst[n:], chunk[:k])
+		n += size
+	}
+	return append(dst, '"')
+}
+
+func hasBase64Prefix(s string) bool {
+	return len(s) >= len(Base64Prefix) && s[:len(Base64Prefix)] == Base64Prefix
+}
+
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst
+	}
+	b := make([]byte, len(dst), 2*cap(dst)+n)
+	copy(b, dst)
+	return b
+}
+
+const hexDigits = "0123456789abcdef"
+
+// safeSet 不需要转义的字节，0xE2 可能是 U+2028 和 U+2029 的开始，需要单独处理。
+var safeSet = func() (set [256]bool) {
+	for b := 0x20; b < 256; b++ {
+		set[b] = b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' && b != 0xE2
+	}
+	return
+}()
+
+// appendString 追加 JSON 字符串，转义规则和 internal/json 包相同。
+func appendString(dst []byte, s string) []byte {
+	if !utf8.ValidString(s) {
+		s = json.Quote(s)
+	}
+	return appendEscaped(dst, s)
+}
+
+// appendMapKey 追加 map 的 key，json 包不会对 key 进行二次 quote，而是将非法
+// 的 UTF-8 字符逐字节替换为 \ufffd 。
+func appendMapKey(dst []byte, s string) []byte {
+	if utf8.ValidString(s) {
+		return appendEscaped(dst, s)
+	}
+	dst = append(dst, '"')
+	for s != "" {
+		i := 0
+		for i < len(s) {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				break
+			}
+			i += size
+		}
+		if i > 0 {
+			n := len(dst)
+			dst = appendEscaped(dst, s[:i])
+			dst = append(dst[:n], dst[n+1:len(dst)-1]...)
+		}
+		if i < len(s) {
+			dst = append(dst, `\ufffd`...)
+			i++
+		}
+		s = s[i:]
+	}
+	return append(dst, '"')
+}
+
+// appendEscaped 追加合法的 UTF-8 字符串，非 ASCII 字符中只有 U+2028 和 U+2029
+// 需要转义，二者的 UTF-8 编码为 E2 80 A8 和 E2 80 A9 。
+func appendEscaped(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if safeSet[b] {
+			i++
+			continue
+		}
+		if b == 0xE2 {
+			if i+2 < len(s) && s[i+1] == 0x80 && s[i+2]&^1 == 0xA8 {
+				dst = append(dst, s[start:i]...)
+				dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[s[i+2]&^0xA0])
+				i += 3
+				start = i
+				continue
+			}
+			i++
+			continue
+		}
+		dst = append(dst, s[start:i]...)
+		switch b {
+		case '\\', '"':
+			dst = append(dst, '\\', b)
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+		}
+		i++
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendText 追加文本消息，和 isBinary 加 appendEscaped 的结果相同，但是只遍历
+// 一次字符串。遇到二进制内容时返回 false ，此时 dst 的内容没有变化。
+func appendText(dst []byte, s string) ([]byte, bool) {
+	n := len(dst)
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf && safeSet[b] && b != 0x7f {
+			i++
+			continue
+		}
+		if b >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return dst[:n], false
+			}
+			if r == '\u2028' || r == '\u2029' {
+				dst = append(dst, s[start:i]...)
+				dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+				start = i + size
+			}
+			i += size
+			continue
+		}
+		switch b {
+		case '\\', '"', '<', '>', '&':
+		case '\n', '\r', '\t':
+		default:
+			if b < 0x20 || b == 0x7f {
+				return dst[:n], false
+			}
+		}
+		dst = append(dst, s[start:i]...)
+		switch b {
+		case '\\', '"':
+			dst = append(dst, '\\', b)
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		default:
+			dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+		}
+		i++
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"'), true
+}
+
+// ReadRawSession 从 r 中读取全部内容并反序列化，读取使用的内存来自对象池。
+func ReadRawSession(r io.Reader) (*RawSession, error) {
+	p := bufferPool.Get().(*[]byte)
+	defer func() { putBuffer(p) }()
+	buf := bytes.NewBuffer((*p)[:0])
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	*p = buf.Bytes()
+	return DecodeRawSession(*p)
+}
+
+// DecodeRawSession 反序列化会话，结果和 ToRawSession 相同，但是不使用反射。
+func DecodeRawSession(data []byte) (*RawSession, error) {
+	d := &decoder{data: data}
+	var session *RawSession
+	err := d.object(func(key []byte) error {
+		if session == nil {
+			session = new(RawSession)
+		}
+		var err error
+		switch string(key) {
+		case "Session":
+			session.Session, err = d.string()
+		case "Timestamp":
+			session.Timestamp, err = d.int64()
+		case "Tags":
+			err = d.array(func() error {
+				s, err := d.string()
+				session.Tags = append(session.Tags, s)
+				return err
+			})
+		case "Inbound":
+			session.Inbound, err = d.rawAction()
+		case "Actions":
+			err = d.array(func() error {
+				a, err := d.rawAction()
+				session.Actions = append(session.Actions, a)
+				return err
+			})
+		default:
+			err = d.skip()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.skipSpace(); d.pos < len(d.data) {
+		return nil, d.error("invalid character after top-level value")
+	}
+	return session, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) error(msg string) error {
+	return fmt.Errorf("fastdev: %s at offset %d", msg, d.pos)
+}
+
+func (d *decoder) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\r', '\n':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *decoder) peek() byte {
+	if d.skipSpace(); d.pos < len(d.data) {
+		return d.data[d.pos]
+	}
+	return 0
+}
+
+// null 跳过 null 值并返回 true，当前值不是 null 时返回 false 。
+func (d *decoder) null() bool {
+	if d.peek() == 'n' && bytes.HasPrefix(d.data[d.pos:], []byte("null")) {
+		d.pos += 4
+		return true
+	}
+	return false
+}
+
+func (d *decoder) expect(c byte) error {
+	if d.peek() != c {
+		return d.error(fmt.Sprintf("expect %q", c))
+	}
+	d.pos++
+	return nil
+}
+
+// object 解析 JSON 对象，对每个 key 调用 f，f 负责解析对应的值。值为 null 时
+// 不调用 f 。
+func (d *decoder) object(f func(key []byte) error) error {
+	if d.null() {
+		return nil
+	}
+	if err := d.expect('{'); err != nil {
+		return err
+	}
+	if d.peek() == '}' {
+		d.pos++
+		return nil
+	}
+	for {
+		key, err := d.stringBytes()
+		if err != nil {
+			return err
+		}
+		if err = d.expect(':'); err != nil {
+			return err
+		}
+		if err = f(key); err != nil {
+			return err
+		}
+		switch d.peek() {
+		case ',':
+			d.pos++
+		case '}':
+			d.pos++
+			return nil
+		default:
+			return d.error("expect ',' or '}'")
+		}
+	}
+}
+
+// array 解析 JSON 数组，f 负责解析每个元素。
+func (d *decoder) array(f func() error) error {
+	if d.null() {
+		return nil
+	}
+	if err := d.expect('['); err != nil {
+		return err
+	}
+	if d.peek() == ']' {
+		d.pos++
+		return nil
+	}
+	for {
+		if err := f(); err != nil {
+			return err
+		}
+		switch d.peek() {
+		case ',':
+			d.pos++
+		case ']':
+			d.pos++
+			return nil
+		default:
+			return d.error("expect ',' or ']'")
+		}
+	}
+}
+
+func (d *decoder) rawAction() (*RawAction, error) {
+	var action *RawAction
+	err := d.object(func(key []byte) error {
+		if action == nil {
+			action = new(RawAction)
+		}
+		var err error
+		switch string(key) {
+		case "Protocol":
+			action.Protocol, err = d.string()
+		case "Timestamp":
+			action.Timestamp, err = d.int64()
+		case "Request":
+			action.Request, err = d.message()
+		case "Response":
+			action.Response, err = d.message()
+		case "Metadata":
+			err = d.object(func(key []byte) error {
+				if action.Metadata == nil {
+					action.Metadata = make(map[string]string)
+				}
+				v, err := d.string()
+				action.Metadata[string(key)] = v
+				return err
+			})
+		default:
+			err = d.skip()
+		}
+		return err
+	})
+	return action, err
+}
+
+func (d *decoder) int64() (int64, error) {
+	if d.null() {
+		return 0, nil
+	}
+	start := d.pos
+	if d.pos < len(d.data) && d.data[d.pos] == '-' {
+		d.pos++
+	}
+	for d.pos < len(d.data) && d.data[d.pos] >= '0' && d.data[d.pos] <= '9' {
+		d.pos++
+	}
+	n, err := strconv.ParseInt(string(d.data[start:d.pos]), 10, 64)
+	if err != nil {
+		d.pos = start
+		return 0, d.error("invalid number")
+	}
+	return n, nil
+}
+
+// message 解析消息，自动识别 base64 编码。
+func (d *decoder) message() (string, error) {
+	if d.null() {
+		return "", nil
+	}
+	b, err := d.stringBytes()
+	if err != nil {
+		return "", err
+	}
+	if bytes.HasPrefix(b, []byte(Base64Prefix)) {
+		b = b[len(Base64Prefix):]
+		buf := make([]byte, base64.StdEncoding.DecodedLen(len(b)))
+		n, err := base64.StdEncoding.Decode(buf, b)
+		if err != nil {
+			return "", err
+		}
+		return string(buf[:n]), nil
+	}
+	return json.Unquote(string(b))
+}
+
+// string 解析字符串，兼容 internal/json 包的 @ 前缀二次 quote 格式。
+func (d *decoder) string() (string, error) {
+	if d.null() {
+		return "", nil
+	}
+	b, err := d.stringBytes()
+	if err != nil {
+		return "", err
+	}
+	return json.Unquote(string(b))
+}
+
+// stringBytes 解析 JSON 字符串，没有转义字符时直接返回原始数据的切片。
+func (d *decoder) stringBytes() ([]byte, error) {
+	if err := d.expect('"'); err != nil {
+		return nil, err
+	}
+	start := d.pos
+	for d.pos < len(d.data) && !stopSet[d.data[d.pos]] {
+		d.pos++
+	}
+	if d.pos >= len(d.data) {
+		return nil, d.error("unexpected end of string")
+	}
+	switch d.data[d.pos] {
+	case '"':
+		d.pos++
+		return d.data[start : d.pos-1], nil
+	case '\\':
+		return d.unescape(start)
+	}
+	return nil, d.error("invalid character in string")
+}
+
+// stopSet 扫描 JSON 字符串时需要停下来处理的字符。
+var stopSet = func() (set [256]bool) {
+	for b := 0; b < 0x20; b++ {
+		set[b] = true
+	}
+	set['"'] = true
+	set['\\'] = true
+	return
+}()
+
+func (d *decoder) unescape(start int) ([]byte, error) {
+	// 先找到字符串的结尾，转义后的长度不会超过原始长度，这样只需分配一次内存。
+	end := d.pos
+	for end < len(d.data) && d.data[end] != '"' {
+		if d.data[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	b := make([]byte, d.pos-start, end-start)
+	copy(b, d.data[start:d.pos])
+	for d.pos < len(d.data) {
+		start = d.pos
+		for d.pos < len(d.data) && !stopSet[d.data[d.pos]] {
+			d.pos++
+		}
+		b = append(b, d.data[start:d.pos]...)
+		if d.pos >= len(d.data) {
+			break
+		}
+		c := d.data[d.pos]
+		switch {
+		case c == '"':
+			d.pos++
+			return b, nil
+		case c < 0x20:
+			return nil, d.error("invalid character in string")
+		}
+		if d.pos+1 >= len(d.data) {
+			break
+		}
+		d.pos++
+		switch c = d.data[d.pos]; c {
+		case '"', '\\', '/':
+			b = append(b, c)
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'u':
+			r, ok := d.hex4(d.pos + 1)
+			if !ok {
+				return nil, d.error("invalid unicode escape")
+			}
+			d.pos += 4
+			if utf16.IsSurrogate(r) {
+				if r2, ok := d.hex4(d.pos + 3); ok && d.data[d.pos+1] == '\\' && d.data[d.pos+2] == 'u' {
+					if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+						r = dec
+						d.pos += 6
+					} else {
+						r = utf8.RuneError
+					}
+				} else {
+					r = utf8.RuneError
+				}
+			}
+			b = appendRune(b, r)
+		default:
+			return nil, d.error("invalid escape character")
+		}
+		d.pos++
+	}
+	return nil, d.error("unexpected end of string")
+}
+
+// hexValues 十六进制字符对应的数值，非十六进制字符为 0xFF 。
+var hexValues = func() (values [256]byte) {
+	for i := range values {
+		values[i] = 0xFF
+	}
+	for i := 0; i < 16; i++ {
+		values[hexDigits[i]] = byte(i)
+		values["0123456789ABCDEF"[i]] = byte(i)
+	}
+	return
+}()
+
+func (d *decoder) hex4(i int) (rune, bool) {
+	if i+4 > len(d.data) {
+		return 0, false
+	}
+	var r rune
+	for _, c := range d.data[i : i+4] {
+		v := hexValues[c]
+		if v == 0xFF {
+			return 0, false
+		}
+		r = r<<4 | rune(v)
+	}
+	return r, true
+}
+
+func appendRune(b []byte, r rune) []byte {
+	if r < utf8.RuneSelf {
+		return append(b, byte(r))
+	}
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return append(b, buf[:n]...)
+}
+
+// skip 跳过任意 JSON 值。
+func (d *decoder) skip() error {
+	switch c := d.peek(); {
+	case c == '{':
+		return d.object(func([]byte) error { return d.skip() })
+	case c == '[':
+		return d.array(d.skip)
+	case c == '"':
+		_, err := d.stringBytes()
+		return err
+	case c == '-' || ('0' <= c && c <= '9'):
+		for d.pos < len(d.data) {
+			switch d.data[d.pos] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				return nil
+			}
+			d.pos++
+		}
+		return nil
+	}
+	for _, lit := range []string{"true", "false", "null"} {
+		if bytes.HasPrefix(d.data[d.pos:], []byte(lit)) {
+			d.pos += len(lit)
+			return nil
+		}
+	}
+	if d.pos >= len(d.data) {
+		return errors.New("fastdev: unexpected end of JSON input")
+	}
+	return d.error("invalid character")
+}
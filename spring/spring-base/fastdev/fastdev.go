@@ -21,8 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
+	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-base/fastdev/internal/json"
 	"github.com/google/uuid"
 )
@@ -32,11 +36,16 @@ const (
 	SQL   = "SQL"
 	REDIS = "REDIS"
 	APCU  = "APCU"
+	JOB   = "JOB" // 后台任务，例如定时任务的一次执行
 )
 
-var (
-	protocols = map[string]Protocol{}
-)
+var protocols = struct {
+	mutex    sync.RWMutex
+	data     map[string]Protocol
+	override bool // 是否允许覆盖已注册的协议。
+}{
+	data: map[string]Protocol{},
+}
 
 type Protocol interface {
 	ShouldDiff() bool
@@ -46,17 +55,54 @@ type Protocol interface {
 }
 
 func GetProtocol(name string) Protocol {
-	return protocols[name]
+	protocols.mutex.RLock()
+	defer protocols.mutex.RUnlock()
+	return protocols.data[name]
 }
 
+// RegisterProtocol 注册协议，默认情况下重复注册会 panic，开启覆盖模式后会使
+// 用新的协议替换旧的协议并输出一条警告信息。
 func RegisterProtocol(name string, protocol Protocol) {
-	if _, ok := protocols[name]; ok {
-		panic(fmt.Errorf("%s: duplicate registration", name))
+	protocols.mutex.Lock()
+	defer protocols.mutex.Unlock()
+	if _, ok := protocols.data[name]; ok {
+		if !protocols.override {
+			panic(fmt.Errorf("%s: duplicate registration", name))
+		}
+		fmt.Fprintf(os.Stderr, "[WARN] fastdev: protocol %s overridden\n", name)
 	}
-	protocols[name] = protocol
+	protocols.data[name] = protocol
+}
+
+// UnregisterProtocol 注销协议，协议存在时返回 true 。
+func UnregisterProtocol(name string) bool {
+	protocols.mutex.Lock()
+	defer protocols.mutex.Unlock()
+	_, ok := protocols.data[name]
+	delete(protocols.data, name)
+	return ok
 }
 
-// NewSessionID 使用 uuid 算法生成新的 Session ID 。
+// Protocols 返回所有已注册协议的名称，按照字母顺序排列。
+func Protocols() []string {
+	protocols.mutex.RLock()
+	defer protocols.mutex.RUnlock()
+	names := make([]string, 0, len(protocols.data))
+	for name := range protocols.data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetProtocolOverride 打开或者关闭协议的覆盖模式，便于测试和插件替换协议实现。
+func SetProtocolOverride(enable bool) {
+	protocols.mutex.Lock()
+	defer protocols.mutex.Unlock()
+	protocols.override = enable
+}
+
+// NewSessionID 使用 uuid 算法生成新的 Session ID，设置了租户时会添加租户前缀。
 func NewSessionID() string {
 	u := uuid.New()
 	buf := make([]byte, 32)
@@ -65,7 +111,7 @@ func NewSessionID() string {
 	hex.Encode(buf[12:16], u[6:8])
 	hex.Encode(buf[16:20], u[8:10])
 	hex.Encode(buf[20:], u[10:])
-	return string(buf)
+	return WithTenant(string(buf))
 }
 
 // CheckTestMode 检查是否是测试模式
@@ -78,6 +124,50 @@ func CheckTestMode() {
 	panic(errors.New("must call under test mode"))
 }
 
+// Base64Prefix 二进制消息使用 base64 编码时携带的前缀。
+const Base64Prefix = "@base64:"
+
+// isBinary 返回 s 是否包含非法的 UTF-8 字符或者除 \t \r \n 之外的控制字符。
+func isBinary(s string) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if (c < 0x20 && c != '\t' && c != '\r' && c != '\n') || c == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// EncodeMessage 对二进制消息 (以及以 Base64Prefix 开头的消息) 进行 base64
+// 编码并添加 Base64Prefix 前缀，其他消息保持不变，以保证消息可以准确还原。
+func EncodeMessage(s string) string {
+	if isBinary(s) || strings.HasPrefix(s, Base64Prefix) {
+		return Base64Prefix + cast.ToBase64([]byte(s))
+	}
+	return s
+}
+
+// DecodeMessage 自动识别 EncodeMessage 的编码结果并还原消息。
+func DecodeMessage(s string) (string, error) {
+	if !strings.HasPrefix(s, Base64Prefix) {
+		return s, nil
+	}
+	b, err := cast.FromBase64(s[len(Base64Prefix):])
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 type Message func() string
 
 func NewMessage(f func() string) Message {
@@ -89,12 +179,13 @@ func (msg Message) Data() string {
 }
 
 func (msg Message) MarshalJSON() ([]byte, error) {
-	return json.Marshal(msg())
+	return json.Marshal(EncodeMessage(msg()))
 }
 
 type Session struct {
 	Session   string    `json:",omitempty"` // 会话 ID
 	Timestamp int64     `json:",omitempty"` // 时间戳
+	Tags      []string  `json:",omitempty"` // 会话标签
 	Inbound   *Action   `json:",omitempty"` // 上游数据
 	Actions   []*Action `json:",omitempty"` // 动作数据
 }
@@ -116,10 +207,13 @@ func (session *Session) Pretty() (string, error) {
 }
 
 type Action struct {
-	Protocol  string  `json:",omitempty"` // 协议名称
-	Timestamp int64   `json:",omitempty"` // 时间戳
-	Request   Message `json:",omitempty"` // 请求内容
-	Response  Message `json:",omitempty"` // 响应内容
+	Protocol  string            `json:",omitempty"` // 协议名称
+	Timestamp int64             `json:",omitempty"` // 时间戳
+	Request   Message           `json:",omitempty"` // 请求内容
+	Response  Message           `json:",omitempty"` // 响应内容
+	Metadata  map[string]string `json:",omitempty"` // 附加信息，例如耗时
+	req, resp text              // SetRequest 和 SetResponse 复用的消息
+	pooled    bool              // 是否是从对象池中获取的对象
 }
 
 func (action *Action) String() (string, error) {
@@ -141,6 +235,7 @@ func (action *Action) Pretty() (string, error) {
 type RawSession struct {
 	Session   string       `json:",omitempty"` // 会话 ID
 	Timestamp int64        `json:",omitempty"` // 时间戳
+	Tags      []string     `json:",omitempty"` // 会话标签
 	Inbound   *RawAction   `json:",omitempty"` // 上游数据
 	Actions   []*RawAction `json:",omitempty"` // 动作数据
 }
@@ -162,10 +257,35 @@ func (session *RawSession) Pretty() (string, error) {
 }
 
 type RawAction struct {
-	Protocol  string `json:",omitempty"` // 协议名称
-	Timestamp int64  `json:",omitempty"` // 时间戳
-	Request   string `json:",omitempty"` // 请求内容
-	Response  string `json:",omitempty"` // 响应内容
+	Protocol  string            `json:",omitempty"` // 协议名称
+	Timestamp int64             `json:",omitempty"` // 时间戳
+	Request   string            `json:",omitempty"` // 请求内容
+	Response  string            `json:",omitempty"` // 响应内容
+	Metadata  map[string]string `json:",omitempty"` // 附加信息，例如耗时
+}
+
+type rawAction RawAction
+
+func (action RawAction) MarshalJSON() ([]byte, error) {
+	action.Request = EncodeMessage(action.Request)
+	action.Response = EncodeMessage(action.Response)
+	return json.Marshal(rawAction(action))
+}
+
+func (action *RawAction) UnmarshalJSON(data []byte) error {
+	var a rawAction
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	var err error
+	if a.Request, err = DecodeMessage(a.Request); err != nil {
+		return err
+	}
+	if a.Response, err = DecodeMessage(a.Response); err != nil {
+		return err
+	}
+	*action = RawAction(a)
+	return nil
 }
 
 func (action *RawAction) String() (string, error) {
@@ -0,0 +1,407 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fastdev_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/fastdev"
+)
+
+type protocol struct {
+	diff bool
+}
+
+func (p *protocol) ShouldDiff() bool {
+	return p.diff
+}
+
+func (p *protocol) GetLabel(data string) string {
+	return data
+}
+
+func (p *protocol) FlatRequest(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (p *protocol) FlatResponse(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func TestRegisterProtocol(t *testing.T) {
+
+	fastdev.RegisterProtocol(fastdev.REDIS, &protocol{})
+	fastdev.RegisterProtocol(fastdev.HTTP, &protocol{})
+	defer func() {
+		fastdev.UnregisterProtocol(fastdev.REDIS)
+		fastdev.UnregisterProtocol(fastdev.HTTP)
+	}()
+
+	assert.Equal(t, fastdev.Protocols(), []string{fastdev.HTTP, fastdev.REDIS})
+
+	assert.Panic(t, func() {
+		fastdev.RegisterProtocol(fastdev.REDIS, &protocol{})
+	}, "REDIS: duplicate registration")
+
+	fastdev.SetProtocolOverride(true)
+	fastdev.RegisterProtocol(fastdev.REDIS, &protocol{diff: true})
+	fastdev.SetProtocolOverride(false)
+	assert.True(t, fastdev.GetProtocol(fastdev.REDIS).ShouldDiff())
+
+	assert.True(t, fastdev.UnregisterProtocol(fastdev.REDIS))
+	assert.False(t, fastdev.UnregisterProtocol(fastdev.REDIS))
+	assert.Nil(t, fastdev.GetProtocol(fastdev.REDIS))
+	assert.Equal(t, fastdev.Protocols(), []string{fastdev.HTTP})
+}
+
+func TestMessage(t *testing.T) {
+
+	messages := []string{
+		"GET ...",
+		"line\r\n\tindent",
+		"\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n",
+		"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff",
+		fastdev.Base64Prefix + "text",
+	}
+
+	for _, msg := range messages {
+		data := msg
+		session := &fastdev.Session{
+			Session: "df3b64266ebe4e63a464e135000a07cd",
+			Inbound: &fastdev.Action{
+				Protocol: fastdev.HTTP,
+				Request:  fastdev.NewMessage(func() string { return data }),
+				Response: fastdev.NewMessage(func() string { return data }),
+			},
+		}
+		str, err := session.Pretty()
+		assert.Nil(t, err)
+		rawSession, err := fastdev.ToRawSession(str)
+		assert.Nil(t, err)
+		assert.Equal(t, rawSession.Inbound.Request, msg)
+		assert.Equal(t, rawSession.Inbound.Response, msg)
+		str, err = rawSession.String()
+		assert.Nil(t, err)
+		rawSession, err = fastdev.ToRawSession(str)
+		assert.Nil(t, err)
+		assert.Equal(t, rawSession.Inbound.Request, msg)
+	}
+
+	assert.Equal(t, fastdev.EncodeMessage("GET ..."), "GET ...")
+	assert.Equal(t, fastdev.EncodeMessage("\x00"), fastdev.Base64Prefix+"AA==")
+
+	_, err := fastdev.DecodeMessage(fastdev.Base64Prefix + "!")
+	assert.Error(t, err, "illegal base64 data")
+
+	rawSession, err := fastdev.ToRawSession(`{"Inbound":{"Request":"@\"\\x00\\xc0\""}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, rawSession.Inbound.Request, "\x00\xc0")
+}
+
+func TestAnonymize(t *testing.T) {
+
+	session := &fastdev.RawSession{
+		Session: "df3b64266ebe4e63a464e135000a07cd",
+		Inbound: &fastdev.RawAction{
+			Protocol: fastdev.HTTP,
+			Request:  "POST /user?email=tom@example.com&phone=13812345678",
+			Response: `{"email":"tom@example.com","friend":"jerry@example.com"}`,
+		},
+		Actions: []*fastdev.RawAction{
+			{
+				Protocol: fastdev.REDIS,
+				Request:  "HGET user:13812345678 email",
+				Response: "tom@example.com",
+			},
+		},
+	}
+
+	rules := fastdev.BuiltinAnonymizeRules(fastdev.Token)
+	fastdev.Anonymize(session, rules)
+	assert.Equal(t, session.Inbound.Request, "POST /user?email=email_1&phone=phone_1")
+	assert.Equal(t, session.Inbound.Response, `{"email":"email_1","friend":"email_2"}`)
+	assert.Equal(t, session.Actions[0].Request, "HGET user:phone_1 email")
+	assert.Equal(t, session.Actions[0].Response, "email_1")
+
+	rule, err := fastdev.NewAnonymizeRule("uid", `uid=[0-9]+`, fastdev.Hash)
+	assert.Nil(t, err)
+	a := fastdev.NewAnonymizer([]*fastdev.AnonymizeRule{rule})
+	s1 := a.String("GET /?uid=100")
+	s2 := a.String("GET /?uid=100&x=1")
+	assert.Matches(t, s1, "GET /\\?uid_[0-9a-f]{16}$")
+	assert.Equal(t, s2, s1+"&x=1")
+	assert.NotEqual(t, a.String("uid=101"), a.String("uid=100"))
+
+	// 没有设置盐时每个 Anonymizer 对象使用不同的随机盐。
+	b := fastdev.NewAnonymizer([]*fastdev.AnonymizeRule{rule})
+	assert.NotEqual(t, b.String("GET /?uid=100"), s1)
+
+	// 设置了盐时结果是确定的。
+	rule.Salt = "secret"
+	s3 := fastdev.NewAnonymizer([]*fastdev.AnonymizeRule{rule}).String("uid=100")
+	s4 := fastdev.NewAnonymizer([]*fastdev.AnonymizeRule{rule}).String("uid=100")
+	assert.Equal(t, s3, s4)
+	assert.Equal(t, s3, "uid_9dfa645ecdbd9217")
+
+	_, err = fastdev.NewAnonymizeRule("bad", "(", fastdev.Hash)
+	assert.Error(t, err, "missing closing")
+}
+
+func TestAnonymizer_Concurrent(t *testing.T) {
+
+	a := fastdev.NewAnonymizer(fastdev.BuiltinAnonymizeRules(fastdev.Token))
+
+	const n = 8
+	results := make([][]string, n)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			for j := 0; j < 1000; j++ {
+				s := fmt.Sprintf("u%d@example.com", (i+j)%n)
+				results[i] = append(results[i], a.String(s))
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// 相同的邮箱总是得到相同的令牌，不同的邮箱得到不同的令牌。
+	tokens := make(map[string]string)
+	for i := 0; i < n; i++ {
+		for j, token := range results[i] {
+			s := fmt.Sprintf("u%d@example.com", (i+j)%n)
+			if v, ok := tokens[s]; ok {
+				assert.Equal(t, token, v)
+			}
+			tokens[s] = token
+		}
+	}
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		assert.Matches(t, token, "^email_[1-8]$")
+		assert.False(t, seen[token])
+		seen[token] = true
+	}
+}
+
+type labelProtocol struct{}
+
+func (p *labelProtocol) ShouldDiff() bool {
+	return true
+}
+
+func (p *labelProtocol) GetLabel(data string) string {
+	return strings.SplitN(data, "?", 2)[0]
+}
+
+func (p *labelProtocol) FlatRequest(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (p *labelProtocol) FlatResponse(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func TestSearch(t *testing.T) {
+
+	fastdev.RegisterProtocol(fastdev.HTTP, &labelProtocol{})
+	defer fastdev.UnregisterProtocol(fastdev.HTTP)
+
+	dir, err := ioutil.TempDir("", "fastdev")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sessions := []*fastdev.RawSession{
+		{
+			Session:   "s1",
+			Timestamp: time.Unix(100, 0).UnixNano(),
+			Tags:      []string{"vip"},
+			Inbound:   &fastdev.RawAction{Protocol: fastdev.HTTP, Request: "POST /pay?id=1"},
+			Actions: []*fastdev.RawAction{
+				{Protocol: fastdev.HTTP, Request: "GET /user?id=1"},
+				{Protocol: fastdev.REDIS, Request: "HGET user 1"},
+			},
+		},
+		{
+			Session:   "s2",
+			Timestamp: time.Unix(200, 0).UnixNano(),
+			Inbound:   &fastdev.RawAction{Protocol: fastdev.HTTP, Request: "POST /pay?id=2"},
+		},
+		{
+			Session:   "s3",
+			Timestamp: time.Unix(300, 0).UnixNano(),
+			Tags:      []string{"vip"},
+			Inbound:   &fastdev.RawAction{Protocol: fastdev.HTTP, Request: "GET /order?id=3"},
+			Actions: []*fastdev.RawAction{
+				{Protocol: fastdev.REDIS, Request: "GET order 3"},
+			},
+		},
+	}
+
+	for i, s := range sessions {
+		str, err := s.Pretty()
+		assert.Nil(t, err)
+		sub := filepath.Join(dir, s.Session[:1])
+		err = os.MkdirAll(sub, os.ModePerm)
+		assert.Nil(t, err)
+		err = ioutil.WriteFile(filepath.Join(sub, sessions[i].Session+".json"), []byte(str), 0644)
+		assert.Nil(t, err)
+	}
+
+	_, err = fastdev.Search(dir, &fastdev.Query{})
+	assert.NotNil(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "package.json"), []byte(`["not a session"]`), 0644)
+	assert.Nil(t, err)
+
+	err = fastdev.BuildIndex(dir)
+	assert.Nil(t, err)
+
+	search := func(q *fastdev.Query) []string {
+		entries, err := fastdev.Search(dir, q)
+		assert.Nil(t, err)
+		var ret []string
+		for _, e := range entries {
+			ret = append(ret, e.Session)
+		}
+		return ret
+	}
+
+	assert.Equal(t, search(&fastdev.Query{}), []string{"s1", "s2", "s3"})
+	assert.Equal(t, search(&fastdev.Query{Inbound: "POST /pay"}), []string{"s1", "s2"})
+	assert.Equal(t, search(&fastdev.Query{Inbound: "POST *", Actions: []string{"REDIS"}}), []string{"s1"})
+	assert.Equal(t, search(&fastdev.Query{Actions: []string{"HTTP:GET /user"}}), []string{"s1"})
+	assert.Equal(t, search(&fastdev.Query{Tags: []string{"vip"}}), []string{"s1", "s3"})
+	assert.Equal(t, search(&fastdev.Query{Since: time.Unix(200, 0)}), []string{"s2", "s3"})
+	assert.Equal(t, search(&fastdev.Query{Until: time.Unix(200, 0)}), []string{"s1"})
+
+	entries, err := fastdev.Search(dir, &fastdev.Query{Inbound: "GET /order"})
+	assert.Nil(t, err)
+	assert.Equal(t, entries[0].File, "s/s3.json")
+	assert.Equal(t, entries[0].Actions, []string{"REDIS:GET order 3"})
+	assert.Equal(t, search(&fastdev.Query{Actions: []string{"REDIS:HGET *"}}), []string{"s1"})
+
+	files, err := filepath.Glob(filepath.Join(dir, fastdev.IndexFile+"*"))
+	assert.Nil(t, err)
+	assert.Equal(t, files, []string{filepath.Join(dir, fastdev.IndexFile)})
+}
+
+func TestAcquireAction(t *testing.T) {
+
+	stats := fastdev.ActionPoolStats()
+	session := &fastdev.Session{Session: "df3b64266ebe4e63a464e135000a07cd"}
+	for i := 0; i < 2; i++ {
+		action := fastdev.AcquireAction()
+		action.Protocol = fastdev.REDIS
+		action.SetRequest("GET a")
+		action.SetResponse("1")
+		session.Actions = append(session.Actions, action)
+	}
+
+	str, err := session.String()
+	assert.Nil(t, err)
+	assert.Equal(t, str, `{"Session":"df3b64266ebe4e63a464e135000a07cd","Actions":[{"Protocol":"REDIS","Request":"GET a","Response":"1"},{"Protocol":"REDIS","Request":"GET a","Response":"1"}]}`)
+
+	action := session.Actions[0]
+	action.SetRequest("GET b")
+	assert.Equal(t, action.Request.Data(), "GET b")
+
+	// 调用者自己创建的对象不会被归还对象池。
+	inbound := &fastdev.Action{Protocol: fastdev.HTTP}
+	session.Inbound = inbound
+
+	session.Release()
+	assert.Nil(t, session.Inbound)
+	assert.Equal(t, len(session.Actions), 0)
+	assert.Equal(t, action.Protocol, "")
+	assert.Nil(t, action.Request)
+	assert.Equal(t, inbound.Protocol, fastdev.HTTP)
+
+	// 重复归还的对象直接忽略。
+	fastdev.ReleaseAction(action)
+
+	s := fastdev.ActionPoolStats()
+	assert.Equal(t, s.Get-stats.Get, uint64(2))
+	assert.Equal(t, s.Put-stats.Put, uint64(2))
+}
+
+func BenchmarkAcquireAction(b *testing.B) {
+	// new-8       6768577  177.7 ns/op  144 B/op  3 allocs/op
+	// acquire-8  26564684  40.58 ns/op    0 B/op  0 allocs/op (含对象池计数开销)
+	request, response := "GET a", "1"
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			action := &fastdev.Action{
+				Protocol: fastdev.REDIS,
+				Request:  fastdev.NewMessage(func() string { return request }),
+				Response: fastdev.NewMessage(func() string { return response }),
+			}
+			benchmarkSink = action
+		}
+	})
+	b.Run("acquire", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			action := fastdev.AcquireAction()
+			action.Protocol = fastdev.REDIS
+			action.SetRequest(request)
+			action.SetResponse(response)
+			benchmarkSink = action
+			fastdev.ReleaseAction(action)
+		}
+	})
+}
+
+var benchmarkSink *fastdev.Action
+
+func TestTenant(t *testing.T) {
+
+	assert.Equal(t, fastdev.GetTenant(), "")
+	assert.Equal(t, fastdev.WithTenant("abc"), "abc")
+
+	err := fastdev.SetTenant("order.prod")
+	assert.Error(t, err, "invalid tenant \"order.prod\"")
+
+	err = fastdev.SetTenant("order-prod")
+	assert.Nil(t, err)
+	defer fastdev.SetTenant("")
+
+	assert.Equal(t, fastdev.WithTenant("abc"), "order-prod.abc")
+	assert.Equal(t, fastdev.WithTenant("pay.abc"), "pay.abc")
+	assert.Matches(t, fastdev.NewSessionID(), "^order-prod\\.[0-9a-f]{32}$")
+
+	tenant, id := fastdev.SplitTenant("order-prod.abc")
+	assert.Equal(t, tenant, "order-prod")
+	assert.Equal(t, id, "abc")
+
+	tenant, id = fastdev.SplitTenant("abc")
+	assert.Equal(t, tenant, "")
+	assert.Equal(t, id, "abc")
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fastdev
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-base/fastdev/internal/json"
+	"github.com/go-spring/spring-base/log"
+)
+
+// IndexFile 会话目录下的索引文件名，每行是一个 JSON 格式的 IndexEntry 。
+const IndexFile = "fastdev.index"
+
+// IndexEntry 会话文件的索引项。
+type IndexEntry struct {
+	File      string   `json:",omitempty"` // 相对于会话目录的文件路径
+	Session   string   `json:",omitempty"` // 会话 ID
+	Timestamp int64    `json:",omitempty"` // 时间戳
+	Inbound   string   `json:",omitempty"` // 上游请求的标签
+	Tags      []string `json:",omitempty"` // 会话标签
+	Actions   []string `json:",omitempty"` // 去重后的 "协议:标签" 列表
+}
+
+// NewIndexEntry 为会话文件创建索引项，标签由协议的 GetLabel 方法计算，协议
+// 未注册时使用请求内容的第一行作为标签。
+func NewIndexEntry(file string, session *RawSession) *IndexEntry {
+	e := &IndexEntry{
+		File:      file,
+		Session:   session.Session,
+		Timestamp: session.Timestamp,
+		Tags:      session.Tags,
+	}
+	if session.Inbound != nil {
+		e.Inbound = label(session.Inbound)
+	}
+	m := make(map[string]struct{})
+	for _, action := range session.Actions {
+		s := action.Protocol
+		if l := label(action); l != "" {
+			s += ":" + l
+		}
+		if _, ok := m[s]; !ok {
+			m[s] = struct{}{}
+			e.Actions = append(e.Actions, s)
+		}
+	}
+	sort.Strings(e.Actions)
+	return e
+}
+
+func label(action *RawAction) string {
+	if action.Request == "" {
+		return ""
+	}
+	if p := GetProtocol(action.Protocol); p != nil {
+		return p.GetLabel(action.Request)
+	}
+	return strings.TrimSpace(strings.SplitN(action.Request, "\n", 2)[0])
+}
+
+// BuildIndex 扫描 dir 目录 (包括子目录) 下所有的 .json 会话文件，生成索引文件。
+// 索引项逐条写入临时文件，全部成功之后再替换原来的索引文件，因此生成过程中
+// Search 仍然可以使用旧的索引。不能解析为会话的 .json 文件会被跳过。
+func BuildIndex(dir string) (err error) {
+	f, err := ioutil.TempFile(dir, IndexFile+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	w := bufio.NewWriter(f)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		session, err := ToRawSession(string(data))
+		if err != nil {
+			log.Warnf("skip %s when building index: %v", path, err)
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(NewIndexEntry(filepath.ToSlash(rel), session))
+		if err != nil {
+			return err
+		}
+		w.Write(b)
+		return w.WriteByte('\n')
+	})
+	if err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if err = f.Chmod(0644); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(dir, IndexFile))
+}
+
+// Query 会话的检索条件，所有非空的条件都需要满足。
+type Query struct {
+	Inbound string    // 上游请求的标签，支持 * 通配符，如 "POST /pay*"
+	Tags    []string  // 需要包含的全部标签
+	Actions []string  // 需要使用的全部 "协议" 或者 "协议:标签"，标签支持 * 通配符
+	Since   time.Time // 会话时间戳的下限 (包含)
+	Until   time.Time // 会话时间戳的上限 (不包含)
+}
+
+// Match 返回索引项是否满足检索条件。
+func (q *Query) Match(e *IndexEntry) bool {
+	if q.Inbound != "" && !WildcardMatch(q.Inbound, e.Inbound) {
+		return false
+	}
+	if !q.Since.IsZero() && e.Timestamp < q.Since.UnixNano() {
+		return false
+	}
+	if !q.Until.IsZero() && e.Timestamp >= q.Until.UnixNano() {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !containsString(e.Tags, tag) {
+			return false
+		}
+	}
+	for _, s := range q.Actions {
+		if !usesAction(e.Actions, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// usesAction 返回动作列表中是否存在 s 描述的动作，s 不包含标签时只比较协议。
+func usesAction(actions []string, s string) bool {
+	withLabel := strings.Contains(s, ":")
+	for _, a := range actions {
+		if withLabel {
+			if WildcardMatch(s, a) {
+				return true
+			}
+		} else if strings.SplitN(a, ":", 2)[0] == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Search 使用 dir 目录下的索引文件检索满足条件的会话，索引文件需要预先通过
+// BuildIndex 生成。
+func Search(dir string, query *Query) ([]*IndexEntry, error) {
+	f, err := os.Open(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ret []*IndexEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e *IndexEntry
+		if err = json.Unmarshal(line, &e); err != nil {
+			return nil, err
+		}
+		if query.Match(e) {
+			ret = append(ret, e)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// WildcardMatch 返回 s 是否和模式 p 匹配，模式中的 * 可以匹配任意字符串。
+func WildcardMatch(p, s string) bool {
+	star, next := -1, 0
+	i, j := 0, 0
+	for j < len(s) {
+		switch {
+		case i < len(p) && p[i] == '*':
+			star, next = i, j
+			i++
+		case i < len(p) && p[i] == s[j]:
+			i++
+			j++
+		case star >= 0:
+			next++
+			i, j = star+1, next
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
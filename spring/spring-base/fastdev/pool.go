@@ -0,0 +1,110 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fastdev
+
+import (
+	"github.com/go-spring/spring-base/util"
+)
+
+// 录制流量时每个请求都会创建 Action 对象和 Message 闭包，高 QPS 下会给 GC
+// 带来很大的压力，因此提供了基于 sync.Pool 的对象复用机制。
+//
+// 对象的所有权：AcquireAction 返回的对象归调用者所有，交给 RecordAction 或
+// RecordInbound 之后归会话所有；会话序列化 (落盘或者上传) 完成之后调用
+// Session.Release 将会话中的 Action 对象归还对象池，此后不能再使用这些对象
+// 以及它们的 Request 和 Response 消息。只有 AcquireAction 返回的对象才会被
+// 归还，调用者自己创建的 Action 对象不受影响。
+
+var actionPool = util.NewPool(func() *Action {
+	return new(Action)
+}, func(action *Action) {
+	action.Protocol = ""
+	action.Timestamp = 0
+	action.Request = nil
+	action.Response = nil
+	action.Metadata = nil
+	action.req.s = ""
+	action.resp.s = ""
+	action.pooled = false
+})
+
+// text 可复用的字符串消息，msg 是绑定到 get 方法上的闭包，只在第一次使用
+// 时创建，之后随 Action 对象一起复用。
+type text struct {
+	s   string
+	msg Message
+}
+
+func (t *text) get() string {
+	return t.s
+}
+
+func (t *text) message(s string) Message {
+	if t.msg == nil {
+		t.msg = t.get
+	}
+	t.s = s
+	return t.msg
+}
+
+// AcquireAction 从对象池中获取一个空的 Action 对象。
+func AcquireAction() *Action {
+	action := actionPool.Get()
+	action.pooled = true
+	return action
+}
+
+// ReleaseAction 将 AcquireAction 返回的 Action 对象重置后归还对象池，不是从对象
+// 池中获取的对象以及已经归还的对象直接忽略。
+func ReleaseAction(action *Action) {
+	if action == nil || !action.pooled {
+		return
+	}
+	actionPool.Put(action)
+}
+
+// SetRequest 使用字符串设置请求内容，复用 Action 对象自带的消息，不会创建新
+// 的闭包。
+func (action *Action) SetRequest(s string) {
+	action.Request = action.req.message(s)
+}
+
+// SetResponse 使用字符串设置响应内容，复用 Action 对象自带的消息，不会创建新
+// 的闭包。
+func (action *Action) SetResponse(s string) {
+	action.Response = action.resp.message(s)
+}
+
+// ActionPoolStats 返回 Action 对象池的统计数据。
+func ActionPoolStats() util.PoolStats {
+	return actionPool.Stats()
+}
+
+// Release 将会话中从对象池获取的 Action 对象归还对象池，必须在会话序列化完成
+// 之后调用，调用之后不能再使用会话中的 Action 对象。
+func (session *Session) Release() {
+	if session == nil {
+		return
+	}
+	ReleaseAction(session.Inbound)
+	for i, action := range session.Actions {
+		ReleaseAction(action)
+		session.Actions[i] = nil
+	}
+	session.Inbound = nil
+	session.Actions = session.Actions[:0]
+}
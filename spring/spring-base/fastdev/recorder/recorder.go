@@ -21,37 +21,59 @@ import (
 	"errors"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-base/chrono"
 	"github.com/go-spring/spring-base/fastdev"
 	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/run"
 )
 
 func init() {
 	if cast.ToBool(os.Getenv("GS_FASTDEV_RECORD")) {
-		recorder.mode = true
+		run.SetMode(run.Record)
 	}
+	chrono.OnWatchStop(func(ctx context.Context, name string, elapsed time.Duration) {
+		if RecordMode() {
+			_ = RecordTiming(ctx, name, elapsed)
+		}
+	})
 }
 
 const (
 	sessionIDKey = "::RECORD-SESSION-ID::"
 )
 
+// TimingPrefix 耗时信息在 Action.Metadata 中的 key 前缀。
+const TimingPrefix = "timing."
+
+// Sink 接收录制完成的会话，例如 agent.Client 的 Upload 方法。
+type Sink func(session *fastdev.Session) error
+
 var recorder struct {
-	mode bool     // 是否为录制模式。
 	data sync.Map // 正在录制的数据。
+	sink Sink     // 录制完成的会话的去处。
+}
+
+// SetSink 设置录制完成的会话的去处，需要在开始录制之前设置。
+func SetSink(sink Sink) {
+	recorder.sink = sink
 }
 
 // RecordMode 返回是否是录制模式。
 func RecordMode() bool {
-	return recorder.mode
+	return run.RecordMode()
 }
 
 // SetRecordMode 打开或者关闭录制模式，仅用于单元测试。
 func SetRecordMode(mode bool) {
 	fastdev.CheckTestMode()
-	recorder.mode = mode
+	if mode {
+		run.SetMode(run.Record)
+	} else if run.RecordMode() {
+		run.SetMode(run.Normal)
+	}
 }
 
 type recordSession struct {
@@ -61,7 +83,7 @@ type recordSession struct {
 }
 
 func onSession(ctx context.Context, f func(*recordSession) error) error {
-	if !recorder.mode {
+	if !RecordMode() {
 		return errors.New("record mode not enabled")
 	}
 	v, ok := knife.Get(ctx, sessionIDKey)
@@ -95,7 +117,10 @@ func StartRecord(ctx context.Context, sessionID string) error {
 	return nil
 }
 
-// StopRecord 停止流量录制
+// StopRecord 停止流量录制。设置了 Sink 时将会话发送给 Sink ，发送成功之后将
+// 会话中的 Action 对象归还对象池并返回 nil ，因此 Sink 返回之后不能再持有会话；
+// 发送失败时同时返回会话和错误。未设置 Sink 或者发送失败时，返回的会话由调用者
+// 在序列化完成之后调用 Session.Release 归还对象池。
 func StopRecord(ctx context.Context) (*fastdev.Session, error) {
 	var ret *fastdev.Session
 	err := onSession(ctx, func(r *recordSession) error {
@@ -107,7 +132,14 @@ func StopRecord(ctx context.Context) (*fastdev.Session, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ret, nil
+	if recorder.sink == nil {
+		return ret, nil
+	}
+	if err = recorder.sink(ret); err != nil {
+		return ret, err
+	}
+	ret.Release()
+	return nil, nil
 }
 
 // RecordInbound 录制 inbound 流量。
@@ -125,7 +157,8 @@ func RecordInbound(ctx context.Context, inbound *fastdev.Action) error {
 	})
 }
 
-// RecordAction 录制 outbound 流量。
+// RecordAction 录制 outbound 流量，调用成功之后 action 归会话所有，返回错误时
+// 仍归调用者所有。可以使用 fastdev.AcquireAction 从对象池中获取 action 对象。
 func RecordAction(ctx context.Context, action *fastdev.Action) error {
 	return onSession(ctx, func(r *recordSession) error {
 		if r.close {
@@ -136,3 +169,40 @@ func RecordAction(ctx context.Context, action *fastdev.Action) error {
 		return nil
 	})
 }
+
+// RecordTags 为正在录制的会话添加标签，标签可以用于会话的检索。
+func RecordTags(ctx context.Context, tags ...string) error {
+	return onSession(ctx, func(r *recordSession) error {
+		if r.close {
+			return errors.New("recording already stopped")
+		}
+		r.session.Tags = append(r.session.Tags, tags...)
+		return nil
+	})
+}
+
+// RecordMetadata 为最近录制的 outbound 流量添加附加信息。
+func RecordMetadata(ctx context.Context, key, value string) error {
+	return onSession(ctx, func(r *recordSession) error {
+		if r.close {
+			return errors.New("recording already stopped")
+		}
+		n := len(r.session.Actions)
+		if n == 0 || r.session.Actions[n-1] == nil {
+			return errors.New("no action recorded")
+		}
+		action := r.session.Actions[n-1]
+		if action.Metadata == nil {
+			action.Metadata = make(map[string]string)
+		}
+		action.Metadata[key] = value
+		return nil
+	})
+}
+
+// RecordTiming 将耗时 (纳秒) 记录到最近录制的 outbound 流量的附加信息中，key
+// 为 TimingPrefix 加上 name 。录制模式下 chrono.Watch 停止时会自动调用该方法，
+// 因此只需在调用下游的前后启动和停止秒表即可。
+func RecordTiming(ctx context.Context, name string, elapsed time.Duration) error {
+	return RecordMetadata(ctx, TimingPrefix+name, cast.ToString(int64(elapsed)))
+}
@@ -22,11 +22,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-spring/spring-base/assert"
 	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-base/chrono"
 	"github.com/go-spring/spring-base/fastdev"
 	"github.com/go-spring/spring-base/fastdev/recorder"
 	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/require"
 )
 
 func TestRecordAction(t *testing.T) {
@@ -39,15 +41,11 @@ func TestRecordAction(t *testing.T) {
 	timeNow := time.Unix(1643364150, 0)
 	ctx, _ := knife.New(context.Background())
 	err := chrono.SetBaseTime(ctx, timeNow)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	sessionID := "df3b64266ebe4e63a464e135000a07cd"
 	err = recorder.StartRecord(ctx, sessionID)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	err = recorder.RecordAction(ctx, &fastdev.Action{
 		Protocol: fastdev.REDIS,
@@ -58,9 +56,7 @@ func TestRecordAction(t *testing.T) {
 			return cast.ToCSV("\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n")
 		}),
 	})
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	err = recorder.RecordAction(ctx, &fastdev.Action{
 		Protocol: fastdev.REDIS,
@@ -71,9 +67,7 @@ func TestRecordAction(t *testing.T) {
 			return cast.ToCSV("1", 2, "3")
 		}),
 	})
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	err = recorder.RecordInbound(ctx, &fastdev.Action{
 		Protocol: fastdev.HTTP,
@@ -84,25 +78,17 @@ func TestRecordAction(t *testing.T) {
 			return "200 ..."
 		}),
 	})
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	s, err := recorder.StopRecord(ctx)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	str, err := s.Pretty()
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 	fmt.Println("got:", str)
 
 	s1, err := fastdev.ToRawSession(str)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 	fmt.Print("json(got): ")
 	fmt.Println(s1.Pretty())
 
@@ -132,9 +118,82 @@ func TestRecordAction(t *testing.T) {
 	}`
 
 	s2, err := fastdev.ToRawSession(expect)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 	fmt.Print("json(expect): ")
 	fmt.Println(s2.Pretty())
 }
+
+func TestRecordTiming(t *testing.T) {
+
+	recorder.SetRecordMode(true)
+	defer func() {
+		recorder.SetRecordMode(false)
+	}()
+
+	ctx, _ := knife.New(context.Background())
+	c := chrono.NewMockClock(time.Unix(1643364150, 0))
+	assert.Nil(t, chrono.SetClock(ctx, c))
+
+	err := recorder.StartRecord(ctx, fastdev.NewSessionID())
+	assert.Nil(t, err)
+
+	err = recorder.RecordTiming(ctx, "redis", time.Millisecond)
+	assert.Error(t, err, "no action recorded")
+
+	w := chrono.StartWatch(ctx, "redis")
+	c.Advance(3 * time.Millisecond)
+	action := fastdev.AcquireAction()
+	action.Protocol = fastdev.REDIS
+	action.SetRequest("GET a")
+	action.SetResponse("1")
+	assert.Nil(t, recorder.RecordAction(ctx, action))
+	w.Stop()
+
+	assert.Nil(t, recorder.RecordMetadata(ctx, "host", "127.0.0.1"))
+
+	s, err := recorder.StopRecord(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Actions[0].Metadata, map[string]string{
+		"timing.redis": "3000000",
+		"host":         "127.0.0.1",
+	})
+
+	str, err := s.String()
+	assert.Nil(t, err)
+	assert.Matches(t, str, `"Metadata":\{"host":"127.0.0.1","timing.redis":"3000000"\}`)
+	s.Release()
+}
+
+func TestStopRecord_Sink(t *testing.T) {
+
+	recorder.SetRecordMode(true)
+	defer func() {
+		recorder.SetSink(nil)
+		recorder.SetRecordMode(false)
+	}()
+
+	var data string
+	recorder.SetSink(func(s *fastdev.Session) error {
+		str, err := s.String()
+		data = str
+		return err
+	})
+
+	ctx, _ := knife.New(context.Background())
+	err := recorder.StartRecord(ctx, "df3b64266ebe4e63a464e135000a07cd")
+	assert.Nil(t, err)
+
+	action := fastdev.AcquireAction()
+	action.Protocol = fastdev.REDIS
+	action.SetRequest("GET a")
+	action.SetResponse("1")
+	assert.Nil(t, recorder.RecordAction(ctx, action))
+
+	stats := fastdev.ActionPoolStats()
+	s, err := recorder.StopRecord(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, s)
+	assert.Matches(t, data, `"Actions":\[\{"Protocol":"REDIS","Timestamp":\d+,"Request":"GET a","Response":"1"\}\]`)
+	assert.Equal(t, action.Protocol, "")
+	assert.Equal(t, fastdev.ActionPoolStats().Put-stats.Put, uint64(1))
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replayer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/internal/json"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
+)
+
+// Result 会话回放完成后的结果。
+type Result struct {
+	Session  string        `json:",omitempty"` // 会话 ID
+	Pass     bool          // 是否回放通过
+	Diffs    []string      `json:",omitempty"` // 差异摘要
+	Duration time.Duration // 回放耗时
+}
+
+// Callback 会话回放完成时的回调函数。
+type Callback func(ctx context.Context, result *Result) error
+
+var callbacks struct {
+	mutex sync.RWMutex
+	list  []Callback
+}
+
+// OnComplete 注册会话回放完成时的回调函数，ReplayInbound 返回之前启动新的
+// goroutine 按照注册的顺序依次执行回调函数，因此回调函数不会阻塞回放，返回的
+// 错误也只输出到日志。
+func OnComplete(f Callback) {
+	callbacks.mutex.Lock()
+	defer callbacks.mutex.Unlock()
+	callbacks.list = append(callbacks.list, f)
+}
+
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+// Webhook 返回一个将回放结果以 JSON 格式 POST 到 url 的回调函数。
+func Webhook(url string) Callback {
+	return func(ctx context.Context, result *Result) error {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := webhookClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = ioutil.ReadAll(resp.Body)
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("webhook %s return status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// complete 会话回放完成，在新的 goroutine 中执行所有的回调函数。回放的请求
+// 结束之后 ctx 可能被取消，因此回调函数使用独立的 context 。
+func complete(r *replayData) {
+
+	callbacks.mutex.RLock()
+	list := callbacks.list
+	callbacks.mutex.RUnlock()
+
+	if len(list) == 0 {
+		return
+	}
+
+	result := newResult(r)
+	util.SafeGo(context.Background(), func(ctx context.Context) {
+		for _, f := range list {
+			if err := f(ctx, result); err != nil {
+				log.Errorf("session %s replay callback error: %v", result.Session, err)
+			}
+		}
+	})
+}
+
+// Report 返回 sessionID 对应会话当前的回放结果。
+func Report(sessionID string) (*Result, error) {
+	v, ok := replayer.data.Load(sessionID)
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	return newResult(v.(*replayData)), nil
+}
+
+func newResult(r *replayData) *Result {
+	diffs := diffSession(r)
+	return &Result{
+		Session:  r.session.Session,
+		Pass:     len(diffs) == 0,
+		Diffs:    diffs,
+		Duration: time.Since(r.start),
+	}
+}
+
+// diffSession 返回回放数据和录制数据之间的差异摘要。
+func diffSession(r *replayData) []string {
+	var diffs []string
+	for i, action := range r.session.Actions {
+		if _, ok := r.matched.Load(action); !ok {
+			diffs = append(diffs, fmt.Sprintf("action[%d] %s not replayed", i, action.Protocol))
+		}
+	}
+	inbound := r.session.Inbound
+	if inbound == nil {
+		return diffs
+	}
+	for _, d := range diffResponse(inbound) {
+		diffs = append(diffs, "inbound "+d)
+	}
+	return diffs
+}
+
+// diffResponse 比较 action 录制的响应和回放的响应，优先使用协议提供的展平方
+// 法逐个 key 进行比较，展平失败时退化为比较整个字符串。
+func diffResponse(action *Action) []string {
+	if action.Response == action.RecResponse {
+		return nil
+	}
+	if p := fastdev.GetProtocol(action.Protocol); p != nil {
+		expect, err1 := p.FlatResponse(action.Response)
+		got, err2 := p.FlatResponse(action.RecResponse)
+		if err1 == nil && err2 == nil && (len(expect) > 0 || len(got) > 0) {
+			return diffFlat(expect, got)
+		}
+	}
+	return []string{fmt.Sprintf("response expect %q but got %q", action.Response, action.RecResponse)}
+}
+
+func diffFlat(expect, got map[string]string) []string {
+	keys := make(map[string]struct{})
+	for k := range expect {
+		keys[k] = struct{}{}
+	}
+	for k := range got {
+		keys[k] = struct{}{}
+	}
+	var diffs []string
+	for k := range keys {
+		v1, ok1 := expect[k]
+		v2, ok2 := got[k]
+		switch {
+		case !ok1:
+			diffs = append(diffs, fmt.Sprintf("%s unexpected %q", k, v2))
+		case !ok2:
+			diffs = append(diffs, fmt.Sprintf("%s missing %q", k, v1))
+		case v1 != v2:
+			diffs = append(diffs, fmt.Sprintf("%s expect %q but got %q", k, v1, v2))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replayer
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/go-spring/spring-base/fastdev"
+)
+
+var matchPatterns struct {
+	mutex sync.RWMutex
+	data  map[string][]map[string]string
+}
+
+// AddMatchPattern 为协议添加一个模糊匹配模式，如 REDIS 协议的 `SET user:* *`。
+// 模式使用协议的 FlatRequest 方法展平，展平后的每个值中的 * 可以匹配任意字符
+// 串。当回放请求和录制请求展平后的 key 与模式完全相同，并且所有的值都能和模式
+// 匹配时，就认为二者是匹配的，这样 key 中包含每次运行都会变化的内容时也能匹配。
+func AddMatchPattern(protocol string, pattern string) error {
+	p := fastdev.GetProtocol(protocol)
+	if p == nil {
+		return errors.New("invalid protocol")
+	}
+	m, err := p.FlatRequest(pattern)
+	if err != nil {
+		return err
+	}
+	if len(m) == 0 {
+		return errors.New("empty pattern")
+	}
+	matchPatterns.mutex.Lock()
+	defer matchPatterns.mutex.Unlock()
+	if matchPatterns.data == nil {
+		matchPatterns.data = make(map[string][]map[string]string)
+	}
+	matchPatterns.data[protocol] = append(matchPatterns.data[protocol], m)
+	return nil
+}
+
+// ClearMatchPatterns 清除协议的所有模糊匹配模式。
+func ClearMatchPatterns(protocol string) {
+	matchPatterns.mutex.Lock()
+	defer matchPatterns.mutex.Unlock()
+	delete(matchPatterns.data, protocol)
+}
+
+func getMatchPatterns(protocol string) []map[string]string {
+	matchPatterns.mutex.RLock()
+	defer matchPatterns.mutex.RUnlock()
+	return matchPatterns.data[protocol]
+}
+
+// matcher 用于判断回放请求和录制请求是否匹配。
+type matcher struct {
+	p        fastdev.Protocol
+	request  string
+	flat     map[string]string
+	patterns []map[string]string
+}
+
+func newMatcher(p fastdev.Protocol, protocol string, request string) *matcher {
+	m := &matcher{p: p, request: request}
+	for _, pattern := range getMatchPatterns(protocol) {
+		if m.flat == nil {
+			flat, err := p.FlatRequest(request)
+			if err != nil {
+				return m
+			}
+			m.flat = flat
+		}
+		if matchFlat(pattern, m.flat) {
+			m.patterns = append(m.patterns, pattern)
+		}
+	}
+	return m
+}
+
+// match 返回录制请求是否和回放请求匹配。
+func (m *matcher) match(recorded string) bool {
+	if recorded == m.request {
+		return true
+	}
+	if len(m.patterns) == 0 {
+		return false
+	}
+	flat, err := m.p.FlatRequest(recorded)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range m.patterns {
+		if matchFlat(pattern, flat) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFlat 返回展平后的请求是否和展平后的模式匹配。
+func matchFlat(pattern, flat map[string]string) bool {
+	if len(pattern) != len(flat) {
+		return false
+	}
+	for k, p := range pattern {
+		v, ok := flat[k]
+		if !ok || !fastdev.WildcardMatch(p, v) {
+			return false
+		}
+	}
+	return true
+}
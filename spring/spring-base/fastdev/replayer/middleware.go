@@ -0,0 +1,52 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replayer
+
+import (
+	"net/http"
+
+	"github.com/go-spring/spring-base/knife"
+)
+
+// DefaultSessionHeader 默认传递回放会话 ID 的请求头。
+const DefaultSessionHeader = "REPLAY-SESSION-ID"
+
+// Middleware 返回一个 http 中间件，回放模式下从 header 指定的请求头中获取
+// 回放会话 ID，初始化 knife 并绑定会话 ID，这样外部驱动注入的回放流量可以
+// 自动路由到对应的录制会话。header 为空时使用 DefaultSessionHeader 。
+func Middleware(header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultSessionHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ReplayMode() {
+				if sessionID := r.Header.Get(header); sessionID != "" {
+					ctx, cached := knife.New(r.Context())
+					if err := SetSessionID(ctx, sessionID); err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+						return
+					}
+					if !cached {
+						r = r.WithContext(ctx)
+					}
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -19,19 +19,22 @@ package replayer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-base/chrono"
 	"github.com/go-spring/spring-base/fastdev"
 	"github.com/go-spring/spring-base/fastdev/internal/json"
 	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/run"
 )
 
 func init() {
 	if cast.ToBool(os.Getenv("GS_FASTDEV_REPLAY")) {
-		replayer.mode = true
+		run.SetMode(run.Replay)
 	}
 }
 
@@ -39,20 +42,33 @@ const (
 	sessionIDKey = "REPLAY-SESSION-ID"
 )
 
+// Source 根据会话 ID 获取录制的会话，例如 agent.Client 的 Fetch 方法。
+type Source func(sessionID string) (*fastdev.RawSession, error)
+
 var replayer struct {
-	mode bool     // 是否是回放模式。
-	data sync.Map // 正在回放的数据。
+	data   sync.Map // 正在回放的数据。
+	source Source   // 回放数据的来源。
 }
 
 // ReplayMode 返回是否是回放模式。
 func ReplayMode() bool {
-	return replayer.mode
+	return run.ReplayMode()
+}
+
+// SetSource 设置回放数据的来源，回放时如果会话没有通过 Store 存储，则从
+// Source 中获取，需要在开始回放之前设置。
+func SetSource(source Source) {
+	replayer.source = source
 }
 
 // SetReplayMode 打开或者关闭回放模式，仅用于单元测试。
 func SetReplayMode(mode bool) {
 	fastdev.CheckTestMode()
-	replayer.mode = mode
+	if mode {
+		run.SetMode(run.Replay)
+	} else if run.ReplayMode() {
+		run.SetMode(run.Normal)
+	}
 }
 
 type Session struct {
@@ -102,6 +118,7 @@ type Action struct {
 	RecResponse     string            `json:",omitempty"` // 响应内容
 	RecFlatRequest  map[string]string `json:",omitempty"` // 请求内容
 	RecFlatResponse map[string]string `json:",omitempty"` // 响应内容
+	Metadata        map[string]string `json:",omitempty"` // 附加信息，例如耗时
 }
 
 func (action *Action) Flat() error {
@@ -165,16 +182,21 @@ func ToSession(session *fastdev.RawSession) (*Session, error) {
 }
 
 func ToAction(action *fastdev.RawAction) *Action {
+	if action == nil {
+		return nil
+	}
 	return &Action{
 		Protocol:  action.Protocol,
 		Timestamp: action.Timestamp,
 		Request:   action.Request,
 		Response:  action.Response,
+		Metadata:  action.Metadata,
 	}
 }
 
 type replayData struct {
 	session *Session
+	start   time.Time
 	matched sync.Map
 	actions map[string]map[string][]*Action
 }
@@ -182,7 +204,7 @@ type replayData struct {
 // Store 存储 sessionID 对应的回放数据。
 func Store(session *Session) error {
 
-	r := &replayData{session: session}
+	r := &replayData{session: session, start: time.Now()}
 	_, loaded := replayer.data.LoadOrStore(session.Session, r)
 	if loaded {
 		return errors.New("session already stored")
@@ -206,13 +228,30 @@ func Store(session *Session) error {
 	return nil
 }
 
+// SkipActions 将 sessionID 对应的回放数据中前 n 个动作标记为已消费，回放时
+// 只会匹配剩余的动作，常用于调试时只重新回放长会话中失败的后半部分。
+func SkipActions(sessionID string, n int) error {
+	v, ok := replayer.data.Load(sessionID)
+	if !ok {
+		return errors.New("session not found")
+	}
+	r := v.(*replayData)
+	if n < 0 || n > len(r.session.Actions) {
+		return fmt.Errorf("action index %d out of range [0,%d]", n, len(r.session.Actions))
+	}
+	for _, action := range r.session.Actions[:n] {
+		r.matched.Store(action, true)
+	}
+	return nil
+}
+
 // Delete 删除 sessionID 对应的回放数据。
 func Delete(sessionID string) {
 	replayer.data.Delete(sessionID)
 }
 
 func GetSessionID(ctx context.Context) (string, error) {
-	if !replayer.mode {
+	if !ReplayMode() {
 		return "", errors.New("replay mode not enabled")
 	}
 	v, ok := knife.Get(ctx, sessionIDKey)
@@ -237,11 +276,37 @@ func getReplayData(ctx context.Context) (*replayData, error) {
 	}
 	v, ok := replayer.data.Load(sessionID)
 	if !ok {
-		return nil, errors.New("session not found")
+		if replayer.source == nil {
+			return nil, errors.New("session not found")
+		}
+		if v, err = fetch(sessionID); err != nil {
+			return nil, err
+		}
 	}
 	return v.(*replayData), nil
 }
 
+// fetch 从 Source 中获取会话并存储，并发获取同一个会话时只会存储一次。
+func fetch(sessionID string) (interface{}, error) {
+	rawSession, err := replayer.source(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := ToSession(rawSession)
+	if err != nil {
+		return nil, err
+	}
+	session.Session = sessionID
+	if err = Store(session); err != nil {
+		if v, ok := replayer.data.Load(sessionID); ok {
+			return v, nil
+		}
+		return nil, err
+	}
+	v, _ := replayer.data.Load(sessionID)
+	return v, nil
+}
+
 func ReplayInbound(ctx context.Context, response string) error {
 	r, err := getReplayData(ctx)
 	if err != nil {
@@ -250,6 +315,7 @@ func ReplayInbound(ctx context.Context, response string) error {
 	r.session.Inbound.RecResponse = response
 	r.session.Inbound.RecRequest = r.session.Inbound.Request
 	r.session.Inbound.RecTimestamp = chrono.Now(ctx).UnixNano()
+	complete(r)
 	return nil
 }
 
@@ -271,8 +337,9 @@ func ReplayAction(ctx context.Context, protocol string, request string) (*Action
 	}
 
 	label := p.GetLabel(request)
+	matcher := newMatcher(p, protocol, request)
 	for _, action := range m[label] {
-		if action.Request != request { // TODO 改为模糊匹配方式
+		if !matcher.match(action.Request) {
 			continue
 		}
 		if _, loaded := r.matched.LoadOrStore(action, true); loaded {
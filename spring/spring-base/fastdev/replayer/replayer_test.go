@@ -18,7 +18,11 @@ package replayer_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/go-spring/spring-base/assert"
@@ -27,6 +31,7 @@ import (
 	"github.com/go-spring/spring-base/fastdev"
 	"github.com/go-spring/spring-base/fastdev/replayer"
 	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/require"
 )
 
 func init() {
@@ -44,9 +49,7 @@ func TestReplayAction(t *testing.T) {
 	sessionID := "39fc5c13443f47da9ff320cc4b02c789"
 	ctx, _ := knife.New(context.Background())
 	err := replayer.SetSessionID(ctx, sessionID)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	recordSession := &fastdev.Session{
 		Session:   sessionID,
@@ -95,28 +98,20 @@ func TestReplayAction(t *testing.T) {
 	}
 
 	str, err := recordSession.Pretty()
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 	fmt.Println("record:", str)
 
 	rawSession, err := fastdev.ToRawSession(str)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	session, err := replayer.ToSession(rawSession)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	fmt.Print("json(record): ")
 	fmt.Println(session.Pretty())
 
 	err = replayer.Store(session)
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	{
 		var (
@@ -145,18 +140,216 @@ func TestReplayAction(t *testing.T) {
 	}
 
 	err = replayer.ReplayInbound(ctx, "200 ...")
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	err = session.Flat()
-	if err != nil {
-		t.Fatal(err)
-	}
+	require.Nil(t, err)
 
 	fmt.Println(session.Pretty())
 }
 
+func TestOnComplete(t *testing.T) {
+
+	replayer.SetReplayMode(true)
+	defer func() {
+		replayer.SetReplayMode(false)
+	}()
+
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies <- string(b)
+	}))
+	defer server.Close()
+
+	const sessionID = "0c3a0bd1a7b34bdd8e1c1b2f2a3d6e01"
+	results := make(chan *replayer.Result, 1)
+	replayer.OnComplete(func(ctx context.Context, r *replayer.Result) error {
+		if r.Session == sessionID {
+			results <- r
+		}
+		return nil
+	})
+	// 回调函数返回的错误不会影响回放的结果。
+	replayer.OnComplete(func(ctx context.Context, r *replayer.Result) error {
+		if r.Session == sessionID {
+			return errors.New("callback error")
+		}
+		return nil
+	})
+	webhook := replayer.Webhook(server.URL)
+	replayer.OnComplete(func(ctx context.Context, r *replayer.Result) error {
+		if r.Session == sessionID {
+			return webhook(ctx, r)
+		}
+		return nil
+	})
+
+	ctx, _ := knife.New(context.Background())
+	err := replayer.SetSessionID(ctx, sessionID)
+	assert.Nil(t, err)
+
+	session := &replayer.Session{
+		Session: sessionID,
+		Inbound: &replayer.Action{
+			Protocol: fastdev.HTTP,
+			Request:  "GET ...",
+			Response: "200 ...",
+		},
+		Actions: []*replayer.Action{
+			{
+				Protocol: fastdev.REDIS,
+				Request:  cast.ToCommandLine("SET", "a", "1"),
+				Response: cast.ToCSV("OK"),
+			},
+			{
+				Protocol: fastdev.REDIS,
+				Request:  cast.ToCommandLine("GET", "a"),
+				Response: cast.ToCSV("1"),
+			},
+		},
+	}
+	err = replayer.Store(session)
+	assert.Nil(t, err)
+	defer replayer.Delete(sessionID)
+
+	action, err := replayer.ReplayAction(ctx, fastdev.REDIS, cast.ToCommandLine("SET", "a", "1"))
+	assert.Nil(t, err)
+	assert.NotNil(t, action)
+
+	err = replayer.ReplayInbound(ctx, "500 ...")
+	assert.Nil(t, err)
+
+	result := <-results
+	assert.False(t, result.Pass)
+	assert.Equal(t, result.Diffs, []string{
+		"action[1] REDIS not replayed",
+		`inbound response expect "200 ..." but got "500 ..."`,
+	})
+	assert.True(t, result.Duration > 0)
+	assert.Matches(t, <-bodies, `"Session":"0c3a0bd1a7b34bdd8e1c1b2f2a3d6e01","Pass":false`)
+}
+
+func TestAddMatchPattern(t *testing.T) {
+
+	replayer.SetReplayMode(true)
+	defer func() {
+		replayer.SetReplayMode(false)
+	}()
+
+	err := replayer.AddMatchPattern(fastdev.REDIS, "SET user:* *")
+	assert.Nil(t, err)
+	defer replayer.ClearMatchPatterns(fastdev.REDIS)
+
+	const sessionID = "5d1e5f0c8f2a4ad6a1c4b8f5e0a7b9c3"
+	ctx, _ := knife.New(context.Background())
+	err = replayer.SetSessionID(ctx, sessionID)
+	assert.Nil(t, err)
+
+	err = replayer.Store(&replayer.Session{
+		Session: sessionID,
+		Actions: []*replayer.Action{
+			{
+				Protocol: fastdev.REDIS,
+				Request:  cast.ToCommandLine("SET", "user:1643364150", "a"),
+				Response: cast.ToCSV("OK"),
+			},
+			{
+				Protocol: fastdev.REDIS,
+				Request:  cast.ToCommandLine("SET", "item:1643364150", "b"),
+				Response: cast.ToCSV("OK"),
+			},
+		},
+	})
+	assert.Nil(t, err)
+	defer replayer.Delete(sessionID)
+
+	action, err := replayer.ReplayAction(ctx, fastdev.REDIS, cast.ToCommandLine("SET", "item:1643364999", "b"))
+	assert.Nil(t, err)
+	assert.Nil(t, action)
+
+	action, err = replayer.ReplayAction(ctx, fastdev.REDIS, cast.ToCommandLine("SET", "user:1643364999", "c"))
+	assert.Nil(t, err)
+	assert.NotNil(t, action)
+	assert.Equal(t, action.Request, cast.ToCommandLine("SET", "user:1643364150", "a"))
+
+	action, err = replayer.ReplayAction(ctx, fastdev.REDIS, cast.ToCommandLine("SET", "user:1643364999", "c"))
+	assert.Nil(t, err)
+	assert.Nil(t, action)
+
+	err = replayer.AddMatchPattern("UNKNOWN", "*")
+	assert.Error(t, err, "invalid protocol")
+}
+
+func TestSkipActions(t *testing.T) {
+
+	replayer.SetReplayMode(true)
+	defer func() {
+		replayer.SetReplayMode(false)
+	}()
+
+	const sessionID = "a3c1f2e4d5b6478899aabbccddeeff00"
+	ctx, _ := knife.New(context.Background())
+	err := replayer.SetSessionID(ctx, sessionID)
+	assert.Nil(t, err)
+
+	request := cast.ToCommandLine("INCR", "a")
+	err = replayer.Store(&replayer.Session{
+		Session: sessionID,
+		Actions: []*replayer.Action{
+			{Protocol: fastdev.REDIS, Request: request, Response: cast.ToCSV(1)},
+			{Protocol: fastdev.REDIS, Request: request, Response: cast.ToCSV(2)},
+			{Protocol: fastdev.REDIS, Request: request, Response: cast.ToCSV(3)},
+		},
+	})
+	assert.Nil(t, err)
+	defer replayer.Delete(sessionID)
+
+	err = replayer.SkipActions(sessionID, 4)
+	assert.Error(t, err, "action index 4 out of range \\[0,3\\]")
+
+	err = replayer.SkipActions(sessionID, 2)
+	assert.Nil(t, err)
+
+	action, err := replayer.ReplayAction(ctx, fastdev.REDIS, request)
+	assert.Nil(t, err)
+	assert.Equal(t, action.Response, cast.ToCSV(3))
+
+	action, err = replayer.ReplayAction(ctx, fastdev.REDIS, request)
+	assert.Nil(t, err)
+	assert.Nil(t, action)
+
+	err = replayer.SkipActions("not-exist", 0)
+	assert.Error(t, err, "session not found")
+}
+
+func TestMiddleware(t *testing.T) {
+
+	var sessionID string
+	handler := replayer.Middleware("X-Replay-Session")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID, _ = replayer.GetSessionID(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Replay-Session", "39fc5c13443f47da9ff320cc4b02c789")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, sessionID, "")
+
+	replayer.SetReplayMode(true)
+	defer func() {
+		replayer.SetReplayMode(false)
+	}()
+
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, sessionID, "39fc5c13443f47da9ff320cc4b02c789")
+
+	sessionID = ""
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(replayer.DefaultSessionHeader, "39fc5c13443f47da9ff320cc4b02c789")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, sessionID, "")
+}
+
 type httpProtocol struct{}
 
 func (p *httpProtocol) ShouldDiff() bool {
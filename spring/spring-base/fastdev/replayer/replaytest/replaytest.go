@@ -0,0 +1,133 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package replaytest 将录制的流量文件转换为单元测试用例。
+package replaytest
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/replayer"
+	"github.com/go-spring/spring-base/knife"
+)
+
+// update 使用带包名前缀的参数名，避免和引用该包的测试自己定义的 -update 参数冲突。
+var update = flag.Bool("replaytest.update", false, "update golden session files")
+
+// Handler 处理会话的上游请求并返回响应，处理过程中的下游请求需要通过
+// replayer.ReplayAction 获取录制的响应。
+type Handler func(ctx context.Context, request string) (string, error)
+
+// Run 加载 dir 目录下所有的 .json 会话文件，每个文件作为一个子测试进行回放，
+// 回放结果和录制结果存在差异时子测试失败。使用 -replaytest.update 参数运行测试
+// 时，会用本次回放得到的上游响应覆盖会话文件中录制的上游响应。
+func Run(t *testing.T, dir string, handler Handler) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+
+	if !replayer.ReplayMode() {
+		replayer.SetReplayMode(true)
+		defer replayer.SetReplayMode(false)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			runFile(t, file, handler)
+		})
+	}
+}
+
+func runFile(t *testing.T, file string, handler Handler) {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawSession, err := fastdev.ToRawSession(string(data))
+	if err != nil {
+		t.Fatalf("unmarshal file %s error %s", file, err.Error())
+	}
+	if rawSession.Inbound == nil {
+		t.Fatalf("inbound not found in file %s", file)
+	}
+
+	session, err := replayer.ToSession(rawSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = replayer.Store(session); err != nil {
+		t.Fatal(err)
+	}
+	defer replayer.Delete(session.Session)
+
+	ctx, _ := knife.New(context.Background())
+	if err = replayer.SetSessionID(ctx, session.Session); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := handler(ctx, session.Inbound.Request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = replayer.ReplayInbound(ctx, response); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := replayer.Report(session.Session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		rawSession.Inbound.Response = response
+		var str string
+		if str, err = rawSession.Pretty(); err != nil {
+			t.Fatal(err)
+		}
+		var info os.FileInfo
+		if info, err = os.Stat(file); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(file, []byte(str+"\n"), info.Mode()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, d := range result.Diffs {
+		if *update && strings.HasPrefix(d, "inbound ") {
+			continue
+		}
+		t.Error(d)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replaytest_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/cast"
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/replayer"
+	"github.com/go-spring/spring-base/fastdev/replayer/replaytest"
+)
+
+// 引用 replaytest 包的测试可以定义自己的 -update 参数。
+var _ = flag.Bool("update", false, "update golden files")
+
+func init() {
+	fastdev.RegisterProtocol(fastdev.HTTP, &httpProtocol{})
+	fastdev.RegisterProtocol(fastdev.REDIS, &redisProtocol{})
+}
+
+func TestRun(t *testing.T) {
+	replaytest.Run(t, "testdata/sessions", func(ctx context.Context, request string) (string, error) {
+		u, err := url.Parse(strings.TrimPrefix(request, "GET "))
+		if err != nil {
+			return "", err
+		}
+		cmd := strings.ToUpper(strings.TrimPrefix(u.Path, "/"))
+		action, err := replayer.ReplayAction(ctx, fastdev.REDIS, cast.ToCommandLine(cmd, u.Query().Get("key")))
+		if err != nil {
+			return "", err
+		}
+		if action == nil {
+			return "", errors.New("action not found")
+		}
+		return "200 " + action.Response, nil
+	})
+}
+
+type httpProtocol struct{}
+
+func (p *httpProtocol) ShouldDiff() bool {
+	return true
+}
+
+func (p *httpProtocol) GetLabel(data string) string {
+	return data[:4]
+}
+
+func (p *httpProtocol) FlatRequest(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (p *httpProtocol) FlatResponse(data string) (map[string]string, error) {
+	return nil, nil
+}
+
+type redisProtocol struct{}
+
+func (p *redisProtocol) ShouldDiff() bool {
+	return true
+}
+
+func (p *redisProtocol) GetLabel(data string) string {
+	return data[:3]
+}
+
+func (p *redisProtocol) FlatRequest(data string) (map[string]string, error) {
+	csv, err := cast.ParseCommandLine(data)
+	if err != nil {
+		return nil, err
+	}
+	return cast.FlatSlice(csv), nil
+}
+
+func (p *redisProtocol) FlatResponse(data string) (map[string]string, error) {
+	csv, err := cast.ParseCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	return cast.FlatSlice(csv), nil
+}
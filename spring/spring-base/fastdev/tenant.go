@@ -0,0 +1,77 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fastdev
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// TenantEnv 设置租户的环境变量。
+const TenantEnv = "GS_SPRING_FASTDEV_TENANT"
+
+// tenantSeparator 租户和会话 ID 之间的分隔符，租户名称中不能包含该字符。
+const tenantSeparator = "."
+
+var tenant atomic.Value
+
+// init 从环境变量中读取租户。包初始化时还不能输出日志或者返回错误，因此无效
+// 的值被忽略，由应用启动时校验 spring.fastdev.tenant 属性 (环境变量会被映射为
+// 该属性) 并返回错误。
+func init() {
+	if s, ok := os.LookupEnv(TenantEnv); ok {
+		_ = SetTenant(s)
+	}
+}
+
+// GetTenant 返回当前的租户，多个服务或者环境共享同一个会话存储时使用租户
+// 区分各自的会话。
+func GetTenant() string {
+	s, _ := tenant.Load().(string)
+	return s
+}
+
+// SetTenant 设置当前的租户，租户只能包含字母、数字、下划线和中划线，空字符串
+// 表示不使用租户。
+func SetTenant(s string) error {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return fmt.Errorf("invalid tenant %q", s)
+		}
+	}
+	tenant.Store(s)
+	return nil
+}
+
+// WithTenant 为会话 ID 添加当前租户的前缀，已经携带租户前缀时保持不变。
+func WithTenant(sessionID string) string {
+	t := GetTenant()
+	if t == "" || strings.Contains(sessionID, tenantSeparator) {
+		return sessionID
+	}
+	return t + tenantSeparator + sessionID
+}
+
+// SplitTenant 将会话 ID 拆分为租户和不带前缀的会话 ID 。
+func SplitTenant(sessionID string) (tenant string, id string) {
+	if i := strings.Index(sessionID, tenantSeparator); i >= 0 {
+		return sessionID[:i], sessionID[i+1:]
+	}
+	return "", sessionID
+}
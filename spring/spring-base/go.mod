@@ -1,6 +1,6 @@
 module github.com/go-spring/spring-base
 
-go 1.14
+go 1.18
 
 require (
 	github.com/golang/mock v1.6.0
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-spring/spring-base/atomic"
+)
+
+// ParseLevel 解析日志级别的名称，不区分大小写。
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return TraceLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "panic":
+		return PanicLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// packageLevel 包级别的日志输出级别。
+type packageLevel struct {
+	pkg   string
+	level Level
+}
+
+// packageLevels 按照包路径长度降序排列的 []packageLevel 。
+var packageLevels atomic.Value
+
+// callerPackages 缓存调用点所在的包，key 是调用点的 pc 。
+var callerPackages sync.Map
+
+// SetPackageLevels 设置包级别的日志输出级别，替换原来的全部设置。key 是包路径，
+// 对该包及其子包生效，多个包路径匹配时使用最长的那个，都不匹配时使用全局级别。
+// 注意 EnableDebug 等方法只判断全局级别。
+func SetPackageLevels(levels map[string]Level) {
+	var s []packageLevel
+	for pkg, level := range levels {
+		s = append(s, packageLevel{pkg: strings.TrimSuffix(pkg, "/"), level: level})
+	}
+	sort.Slice(s, func(i, j int) bool { return len(s[i].pkg) > len(s[j].pkg) })
+	packageLevels.Store(s)
+}
+
+// PackageLevels 返回包级别的日志输出级别。
+func PackageLevels() map[string]Level {
+	s, _ := packageLevels.Load().([]packageLevel)
+	m := make(map[string]Level)
+	for _, l := range s {
+		m[l.pkg] = l.level
+	}
+	return m
+}
+
+// enabled 返回调用点是否允许输出 level 级别的日志，只能在 output 和 outputf
+// 中调用，skip 和 Entry.GetSkip 的含义相同。
+func enabled(level Level, skip int) bool {
+	s, _ := packageLevels.Load().([]packageLevel)
+	if len(s) == 0 {
+		return GetLevel() <= level
+	}
+	pkg := callerPackage(skip + 4)
+	for _, l := range s {
+		if pkg == l.pkg || strings.HasPrefix(pkg, l.pkg+"/") {
+			return l.level <= level
+		}
+	}
+	return GetLevel() <= level
+}
+
+// callerPackage 返回调用点所在的包路径。
+func callerPackage(skip int) string {
+	rpc := make([]uintptr, 1)
+	if runtime.Callers(skip+1, rpc) < 1 {
+		return ""
+	}
+	if v, ok := callerPackages.Load(rpc[0]); ok {
+		return v.(string)
+	}
+	frame, _ := runtime.CallersFrames(rpc).Next()
+	pkg := funcPackage(frame.Function)
+	callerPackages.Store(rpc[0], pkg)
+	return pkg
+}
+
+// funcPackage 从 github.com/a/b.(*T).M 这样的函数全名中解析出包路径。
+func funcPackage(fn string) string {
+	i := strings.LastIndex(fn, "/")
+	if j := strings.Index(fn[i+1:], "."); j >= 0 {
+		return fn[:i+1+j]
+	}
+	return fn
+}
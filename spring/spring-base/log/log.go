@@ -45,10 +45,18 @@ var config = struct {
 	level:  *atomic.NewUint32(uint32(InfoLevel)),
 }
 
+func init() {
+	// SafeGo 等方法启动的 goroutine 发生 panic 时输出 PANIC 级别的日志。
+	util.OnPanic(func(ctx context.Context, r interface{}, stack []byte) {
+		Ctx(ctx).Panicf("%v\n%s", r, stack)
+	})
+}
+
 // Reset 恢复默认的日志输出配置。
 func Reset() {
 	SetOutput(Console)
 	SetLevel(InfoLevel)
+	SetPackageLevels(nil)
 }
 
 const (
@@ -156,7 +164,7 @@ func do(level Level, e Entry, args []interface{}) {
 }
 
 func output(level Level, e Entry, args []interface{}) {
-	if GetLevel() > level {
+	if !enabled(level, e.GetSkip()) {
 		return
 	}
 	if len(args) == 1 {
@@ -168,7 +176,7 @@ func output(level Level, e Entry, args []interface{}) {
 }
 
 func outputf(level Level, e Entry, format string, args []interface{}) {
-	if GetLevel() > level {
+	if !enabled(level, e.GetSkip()) {
 		return
 	}
 	if len(args) == 1 {
@@ -17,8 +17,10 @@
 package log
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -292,3 +294,66 @@ func TestSkip(t *testing.T) {
 		Skip(1).Infof(format, args...)
 	}("log skip test")
 }
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("DEBUG")
+	assert.Nil(t, err)
+	assert.Equal(t, level, DebugLevel)
+	level, err = ParseLevel(" warning ")
+	assert.Nil(t, err)
+	assert.Equal(t, level, WarnLevel)
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err, "unknown log level \"verbose\"")
+}
+
+func TestPackageLevels(t *testing.T) {
+
+	var buf bytes.Buffer
+	SetOutput(PatternOutput("%l %m", &buf))
+	defer Reset()
+
+	assert.Equal(t, funcPackage("github.com/go-spring/spring-base/log.(*Message).Reuse"), "github.com/go-spring/spring-base/log")
+	assert.Equal(t, funcPackage("main.main"), "main")
+
+	SetLevel(ErrorLevel)
+	SetPackageLevels(map[string]Level{
+		"github.com/go-spring":                 WarnLevel,
+		"github.com/go-spring/spring-base/log": DebugLevel,
+	})
+	assert.Equal(t, PackageLevels(), map[string]Level{
+		"github.com/go-spring":                 WarnLevel,
+		"github.com/go-spring/spring-base/log": DebugLevel,
+	})
+	Trace("a")
+	Debug("b")
+	Tag("t").Infof("c%d", 1)
+	Ctx(context.Background()).Debug("d")
+
+	SetPackageLevels(map[string]Level{"github.com/go-spring": WarnLevel})
+	Info("e")
+	Warn("f")
+	SetPackageLevels(map[string]Level{"github.com/go-spring/spring-base/logger": TraceLevel})
+	Warn("g")
+	Error("h")
+	assert.Equal(t, buf.String(), "DEBUG b\nINFO c1\nDEBUG d\nWARN f\nERROR h\n")
+}
+
+func TestPatternOutput(t *testing.T) {
+
+	fixedTime, _ := time.Parse(time.RFC3339, "2021-06-01T10:20:30Z")
+	ctx, _ := knife.New(context.Background())
+	assert.Nil(t, chrono.SetFixedTime(ctx, fixedTime))
+
+	var buf bytes.Buffer
+	SetOutput(PatternOutput("%d|%l|%t|%m|100%%|%x", &buf))
+	defer Reset()
+
+	Ctx(ctx).Tag("__in").Info("a", "=", 1)
+	assert.Equal(t, buf.String(), "2021-06-01T10:20:30.000|INFO|__in|a=1|100%|%x\n")
+
+	buf.Reset()
+	SetOutput(PatternOutput("", &buf))
+	line := code.Line() + 1
+	Ctx(ctx).Warn("b")
+	assert.Equal(t, buf.String(), fmt.Sprintf("[WARN][2021-06-01T10:20:30.000][%s:%d] b\n", code.File(), line))
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-spring/spring-base/cast"
+)
+
+// DefaultPattern PatternOutput 默认的日志格式。
+const DefaultPattern = "[%l][%d][%f] %m"
+
+// patternOutput 按照 pattern 格式化日志的 Output 。
+type patternOutput struct {
+	pattern string
+	mutex   sync.Mutex
+	w       io.Writer
+}
+
+// PatternOutput 按照 pattern 格式化日志并写入 w ，每条日志占一行。pattern 支持
+// %d (时间)、%l (级别)、%f (文件名和行号)、%t (标签)、%m (消息) 以及 %% ，
+// pattern 为空时使用 DefaultPattern 。
+func PatternOutput(pattern string, w io.Writer) Output {
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	return &patternOutput{pattern: pattern, w: w}
+}
+
+func (o *patternOutput) Do(level Level, msg *Message) {
+	defer func() { msg.Reuse() }()
+	var buf bytes.Buffer
+	for i := 0; i < len(o.pattern); i++ {
+		c := o.pattern[i]
+		if c != '%' || i == len(o.pattern)-1 {
+			buf.WriteByte(c)
+			continue
+		}
+		i++
+		switch o.pattern[i] {
+		case 'd':
+			buf.WriteString(msg.Time().Format("2006-01-02T15:04:05.000"))
+		case 'l':
+			buf.WriteString(strings.ToUpper(level.String()))
+		case 'f':
+			buf.WriteString(msg.File())
+			buf.WriteByte(':')
+			buf.WriteString(strconv.Itoa(msg.Line()))
+		case 't':
+			buf.WriteString(msg.Tag())
+		case 'm':
+			for _, a := range msg.Args() {
+				buf.WriteString(cast.ToString(a))
+			}
+		case '%':
+			buf.WriteByte('%')
+		default:
+			buf.WriteByte('%')
+			buf.WriteByte(o.pattern[i])
+		}
+	}
+	buf.WriteByte('\n')
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	_, _ = o.w.Write(buf.Bytes())
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package require 提供和 assert 相同的断言函数，区别在于断言失败时调用
+// t.FailNow() 终止测试。
+package require
+
+import (
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+)
+
+// T testing.T 的简化接口。
+type T interface {
+	assert.T
+	FailNow()
+}
+
+// True 同 assert.True ，断言失败时终止测试。
+func True(t T, got bool, msg ...string) {
+	t.Helper()
+	if !assert.True(t, got, msg...) {
+		t.FailNow()
+	}
+}
+
+// False 同 assert.False ，断言失败时终止测试。
+func False(t T, got bool, msg ...string) {
+	t.Helper()
+	if !assert.False(t, got, msg...) {
+		t.FailNow()
+	}
+}
+
+// Nil 同 assert.Nil ，断言失败时终止测试。
+func Nil(t T, got interface{}, msg ...string) {
+	t.Helper()
+	if !assert.Nil(t, got, msg...) {
+		t.FailNow()
+	}
+}
+
+// NotNil 同 assert.NotNil ，断言失败时终止测试。
+func NotNil(t T, got interface{}, msg ...string) {
+	t.Helper()
+	if !assert.NotNil(t, got, msg...) {
+		t.FailNow()
+	}
+}
+
+// Equal 同 assert.Equal ，断言失败时终止测试。
+func Equal(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.Equal(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// NotEqual 同 assert.NotEqual ，断言失败时终止测试。
+func NotEqual(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.NotEqual(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// Same 同 assert.Same ，断言失败时终止测试。
+func Same(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.Same(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// NotSame 同 assert.NotSame ，断言失败时终止测试。
+func NotSame(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.NotSame(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// Panic 同 assert.Panic ，断言失败时终止测试。
+func Panic(t T, fn func(), expr string, msg ...string) {
+	t.Helper()
+	if !assert.Panic(t, fn, expr, msg...) {
+		t.FailNow()
+	}
+}
+
+// Panics 同 assert.Panics ，断言失败时终止测试。
+func Panics(t T, fn func(), msg ...string) {
+	t.Helper()
+	if !assert.Panics(t, fn, msg...) {
+		t.FailNow()
+	}
+}
+
+// NotPanics 同 assert.NotPanics ，断言失败时终止测试。
+func NotPanics(t T, fn func(), msg ...string) {
+	t.Helper()
+	if !assert.NotPanics(t, fn, msg...) {
+		t.FailNow()
+	}
+}
+
+// PanicsWithValue 同 assert.PanicsWithValue ，断言失败时终止测试。
+func PanicsWithValue(t T, fn func(), expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.PanicsWithValue(t, fn, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// Eventually 同 assert.Eventually ，断言失败时终止测试。
+func Eventually(t T, cond func() bool, timeout, interval time.Duration, msg ...string) {
+	t.Helper()
+	if !assert.Eventually(t, cond, timeout, interval, msg...) {
+		t.FailNow()
+	}
+}
+
+// Never 同 assert.Never ，断言失败时终止测试。
+func Never(t T, cond func() bool, timeout, interval time.Duration, msg ...string) {
+	t.Helper()
+	if !assert.Never(t, cond, timeout, interval, msg...) {
+		t.FailNow()
+	}
+}
+
+// Matches 同 assert.Matches ，断言失败时终止测试。
+func Matches(t T, got string, expr string, msg ...string) {
+	t.Helper()
+	if !assert.Matches(t, got, expr, msg...) {
+		t.FailNow()
+	}
+}
+
+// Error 同 assert.Error ，断言失败时终止测试。
+func Error(t T, got error, expr string, msg ...string) {
+	t.Helper()
+	if !assert.Error(t, got, expr, msg...) {
+		t.FailNow()
+	}
+}
+
+// ErrorIs 同 assert.ErrorIs ，断言失败时终止测试。
+func ErrorIs(t T, got error, target error, msg ...string) {
+	t.Helper()
+	if !assert.ErrorIs(t, got, target, msg...) {
+		t.FailNow()
+	}
+}
+
+// ErrorAs 同 assert.ErrorAs ，断言失败时终止测试。
+func ErrorAs(t T, got error, target interface{}, msg ...string) {
+	t.Helper()
+	if !assert.ErrorAs(t, got, target, msg...) {
+		t.FailNow()
+	}
+}
+
+// ErrorContains 同 assert.ErrorContains ，断言失败时终止测试。
+func ErrorContains(t T, got error, substr string, msg ...string) {
+	t.Helper()
+	if !assert.ErrorContains(t, got, substr, msg...) {
+		t.FailNow()
+	}
+}
+
+// TypeOf 同 assert.TypeOf ，断言失败时终止测试。
+func TypeOf(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.TypeOf(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// Implements 同 assert.Implements ，断言失败时终止测试。
+func Implements(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.Implements(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// JSONEqual 同 assert.JSONEqual ，断言失败时终止测试。
+func JSONEqual(t T, got string, expect string, msg ...string) {
+	t.Helper()
+	if !assert.JSONEqual(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// JSONSubset 同 assert.JSONSubset ，断言失败时终止测试。
+func JSONSubset(t T, got string, expect string, msg ...string) {
+	t.Helper()
+	if !assert.JSONSubset(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// Len 同 assert.Len ，断言失败时终止测试。
+func Len(t T, got interface{}, length int, msg ...string) {
+	t.Helper()
+	if !assert.Len(t, got, length, msg...) {
+		t.FailNow()
+	}
+}
+
+// Contains 同 assert.Contains ，断言失败时终止测试。
+func Contains(t T, got interface{}, elem interface{}, msg ...string) {
+	t.Helper()
+	if !assert.Contains(t, got, elem, msg...) {
+		t.FailNow()
+	}
+}
+
+// NotContains 同 assert.NotContains ，断言失败时终止测试。
+func NotContains(t T, got interface{}, elem interface{}, msg ...string) {
+	t.Helper()
+	if !assert.NotContains(t, got, elem, msg...) {
+		t.FailNow()
+	}
+}
+
+// ElementsMatch 同 assert.ElementsMatch ，断言失败时终止测试。
+func ElementsMatch(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.ElementsMatch(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// SubsetOf 同 assert.SubsetOf ，断言失败时终止测试。
+func SubsetOf(t T, got interface{}, expect interface{}, msg ...string) {
+	t.Helper()
+	if !assert.SubsetOf(t, got, expect, msg...) {
+		t.FailNow()
+	}
+}
+
+// That 同 assert.That ，断言失败时终止测试。
+func That(t T, got interface{}, m assert.Matcher, msg ...string) {
+	t.Helper()
+	if !assert.That(t, got, m, msg...) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package require_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/require"
+)
+
+type fakeT struct {
+	logs    []string
+	failed  bool
+	stopped bool
+}
+
+func (t *fakeT) Helper()                 {}
+func (t *fakeT) Fail()                   { t.failed = true }
+func (t *fakeT) Log(args ...interface{}) { t.logs = append(t.logs, fmt.Sprint(args...)) }
+func (t *fakeT) FailNow()                { t.failed = true; t.stopped = true }
+
+func TestRequire(t *testing.T) {
+
+	{
+		g := &fakeT{}
+		require.Nil(g, nil)
+		require.Equal(g, 1, 1)
+		require.ErrorContains(g, errors.New("an error"), "error")
+		assert.False(t, g.failed)
+		assert.False(t, g.stopped)
+		assert.Nil(t, g.logs)
+	}
+
+	{
+		g := &fakeT{}
+		require.Nil(g, errors.New("an error"), "param (index=0)")
+		assert.True(t, g.failed)
+		assert.True(t, g.stopped)
+		assert.Equal(t, g.logs, []string{"got (*errors.errorString) an error but expect nil; param (index=0)"})
+	}
+
+	{
+		g := &fakeT{}
+		require.JSONEqual(g, `{"a":1}`, `{"a":2}`)
+		assert.True(t, g.stopped)
+	}
+}
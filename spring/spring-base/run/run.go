@@ -0,0 +1,107 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package run 提供了程序运行模式的唯一来源，录制、回放等子系统都通过该包
+// 判断当前的运行模式，避免各自维护的开关之间出现不一致。
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-spring/spring-base/atomic"
+)
+
+// ModeEnv 设置运行模式的环境变量。
+const ModeEnv = "GS_SPRING_RUN_MODE"
+
+// Mode 程序的运行模式。
+type Mode int32
+
+const (
+	Normal = Mode(iota) // 正常模式
+	Record              // 流量录制模式
+	Replay              // 流量回放模式
+	Test                // 测试模式
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Normal:
+		return "normal"
+	case Record:
+		return "record"
+	case Replay:
+		return "replay"
+	case Test:
+		return "test"
+	}
+	return ""
+}
+
+// ParseMode 解析运行模式，不区分大小写，空字符串表示正常模式。
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "normal":
+		return Normal, nil
+	case "record":
+		return Record, nil
+	case "replay":
+		return Replay, nil
+	case "test":
+		return Test, nil
+	}
+	return Normal, fmt.Errorf("invalid run mode %q", s)
+}
+
+var mode atomic.Int32
+
+// init 从环境变量中读取运行模式。包初始化时还不能输出日志或者返回错误，因此
+// 无效的值被忽略，运行模式保持为正常模式，由应用启动时校验 spring.run.mode
+// 属性 (环境变量会被映射为该属性) 并返回错误。
+func init() {
+	if s, ok := os.LookupEnv(ModeEnv); ok {
+		if m, err := ParseMode(s); err == nil {
+			SetMode(m)
+		}
+	}
+}
+
+// GetMode 返回当前的运行模式。
+func GetMode() Mode {
+	return Mode(mode.Load())
+}
+
+// SetMode 设置当前的运行模式。
+func SetMode(m Mode) {
+	mode.Store(int32(m))
+}
+
+// RecordMode 返回是否是流量录制模式。
+func RecordMode() bool {
+	return GetMode() == Record
+}
+
+// ReplayMode 返回是否是流量回放模式。
+func ReplayMode() bool {
+	return GetMode() == Replay
+}
+
+// TestMode 返回是否是测试模式。
+func TestMode() bool {
+	return GetMode() == Test
+}
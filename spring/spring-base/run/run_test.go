@@ -0,0 +1,48 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package run_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/run"
+)
+
+func TestMode(t *testing.T) {
+
+	assert.Equal(t, run.GetMode(), run.Normal)
+
+	for _, m := range []run.Mode{run.Normal, run.Record, run.Replay, run.Test} {
+		v, err := run.ParseMode(m.String())
+		assert.Nil(t, err)
+		assert.Equal(t, v, m)
+	}
+
+	m, err := run.ParseMode(" Replay ")
+	assert.Nil(t, err)
+	assert.Equal(t, m, run.Replay)
+
+	_, err = run.ParseMode("debug")
+	assert.Error(t, err, "invalid run mode \"debug\"")
+
+	run.SetMode(run.Record)
+	defer run.SetMode(run.Normal)
+	assert.True(t, run.RecordMode())
+	assert.False(t, run.ReplayMode())
+	assert.False(t, run.TestMode())
+}
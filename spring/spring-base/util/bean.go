@@ -18,6 +18,11 @@ package util
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
 )
 
 // CopyBean 使用 JSON 序列化的方式进行拷贝，支持匿名字段，支持类型转换。
@@ -28,3 +33,317 @@ func CopyBean(src interface{}, dest interface{}) error {
 	}
 	return json.Unmarshal(bytes, dest)
 }
+
+// DeepCopy 使用反射的方式将 src 深拷贝到 dest 指向的对象，支持指针、切片、
+// map 、私有字段以及循环引用，time.Time 、函数和通道等保持浅拷贝。src 可以
+// 是 T 或者 *T ，dest 必须是 *T ，类型不一致时退化为 CopyBean 。
+func DeepCopy(src interface{}, dest interface{}) error {
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return errors.New("dest should be a non-nil pointer")
+	}
+
+	sv := reflect.ValueOf(src)
+	if !sv.IsValid() {
+		dv.Elem().Set(reflect.Zero(dv.Elem().Type()))
+		return nil
+	}
+
+	c := &copier{visited: make(map[visitKey]reflect.Value)}
+	if sv.Type() == dv.Type() {
+		if sv.IsNil() {
+			dv.Elem().Set(reflect.Zero(dv.Elem().Type()))
+			return nil
+		}
+		c.visited[visitKey{sv.Pointer(), sv.Type()}] = dv
+		sv = sv.Elem()
+	}
+
+	if sv.Type() != dv.Elem().Type() {
+		return CopyBean(src, dest)
+	}
+
+	c.copy(dv.Elem(), sv)
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type copier struct {
+	visited map[visitKey]reflect.Value
+}
+
+// copy 将 src 深拷贝到 dst ，dst 必须是可以设置的。
+func (c *copier) copy(dst, src reflect.Value) {
+
+	if src.Type() == timeType {
+		dst.Set(src)
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(src.Type()))
+			return
+		}
+		key := visitKey{src.Pointer(), src.Type()}
+		if v, ok := c.visited[key]; ok {
+			dst.Set(v)
+			return
+		}
+		v := reflect.New(src.Type().Elem())
+		c.visited[key] = v
+		c.copy(v.Elem(), src.Elem())
+		dst.Set(v)
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(src.Type()))
+			return
+		}
+		e := src.Elem()
+		v := reflect.New(e.Type()).Elem()
+		c.copy(v, e)
+		dst.Set(v)
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			df, sf := dst.Field(i), src.Field(i)
+			if src.Type().Field(i).PkgPath != "" {
+				df, sf = PatchValue(df), PatchValue(sf)
+			}
+			c.copy(df, sf)
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(src.Type()))
+			return
+		}
+		key := visitKey{src.Pointer(), src.Type()}
+		if v, ok := c.visited[key]; ok && v.Len() == src.Len() {
+			dst.Set(v)
+			return
+		}
+		v := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.visited[key] = v
+		for i := 0; i < src.Len(); i++ {
+			c.copy(v.Index(i), src.Index(i))
+		}
+		dst.Set(v)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(src.Type()))
+			return
+		}
+		key := visitKey{src.Pointer(), src.Type()}
+		if v, ok := c.visited[key]; ok {
+			dst.Set(v)
+			return
+		}
+		v := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.visited[key] = v
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(src.Type().Key()).Elem()
+			c.copy(k, iter.Key())
+			e := reflect.New(src.Type().Elem()).Elem()
+			c.copy(e, iter.Value())
+			v.SetMapIndex(k, e)
+		}
+		dst.Set(v)
+	default:
+		dst.Set(src)
+	}
+}
+
+type converterKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+var copyConverters = map[converterKey]reflect.Value{}
+
+func init() {
+	RegisterCopyConverter(func(s string) (time.Time, error) {
+		return time.Parse(time.RFC3339Nano, s)
+	})
+	RegisterCopyConverter(func(t time.Time) (string, error) {
+		return t.Format(time.RFC3339Nano), nil
+	})
+}
+
+// RegisterCopyConverter 注册 CopyProperties 使用的类型转换器，转换器的函数
+// 原型为 func(From)(To,error) ，相同类型的转换器会被覆盖。
+func RegisterCopyConverter(fn interface{}) {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 1 ||
+		t.NumOut() != 2 || !IsErrorType(t.Out(1)) {
+		panic(errors.New("fn must be func(From)(To,error)"))
+	}
+	copyConverters[converterKey{t.In(0), t.Out(0)}] = reflect.ValueOf(fn)
+}
+
+// CopyProperties 按照字段名称将 src 的字段拷贝到 dest 指向的结构体，src 可以
+// 是结构体或者结构体指针。字段名称首先精确匹配，然后忽略大小写以及下划线和
+// 中划线进行匹配，也可以通过 copy:"srcField" 标签指定源字段，copy:"-" 表示
+// 不拷贝。类型不同的字段依次尝试注册的类型转换器、数值类型之间的转换以及结构
+// 体之间的递归拷贝，均不支持时返回错误。匿名结构体的字段会被展开。
+func CopyProperties(src interface{}, dest interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errors.New("dest should be a non-nil struct pointer")
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Kind() == reflect.Ptr {
+		if sv.IsNil() {
+			return nil
+		}
+		sv = sv.Elem()
+	}
+	if sv.Kind() != reflect.Struct {
+		return errors.New("src should be a struct or struct pointer")
+	}
+	return copyStruct(dv.Elem(), sv)
+}
+
+// relaxedName 返回忽略大小写以及下划线和中划线后的字段名称。
+func relaxedName(s string) string {
+	s = strings.ReplaceAll(s, "_", "")
+	s = strings.ReplaceAll(s, "-", "")
+	return strings.ToLower(s)
+}
+
+type copyField struct {
+	name  string
+	value reflect.Value
+	tag   string
+}
+
+// exportedFields 返回结构体所有导出的字段，匿名结构体的字段会被展开。
+func exportedFields(v reflect.Value) []copyField {
+	var fields []copyField
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, exportedFields(v.Field(i))...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		fields = append(fields, copyField{f.Name, v.Field(i), f.Tag.Get("copy")})
+	}
+	return fields
+}
+
+func copyStruct(dst, src reflect.Value) error {
+	srcFields := exportedFields(src)
+	for _, df := range exportedFields(dst) {
+		if df.tag == "-" {
+			continue
+		}
+		name := df.name
+		if df.tag != "" {
+			name = df.tag
+		}
+		sf, ok := findField(srcFields, name)
+		if !ok {
+			continue
+		}
+		if err := copyValue(df.value, sf.value); err != nil {
+			return fmt.Errorf("copy field %s error: %w", df.name, err)
+		}
+	}
+	return nil
+}
+
+// findField 查找名称为 name 的字段，精确匹配优先。
+func findField(fields []copyField, name string) (copyField, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	relaxed := relaxedName(name)
+	for _, f := range fields {
+		if relaxedName(f.name) == relaxed {
+			return f, true
+		}
+	}
+	return copyField{}, false
+}
+
+func copyValue(dst, src reflect.Value) error {
+
+	st, dt := src.Type(), dst.Type()
+	if st == dt {
+		c := &copier{visited: make(map[visitKey]reflect.Value)}
+		c.copy(dst, src)
+		return nil
+	}
+
+	if fn, ok := copyConverters[converterKey{st, dt}]; ok {
+		out := fn.Call([]reflect.Value{src})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return err
+		}
+		dst.Set(out[0])
+		return nil
+	}
+
+	if isNumberKind(st.Kind()) && isNumberKind(dt.Kind()) ||
+		st.Kind() == reflect.String && dt.Kind() == reflect.String ||
+		st.Kind() == reflect.Bool && dt.Kind() == reflect.Bool {
+		dst.Set(src.Convert(dt))
+		return nil
+	}
+
+	switch {
+	case st.Kind() == reflect.Struct && dt.Kind() == reflect.Struct:
+		return copyStruct(dst, src)
+	case st.Kind() == reflect.Ptr && dt.Kind() == reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+		v := reflect.New(dt.Elem())
+		if err := copyValue(v.Elem(), src.Elem()); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	case st.Kind() == reflect.Slice && dt.Kind() == reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+		v := reflect.MakeSlice(dt, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := copyValue(v.Index(i), src.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		dst.Set(v)
+		return nil
+	}
+	return fmt.Errorf("can't convert %s to %s", st, dt)
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
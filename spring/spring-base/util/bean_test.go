@@ -0,0 +1,233 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+type copyNode struct {
+	Name     string
+	Next     *copyNode
+	Children []*copyNode
+}
+
+type copyBean struct {
+	Int      int
+	Time     time.Time
+	Ptr      *int
+	Slice    []string
+	Array    [2]int
+	Map      map[string][]int
+	Any      interface{}
+	Fn       func() int
+	private  string
+	Embedded *copyNode
+}
+
+func TestDeepCopy(t *testing.T) {
+
+	i := 3
+	now := time.Now()
+	src := &copyBean{
+		Int:     1,
+		Time:    now,
+		Ptr:     &i,
+		Slice:   []string{"a", "b"},
+		Array:   [2]int{1, 2},
+		Map:     map[string][]int{"a": {1}},
+		Any:     map[string]interface{}{"a": []interface{}{1.5}},
+		Fn:      func() int { return 5 },
+		private: "private",
+	}
+	node := &copyNode{Name: "root"}
+	node.Next = node
+	node.Children = []*copyNode{node, {Name: "child"}}
+	src.Embedded = node
+
+	var dest copyBean
+	err := util.DeepCopy(src, &dest)
+	assert.Nil(t, err)
+
+	assert.Equal(t, dest.Int, 1)
+	assert.True(t, dest.Time.Equal(now))
+	assert.Equal(t, dest.Time.Location(), now.Location())
+	assert.Equal(t, *dest.Ptr, 3)
+	assert.NotSame(t, dest.Ptr, src.Ptr)
+	assert.Equal(t, dest.Slice, src.Slice)
+	assert.Equal(t, dest.Array, src.Array)
+	assert.Equal(t, dest.Map, src.Map)
+	assert.Equal(t, dest.Any, src.Any)
+	assert.Equal(t, dest.Fn(), 5)
+	assert.Equal(t, dest.private, "private")
+
+	assert.Equal(t, dest.Embedded.Name, "root")
+	assert.NotSame(t, dest.Embedded, node)
+	assert.Same(t, dest.Embedded.Next, dest.Embedded)
+	assert.Same(t, dest.Embedded.Children[0], dest.Embedded)
+	assert.Equal(t, dest.Embedded.Children[1].Name, "child")
+
+	*src.Ptr = 4
+	src.Slice[0] = "x"
+	src.Map["a"][0] = 2
+	src.Any.(map[string]interface{})["a"].([]interface{})[0] = 2.5
+	assert.Equal(t, *dest.Ptr, 3)
+	assert.Equal(t, dest.Slice, []string{"a", "b"})
+	assert.Equal(t, dest.Map, map[string][]int{"a": {1}})
+	assert.Equal(t, dest.Any, map[string]interface{}{"a": []interface{}{1.5}})
+
+	var n *copyNode
+	assert.Nil(t, util.DeepCopy(node, &n))
+	assert.Same(t, n.Next, n)
+
+	var m map[string]int
+	assert.Nil(t, util.DeepCopy(nil, &m))
+	assert.Nil(t, m)
+
+	var s struct{ Int int }
+	assert.Nil(t, util.DeepCopy(struct{ Int int32 }{3}, &s))
+	assert.Equal(t, s.Int, 3)
+
+	assert.Error(t, util.DeepCopy(src, dest), "dest should be a non-nil pointer")
+}
+
+// BenchmarkDeepCopy
+// json-8    	  368254	      2976 ns/op
+// reflect-8 	  212654	      5440 ns/op
+func BenchmarkDeepCopy(b *testing.B) {
+
+	src := &copyBean{
+		Int:   1,
+		Time:  time.Now(),
+		Slice: []string{"a", "b"},
+		Map:   map[string][]int{"a": {1}, "b": {2, 3}},
+		Any:   map[string]interface{}{"a": []interface{}{1.5}},
+	}
+
+	b.Run("json", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var dest copyBean
+			_ = util.CopyBean(src, &dest)
+		}
+	})
+
+	b.Run("reflect", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var dest copyBean
+			_ = util.DeepCopy(src, &dest)
+		}
+	})
+}
+
+type copyStatus int
+
+const (
+	statusUnknown copyStatus = iota
+	statusActive
+)
+
+type copyBase struct {
+	ID int64
+}
+
+type copyEntity struct {
+	copyBase
+	UserName  string
+	Status    int
+	CreatedAt time.Time
+	Amount    float32
+	Address   *copyAddress
+	Tags      []string
+	Secret    string
+}
+
+type copyAddress struct {
+	City string
+}
+
+type copyDTO struct {
+	Id         int
+	User_Name  string
+	Status     copyStatus
+	CreatedAt  string
+	Total      float64 `copy:"Amount"`
+	Address    *struct{ CITY string }
+	Tags       []string
+	Secret     string `copy:"-"`
+	Missing    bool
+	Expiration time.Time
+}
+
+func TestCopyProperties(t *testing.T) {
+
+	created := time.Date(2022, 3, 4, 5, 6, 7, 800, time.UTC)
+	src := &copyEntity{
+		copyBase:  copyBase{ID: 3},
+		UserName:  "jim",
+		Status:    int(statusActive),
+		CreatedAt: created,
+		Amount:    1.5,
+		Address:   &copyAddress{City: "Beijing"},
+		Tags:      []string{"vip"},
+		Secret:    "secret",
+	}
+
+	var dto copyDTO
+	err := util.CopyProperties(src, &dto)
+	assert.Nil(t, err)
+	assert.Equal(t, dto.Id, 3)
+	assert.Equal(t, dto.User_Name, "jim")
+	assert.Equal(t, dto.Status, statusActive)
+	assert.Equal(t, dto.CreatedAt, "2022-03-04T05:06:07.0000008Z")
+	assert.Equal(t, dto.Total, 1.5)
+	assert.Equal(t, dto.Address.CITY, "Beijing")
+	assert.Equal(t, dto.Tags, []string{"vip"})
+	assert.Equal(t, dto.Secret, "")
+
+	src.Tags[0] = "x"
+	assert.Equal(t, dto.Tags, []string{"vip"})
+
+	var entity copyEntity
+	err = util.CopyProperties(dto, &entity)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.ID, int64(3))
+	assert.True(t, entity.CreatedAt.Equal(created))
+
+	dto.CreatedAt = "2022"
+	err = util.CopyProperties(dto, &entity)
+	assert.Error(t, err, "copy field CreatedAt error: parsing time \"2022\"")
+
+	util.RegisterCopyConverter(func(s copyStatus) (string, error) {
+		return [...]string{"unknown", "active"}[s], nil
+	})
+	var status struct{ Status string }
+	err = util.CopyProperties(dto, &status)
+	assert.Nil(t, err)
+	assert.Equal(t, status.Status, "active")
+
+	var bad struct{ Tags int }
+	err = util.CopyProperties(dto, &bad)
+	assert.Error(t, err, "copy field Tags error: can't convert \\[\\]string to int")
+
+	assert.Error(t, util.CopyProperties(dto, dto), "dest should be a non-nil struct pointer")
+	assert.Error(t, util.CopyProperties(3, &dto), "src should be a struct or struct pointer")
+	assert.Panic(t, func() { util.RegisterCopyConverter(func(s string) int { return 0 }) }, "fn must be func\\(From\\)\\(To,error\\)")
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+)
+
+// Map 使用 fn 将 []T 转换为 []R 。
+func Map[T, R any](s []T, fn func(T) R) []R {
+	r := make([]R, len(s))
+	for i, e := range s {
+		r[i] = fn(e)
+	}
+	return r
+}
+
+// Filter 返回 fn 返回 true 的元素组成的新切片。
+func Filter[T any](s []T, fn func(T) bool) []T {
+	r := make([]T, 0)
+	for _, e := range s {
+		if fn(e) {
+			r = append(r, e)
+		}
+	}
+	return r
+}
+
+// Reduce 使用 fn 从 init 开始依次累积切片的元素。
+func Reduce[T, R any](s []T, fn func(R, T) R, init R) R {
+	acc := init
+	for _, e := range s {
+		acc = fn(acc, e)
+	}
+	return acc
+}
+
+// Unique 返回去除重复元素之后的新切片，保留元素第一次出现的顺序。
+func Unique[T comparable](s []T) []T {
+	r := make([]T, 0, len(s))
+	seen := make(map[T]bool, len(s))
+	for _, e := range s {
+		if !seen[e] {
+			seen[e] = true
+			r = append(r, e)
+		}
+	}
+	return r
+}
+
+// Chunk 将 []T 按照 size 分割为 [][]T ，最后一组的元素可能少于 size 个。
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic(fmt.Errorf("Chunk: size should be positive but got %d", size))
+	}
+	r := make([][]T, 0, (len(s)+size-1)/size)
+	for i := 0; i < len(s); i += size {
+		j := i + size
+		if j > len(s) {
+			j = len(s)
+		}
+		r = append(r, append([]T(nil), s[i:j]...))
+	}
+	return r
+}
+
+// GroupBy 使用 fn 将 []T 分组为 map[K][]T ，组内保持元素的顺序。
+func GroupBy[T any, K comparable](s []T, fn func(T) K) map[K][]T {
+	r := make(map[K][]T)
+	for _, e := range s {
+		k := fn(e)
+		r[k] = append(r[k], e)
+	}
+	return r
+}
+
+// Keys 返回 map[K]V 的所有键，顺序是不确定的。
+func Keys[K comparable, V any](m map[K]V) []K {
+	r := make([]K, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Values 返回 map[K]V 的所有值，顺序是不确定的。
+func Values[K comparable, V any](m map[K]V) []V {
+	r := make([]V, 0, len(m))
+	for _, v := range m {
+		r = append(r, v)
+	}
+	return r
+}
+
+// Merge 将多个 map 合并为一个新的 map ，相同的键后面的值覆盖前面的值，nil map
+// 会被忽略。
+func Merge[K comparable, V any](maps ...map[K]V) map[K]V {
+	if len(maps) == 0 {
+		return nil
+	}
+	r := make(map[K]V)
+	for _, m := range maps {
+		for k, v := range m {
+			r[k] = v
+		}
+	}
+	return r
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+func TestSliceUtils(t *testing.T) {
+
+	nums := []int{1, 2, 3, 4, 5}
+
+	strs := util.Map(nums, func(i int) string { return strconv.Itoa(i * 2) })
+	assert.Equal(t, strs, []string{"2", "4", "6", "8", "10"})
+
+	odd := util.Filter(nums, func(i int) bool { return i%2 == 1 })
+	assert.Equal(t, odd, []int{1, 3, 5})
+	assert.Equal(t, util.Filter([]int{}, func(i int) bool { return true }), []int{})
+
+	sum := util.Reduce(nums, func(acc int, i int) int { return acc + i }, 10)
+	assert.Equal(t, sum, 25)
+	joined := util.Reduce(nums, func(acc string, i int) string { return acc + strconv.Itoa(i) }, "")
+	assert.Equal(t, joined, "12345")
+
+	assert.Equal(t, util.Unique([]string{"b", "a", "b", "c", "a"}), []string{"b", "a", "c"})
+
+	assert.Equal(t, util.Chunk(nums, 2), [][]int{{1, 2}, {3, 4}, {5}})
+	assert.Equal(t, util.Chunk([]int{1, 2, 3}, 3), [][]int{{1, 2, 3}})
+	assert.Equal(t, util.Chunk([]int{}, 3), [][]int{})
+
+	groups := util.GroupBy([]string{"apple", "bob", "avocado", "cat"}, func(s string) byte { return s[0] })
+	assert.Equal(t, groups, map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"bob"},
+		'c': {"cat"},
+	})
+
+	assert.Panic(t, func() { util.Chunk(nums, 0) }, "Chunk: size should be positive but got 0")
+}
+
+func TestMapUtils(t *testing.T) {
+
+	m := map[string]int{"a": 1, "b": 2}
+
+	keys := util.Keys(m)
+	sort.Strings(keys)
+	assert.Equal(t, keys, []string{"a", "b"})
+
+	values := util.Values(m)
+	sort.Ints(values)
+	assert.Equal(t, values, []int{1, 2})
+
+	merged := util.Merge(m, nil, map[string]int{"b": 3, "c": 4})
+	assert.Equal(t, merged, map[string]int{"a": 1, "b": 3, "c": 4})
+	assert.Equal(t, m, map[string]int{"a": 1, "b": 2})
+	assert.Nil(t, util.Merge[string, int]())
+}
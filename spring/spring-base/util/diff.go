@@ -0,0 +1,201 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change 描述一个字段的变化，Old 或者 New 为 nil 表示字段被添加或者删除。
+type Change struct {
+	Path string      // 字段路径，例如 Address.City 、Tags[0] 、Attrs.key
+	Old  interface{} // 旧值
+	New  interface{} // 新值
+}
+
+// DiffOption Diff 的可选项。
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	ignores []string
+}
+
+// IgnorePath 忽略指定路径及其子路径的变化，路径中的 [*] 匹配任意下标。
+func IgnorePath(paths ...string) DiffOption {
+	return func(opts *diffOptions) {
+		opts.ignores = append(opts.ignores, paths...)
+	}
+}
+
+// Diff 逐字段比较 a 和 b ，返回所有叶子字段的变化。结构体只比较导出字段，
+// time.Time 使用 Equal 比较，map 的键按照字符串形式排序。
+func Diff(a, b interface{}, opts ...DiffOption) []Change {
+	d := &differ{visited: make(map[[2]uintptr]bool)}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	d.diff("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return d.changes
+}
+
+type differ struct {
+	opts    diffOptions
+	changes []Change
+	visited map[[2]uintptr]bool
+}
+
+// ignored 返回 path 是否被忽略。
+func (d *differ) ignored(path string) bool {
+	for _, p := range d.opts.ignores {
+		if matchPath(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath 返回 path 是否是 pattern 或者 pattern 的子路径。
+func matchPath(pattern, path string) bool {
+	for {
+		i := strings.Index(pattern, "[*]")
+		if i < 0 {
+			break
+		}
+		if !strings.HasPrefix(path, pattern[:i+1]) {
+			return false
+		}
+		path = path[i+1:]
+		j := strings.IndexByte(path, ']')
+		if j < 0 {
+			return false
+		}
+		path = path[j:]
+		pattern = pattern[i+2:]
+	}
+	if !strings.HasPrefix(path, pattern) {
+		return false
+	}
+	rest := path[len(pattern):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
+func (d *differ) add(path string, a, b reflect.Value) {
+	c := Change{Path: path}
+	if a.IsValid() && a.CanInterface() {
+		c.Old = a.Interface()
+	}
+	if b.IsValid() && b.CanInterface() {
+		c.New = b.Interface()
+	}
+	d.changes = append(d.changes, c)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+
+	if path != "" && d.ignored(path) {
+		return
+	}
+
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.add(path, a, b)
+		}
+		return
+	}
+
+	if a.Type() != b.Type() {
+		d.add(path, a, b)
+		return
+	}
+
+	if a.Type() == timeType {
+		if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+			d.add(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, a, b)
+			}
+			return
+		}
+		if a.Kind() == reflect.Ptr {
+			key := [2]uintptr{a.Pointer(), b.Pointer()}
+			if d.visited[key] {
+				return
+			}
+			d.visited[key] = true
+		}
+		d.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			d.diff(joinPath(path, f.Name), a.Field(i), b.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		n := a.Len()
+		if b.Len() > n {
+			n = b.Len()
+		}
+		for i := 0; i < n; i++ {
+			var ai, bi reflect.Value
+			if i < a.Len() {
+				ai = a.Index(i)
+			}
+			if i < b.Len() {
+				bi = b.Index(i)
+			}
+			d.diff(fmt.Sprintf("%s[%d]", path, i), ai, bi)
+		}
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		names := make([]string, 0, len(keys))
+		for s := range keys {
+			names = append(names, s)
+		}
+		sort.Strings(names)
+		for _, s := range names {
+			k := keys[s]
+			d.diff(joinPath(path, s), a.MapIndex(k), b.MapIndex(k))
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			d.add(path, a, b)
+		}
+	}
+}
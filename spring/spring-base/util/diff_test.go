@@ -0,0 +1,91 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+type diffAddress struct {
+	City string
+	Zip  *int
+}
+
+type diffUser struct {
+	Name      string
+	Age       int
+	UpdatedAt time.Time
+	Address   *diffAddress
+	Tags      []string
+	Attrs     map[string]interface{}
+	Orders    []diffAddress
+	secret    string
+}
+
+func TestDiff(t *testing.T) {
+
+	zip := 100000
+	now := time.Now()
+	a := diffUser{
+		Name:      "jim",
+		Age:       18,
+		UpdatedAt: now,
+		Address:   &diffAddress{City: "Beijing", Zip: &zip},
+		Tags:      []string{"a", "b"},
+		Attrs:     map[string]interface{}{"level": 1, "vip": true},
+		Orders:    []diffAddress{{City: "x"}, {City: "y"}},
+		secret:    "a",
+	}
+
+	b := a
+	b.secret = "b"
+	b.UpdatedAt = now.UTC()
+	assert.Nil(t, util.Diff(a, b))
+	assert.Nil(t, util.Diff(&a, &b))
+
+	b.Age = 19
+	b.Address = &diffAddress{City: "Shanghai"}
+	b.Tags = []string{"a"}
+	b.Attrs = map[string]interface{}{"level": 2, "new": "x"}
+	b.Orders = []diffAddress{{City: "x"}, {City: "z"}}
+
+	changes := util.Diff(a, b)
+	assert.Equal(t, changes, []util.Change{
+		{Path: "Age", Old: 18, New: 19},
+		{Path: "Address.City", Old: "Beijing", New: "Shanghai"},
+		{Path: "Address.Zip", Old: &zip, New: (*int)(nil)},
+		{Path: "Tags[1]", Old: "b", New: nil},
+		{Path: "Attrs.level", Old: 1, New: 2},
+		{Path: "Attrs.new", Old: nil, New: "x"},
+		{Path: "Attrs.vip", Old: true, New: nil},
+		{Path: "Orders[1].City", Old: "y", New: "z"},
+	})
+
+	changes = util.Diff(a, b, util.IgnorePath("Address", "Attrs.level", "Orders[*].City", "Tag"))
+	assert.Equal(t, changes, []util.Change{
+		{Path: "Age", Old: 18, New: 19},
+		{Path: "Tags[1]", Old: "b", New: nil},
+		{Path: "Attrs.new", Old: nil, New: "x"},
+		{Path: "Attrs.vip", Old: true, New: nil},
+	})
+
+	assert.Equal(t, util.Diff(1, "1"), []util.Change{{Path: "", Old: 1, New: "1"}})
+}
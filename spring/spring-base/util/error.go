@@ -19,6 +19,7 @@ package util
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // UnsupportedMethod 如果某个方法禁止被调用则可以抛出此错误。
@@ -42,7 +43,7 @@ var WrapFormat = func(err error, fileline string, format string, a ...interface{
 
 // Error 创建携带文件信息的 error 对象。文件信息未来也许可以在编译期计算。
 func Error(fileline string, text string) error {
-	return WrapFormat(nil, fileline, "", text)
+	return WrapFormat(nil, fileline, "%s", text)
 }
 
 // Errorf 创建携带文件信息的 error 对象。文件信息未来也许可以在编译期计算。
@@ -52,10 +53,86 @@ func Errorf(fileline string, format string, a ...interface{}) error {
 
 // Wrap 创建携带文件信息的 error 对象。文件信息未来也许可以在编译期计算。
 func Wrap(err error, fileline string, text string) error {
-	return WrapFormat(err, fileline, "", text)
+	return WrapFormat(err, fileline, "%s", text)
 }
 
 // Wrapf 创建携带文件信息的 error 对象。文件信息未来也许可以在编译期计算。
 func Wrapf(err error, fileline string, format string, a ...interface{}) error {
 	return WrapFormat(err, fileline, format, a...)
 }
+
+// Errors 收集多个错误，例如容器一次报告所有的错误而不是遇到第一个错误就返回。
+// 零值可以直接使用，支持 errors.Is 和 errors.As 在所有收集的错误中查找。
+type Errors struct {
+	errs []error
+}
+
+// Append 添加错误，忽略 nil ，*Errors 会被展开。
+func (e *Errors) Append(errs ...error) {
+	for _, err := range errs {
+		switch v := err.(type) {
+		case nil:
+		case *Errors:
+			if v != nil {
+				e.errs = append(e.errs, v.errs...)
+			}
+		default:
+			e.errs = append(e.errs, err)
+		}
+	}
+}
+
+// Len 返回收集的错误的数量。
+func (e *Errors) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.errs)
+}
+
+// Errors 返回收集的所有错误。
+func (e *Errors) Errors() []error {
+	if e == nil {
+		return nil
+	}
+	return e.errs
+}
+
+// ErrorOrNil 没有收集到错误时返回 nil ，否则返回 e 。
+func (e *Errors) ErrorOrNil() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *Errors) Error() string {
+	if len(e.errs) == 1 {
+		return e.errs[0].Error()
+	}
+	s := fmt.Sprintf("%d errors occurred:", len(e.errs))
+	for _, err := range e.errs {
+		s += "\n\t* " + strings.ReplaceAll(err.Error(), "\n", "\n\t  ")
+	}
+	return s
+}
+
+// Is 返回收集的错误中是否有错误匹配 target ，供 errors.Is 使用。
+func (e *Errors) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 查找第一个可以赋值给 target 的错误，供 errors.As 使用。
+func (e *Errors) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+type codeError struct{ code int }
+
+func (e *codeError) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+func TestErrors(t *testing.T) {
+
+	var errs util.Errors
+	assert.Nil(t, errs.ErrorOrNil())
+	assert.Nil(t, (*util.Errors)(nil).ErrorOrNil())
+
+	errs.Append(nil, io.EOF)
+	assert.Equal(t, errs.Len(), 1)
+	assert.Error(t, errs.ErrorOrNil(), "^EOF$")
+
+	var nested util.Errors
+	nested.Append(&codeError{3}, errors.New("line1\nline2"))
+	errs.Append(&nested, (*util.Errors)(nil))
+	assert.Equal(t, errs.Len(), 3)
+
+	err := errs.ErrorOrNil()
+	assert.Equal(t, err.Error(), "3 errors occurred:\n\t* EOF\n\t* code 3\n\t* line1\n\t  line2")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.False(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	var ce *codeError
+	assert.ErrorAs(t, fmt.Errorf("wrap: %w", err), &ce)
+	assert.Equal(t, ce.code, 3)
+}
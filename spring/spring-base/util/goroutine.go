@@ -0,0 +1,88 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicHandler 处理 goroutine 中发生的 panic ，stack 是发生 panic 时的调用栈。
+type PanicHandler func(ctx context.Context, r interface{}, stack []byte)
+
+var panicHandlers struct {
+	sync.RWMutex
+	list []PanicHandler
+}
+
+// OnPanic 注册 PanicHandler ，SafeGo 和 SafeGoWithRecover 启动的 goroutine
+// 发生 panic 时依次调用所有的 PanicHandler ，log 包会注册一个输出日志的
+// PanicHandler ，应用可以注册上报监控的 PanicHandler 。
+func OnPanic(h PanicHandler) {
+	panicHandlers.Lock()
+	defer panicHandlers.Unlock()
+	panicHandlers.list = append(panicHandlers.list, h)
+}
+
+// handlePanic 调用所有的 PanicHandler ，没有注册时输出到标准错误。
+func handlePanic(ctx context.Context, r interface{}, stack []byte) {
+	panicHandlers.RLock()
+	handlers := panicHandlers.list
+	panicHandlers.RUnlock()
+	if len(handlers) == 0 {
+		fmt.Fprintf(os.Stderr, "panic: %v\n%s", r, stack)
+		return
+	}
+	for _, h := range handlers {
+		func() {
+			defer func() { recover() }() // PanicHandler 的 panic 不能影响进程
+			h(ctx, r, stack)
+		}()
+	}
+}
+
+// SafeGo 启动一个 goroutine 执行 fn ，fn 发生 panic 时不会导致进程退出，而是
+// 交给注册的 PanicHandler 处理。
+func SafeGo(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				handlePanic(ctx, r, debug.Stack())
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+// SafeGoWithRecover 启动一个 goroutine 执行 fn ，fn 发生 panic 时先交给注册
+// 的 PanicHandler 处理，然后调用 onPanic ，例如释放资源或者重新启动任务。
+func SafeGoWithRecover(fn func(), onPanic func(r interface{})) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				handlePanic(context.Background(), r, debug.Stack())
+				if onPanic != nil {
+					onPanic(r)
+				}
+			}
+		}()
+		fn()
+	}()
+}
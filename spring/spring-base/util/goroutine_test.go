@@ -0,0 +1,65 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+func TestSafeGo(t *testing.T) {
+
+	type panicInfo struct {
+		ctx   context.Context
+		r     interface{}
+		stack string
+	}
+
+	ch := make(chan panicInfo, 2)
+	util.OnPanic(func(ctx context.Context, r interface{}, stack []byte) {
+		ch <- panicInfo{ctx, r, string(stack)}
+	})
+	util.OnPanic(func(ctx context.Context, r interface{}, stack []byte) {
+		panic("handler panic")
+	})
+
+	ctx := context.WithValue(context.Background(), "key", "value")
+	util.SafeGo(ctx, func(ctx context.Context) {
+		panic("safe go panic")
+	})
+
+	info := <-ch
+	assert.Equal(t, info.ctx.Value("key"), "value")
+	assert.Equal(t, info.r, "safe go panic")
+	assert.True(t, strings.Contains(info.stack, "goroutine_test.go"))
+
+	done := make(chan interface{})
+	util.SafeGoWithRecover(func() {
+		var m map[string]int
+		m["a"] = 1
+	}, func(r interface{}) {
+		done <- r
+	})
+
+	info = <-ch
+	assert.Error(t, info.r.(error), "assignment to entry in nil map")
+	assert.Equal(t, <-done, info.r)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// PoolStats 对象池的统计数据。
+type PoolStats struct {
+	Get  uint64 // 获取对象的次数
+	Put  uint64 // 归还对象的次数
+	Miss uint64 // 池中没有可用对象而新建对象的次数
+}
+
+// Pool 对 sync.Pool 的类型安全封装，支持对象的创建和重置钩子，并统计获取、
+// 归还以及未命中的次数，便于观察对象的复用情况。
+type Pool[T any] struct {
+	pool  sync.Pool
+	reset func(T)
+	get   uint64
+	put   uint64
+	miss  uint64
+}
+
+// NewPool 创建对象池，newFn 用于创建新对象，reset 在对象归还时调用，可以为 nil 。
+func NewPool[T any](newFn func() T, reset func(T)) *Pool[T] {
+	p := &Pool[T]{reset: reset}
+	p.pool.New = func() interface{} {
+		atomic.AddUint64(&p.miss, 1)
+		return newFn()
+	}
+	return p
+}
+
+// Get 从对象池中获取一个对象，池中没有可用对象时新建一个。
+func (p *Pool[T]) Get() T {
+	atomic.AddUint64(&p.get, 1)
+	return p.pool.Get().(T)
+}
+
+// Put 重置对象之后将其归还对象池，x 为 nil 时什么都不做。
+func (p *Pool[T]) Put(x T) {
+	if IsNil(reflect.ValueOf(&x).Elem()) {
+		return
+	}
+	if p.reset != nil {
+		p.reset(x)
+	}
+	atomic.AddUint64(&p.put, 1)
+	p.pool.Put(x)
+}
+
+// Stats 返回对象池的统计数据。
+func (p *Pool[T]) Stats() PoolStats {
+	return PoolStats{
+		Get:  atomic.LoadUint64(&p.get),
+		Put:  atomic.LoadUint64(&p.put),
+		Miss: atomic.LoadUint64(&p.miss),
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+func TestPool(t *testing.T) {
+
+	p := util.NewPool(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, func(b *bytes.Buffer) {
+		b.Reset()
+	})
+
+	b := p.Get()
+	assert.Equal(t, b.Len(), 0)
+	b.WriteString("abc")
+	p.Put(b)
+	assert.Equal(t, b.Len(), 0)
+	p.Put(nil)
+
+	s := p.Stats()
+	assert.Equal(t, s.Get, uint64(1))
+	assert.Equal(t, s.Put, uint64(1))
+	assert.Equal(t, s.Miss, uint64(1))
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff 返回第 n 次 (从 1 开始) 重试之前需要等待的时间。
+type Backoff func(n int) time.Duration
+
+// ConstantBackoff 每次重试之前等待相同的时间。
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(n int) time.Duration { return d }
+}
+
+// ExponentialBackoff 重试之前等待的时间从 base 开始指数增长，最多不超过 max ，
+// max 为 0 表示不限制。
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(n int) time.Duration {
+		d := float64(base) * math.Pow(2, float64(n-1))
+		if max > 0 && d > float64(max) {
+			return max
+		}
+		return toDuration(d)
+	}
+}
+
+// toDuration 将 d 转换为 time.Duration ，超出范围时取 math.MaxInt64 。
+func toDuration(d float64) time.Duration {
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// WithJitter 为 b 的等待时间增加 [-factor*d, factor*d] 范围内的随机抖动，避免
+// 大量客户端同时重试，factor 的取值范围为 [0,1] 。
+func WithJitter(b Backoff, factor float64) Backoff {
+	return func(n int) time.Duration {
+		d := float64(b(n))
+		d += d * factor * (2*rand.Float64() - 1)
+		if d < 0 {
+			return 0
+		}
+		return toDuration(d)
+	}
+}
+
+// RetryOption Retry 的可选项。
+type RetryOption func(*retryOptions)
+
+type retryOptions struct {
+	retryable func(err error) bool
+}
+
+// RetryIf 只有 retryable 返回 true 的错误才会重试，默认所有错误都重试。
+func RetryIf(retryable func(err error) bool) RetryOption {
+	return func(opts *retryOptions) {
+		opts.retryable = retryable
+	}
+}
+
+// Retry 执行 fn 直到成功或者达到最大执行次数 attempts ，每次重试之前按照
+// backoff 等待，backoff 为 nil 时立即重试。返回最后一次执行的错误，不可重试
+// 的错误会被立即返回，等待过程中 ctx 被取消时返回 ctx.Err() 。
+func Retry(ctx context.Context, attempts int, backoff Backoff, fn func(ctx context.Context) error, opts ...RetryOption) error {
+
+	var o retryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for n := 1; ; n++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if n >= attempts || o.retryable != nil && !o.retryable(err) {
+			return err
+		}
+		var d time.Duration
+		if backoff != nil {
+			d = backoff(n)
+		}
+		if err = sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep 等待 d 时间或者 ctx 被取消。
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
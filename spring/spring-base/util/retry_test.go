@@ -0,0 +1,105 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+func TestBackoff(t *testing.T) {
+
+	b := util.ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, b(1), 10*time.Millisecond)
+	assert.Equal(t, b(2), 20*time.Millisecond)
+	assert.Equal(t, b(3), 40*time.Millisecond)
+	assert.Equal(t, b(4), 50*time.Millisecond)
+
+	b = util.ExponentialBackoff(time.Second, 0)
+	assert.Equal(t, b(64), time.Duration(math.MaxInt64))
+	assert.Equal(t, b(1000), time.Duration(math.MaxInt64))
+	j := util.WithJitter(util.ConstantBackoff(math.MaxInt64), 1)
+	for i := 0; i < 100; i++ {
+		assert.True(t, j(1) >= 0)
+	}
+
+	assert.Equal(t, util.ConstantBackoff(time.Second)(5), time.Second)
+
+	j = util.WithJitter(util.ConstantBackoff(100*time.Millisecond), 0.5)
+	for i := 0; i < 100; i++ {
+		d := j(1)
+		assert.True(t, d >= 50*time.Millisecond && d <= 150*time.Millisecond)
+	}
+}
+
+func TestRetry(t *testing.T) {
+
+	errTemp := errors.New("temporary error")
+	errFatal := errors.New("fatal error")
+
+	t.Run("success", func(t *testing.T) {
+		n := 0
+		err := util.Retry(context.Background(), 5, util.ConstantBackoff(time.Millisecond), func(ctx context.Context) error {
+			if n++; n < 3 {
+				return errTemp
+			}
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, n, 3)
+	})
+
+	t.Run("attempts", func(t *testing.T) {
+		n := 0
+		err := util.Retry(context.Background(), 3, nil, func(ctx context.Context) error {
+			n++
+			return errTemp
+		})
+		assert.Equal(t, err, errTemp)
+		assert.Equal(t, n, 3)
+	})
+
+	t.Run("retryable", func(t *testing.T) {
+		n := 0
+		err := util.Retry(context.Background(), 3, nil, func(ctx context.Context) error {
+			if n++; n < 2 {
+				return errTemp
+			}
+			return errFatal
+		}, util.RetryIf(func(err error) bool { return errors.Is(err, errTemp) }))
+		assert.Equal(t, err, errFatal)
+		assert.Equal(t, n, 2)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		n := 0
+		err := util.Retry(ctx, 10, util.ConstantBackoff(time.Hour), func(ctx context.Context) error {
+			n++
+			return errTemp
+		})
+		assert.Equal(t, err, context.DeadlineExceeded)
+		assert.Equal(t, n, 1)
+	})
+}
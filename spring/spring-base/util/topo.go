@@ -0,0 +1,146 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Edge 表示一条依赖关系，From 必须排在 To 的前面。
+type Edge[T comparable] struct {
+	From T
+	To   T
+}
+
+// CycleError 排序时发现的循环依赖。
+type CycleError[T comparable] struct {
+	Cycle []T // 首尾相同的循环路径
+}
+
+func (e *CycleError[T]) Error() string {
+	s := make([]string, len(e.Cycle))
+	for i, n := range e.Cycle {
+		s[i] = fmt.Sprint(n)
+	}
+	return "found sorting cycle: " + strings.Join(s, " -> ")
+}
+
+// TopoSort 对 nodes 进行拓扑排序。没有依赖关系的节点保持在 nodes 中的先后顺序，
+// 因此相同的输入总是得到相同的结果。存在循环依赖时返回 *CycleError ，边引用了
+// 不存在的节点时返回错误。
+func TopoSort[T comparable](nodes []T, edges []Edge[T]) ([]T, error) {
+
+	index := make(map[T]int, len(nodes))
+	for i, n := range nodes {
+		if _, ok := index[n]; ok {
+			return nil, fmt.Errorf("duplicate node %v", n)
+		}
+		index[n] = i
+	}
+
+	inDegree := make([]int, len(nodes))
+	next := make([][]int, len(nodes))
+	for _, e := range edges {
+		from, ok := index[e.From]
+		if !ok {
+			return nil, fmt.Errorf("unknown node %v", e.From)
+		}
+		to, ok := index[e.To]
+		if !ok {
+			return nil, fmt.Errorf("unknown node %v", e.To)
+		}
+		next[from] = append(next[from], to)
+		inDegree[to]++
+	}
+
+	// ready 是按照节点下标升序排列的可以输出的节点。
+	var ready []int
+	for i := range nodes {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	sorted := make([]T, 0, len(nodes))
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, nodes[i])
+		for _, j := range next[i] {
+			if inDegree[j]--; inDegree[j] == 0 {
+				k := sort.SearchInts(ready, j)
+				ready = append(ready, 0)
+				copy(ready[k+1:], ready[k:])
+				ready[k] = j
+			}
+		}
+	}
+
+	if len(sorted) < len(nodes) {
+		return nil, &CycleError[T]{Cycle: findCycle(nodes, next, inDegree)}
+	}
+	return sorted, nil
+}
+
+// findCycle 在剩余的节点中查找一个循环。剩余的节点都至少有一个剩余的前驱
+// 节点，所以沿着反向边查找一定能够回到走过的节点。
+func findCycle[T comparable](nodes []T, next [][]int, inDegree []int) []T {
+	prev := make([][]int, len(nodes))
+	for i := range next {
+		for _, j := range next[i] {
+			prev[j] = append(prev[j], i)
+		}
+	}
+	start := 0
+	for inDegree[start] == 0 {
+		start++
+	}
+	visited := make(map[int]int)
+	var path []int
+	for i := start; ; {
+		if k, ok := visited[i]; ok {
+			path = append(path[k:], i)
+			break
+		}
+		visited[i] = len(path)
+		path = append(path, i)
+		for _, j := range prev[i] {
+			if inDegree[j] > 0 {
+				i = j
+				break
+			}
+		}
+	}
+	// 反转为依赖的方向，并且从下标最小的节点开始。
+	n := len(path) - 1
+	for k := 0; k < n/2; k++ {
+		path[k], path[n-1-k] = path[n-1-k], path[k]
+	}
+	m := 0
+	for k := 1; k < n; k++ {
+		if path[k] < path[m] {
+			m = k
+		}
+	}
+	cycle := make([]T, 0, n+1)
+	for k := 0; k <= n; k++ {
+		cycle = append(cycle, nodes[path[(m+k)%n]])
+	}
+	return cycle
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+func TestTopoSort(t *testing.T) {
+
+	nodes := []string{"app", "cache", "db", "config", "log"}
+	edges := []util.Edge[string]{
+		{From: "config", To: "db"},
+		{From: "config", To: "cache"},
+		{From: "db", To: "app"},
+		{From: "cache", To: "app"},
+		{From: "log", To: "db"},
+	}
+
+	for i := 0; i < 10; i++ {
+		sorted, err := util.TopoSort(nodes, edges)
+		assert.Nil(t, err)
+		assert.Equal(t, sorted, []string{"config", "cache", "log", "db", "app"})
+	}
+
+	ints, err := util.TopoSort([]int{3, 2, 1}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ints, []int{3, 2, 1})
+
+	_, err = util.TopoSort([]string{"a", "b", "c", "d"}, []util.Edge[string]{
+		{From: "d", To: "a"},
+		{From: "b", To: "c"},
+		{From: "c", To: "d"},
+		{From: "d", To: "b"},
+	})
+	assert.Error(t, err, "found sorting cycle: b -> c -> d -> b")
+	var cycleErr *util.CycleError[string]
+	assert.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, cycleErr.Cycle, []string{"b", "c", "d", "b"})
+
+	_, err = util.TopoSort([]string{"a"}, []util.Edge[string]{{From: "a", To: "a"}})
+	assert.Error(t, err, "found sorting cycle: a -> a")
+
+	_, err = util.TopoSort([]string{"a"}, []util.Edge[string]{{From: "a", To: "b"}})
+	assert.Error(t, err, "unknown node b")
+
+	_, err = util.TopoSort([]string{"a", "a"}, nil)
+	assert.Error(t, err, "duplicate node a")
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidateRule 校验规则，v 是字段的值，param 是规则的参数，例如 min=1 中的 1 ，
+// 校验失败时返回的错误信息不需要包含字段名称。
+type ValidateRule func(v reflect.Value, param string) error
+
+var validateRules = struct {
+	sync.RWMutex
+	m map[string]ValidateRule
+}{
+	m: map[string]ValidateRule{
+		"required": validateRequired,
+		"min":      validateMin,
+		"max":      validateMax,
+		"len":      validateLen,
+		"oneof":    validateOneOf,
+	},
+}
+
+// RegisterValidateRule 注册校验规则，已存在的规则会被覆盖。
+func RegisterValidateRule(name string, rule ValidateRule) {
+	validateRules.Lock()
+	defer validateRules.Unlock()
+	validateRules.m[name] = rule
+}
+
+func getValidateRule(name string) (ValidateRule, bool) {
+	validateRules.RLock()
+	defer validateRules.RUnlock()
+	r, ok := validateRules.m[name]
+	return r, ok
+}
+
+// Validate 根据字段的 validate 标签校验结构体，例如 validate:"required,min=1"，
+// 多个规则使用逗号分隔，omitempty 表示字段为零值时不再校验其他规则。嵌套的结构
+// 体以及结构体切片会被递归校验，返回所有字段的校验错误。i 也可以是 reflect.Value 。
+func Validate(i interface{}) error {
+	v, ok := i.(reflect.Value)
+	if !ok {
+		v = reflect.ValueOf(i)
+	}
+	var errs Errors
+	validateValue(&errs, "", v)
+	return errs.ErrorOrNil()
+}
+
+func validateValue(errs *Errors, path string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			validateValue(errs, path, v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(errs, fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(errs, fmt.Sprintf("%s[%v]", path, iter.Key()), iter.Value())
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			fv := v.Field(i)
+			if f.PkgPath != "" {
+				if !f.Anonymous {
+					continue
+				}
+				fv = PatchValue(fv) // 私有的匿名结构体的导出字段仍然需要校验
+			}
+			fp := f.Name
+			if f.Anonymous {
+				fp = path
+			} else if path != "" {
+				fp = path + "." + f.Name
+			}
+			if tag, ok := f.Tag.Lookup("validate"); ok && tag != "-" {
+				if !validateField(errs, fp, fv, tag) {
+					continue
+				}
+			}
+			validateValue(errs, fp, fv)
+		}
+	}
+}
+
+// ValidateTag 使用 validate 标签中的规则校验单个值，校验错误添加到 errs 中，path
+// 是错误信息中的字段路径。返回值表示是否需要继续校验值的内部，例如 omitempty 规
+// 则遇到零值或者校验失败时不再需要继续校验。
+func ValidateTag(errs *Errors, path string, v reflect.Value, tag string) bool {
+	return validateField(errs, path, v, tag)
+}
+
+// validateField 使用标签中的规则校验字段，返回是否需要继续校验字段的内部。
+func validateField(errs *Errors, path string, v reflect.Value, tag string) bool {
+	for _, s := range strings.Split(tag, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if s == "omitempty" {
+			if v.IsZero() {
+				return false
+			}
+			continue
+		}
+		name, param := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			name, param = s[:i], s[i+1:]
+		}
+		rule, ok := getValidateRule(name)
+		if !ok {
+			errs.Append(fmt.Errorf("%s: unknown validate rule %q", path, name))
+			return false
+		}
+		if err := rule(v, param); err != nil {
+			errs.Append(fmt.Errorf("%s %w", path, err))
+			return false
+		}
+	}
+	return true
+}
+
+func validateRequired(v reflect.Value, param string) error {
+	if v.IsZero() {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+// sizeOf 返回数值类型的值，或者字符串、切片、map 的长度。
+func sizeOf(v reflect.Value) (size float64, isLen bool, err error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), false, nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, nil
+	case reflect.String:
+		return float64(len([]rune(v.String()))), true, nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true, nil
+	}
+	return 0, false, fmt.Errorf("has unsupported type %s", v.Type())
+}
+
+func compareSize(v reflect.Value, rule, param string, ok func(size, limit float64) bool, desc string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("has invalid %s param %q", rule, param)
+	}
+	size, isLen, err := sizeOf(v)
+	if err != nil {
+		return err
+	}
+	if ok(size, limit) {
+		return nil
+	}
+	if isLen {
+		return fmt.Errorf("length must be %s %s", desc, param)
+	}
+	return fmt.Errorf("must be %s %s", desc, param)
+}
+
+func validateMin(v reflect.Value, param string) error {
+	return compareSize(v, "min", param, func(size, limit float64) bool { return size >= limit }, "at least")
+}
+
+func validateMax(v reflect.Value, param string) error {
+	return compareSize(v, "max", param, func(size, limit float64) bool { return size <= limit }, "at most")
+}
+
+func validateLen(v reflect.Value, param string) error {
+	if _, isLen, _ := sizeOf(v); !isLen {
+		return fmt.Errorf("has unsupported type %s", v.Type())
+	}
+	return compareSize(v, "len", param, func(size, limit float64) bool { return size == limit }, "exactly")
+}
+
+func validateOneOf(v reflect.Value, param string) error {
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		return fmt.Errorf("has unsupported type %s", v.Type())
+	}
+	options := strings.Fields(param)
+	for _, o := range options {
+		if o == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of [%s]", strings.Join(options, " "))
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+)
+
+type validateAddress struct {
+	City string `validate:"required"`
+}
+
+type validateBase struct {
+	ID int64 `validate:"min=1"`
+}
+
+type validateUser struct {
+	validateBase
+	Name    string             `validate:"required,max=5"`
+	Age     int                `validate:"min=0,max=150"`
+	Role    string             `validate:"oneof=admin user"`
+	Code    string             `validate:"omitempty,len=4,upper"`
+	Tags    []string           `validate:"max=2"`
+	Home    *validateAddress   `validate:"required"`
+	Others  []*validateAddress `validate:"omitempty"`
+	Ignored string             `validate:"-"`
+	private string             `validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+
+	util.RegisterValidateRule("upper", func(v reflect.Value, param string) error {
+		if s := v.String(); s != strings.ToUpper(s) {
+			return errors.New("must be upper case")
+		}
+		return nil
+	})
+
+	u := &validateUser{
+		validateBase: validateBase{ID: 1},
+		Name:         "jim",
+		Age:          18,
+		Role:         "admin",
+		Home:         &validateAddress{City: "Beijing"},
+	}
+	assert.Nil(t, util.Validate(u))
+	assert.Nil(t, util.Validate(reflect.ValueOf(*u)))
+
+	u.Code = "ABCD"
+	assert.Nil(t, util.Validate(u))
+
+	u = &validateUser{
+		Name:   "jimmy green",
+		Age:    200,
+		Role:   "root",
+		Code:   "abcd",
+		Tags:   []string{"a", "b", "c"},
+		Others: []*validateAddress{{City: "x"}, {}},
+	}
+	err := util.Validate(u)
+	assert.Equal(t, err.Error(), strings.Join([]string{
+		"8 errors occurred:",
+		"\t* ID must be at least 1",
+		"\t* Name length must be at most 5",
+		"\t* Age must be at most 150",
+		"\t* Role must be one of [admin user]",
+		"\t* Code must be upper case",
+		"\t* Tags length must be at most 2",
+		"\t* Home is required",
+		"\t* Others[1].City is required",
+	}, "\n"))
+
+	var s struct {
+		Name string `validate:"unknown"`
+		Age  string `validate:"min=x"`
+		Flag bool   `validate:"max=1"`
+	}
+	assert.Error(t, util.Validate(s), "Name: unknown validate rule \"unknown\"\n.*Age has invalid min param \"x\"\n.*Flag has unsupported type bool")
+}
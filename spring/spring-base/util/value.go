@@ -79,3 +79,35 @@ func IsNil(v reflect.Value) bool {
 	}
 	return !v.IsValid()
 }
+
+// Ptr 返回一个指向 v 副本的指针，例如 Ptr(3) 返回 *int 。
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref 返回指针 p 指向的值，p 为 nil 时返回默认值 def 。
+func Deref[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// IsZero 返回 v 是否为其类型的零值，nil 也被视为零值。
+func IsZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.ValueOf(v).IsZero()
+}
+
+// Coalesce 返回 vals 中第一个非零值的元素，全部为零值时返回 T 的零值。
+func Coalesce[T any](vals ...T) T {
+	for _, v := range vals {
+		if !IsZero(v) {
+			return v
+		}
+	}
+	var zero T
+	return zero
+}
@@ -81,3 +81,37 @@ func TestIsNil(t *testing.T) {
 	assert.False(t, util.IsNil(reflect.ValueOf(3)))
 	assert.False(t, util.IsNil(reflect.ValueOf("3")))
 }
+
+func TestPtr(t *testing.T) {
+	var p *int = util.Ptr(3)
+	assert.Equal(t, *p, 3)
+	var s *string = util.Ptr("abc")
+	assert.Equal(t, *s, "abc")
+	assert.NotSame(t, util.Ptr(3), p)
+}
+
+func TestDeref(t *testing.T) {
+	assert.Equal(t, util.Deref(nil, 1), 1)
+	assert.Equal(t, util.Deref(util.Ptr(3), 1), 3)
+	assert.Equal(t, util.Deref((*string)(nil), "def"), "def")
+}
+
+func TestIsZero(t *testing.T) {
+	assert.True(t, util.IsZero(nil))
+	assert.True(t, util.IsZero(0))
+	assert.True(t, util.IsZero(""))
+	assert.True(t, util.IsZero((*int)(nil)))
+	assert.True(t, util.IsZero(struct{ A int }{}))
+	assert.False(t, util.IsZero(1))
+	assert.False(t, util.IsZero("a"))
+	assert.False(t, util.IsZero(struct{ A int }{A: 1}))
+}
+
+func TestCoalesce(t *testing.T) {
+	assert.Equal(t, util.Coalesce[int](), 0)
+	assert.Equal(t, util.Coalesce(0, 0), 0)
+	assert.Equal(t, util.Coalesce("", "a", "b"), "a")
+	assert.Equal(t, util.Coalesce(0, 0, 3), 3)
+	assert.Nil(t, util.Coalesce[*int](nil, nil))
+	assert.Nil(t, util.Coalesce[interface{}](0, "", nil))
+}
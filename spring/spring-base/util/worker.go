@@ -0,0 +1,224 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-spring/spring-base/knife"
+)
+
+// ErrWorkerPoolClosed 向已经关闭的 WorkerPool 提交任务时返回的错误。
+var ErrWorkerPoolClosed = errors.New("worker pool closed")
+
+// ErrWorkerPoolFull 任务队列已满并且拒绝策略为 RejectAbort 时返回的错误。
+var ErrWorkerPoolFull = errors.New("worker pool queue full")
+
+// RejectPolicy 任务队列已满时的处理方式。
+type RejectPolicy int
+
+const (
+	RejectBlock      = RejectPolicy(iota) // 阻塞直到队列有空闲位置
+	RejectAbort                           // 返回 ErrWorkerPoolFull
+	RejectCallerRuns                      // 在提交任务的 goroutine 中执行
+	RejectDiscard                         // 直接丢弃任务
+)
+
+// WorkerPoolStats WorkerPool 的统计数据。
+type WorkerPoolStats struct {
+	Workers    int    // 工作 goroutine 的数量
+	Running    int64  // 正在执行的任务数量
+	QueueDepth int    // 排队等待执行的任务数量
+	Completed  uint64 // 已经执行完成的任务数量
+	Rejected   uint64 // 队列已满时被拒绝的任务数量
+}
+
+type workerTask struct {
+	ctx context.Context
+	fn  func(ctx context.Context)
+}
+
+// WorkerPool 固定数量 goroutine 的任务池，任务队列有界，队列满时按照拒绝策略
+// 处理，默认阻塞。
+type WorkerPool struct {
+	size      int
+	policy    RejectPolicy
+	tasks     chan workerTask
+	wg        sync.WaitGroup
+	mu        sync.RWMutex
+	closed    bool
+	closing   chan struct{}  // Shutdown 开始时关闭，唤醒阻塞的 Submit
+	senders   sync.WaitGroup // 阻塞在任务队列上的 Submit
+	running   int64
+	completed uint64
+	rejected  uint64
+}
+
+// WorkerPoolOption WorkerPool 的配置项。
+type WorkerPoolOption func(p *workerPoolOptions)
+
+type workerPoolOptions struct {
+	queueSize int
+	policy    RejectPolicy
+}
+
+// WithQueueSize 设置任务队列的长度，默认和工作 goroutine 的数量相同。
+func WithQueueSize(n int) WorkerPoolOption {
+	return func(p *workerPoolOptions) {
+		p.queueSize = n
+	}
+}
+
+// WithRejectPolicy 设置任务队列已满时的处理方式，默认为 RejectBlock 。
+func WithRejectPolicy(policy RejectPolicy) WorkerPoolOption {
+	return func(p *workerPoolOptions) {
+		p.policy = policy
+	}
+}
+
+// NewWorkerPool 创建包含 size 个工作 goroutine 的任务池。
+func NewWorkerPool(size int, opts ...WorkerPoolOption) *WorkerPool {
+	if size <= 0 {
+		panic("worker pool size must be positive")
+	}
+	o := workerPoolOptions{queueSize: size}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := &WorkerPool{
+		size:    size,
+		policy:  o.policy,
+		tasks:   make(chan workerTask, o.queueSize),
+		closing: make(chan struct{}),
+	}
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+	for t := range p.tasks {
+		p.run(t)
+	}
+}
+
+func (p *WorkerPool) run(t workerTask) {
+	atomic.AddInt64(&p.running, 1)
+	defer func() {
+		if r := recover(); r != nil {
+			handlePanic(t.ctx, r, debug.Stack())
+		}
+		atomic.AddInt64(&p.running, -1)
+		atomic.AddUint64(&p.completed, 1)
+	}()
+	t.fn(t.ctx)
+}
+
+// Submit 提交任务，任务在独立的 context.Context 中执行，不受 ctx 取消的影响，
+// 但是会拷贝 ctx 上 knife 缓存的内容。队列满时按照拒绝策略处理，RejectBlock
+// 策略阻塞直到有空闲位置、ctx 被取消或者任务池开始关闭，阻塞期间不持有锁，
+// 因此不会妨碍 Shutdown 。任务发生 panic 时交给注册的 PanicHandler 处理。
+func (p *WorkerPool) Submit(ctx context.Context, fn func(ctx context.Context)) error {
+	taskCtx, err := knife.Copy(ctx)
+	if err != nil {
+		return err
+	}
+	if taskCtx == nil {
+		taskCtx = context.Background()
+	}
+	t := workerTask{ctx: taskCtx, fn: fn}
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrWorkerPoolClosed
+	}
+	select {
+	case p.tasks <- t:
+		p.mu.RUnlock()
+		return nil
+	default:
+	}
+	if p.policy == RejectBlock {
+		p.senders.Add(1)
+		p.mu.RUnlock()
+		defer p.senders.Done()
+		select {
+		case p.tasks <- t:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.closing:
+			return ErrWorkerPoolClosed
+		}
+	}
+	p.mu.RUnlock()
+	atomic.AddUint64(&p.rejected, 1)
+	switch p.policy {
+	case RejectCallerRuns:
+		p.run(t)
+		return nil
+	case RejectDiscard:
+		return nil
+	default:
+		return ErrWorkerPoolFull
+	}
+}
+
+// Shutdown 停止接收新任务并等待已提交的任务执行完成，ctx 被取消时直接返回
+// ctx.Err() ，剩余的任务仍然会在后台继续执行。阻塞在队列上的 Submit 返回
+// ErrWorkerPoolClosed ，等它们全部退出之后才关闭任务队列。
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+		go func() {
+			p.senders.Wait()
+			close(p.tasks)
+		}()
+	}
+	p.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats 返回 WorkerPool 的统计数据。
+func (p *WorkerPool) Stats() WorkerPoolStats {
+	return WorkerPoolStats{
+		Workers:    p.size,
+		Running:    atomic.LoadInt64(&p.running),
+		QueueDepth: len(p.tasks),
+		Completed:  atomic.LoadUint64(&p.completed),
+		Rejected:   atomic.LoadUint64(&p.rejected),
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/util"
+)
+
+func TestWorkerPool(t *testing.T) {
+
+	p := util.NewWorkerPool(2, util.WithQueueSize(4))
+
+	ctx, _ := knife.New(context.Background())
+	assert.Nil(t, knife.Set(ctx, "trace", "abc"))
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		mu     sync.Mutex
+		traces []interface{}
+	)
+	block := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		err := p.Submit(ctx, func(ctx context.Context) {
+			<-block
+			v, _ := knife.Get(ctx, "trace")
+			mu.Lock()
+			traces = append(traces, v, ctx.Err())
+			mu.Unlock()
+		})
+		assert.Nil(t, err)
+	}
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		s := p.Stats()
+		return s.Running == 2 && s.QueueDepth == 2
+	}, time.Second, time.Millisecond)
+
+	assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) {
+		panic("oops")
+	}))
+	close(block)
+
+	assert.Nil(t, p.Shutdown(context.Background()))
+	assert.Equal(t, p.Stats(), util.WorkerPoolStats{Workers: 2, Completed: 5})
+	assert.Equal(t, traces, []interface{}{
+		"abc", nil, "abc", nil, "abc", nil, "abc", nil,
+	})
+
+	err := p.Submit(context.Background(), func(ctx context.Context) {})
+	assert.Equal(t, err, util.ErrWorkerPoolClosed)
+}
+
+func TestWorkerPool_Shutdown(t *testing.T) {
+
+	p := util.NewWorkerPool(1)
+	block := make(chan struct{})
+	defer close(block)
+	assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) { <-block }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, p.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestWorkerPool_ShutdownBlockedSubmit(t *testing.T) {
+
+	p := util.NewWorkerPool(1, util.WithQueueSize(1))
+	block := make(chan struct{})
+	assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) { <-block }))
+	assert.Eventually(t, func() bool {
+		return p.Stats().Running == 1
+	}, time.Second, time.Millisecond)
+	assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) {}))
+
+	// 队列已满，RejectBlock 策略的 Submit 阻塞在队列上。
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.Submit(context.Background(), func(ctx context.Context) {})
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, p.Shutdown(ctx), context.DeadlineExceeded)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, <-errCh, util.ErrWorkerPoolClosed)
+
+	close(block)
+	assert.Nil(t, p.Shutdown(context.Background()))
+	assert.Equal(t, p.Stats().Completed, uint64(2))
+}
+
+func TestWorkerPool_RejectPolicy(t *testing.T) {
+
+	submit := func(policy util.RejectPolicy) (*util.WorkerPool, []error, chan struct{}) {
+		p := util.NewWorkerPool(1, util.WithQueueSize(1), util.WithRejectPolicy(policy))
+		block := make(chan struct{})
+		var errs []error
+		errs = append(errs, p.Submit(context.Background(), func(ctx context.Context) { <-block }))
+		assert.Eventually(t, func() bool {
+			return p.Stats().Running == 1
+		}, time.Second, time.Millisecond)
+		errs = append(errs, p.Submit(context.Background(), func(ctx context.Context) { <-block }))
+		assert.Equal(t, p.Stats().QueueDepth, 1)
+		return p, errs, block
+	}
+
+	p, errs, block := submit(util.RejectAbort)
+	assert.Equal(t, errs, []error{nil, nil})
+	assert.Equal(t, p.Submit(context.Background(), func(ctx context.Context) {}), util.ErrWorkerPoolFull)
+	assert.Equal(t, p.Stats().Rejected, uint64(1))
+	close(block)
+	assert.Nil(t, p.Shutdown(context.Background()))
+	assert.Equal(t, p.Stats().Completed, uint64(2))
+
+	p, _, block = submit(util.RejectDiscard)
+	ran := false
+	assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) { ran = true }))
+	close(block)
+	assert.Nil(t, p.Shutdown(context.Background()))
+	assert.False(t, ran)
+	assert.Equal(t, p.Stats().Rejected, uint64(1))
+
+	p, _, block = submit(util.RejectCallerRuns)
+	assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) { ran = true }))
+	assert.True(t, ran)
+	close(block)
+	assert.Nil(t, p.Shutdown(context.Background()))
+	assert.Equal(t, p.Stats().Completed, uint64(3))
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dync
+
+import (
+	"time"
+
+	"github.com/go-spring/spring-base/atomic"
+	"github.com/go-spring/spring-base/conf"
+)
+
+// Duration 可以动态刷新的 time.Duration 类型的属性值。
+type Duration struct {
+	v atomic.Duration
+}
+
+// Value 返回当前的属性值。
+func (x *Duration) Value() time.Duration {
+	return x.v.Load()
+}
+
+// Refresh 绑定新的属性值，绑定失败时保留原来的值。
+func (x *Duration) Refresh(p *conf.Properties, param conf.BindParam) error {
+	var v time.Duration
+	if err := bindValue(p, param, &v); err != nil {
+		return err
+	}
+	x.v.Store(v)
+	return nil
+}
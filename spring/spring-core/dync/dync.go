@@ -0,0 +1,39 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dync 提供可以动态刷新的属性值，bean 的字段使用这些类型时，配置热加载
+// 之后字段的值原子地更新为新的属性值，而不需要重新创建 bean ，适用于限流阈值、灰度
+// 比例这类需要在运行时调整的参数。例如：
+//
+//	type Limiter struct {
+//		Rate dync.Int64 `value:"${limiter.rate:=100}"`
+//	}
+//
+// 属性绑定失败时保留原来的值。
+package dync
+
+import (
+	"reflect"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// bindValue 将 param 对应的属性值绑定到 ptr 指向的对象上。
+func bindValue(p *conf.Properties, param conf.BindParam, ptr interface{}) error {
+	v := reflect.ValueOf(ptr).Elem()
+	param.Type = v.Type()
+	return conf.BindValue(p, v, param)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/dync"
+)
+
+type limiter struct {
+	Enabled dync.Bool     `value:"${enabled:=true}"`
+	Rate    dync.Int64    `value:"${rate}"`
+	Burst   dync.Uint64   `value:"${burst:=10}"`
+	Ratio   dync.Float64  `value:"${ratio:=0.5}"`
+	Name    dync.String   `value:"${name:=default}"`
+	Timeout dync.Duration `value:"${timeout:=1s}"`
+}
+
+func TestDynamicValues(t *testing.T) {
+
+	p := conf.Map(map[string]interface{}{
+		"limiter.rate": 100,
+	})
+
+	var l limiter
+	assert.Nil(t, p.Bind(&l, conf.Key("limiter")))
+	assert.True(t, l.Enabled.Value())
+	assert.Equal(t, l.Rate.Value(), int64(100))
+	assert.Equal(t, l.Burst.Value(), uint64(10))
+	assert.Equal(t, l.Ratio.Value(), 0.5)
+	assert.Equal(t, l.Name.Value(), "default")
+	assert.Equal(t, l.Timeout.Value(), time.Second)
+
+	p = conf.Map(map[string]interface{}{
+		"limiter.enabled": false,
+		"limiter.rate":    200,
+		"limiter.burst":   20,
+		"limiter.ratio":   0.8,
+		"limiter.name":    "api",
+		"limiter.timeout": "3s",
+	})
+	assert.Nil(t, p.Bind(&l, conf.Key("limiter")))
+	assert.False(t, l.Enabled.Value())
+	assert.Equal(t, l.Rate.Value(), int64(200))
+	assert.Equal(t, l.Burst.Value(), uint64(20))
+	assert.Equal(t, l.Ratio.Value(), 0.8)
+	assert.Equal(t, l.Name.Value(), "api")
+	assert.Equal(t, l.Timeout.Value(), 3*time.Second)
+
+	// 绑定失败时保留原来的值。
+	p = conf.Map(map[string]interface{}{
+		"limiter.rate": "abc",
+	})
+	err := p.Bind(&l.Rate, conf.Key("limiter.rate"))
+	assert.Error(t, err, "bind Int64 error: property \"limiter.rate\"\nstrconv.ParseInt: parsing \"abc\": invalid syntax")
+	assert.Equal(t, l.Rate.Value(), int64(200))
+}
@@ -1,6 +1,6 @@
 module github.com/go-spring/spring-core
 
-go 1.14
+go 1.18
 
 require (
 	github.com/go-spring/spring-base v1.1.0-rc3
@@ -9,4 +9,9 @@ require (
 	github.com/pelletier/go-toml v1.9.4
 )
 
- replace github.com/go-spring/spring-base => ../spring-base
+require (
+	github.com/magiconair/properties v1.8.5 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+)
+
+replace github.com/go-spring/spring-base => ../spring-base
@@ -23,17 +23,20 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/go-spring/spring-base/conf"
 	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
 	"github.com/go-spring/spring-core/grpc"
 	"github.com/go-spring/spring-core/gs/arg"
 	"github.com/go-spring/spring-core/gs/internal"
+	"github.com/go-spring/spring-core/health"
 	"github.com/go-spring/spring-core/mq"
 	"github.com/go-spring/spring-core/web"
 )
@@ -41,17 +44,37 @@ import (
 // SpringBannerVisible 是否显示 banner。
 const SpringBannerVisible = "spring.banner.visible"
 
-// AppRunner 命令行启动器接口
+// AppRunner 命令行启动器接口，按照 bean 的 Order 从小到大执行，Run 发生 panic
+// 视为执行失败，失败之后的处理方式由 spring.application.runner.failure-policy
+// 决定。
 type AppRunner interface {
 	Run(ctx Context)
 }
 
-// AppEvent 应用运行过程中的事件
+const (
+	RunnerFailureAbort    = "abort"    // 命令行启动器执行失败时终止启动
+	RunnerFailureContinue = "continue" // 命令行启动器执行失败时输出日志并继续启动
+)
+
+// AppEvent 应用运行过程中的事件。
+//
+// Deprecated: 使用 App.Listen 或者 EventListener 类型的 bean 监听 StartedEvent
+// 和 StoppingEvent 等有类型的事件。
 type AppEvent interface {
 	OnAppStart(ctx Context)        // 应用启动的事件
 	OnAppStop(ctx context.Context) // 应用停止的事件
 }
 
+// ShutdownPhase 应用关闭过程中的阶段。
+type ShutdownPhase string
+
+const (
+	ShutdownStarted        = ShutdownPhase("started")         // 开始关闭
+	ShutdownTrafficStopped = ShutdownPhase("traffic-stopped") // 已停止接收流量
+	ShutdownTasksDrained   = ShutdownPhase("tasks-drained")   // 后台任务已结束
+	ShutdownCompleted      = ShutdownPhase("completed")       // bean 已全部销毁
+)
+
 type tempApp struct {
 	router      web.Router
 	consumers   *Consumers
@@ -66,10 +89,45 @@ type App struct {
 	c *container
 	b *bootstrap
 
-	exitChan chan struct{}
-
-	Events  []AppEvent  `autowire:"${application-event.collection:=*?}"`
-	Runners []AppRunner `autowire:"${command-line-runner.collection:=*?}"`
+	exitChan   chan struct{}
+	exitMutex  sync.Mutex
+	exitErr    error // ShutdownWithError 设置的错误
+	listenerMu sync.RWMutex
+	listeners  []*EventListener
+	tasks      []*ScheduledTask
+	executor   *TaskExecutor
+	lifecycles []Lifecycle       // 已经启动的 Lifecycle
+	origins    map[string]string // 属性的来源
+	remote     remoteState
+	sources    propertySources
+	startup    startupTimer
+	readyMutex sync.RWMutex
+	ready      bool
+	stopMutex  sync.Mutex
+	stopHooks  []stopHook                  // OnStop 注册的清理函数
+	dryRun     bool                        // 是否为校验模式
+	hooks      map[HookPoint][]startupHook // OnStartup 注册的回调函数
+
+	HealthIndicators []health.Indicator `autowire:"${health-indicator.collection:=*?}"`
+
+	Events         []AppEvent       `autowire:"${application-event.collection:=*?}"`
+	Runners        []AppRunner      `autowire:"${command-line-runner.collection:=*?}"`
+	PanicHandlers  []PanicHandler   `autowire:"${panic-handler.collection:=*?}"`
+	EventListeners []*EventListener `autowire:"${application-event-listener.collection:=*?}"`
+	ScheduledTasks []*ScheduledTask `autowire:"${scheduled-task.collection:=*?}"`
+
+	// ShutdownTimeout 关闭应用时等待正在处理的请求和后台任务结束的最长时间，
+	// 超时之后直接销毁 bean ，为 0 时一直等待。
+	ShutdownTimeout time.Duration `value:"${spring.application.shutdown-timeout:=30s}"`
+
+	// ReadinessRetryInterval ReadyHook 返回错误之后重试的间隔。
+	ReadinessRetryInterval time.Duration `value:"${spring.application.readiness.retry-interval:=5s}"`
+
+	// RunnerParallel 是否并发执行命令行启动器，并发执行时 Order 不再生效。
+	RunnerParallel bool `value:"${spring.application.runner.parallel:=false}"`
+
+	// RunnerFailurePolicy 命令行启动器执行失败时的处理方式，abort 或者 continue 。
+	RunnerFailurePolicy string `value:"${spring.application.runner.failure-policy:=abort}"`
 }
 
 type Consumers struct {
@@ -120,6 +178,8 @@ func (app *App) Banner(banner string) {
 	app.banner = banner
 }
 
+// Run 启动应用并且等待应用退出，返回的错误是 *ExitError 类型，可以通过 ExitCode
+// 函数获取退出码。启动失败或者启动过程中发生 panic 时打印最后一条日志然后返回。
 func (app *App) Run() error {
 
 	// 响应控制台的 Ctrl+C 及 kill 命令。
@@ -130,19 +190,79 @@ func (app *App) Run() error {
 		app.ShutDown(fmt.Sprintf("signal %v", sig))
 	}()
 
-	if err := app.start(); err != nil {
+	app.c.panicHandler = app.onPanic
+
+	if err := app.startSafely(); err != nil {
+		app.stopLifecycles(context.Background())
+		log.Errorf("application failed to start, exit code %d: %v", ExitCode(err), err)
 		return err
 	}
 
+	if app.dryRun {
+		app.finishDryRun()
+		return nil
+	}
+
 	<-app.exitChan
 
+	err := exitError(ExitShutdownError, app.stop())
+
 	if app.b != nil {
 		app.b.c.Close()
 	}
 
-	app.c.Close()
-	log.Info("application exited")
-	return nil
+	app.exitMutex.Lock()
+	exitErr := app.exitErr
+	app.exitMutex.Unlock()
+	if exitErr != nil {
+		if err != nil {
+			log.Errorf("shutdown error: %v", err)
+		}
+		err = exitError(ExitRuntimeError, exitErr)
+	}
+
+	log.Infof("application exited, exit code %d", ExitCode(err))
+	return err
+}
+
+// stop 按照停止接收流量、等待后台任务结束、执行清理函数、销毁 bean 的顺序关闭
+// 应用，所有阶段共享 spring.application.shutdown-timeout 设置的超时时间。
+func (app *App) stop() error {
+
+	ctx := context.Background()
+	if app.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, app.ShutdownTimeout)
+		defer cancel()
+	}
+
+	app.setReady(false)
+	app.notifyShutdown(ctx, ShutdownStarted)
+
+	// 逆序通知应用停止事件，先启动的后停止。
+	for i := len(app.Events) - 1; i >= 0; i-- {
+		app.Events[i].OnAppStop(ctx)
+	}
+	app.stopLifecycles(ctx)
+	app.notifyShutdown(ctx, ShutdownTrafficStopped)
+
+	err := app.c.stopGoroutines(ctx)
+	app.notifyShutdown(ctx, ShutdownTasksDrained)
+
+	if hookErr := app.runStopHooks(ctx); err == nil {
+		err = hookErr
+	}
+
+	app.c.destroy()
+	app.notifyShutdown(ctx, ShutdownCompleted)
+	return err
+}
+
+func (app *App) notifyShutdown(ctx context.Context, phase ShutdownPhase) {
+	log.Infof("application shutdown phase %s", phase)
+	if err := app.Publish(ctx, StoppingEvent{Phase: phase}); err != nil {
+		log.Errorf("publish stopping event error: %v", err)
+	}
 }
 
 func (app *App) clear() {
@@ -155,6 +275,7 @@ func (app *App) clear() {
 
 func (app *App) start() error {
 
+	app.startup.begin()
 	app.Object(app)
 	app.Object(app.consumers)
 	app.Object(app.grpcServers)
@@ -169,51 +290,198 @@ func (app *App) start() error {
 		return err
 	}
 
-	showBanner, _ := strconv.ParseBool(e.p.Get(SpringBannerVisible))
-	if showBanner {
-		app.printBanner(app.getBanner(e))
-	}
-
 	if app.b != nil {
 		if err := app.b.start(e); err != nil {
 			return err
 		}
 	}
 
-	if err := app.loadProperties(e); err != nil {
+	app.startup.mark("prepare")
+
+	app.sources.config = e
+	app.sources.locators = []ResourceLocator{e.resourceLocator}
+	if app.b != nil {
+		app.sources.locators = append(app.sources.locators, app.b.resourceLocators...)
+	}
+
+	files, origins, err := app.loadProperties(e)
+	if err != nil {
+		return err
+	}
+
+	if err = app.loadRemoteProperties(app.c.ctx); err != nil {
+		return err
+	}
+
+	// 合并代码、配置文件、远程配置以及从环境变量和命令行解析的属性
+	app.sources.code = app.c.p
+	app.sources.files = files
+	app.sources.fileOrigins = origins
+	app.sources.env = e.p
+	app.sources.cmd = e.cmd
+	app.sources.mu.Lock()
+	app.sources.current, err = app.mergeProperties()
+	app.c.p, app.c.origins = app.sources.current, app.origins
+	app.sources.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	app.startup.mark("properties")
+
+	if dryRunRequested(app.c.p) {
+		app.dryRun = true
+	}
+
+	if err = app.configureLogging(app.c.p); err != nil {
+		return err
+	}
+
+	// 加载完所有属性之后再打印 banner ，这样 banner 中可以引用配置文件中的属性。
+	showBanner, _ := strconv.ParseBool(app.c.p.Get(SpringBannerVisible))
+	if showBanner {
+		app.printBanner(app.expandBanner(app.getBanner(e)))
+	}
+
+	app.registerHealth()
+
+	if err := app.registerExecutor(); err != nil {
 		return err
 	}
 
-	// 保存从环境变量和命令行解析的属性
-	for _, k := range e.p.Keys() {
-		app.c.p.Set(k, e.p.Get(k))
+	if err := app.registerMessageSource(e); err != nil {
+		return err
+	}
+
+	if err := app.runHooks(app.c.Context(), BeforeRefresh); err != nil {
+		return err
 	}
 
 	if err := app.c.Refresh(internal.AutoClear(false)); err != nil {
 		return err
 	}
+	app.startup.mark("refresh")
 
-	// 执行命令行启动器
-	for _, r := range app.Runners {
-		r.Run(app.c)
+	if app.dryRun {
+		return nil
+	}
+
+	if err := app.runHooks(app.c.Context(), AfterWiring); err != nil {
+		return err
+	}
+
+	if err := app.startAdmin(); err != nil {
+		return err
 	}
 
+	if err := app.startDebug(); err != nil {
+		return err
+	}
+
+	app.watchRemoteProperties()
+
+	if err := app.watchConfigFiles(); err != nil {
+		return err
+	}
+
+	ctx := app.c.Context()
+	if err := app.runHooks(ctx, BeforeServing); err != nil {
+		return err
+	}
+
+	if err := app.startLifecycles(ctx, app.collectLifecycles()); err != nil {
+		return err
+	}
+	app.startup.mark("lifecycles")
+
+	if err := app.Publish(ctx, StartedEvent{Context: app.c}); err != nil {
+		return err
+	}
+
+	if err := app.runRunners(); err != nil {
+		return err
+	}
+	app.startup.mark("runners")
+
 	// 通知应用启动事件
 	for _, event := range app.Events {
 		event.OnAppStart(app.c)
 	}
 
+	if err := app.startScheduler(); err != nil {
+		return err
+	}
+
+	if err := app.Publish(ctx, ReadyEvent{Context: app.c}); err != nil {
+		return err
+	}
+	app.awaitReady()
+	app.startup.mark("ready")
+
 	app.clear()
 
-	// 通知应用停止事件
-	app.c.Go(func(ctx context.Context) {
-		<-ctx.Done()
-		for _, event := range app.Events {
-			event.OnAppStop(context.Background())
+	log.Info("application started successfully")
+	app.logStartup()
+	return nil
+}
+
+// runRunners 顺序或者并发地执行命令行启动器。
+func (app *App) runRunners() error {
+
+	abort := true
+	switch app.RunnerFailurePolicy {
+	case RunnerFailureAbort:
+	case RunnerFailureContinue:
+		abort = false
+	default:
+		return fmt.Errorf("unknown runner failure policy %q", app.RunnerFailurePolicy)
+	}
+
+	run := func(r AppRunner) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = fmt.Errorf("runner %T failed: %v", r, v)
+			}
+		}()
+		r.Run(app.c)
+		return nil
+	}
+
+	if !app.RunnerParallel {
+		for _, r := range app.Runners {
+			if err := run(r); err != nil {
+				if abort {
+					return err
+				}
+				log.Error(err)
+			}
 		}
-	})
+		return nil
+	}
 
-	log.Info("application started successfully")
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs util.Errors
+	)
+	for _, r := range app.Runners {
+		wg.Add(1)
+		go func(r AppRunner) {
+			defer wg.Done()
+			if err := run(r); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs.Append(err)
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	if err := errs.ErrorOrNil(); err != nil {
+		if abort {
+			return err
+		}
+		log.Error(err)
+	}
 	return nil
 }
 
@@ -244,6 +512,27 @@ func (app *App) getBanner(e *configuration) string {
 	return banner
 }
 
+// expandBanner 展开 banner 中 ${spring.application.name} 形式的属性引用，
+// 另外还可以通过 ${spring.version} 引用 go-spring 的版本号，展开失败时原样返回。
+func (app *App) expandBanner(banner string) string {
+	if !strings.Contains(banner, "${") {
+		return banner
+	}
+	p := conf.New()
+	for _, k := range app.c.p.Keys() {
+		p.Set(k, app.c.p.Get(k))
+	}
+	if !p.Has("spring.version") {
+		p.Set("spring.version", Version)
+	}
+	s, err := p.Resolve(banner)
+	if err != nil {
+		log.Warnf("resolve banner error: %v", err)
+		return banner
+	}
+	return s
+}
+
 // printBanner 打印 banner 到控制台
 func (app *App) printBanner(banner string) {
 
@@ -273,54 +562,48 @@ func (app *App) printBanner(banner string) {
 	fmt.Println(string(padding) + Version + "\n")
 }
 
-func (app *App) loadProperties(e *configuration) error {
+// loadProperties 加载 application 文件以及所有激活的 profile 对应的文件，后加载
+// 的属性覆盖先加载的属性，因此 profile 文件优先于 application 文件，多个 profile
+// 之间排在后面的优先。配置文件中的 spring.config.import 指令会被递归处理。返回
+// 的 map 记录每个属性来自哪个文件。
+func (app *App) loadProperties(e *configuration) (*conf.Properties, map[string]string, error) {
 	var resources []Resource
 
 	for _, ext := range e.ConfigExtensions {
-		sources, err := app.loadResource(e, "application"+ext)
+		sources, err := app.loadResource("application" + ext)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		resources = append(resources, sources...)
 	}
 
 	for _, profile := range e.ActiveProfiles {
 		for _, ext := range e.ConfigExtensions {
-			sources, err := app.loadResource(e, "application-"+profile+ext)
+			sources, err := app.loadResource("application-" + profile + ext)
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 			resources = append(resources, sources...)
 		}
 	}
 
-	for _, resource := range resources {
-		b, err := ioutil.ReadAll(resource)
-		if err != nil {
-			return err
-		}
-		p, err := conf.Bytes(b, filepath.Ext(resource.Name()))
-		if err != nil {
-			return err
-		}
-		for _, key := range p.Keys() {
-			app.c.p.Set(key, p.Get(key))
+	ret := conf.New()
+	origins := make(map[string]string)
+	for i, resource := range resources {
+		if err := app.loadConfigResource(e, resource, ret, origins, nil); err != nil {
+			for _, r := range resources[i+1:] {
+				closeResource(r)
+			}
+			return nil, nil, err
 		}
 	}
 
-	return nil
+	return ret, origins, nil
 }
 
-func (app *App) loadResource(e *configuration, filename string) ([]Resource, error) {
-
-	var locators []ResourceLocator
-	locators = append(locators, e.resourceLocator)
-	if app.b != nil {
-		locators = append(locators, app.b.resourceLocators...)
-	}
-
+func (app *App) loadResource(filename string) ([]Resource, error) {
 	var resources []Resource
-	for _, locator := range locators {
+	for _, locator := range app.sources.locators {
 		sources, err := locator.Locate(filename)
 		if err != nil {
 			return nil, err
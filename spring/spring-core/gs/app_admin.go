@@ -0,0 +1,293 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/recorder"
+	"github.com/go-spring/spring-base/fastdev/replayer"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-core/web"
+)
+
+// adminConfig 管理服务器的配置，属性前缀为 spring.admin 。
+type adminConfig struct {
+	Enabled  bool   `value:"${enabled:=false}"`
+	Host     string `value:"${host:=127.0.0.1}"`
+	Port     int    `value:"${port:=8081}"`
+	Beans    bool   `value:"${endpoints.beans.enabled:=true}"`
+	Env      bool   `value:"${endpoints.env.enabled:=true}"`
+	Mappings bool   `value:"${endpoints.mappings.enabled:=true}"`
+	Deps     bool   `value:"${endpoints.dependencies.enabled:=true}"`
+	Startup  bool   `value:"${endpoints.startup.enabled:=true}"`
+	Metrics  bool   `value:"${endpoints.metrics.enabled:=true}"`
+	FastDev  bool   `value:"${endpoints.fastdev.enabled:=true}"`
+	Loggers  bool   `value:"${endpoints.loggers.enabled:=true}"`
+}
+
+// BeanInfo /beans 接口返回的 bean 信息。
+type BeanInfo struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Class     string   `json:"class"`
+	Source    string   `json:"source"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Primary   bool     `json:"primary,omitempty"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Exports   []string `json:"exports,omitempty"`
+}
+
+// PropertyInfo /env 接口返回的属性信息，Origin 是属性的来源。
+type PropertyInfo struct {
+	Value  string `json:"value"`
+	Origin string `json:"origin"`
+}
+
+// MappingInfo /mappings 接口返回的路由信息。
+type MappingInfo struct {
+	Methods []string `json:"methods"`
+	Path    string   `json:"path"`
+}
+
+const (
+	originEnvironment = "environment"  // 环境变量
+	originCommandLine = "command-line" // 命令行参数
+	originCode        = "code"         // 代码中设置
+)
+
+// startAdmin 在独立的端口上启动管理服务器，必须在容器刷新之后、清理之前调用，
+// bean 、属性以及路由等信息都是在此时生成的快照。
+func (app *App) startAdmin() error {
+
+	var cfg adminConfig
+	if err := app.c.p.Bind(&cfg, conf.Key("spring.admin")); err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	if cfg.Beans {
+		beans := app.beanInfos()
+		mux.HandleFunc("/beans", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, beans)
+		})
+	}
+	if cfg.Env {
+		env := app.propertyInfos()
+		mux.HandleFunc("/env", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, env)
+		})
+	}
+	if cfg.Mappings {
+		mappings := app.mappingInfos()
+		mux.HandleFunc("/mappings", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, mappings)
+		})
+	}
+	if cfg.Deps {
+		mux.HandleFunc("/dependencies", func(w http.ResponseWriter, r *http.Request) {
+			g := app.DependencyGraph()
+			if r.URL.Query().Get("format") == "dot" {
+				w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+				_ = g.DOT(w)
+				return
+			}
+			writeJSON(w, g)
+		})
+	}
+	if cfg.Startup {
+		mux.HandleFunc("/startup", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, app.startupInfo())
+		})
+	}
+	if cfg.Metrics {
+		start := time.Now()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			m := metrics(start)
+			if app.executor != nil {
+				m["executor"] = app.executor.Stats()
+			}
+			writeJSON(w, m)
+		})
+	}
+	if cfg.FastDev {
+		mux.HandleFunc("/fastdev", handleFastDev)
+	}
+	if cfg.Loggers {
+		mux.HandleFunc("/loggers", handleLoggers)
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Infof("admin server started on %s", l.Addr())
+
+	svr := &http.Server{Handler: mux}
+	app.c.Go(func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			_ = svr.Shutdown(context.Background())
+		}()
+		if err := svr.Serve(l); err != nil && err != http.ErrServerClosed {
+			log.Errorf("admin server error: %v", err)
+		}
+	})
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(b)
+}
+
+// beanInfos 返回所有有效 bean 的信息，按照注册顺序排列。
+func (app *App) beanInfos() []BeanInfo {
+	var ret []BeanInfo
+	for _, b := range app.c.beans {
+		if b.status == Deleted {
+			continue
+		}
+		info := BeanInfo{
+			Name:    b.BeanName(),
+			Type:    b.TypeName(),
+			Class:   b.getClass(),
+			Aliases: b.aliases,
+			Source:  b.FileLine(),
+			Primary: b.primary,
+		}
+		for _, s := range b.depends {
+			info.DependsOn = append(info.DependsOn, fmt.Sprint(s))
+		}
+		for _, t := range b.exports {
+			info.Exports = append(info.Exports, t.String())
+		}
+		ret = append(ret, info)
+	}
+	return ret
+}
+
+// DependencyGraph 返回单例 bean 之间的依赖关系图，需要在应用启动之后调用。
+func (app *App) DependencyGraph() *DependencyGraph {
+	return app.c.dependencyGraph()
+}
+
+// propertyInfos 返回所有属性的值和来源，敏感属性的值会被隐藏。
+func (app *App) propertyInfos() map[string]PropertyInfo {
+	app.sources.mu.Lock()
+	p, origins := app.c.p, app.origins
+	if app.sources.current != nil {
+		p = app.sources.current
+	}
+	app.sources.mu.Unlock()
+	ret := make(map[string]PropertyInfo)
+	for _, k := range p.Keys() {
+		origin, ok := origins[k]
+		if !ok {
+			origin = originCode
+		}
+		v := p.Get(k)
+		if isSensitiveKey(k) {
+			v = "******"
+		}
+		ret[k] = PropertyInfo{Value: v, Origin: origin}
+	}
+	return ret
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"password", "secret", "token", "credential"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingInfos 返回所有的路由信息，按照路径排序。
+func (app *App) mappingInfos() []MappingInfo {
+	var ret []MappingInfo
+	for _, m := range app.router.Mappers() {
+		methods := web.GetMethod(m.Method())
+		sort.Strings(methods)
+		ret = append(ret, MappingInfo{Methods: methods, Path: m.Path()})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Path < ret[j].Path })
+	return ret
+}
+
+func metrics(start time.Time) map[string]interface{} {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return map[string]interface{}{
+		"uptime":          time.Since(start).String(),
+		"goroutines":      runtime.NumGoroutine(),
+		"memory.alloc":    m.Alloc,
+		"memory.sys":      m.Sys,
+		"memory.heap":     m.HeapInuse,
+		"gc.count":        m.NumGC,
+		"gc.pause.total":  time.Duration(m.PauseTotalNs).String(),
+		"fastdev.actions": fastdev.ActionPoolStats(),
+	}
+}
+
+// handleFastDev GET 返回流量录制和回放的开关状态，POST 通过 record 和 replay
+// 参数修改开关状态。
+func handleFastDev(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		for name, set := range map[string]func(bool){
+			"record": recorder.SetRecordMode,
+			"replay": replayer.SetReplayMode,
+		} {
+			s := r.FormValue(name)
+			if s == "" {
+				continue
+			}
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s value %q", name, s), http.StatusBadRequest)
+				return
+			}
+			set(b)
+		}
+	}
+	writeJSON(w, map[string]bool{
+		"record": recorder.RecordMode(),
+		"replay": replayer.ReplayMode(),
+	})
+}
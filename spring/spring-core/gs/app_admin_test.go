@@ -0,0 +1,113 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/web"
+)
+
+type adminDepBean struct {
+	Bean *shutdownBean `autowire:"my-bean"`
+}
+
+func TestApp_Admin(t *testing.T) {
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	assert.Nil(t, l.Close())
+
+	os.Clearenv()
+	gs.Setenv("GS_DB_PASSWORD", "123456")
+	app := gs.NewApp()
+	app.Property("spring.admin.enabled", true)
+	app.Property("spring.admin.port", port)
+	app.Property("spring.admin.endpoints.metrics.enabled", false)
+	app.Object(&shutdownBean{}).Name("my-bean")
+	app.Object(&adminDepBean{}).Name("dep-bean")
+	app.GetMapping("/hello", func(ctx web.Context) {})
+
+	errCh := startApp(app)
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, path))
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	code, body := get("/beans")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Matches(t, body, `"name": "my-bean",\s+"type": "github.com/go-spring/spring-core/gs_test/gs_test.shutdownBean"`)
+
+	_, body = get("/env")
+	assert.Matches(t, body, `"db.password": \{\s+"value": "\*\*\*\*\*\*",\s+"origin": "environment"`)
+	assert.Matches(t, body, `"spring.admin.enabled": \{\s+"value": "true",\s+"origin": "code"`)
+
+	_, body = get("/mappings")
+	assert.Matches(t, body, `"methods": \[\s+"GET"\s+\],\s+"path": "/hello"`)
+
+	code, _ = get("/metrics")
+	assert.Equal(t, code, http.StatusNotFound)
+
+	const (
+		depID = "github.com/go-spring/spring-core/gs_test/gs_test.adminDepBean:dep-bean"
+		myID  = "github.com/go-spring/spring-core/gs_test/gs_test.shutdownBean:my-bean"
+	)
+	_, body = get("/dependencies")
+	assert.Matches(t, body, `"from": "`+depID+`",\s+"to": "`+myID+`"`)
+	_, body = get("/dependencies?format=dot")
+	assert.Matches(t, body, `^digraph beans \{\n`)
+	assert.Matches(t, body, `\t"`+depID+`" -> "`+myID+`";\n`)
+	assert.Matches(t, body, `\t"`+myID+`" \[label="my-bean\\ngithub.com/go-spring/spring-core/gs_test/gs_test.shutdownBean"\];\n`)
+
+	_, body = get("/startup")
+	assert.Matches(t, body, `"name": "refresh"`)
+	assert.Matches(t, body, `"name": "my-bean",\s+"self": "[^"]+",\s+"source": "[^"]+app_admin_test.go:\d+"`)
+
+	found := false
+	for _, e := range app.DependencyGraph().Edges {
+		if e.From == depID {
+			assert.Equal(t, e, gs.DependencyEdge{From: depID, To: myID})
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	resp, err := http.PostForm(fmt.Sprintf("http://127.0.0.1:%d/fastdev", port), url.Values{"record": {"x"}})
+	assert.Nil(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+	assert.Nil(t, resp.Body.Close())
+
+	_, body = get("/fastdev")
+	assert.Matches(t, body, `"record": false`)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
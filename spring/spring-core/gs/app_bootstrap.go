@@ -24,7 +24,8 @@ import (
 )
 
 type tempBootstrap struct {
-	resourceLocators []ResourceLocator `autowire:""`
+	resourceLocators []ResourceLocator      `autowire:"*?"`
+	remoteSources    []RemotePropertySource `autowire:"*?"`
 }
 
 type bootstrap struct {
@@ -34,7 +35,8 @@ type bootstrap struct {
 
 func newBootstrap() *bootstrap {
 	return &bootstrap{
-		c: New().(*container),
+		tempBootstrap: &tempBootstrap{},
+		c:             New().(*container),
 	}
 }
 
@@ -67,14 +69,27 @@ func (b *bootstrap) ResourceLocator(i interface{}) *BeanDefinition {
 	return b.c.register(NewBean(reflect.ValueOf(i))).Export((*ResourceLocator)(nil))
 }
 
+// RemotePropertySource 注册远程配置源。
+func (b *bootstrap) RemotePropertySource(i interface{}) *BeanDefinition {
+	return b.c.register(NewBean(reflect.ValueOf(i))).Export((*RemotePropertySource)(nil))
+}
+
 func (b *bootstrap) start(e *configuration) error {
 
-	b.c.Object(b)
+	b.c.Object(b.tempBootstrap)
 
 	if err := b.loadBootstrap(e); err != nil {
 		return err
 	}
 
+	// 环境变量按照宽松规则覆盖配置文件中的属性
+	for _, k := range b.c.p.Keys() {
+		if v, ok := e.env.Lookup(k); ok {
+			b.c.p.Set(k, v)
+		}
+	}
+	b.c.p.SetRelaxedEnv(e.env)
+
 	// 保存从环境变量和命令行解析的属性
 	for _, k := range e.p.Keys() {
 		b.c.p.Set(k, e.p.Get(k))
@@ -21,7 +21,10 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/go-spring/spring-base/chrono"
 	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/run"
 )
 
 // EnvPrefix 属性覆盖的环境变量需要携带该前缀。
@@ -34,38 +37,46 @@ const IncludeEnvPatterns = "INCLUDE_ENV_PATTERNS"
 const ExcludeEnvPatterns = "EXCLUDE_ENV_PATTERNS"
 
 type configuration struct {
-	p *conf.Properties
+	p   *conf.Properties // 环境变量和命令行参数
+	cmd *conf.Properties // 命令行参数，优先级最高
+	env *conf.RelaxedEnv // 按照宽松规则匹配属性的环境变量
 
 	resourceLocator  ResourceLocator
 	ActiveProfiles   []string `value:"${spring.profiles.active:=}"`
-	ConfigExtensions []string `value:"${spring.config.extensions:=.properties,.prop,.yaml,.yml,.toml,.tml}"`
+	ConfigExtensions []string `value:"${spring.config.extensions:=.properties,.prop,.yaml,.yml,.toml,.tml,.hcl,.env}"`
+	RunMode          string   `value:"${spring.run.mode:=}"`
+	FastDevTenant    string   `value:"${spring.fastdev.tenant:=}"`
+	TimeZone         string   `value:"${spring.time.zone:=}"`
 }
 
-// loadCmdArgs 加载 -name value 形式的命令行参数。
-func loadCmdArgs(p *conf.Properties) error {
-	for i := 0; i < len(os.Args); i++ {
-		s := os.Args[i]
+// loadCmdArgs 加载 --name=value 以及 -name value 形式的命令行参数，遇到 -- 时
+// 停止解析，之后的参数留给应用自己处理。
+func loadCmdArgs(args []string, p *conf.Properties) error {
+	for i := 0; i < len(args); i++ {
+		s := args[i]
+		if s == "--" {
+			return nil
+		}
 		if strings.HasPrefix(s, "--") {
 			ss := strings.SplitN(strings.TrimPrefix(s, "--"), "=", 2)
 			k, v := ss[0], ""
 			if len(ss) > 1 {
 				v = ss[1]
 			}
-			p.Set(k, v)
+			if err := p.Set(k, v); err != nil {
+				return err
+			}
 			continue
 		}
 		if strings.HasPrefix(s, "-") {
 			k, v := s[1:], ""
-			if i >= len(os.Args)-1 {
-				p.Set(k, v)
-				return nil
-			}
-			next := os.Args[i+1]
-			if !strings.HasPrefix(next, "-") && !strings.HasPrefix(next, "--") {
-				v = os.Args[i+1]
+			if i < len(args)-1 && !strings.HasPrefix(args[i+1], "-") {
+				v = args[i+1]
 				i++
 			}
-			p.Set(k, v)
+			if err := p.Set(k, v); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -73,7 +84,8 @@ func loadCmdArgs(p *conf.Properties) error {
 
 // loadSystemEnv 添加符合 includes 条件的环境变量，排除符合 excludes 条件的
 // 环境变量。如果发现存在允许通过环境变量覆盖的属性名，那么保存时转换成真正的属性名。
-func loadSystemEnv(p *conf.Properties) error {
+// 返回的 RelaxedEnv 包含所有符合条件的环境变量，EnvPrefix 前缀的环境变量去掉前缀。
+func loadSystemEnv(p *conf.Properties) (*conf.RelaxedEnv, error) {
 
 	toRex := func(patterns []string) ([]*regexp.Regexp, error) {
 		var rex []*regexp.Regexp
@@ -93,7 +105,7 @@ func loadSystemEnv(p *conf.Properties) error {
 	}
 	includeRex, err := toRex(includes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var excludes []string
@@ -102,7 +114,7 @@ func loadSystemEnv(p *conf.Properties) error {
 	}
 	excludeRex, err := toRex(excludes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	matches := func(rex []*regexp.Regexp, s string) bool {
@@ -114,6 +126,7 @@ func loadSystemEnv(p *conf.Properties) error {
 		return false
 	}
 
+	var environ []string
 	for _, env := range os.Environ() {
 		ss := strings.SplitN(env, "=", 2)
 		k, v := ss[0], ""
@@ -125,25 +138,63 @@ func loadSystemEnv(p *conf.Properties) error {
 			propKey = strings.ReplaceAll(propKey, "_", ".")
 			propKey = strings.ToLower(propKey)
 			p.Set(propKey, v)
+			environ = append(environ, strings.TrimPrefix(env, EnvPrefix))
 			continue
 		}
 		if matches(includeRex, k) && !matches(excludeRex, k) {
 			p.Set(k, v)
+			environ = append(environ, env)
 		}
 	}
-	return nil
+	return conf.NewRelaxedEnv(environ), nil
+}
+
+// trimProfiles 去掉 profile 两端的空白以及空的 profile ，例如 "dev, metrics" 。
+func trimProfiles(profiles []string) []string {
+	var ret []string
+	for _, s := range profiles {
+		if s = strings.TrimSpace(s); s != "" {
+			ret = append(ret, s)
+		}
+	}
+	return ret
 }
 
 func (e *configuration) prepare() error {
-	if err := loadSystemEnv(e.p); err != nil {
+	env, err := loadSystemEnv(e.p)
+	if err != nil {
 		return err
 	}
-	if err := loadCmdArgs(e.p); err != nil {
+	e.env = env
+	e.p.SetRelaxedEnv(env)
+	e.cmd = conf.New()
+	if err = loadCmdArgs(os.Args[1:], e.cmd); err != nil {
 		return err
 	}
+	for _, k := range e.cmd.Keys() {
+		e.p.Set(k, e.cmd.Get(k))
+	}
 	if err := e.p.Bind(e); err != nil {
 		return err
 	}
+	e.ActiveProfiles = trimProfiles(e.ActiveProfiles)
+	if e.RunMode != "" {
+		m, err := run.ParseMode(e.RunMode)
+		if err != nil {
+			return err
+		}
+		run.SetMode(m)
+	}
+	if e.FastDevTenant != "" {
+		if err := fastdev.SetTenant(e.FastDevTenant); err != nil {
+			return err
+		}
+	}
+	if e.TimeZone != "" {
+		if err := chrono.LoadLocation(e.TimeZone); err != nil {
+			return err
+		}
+	}
 	if err := e.p.Bind(e.resourceLocator); err != nil {
 		return err
 	}
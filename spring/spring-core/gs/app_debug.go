@@ -0,0 +1,77 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/log"
+)
+
+// debugConfig 调试服务器的配置，默认只监听本机地址。
+type debugConfig struct {
+	Enabled bool   `value:"${enabled:=false}"`
+	Host    string `value:"${host:=127.0.0.1}"`
+	Port    int    `value:"${port:=6060}"`
+}
+
+// startDebug 通过 spring.debug.pprof.enabled=true 在独立的端口上启动调试服务器，
+// 提供 /debug/pprof/ 下的性能分析接口以及 /debug/vars 下的 expvar 变量，调试服务
+// 器随着应用的退出而关闭。
+func (app *App) startDebug() error {
+
+	var cfg debugConfig
+	if err := app.c.p.Bind(&cfg, conf.Key("spring.debug.pprof")); err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Infof("debug server started on %s", l.Addr())
+
+	svr := &http.Server{Handler: mux}
+	app.c.Go(func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			_ = svr.Shutdown(context.Background())
+		}()
+		if err := svr.Serve(l); err != nil && err != http.ErrServerClosed {
+			log.Errorf("debug server error: %v", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+func TestApp_DebugServer(t *testing.T) {
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	assert.Nil(t, l.Close())
+
+	os.Clearenv()
+	app := gs.NewApp()
+	app.Property("spring.debug.pprof.enabled", true)
+	app.Property("spring.debug.pprof.port", port)
+
+	errCh := startApp(app)
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, path))
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	code, body := get("/debug/pprof/")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Matches(t, body, "goroutine")
+
+	code, body = get("/debug/pprof/goroutine?debug=1")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Matches(t, body, "goroutine profile: total")
+
+	code, body = get("/debug/vars")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Matches(t, body, `"memstats":`)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+
+	_, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/debug/vars", port))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"strconv"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/log"
+)
+
+// Validate 以校验模式运行应用：加载配置、解析占位符、创建 bean 并完成注入，然后
+// 销毁 bean 并返回，不会启动 Lifecycle 、命令行启动器、管理服务器等组件，因此
+// 也不会监听端口，可以在 CI 中提前发现缺失的 bean 以及错误的配置。命令行参数
+// --dry-run 或者属性 spring.main.dry-run=true 可以让 Run 以同样的方式运行。
+func (app *App) Validate() error {
+	app.dryRun = true
+	return app.Run()
+}
+
+// dryRunRequested 返回是否通过命令行参数或者属性开启了校验模式。
+func dryRunRequested(p *conf.Properties) bool {
+	if p.Has("dry-run") {
+		s := p.Get("dry-run")
+		if s == "" {
+			return true
+		}
+		b, _ := strconv.ParseBool(s)
+		return b
+	}
+	b, _ := strconv.ParseBool(p.Get("spring.main.dry-run"))
+	return b
+}
+
+// finishDryRun 校验通过后销毁所有的 bean 。
+func (app *App) finishDryRun() {
+	app.c.Close()
+	if app.b != nil {
+		app.b.c.Close()
+	}
+	app.clear()
+	log.Info("application validated successfully")
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type dryRunLifecycle struct {
+	r *shutdownRecorder
+}
+
+func (l *dryRunLifecycle) Start(ctx context.Context) error {
+	l.r.add("start")
+	return nil
+}
+
+func (l *dryRunLifecycle) Stop(ctx context.Context) error {
+	l.r.add("stop")
+	return nil
+}
+
+func (l *dryRunLifecycle) Phase() int { return 0 }
+
+type dryRunUser struct {
+	Zero *BeanZero `autowire:""`
+}
+
+func TestApp_DryRun(t *testing.T) {
+
+	t.Run("validate", func(t *testing.T) {
+		os.Clearenv()
+		r := new(shutdownRecorder)
+		app := gs.NewApp()
+		app.Object(&dryRunLifecycle{r: r}).Destroy(func(l *dryRunLifecycle) {
+			r.add("destroy")
+		})
+		assert.Nil(t, app.Validate())
+		assert.Equal(t, r.phases, []string{"destroy"})
+	})
+
+	t.Run("property", func(t *testing.T) {
+		os.Clearenv()
+		r := new(shutdownRecorder)
+		app := gs.NewApp()
+		app.Property("spring.main.dry-run", true)
+		app.Object(&dryRunLifecycle{r: r})
+		assert.Nil(t, app.Run())
+		assert.Nil(t, r.phases)
+	})
+
+	t.Run("missing bean", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Object(new(dryRunUser))
+		err := app.Validate()
+		assert.Equal(t, gs.ExitCode(err), gs.ExitStartupError)
+		assert.Error(t, err, "can't find bean")
+	})
+}
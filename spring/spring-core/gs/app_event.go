@@ -0,0 +1,158 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
+)
+
+// StartedEvent 容器刷新完成之后、执行命令行启动器之前发布的事件。
+type StartedEvent struct {
+	Context Context
+}
+
+// ReadyEvent 命令行启动器执行完成，应用可以对外提供服务时发布的事件。
+type ReadyEvent struct {
+	Context Context
+}
+
+// StoppingEvent 应用关闭过程中每个阶段发布一次的事件。
+type StoppingEvent struct {
+	Phase ShutdownPhase
+}
+
+// PropertiesReboundEvent 设置了属性前缀的 Refreshable bean 在前缀下的属性发生
+// 变化并且重新绑定成功之后发布的事件，Bean 是重新绑定的 bean 。
+type PropertiesReboundEvent struct {
+	Prefix string
+	Bean   interface{}
+}
+
+// ConfigChangedEvent 属性值发生变化时发布的事件，Keys 是发生变化的属性名。
+type ConfigChangedEvent struct {
+	Keys []string
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// EventListener 事件监听器，按照事件的类型接收事件。
+type EventListener struct {
+	fn    reflect.Value
+	typ   reflect.Type
+	async bool
+}
+
+// NewEventListener 创建事件监听器，fn 的形式为 func(context.Context, T) 或者
+// func(context.Context, T) error ，T 是监听的事件类型，可以是接口类型，此时
+// 接收所有实现了该接口的事件。
+func NewEventListener(fn interface{}) *EventListener {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 2 || t.In(0) != contextType {
+		panic(errors.New("listener should be func(context.Context, T) [error]"))
+	}
+	if t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		panic(errors.New("listener should be func(context.Context, T) [error]"))
+	}
+	return &EventListener{fn: reflect.ValueOf(fn), typ: t.In(1)}
+}
+
+// Async 在新的 goroutine 中执行监听器，Publish 不等待其执行完成，监听器返回
+// 的错误以及发生的 panic 只输出到日志。
+func (l *EventListener) Async() *EventListener {
+	l.async = true
+	return l
+}
+
+// EventType 返回监听的事件类型。
+func (l *EventListener) EventType() reflect.Type {
+	return l.typ
+}
+
+func (l *EventListener) accept(t reflect.Type) bool {
+	if l.typ.Kind() == reflect.Interface {
+		return t.Implements(l.typ)
+	}
+	return t == l.typ
+}
+
+func (l *EventListener) invoke(ctx context.Context, event interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("listener panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	out := l.fn.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(event)})
+	if len(out) > 0 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
+
+// Listen 注册事件监听器，参考 NewEventListener 的解释。
+func (app *App) Listen(fn interface{}) *EventListener {
+	l := NewEventListener(fn)
+	app.listenerMu.Lock()
+	app.listeners = append(app.listeners, l)
+	app.listenerMu.Unlock()
+	return l
+}
+
+// eventListeners 返回 Listen 注册的和 bean 形式的事件监听器的副本，事件可能在
+// 多个 goroutine 中同时发布，遍历副本避免和 Listen 发生竞争。
+func (app *App) eventListeners() []*EventListener {
+	app.listenerMu.RLock()
+	defer app.listenerMu.RUnlock()
+	ret := make([]*EventListener, 0, len(app.listeners)+len(app.EventListeners))
+	ret = append(ret, app.listeners...)
+	return append(ret, app.EventListeners...)
+}
+
+// Publish 发布事件，按照注册顺序调用所有接收该类型事件的监听器，同步执行的
+// 监听器返回的错误汇总之后返回。
+func (app *App) Publish(ctx context.Context, event interface{}) error {
+	if event == nil {
+		return errors.New("event can't be nil")
+	}
+	t := reflect.TypeOf(event)
+	var errs util.Errors
+	for _, l := range app.eventListeners() {
+		if !l.accept(t) {
+			continue
+		}
+		if l.async {
+			l := l
+			util.SafeGo(ctx, func(ctx context.Context) {
+				if err := l.invoke(ctx, event); err != nil {
+					log.Errorf("publish %s error: %v", t, err)
+				}
+			})
+			continue
+		}
+		errs.Append(l.invoke(ctx, event))
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type orderEvent interface {
+	OrderID() string
+}
+
+type orderCreated struct{ id string }
+
+func (e orderCreated) OrderID() string { return e.id }
+
+func TestApp_Publish(t *testing.T) {
+
+	os.Clearenv()
+	app := gs.NewApp()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	add := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, s)
+	}
+
+	app.Listen(func(ctx context.Context, e gs.StartedEvent) { add("started") })
+	app.Listen(func(ctx context.Context, e gs.ReadyEvent) { add("ready") })
+	app.Listen(func(ctx context.Context, e orderCreated) error {
+		add("created " + e.id)
+		return errors.New("sync error")
+	})
+	app.Object(gs.NewEventListener(func(ctx context.Context, e orderEvent) {
+		add("order " + e.OrderID())
+	})).Name("order-listener")
+	async := make(chan string, 1)
+	app.Listen(func(ctx context.Context, e orderCreated) error {
+		async <- "async " + e.id
+		return errors.New("async error")
+	}).Async()
+
+	errCh := startApp(app)
+
+	err := app.Publish(context.Background(), orderCreated{id: "1"})
+	assert.Error(t, err, "sync error")
+	assert.Equal(t, <-async, "async 1")
+
+	assert.Error(t, app.Publish(context.Background(), nil), "event can't be nil")
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+	assert.Equal(t, events, []string{"started", "ready", "created 1", "order 1"})
+
+	assert.Panic(t, func() {
+		gs.NewEventListener(func(e orderCreated) {})
+	}, "listener should be func\\(context.Context, T\\) \\[error\\]")
+}
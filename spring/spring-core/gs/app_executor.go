@@ -0,0 +1,111 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/util"
+)
+
+// TaskExecutorConfig 异步任务执行器的配置，属性前缀为 spring.task.execution 。
+// RejectPolicy 是任务队列已满时的处理方式，可以是 block、abort、caller-runs
+// 或者 discard 。
+type TaskExecutorConfig struct {
+	Enabled      bool   `value:"${enabled:=true}"`
+	CoreSize     int    `value:"${core-size:=8}"`
+	QueueSize    int    `value:"${queue-size:=100}"`
+	RejectPolicy string `value:"${reject-policy:=abort}"`
+}
+
+var rejectPolicies = map[string]util.RejectPolicy{
+	"block":       util.RejectBlock,
+	"abort":       util.RejectAbort,
+	"caller-runs": util.RejectCallerRuns,
+	"discard":     util.RejectDiscard,
+}
+
+// TaskExecutor 异步任务执行器，使用固定数量的 goroutine 执行提交的任务，应用
+// 关闭时等待已经提交的任务执行完成。应用代码应该注入 *TaskExecutor 执行异步
+// 任务，而不是随意启动 goroutine 。
+type TaskExecutor struct {
+	pool *util.WorkerPool
+}
+
+// NewTaskExecutor 创建异步任务执行器。
+func NewTaskExecutor(cfg TaskExecutorConfig) (*TaskExecutor, error) {
+	if cfg.CoreSize <= 0 {
+		return nil, fmt.Errorf("invalid task executor core size %d", cfg.CoreSize)
+	}
+	if cfg.QueueSize < 0 {
+		return nil, fmt.Errorf("invalid task executor queue size %d", cfg.QueueSize)
+	}
+	policy, ok := rejectPolicies[cfg.RejectPolicy]
+	if !ok {
+		return nil, fmt.Errorf("unknown task executor reject policy %q", cfg.RejectPolicy)
+	}
+	pool := util.NewWorkerPool(cfg.CoreSize, util.WithQueueSize(cfg.QueueSize), util.WithRejectPolicy(policy))
+	return &TaskExecutor{pool: pool}, nil
+}
+
+// Submit 提交异步任务，参考 util.WorkerPool.Submit 的解释。
+func (e *TaskExecutor) Submit(ctx context.Context, fn func(ctx context.Context)) error {
+	return e.pool.Submit(ctx, fn)
+}
+
+// Stats 返回执行器的统计数据。
+func (e *TaskExecutor) Stats() util.WorkerPoolStats {
+	return e.pool.Stats()
+}
+
+// Start 实现 Lifecycle 接口，执行器创建之后即可提交任务。
+func (e *TaskExecutor) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop 实现 Lifecycle 接口，停止接收新任务并等待已经提交的任务执行完成。
+func (e *TaskExecutor) Stop(ctx context.Context) error {
+	return e.pool.Shutdown(ctx)
+}
+
+// Phase 实现 Lifecycle 接口，执行器最先启动、最后停止，以便其他 Lifecycle
+// 停止时仍然可以提交任务。
+func (e *TaskExecutor) Phase() int {
+	return math.MinInt32
+}
+
+// registerExecutor 注册名为 task-executor 的异步任务执行器，可以通过
+// spring.task.execution.enabled=false 关闭。
+func (app *App) registerExecutor() error {
+	var cfg TaskExecutorConfig
+	if err := app.c.p.Bind(&cfg, conf.Key("spring.task.execution")); err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	e, err := NewTaskExecutor(cfg)
+	if err != nil {
+		return err
+	}
+	app.executor = e
+	app.Object(e).Name("task-executor")
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type executorUser struct {
+	Executor *gs.TaskExecutor `autowire:""`
+}
+
+func TestApp_TaskExecutor(t *testing.T) {
+
+	t.Run("drain", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Property("spring.task.execution.core-size", 2)
+		app.Property("spring.task.execution.queue-size", 4)
+		user := new(executorUser)
+		app.Object(user)
+
+		errCh := startApp(app)
+
+		var mu sync.Mutex
+		count := 0
+		submit := func(n int) {
+			for i := 0; i < n; i++ {
+				err := user.Executor.Submit(context.Background(), func(ctx context.Context) {
+					time.Sleep(50 * time.Millisecond)
+					mu.Lock()
+					count++
+					mu.Unlock()
+				})
+				assert.Nil(t, err)
+			}
+		}
+		submit(2)
+		assert.Eventually(t, func() bool {
+			return user.Executor.Stats().Running == 2
+		}, time.Second, time.Millisecond)
+		submit(4)
+		err := user.Executor.Submit(context.Background(), func(ctx context.Context) {})
+		assert.Equal(t, err, util.ErrWorkerPoolFull)
+		assert.Equal(t, user.Executor.Stats().Workers, 2)
+
+		app.ShutDown("run test end")
+		assert.Nil(t, <-errCh)
+		assert.Equal(t, count, 6)
+		assert.Equal(t, user.Executor.Stats().Rejected, uint64(1))
+		err = user.Executor.Submit(context.Background(), func(ctx context.Context) {})
+		assert.Equal(t, err, util.ErrWorkerPoolClosed)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Property("spring.task.execution.reject-policy", "drop")
+		assert.Error(t, app.Run(), "unknown task executor reject policy \"drop\"")
+
+		app = gs.NewApp()
+		app.Property("spring.task.execution.enabled", false)
+		app.Object(new(executorUser))
+		assert.Error(t, app.Run(), "can't find bean, bean:\"\" type:\"\\*gs.TaskExecutor\"")
+	})
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/go-spring/spring-base/log"
+)
+
+// 应用的退出码，通过 ExitCode 函数从 Run 返回的错误中获取。
+const (
+	ExitOK            = 0 // 正常退出
+	ExitStartupError  = 1 // 启动失败
+	ExitStartupPanic  = 2 // 启动过程中发生 panic
+	ExitRuntimeError  = 3 // 运行时通过 ShutdownWithError 退出
+	ExitShutdownError = 4 // 关闭应用时发生错误
+)
+
+// ExitError 带有退出码的错误，Run 返回的错误都是这种类型。
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode 返回 err 对应的退出码，通常这样使用 os.Exit(gs.ExitCode(gs.Run())) 。
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var e *ExitError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ExitStartupError
+}
+
+func exitError(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// PanicHandler 处理容器管理的 goroutine 中发生的 panic ，例如通过 App.Go 启动的
+// goroutine ，可以在这里上报告警或者调用 App.ShutdownWithError 退出应用。没有注
+// 册 PanicHandler 时只打印日志。
+type PanicHandler interface {
+	OnPanic(r interface{}, stack []byte)
+}
+
+// onPanic 将 goroutine 中发生的 panic 交给 PanicHandler 处理。
+func (app *App) onPanic(r interface{}, stack []byte) {
+	log.Panicf("goroutine panic: %v\n%s", r, stack)
+	for _, h := range app.PanicHandlers {
+		app.handlePanic(h, r, stack)
+	}
+}
+
+func (app *App) handlePanic(h PanicHandler, r interface{}, stack []byte) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Errorf("panic handler %T panic: %v", h, v)
+		}
+	}()
+	h.OnPanic(r, stack)
+}
+
+// startSafely 启动应用，启动过程中发生的 panic 被转换为错误。
+func (app *App) startSafely() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = exitError(ExitStartupPanic, fmt.Errorf("startup panic: %v\n%s", r, debug.Stack()))
+		}
+	}()
+	return exitError(ExitStartupError, app.start())
+}
+
+// ShutdownWithError 以错误的方式关闭应用，应用按照正常的流程关闭，Run 返回的错误
+// 包含 err 并且退出码为 ExitRuntimeError ，多次调用时只保留第一个错误。
+func (app *App) ShutdownWithError(err error) {
+	app.exitMutex.Lock()
+	if app.exitErr == nil {
+		app.exitErr = err
+	}
+	app.exitMutex.Unlock()
+	app.ShutDown(fmt.Sprintf("with error: %v", err))
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type panicRecorder struct {
+	app    *gs.App
+	values chan interface{}
+}
+
+func (h *panicRecorder) OnPanic(r interface{}, stack []byte) {
+	h.values <- r
+	h.app.ShutdownWithError(fmt.Errorf("goroutine panic: %v", r))
+}
+
+func TestApp_ExitCode(t *testing.T) {
+
+	assert.Equal(t, gs.ExitCode(nil), gs.ExitOK)
+	assert.Equal(t, gs.ExitCode(errors.New("error")), gs.ExitStartupError)
+
+	t.Run("startup error", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Property("spring.application.runner.failure-policy", "unknown")
+		err := app.Run()
+		assert.Error(t, err, "unknown runner failure policy \"unknown\"")
+		assert.Equal(t, gs.ExitCode(err), gs.ExitStartupError)
+	})
+
+	t.Run("startup panic", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Object(new(slowDep)).Init(func(*slowDep) { panic("boom") })
+		err := app.Run()
+		assert.Error(t, err, "startup panic: boom")
+		assert.Equal(t, gs.ExitCode(err), gs.ExitStartupPanic)
+	})
+
+	t.Run("goroutine panic", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		h := &panicRecorder{app: app, values: make(chan interface{}, 1)}
+		app.Object(h).Export((*gs.PanicHandler)(nil))
+
+		errCh := startApp(app)
+
+		app.Go(func(ctx context.Context) { panic("oops") })
+		assert.Equal(t, <-h.values, "oops")
+		err := <-errCh
+		assert.Error(t, err, "goroutine panic: oops")
+		assert.Equal(t, gs.ExitCode(err), gs.ExitRuntimeError)
+	})
+
+	t.Run("shutdown with error", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		errCh := startApp(app)
+
+		cause := errors.New("lost connection")
+		app.ShutdownWithError(cause)
+		app.ShutdownWithError(errors.New("ignored"))
+		err := <-errCh
+		assert.True(t, errors.Is(err, cause))
+		assert.Equal(t, gs.ExitCode(err), gs.ExitRuntimeError)
+	})
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/health"
+	"github.com/go-spring/spring-core/web"
+)
+
+const (
+	HealthEnabled       = "spring.health.enabled"
+	HealthDiskPath      = "spring.health.disk.path"
+	HealthDiskThreshold = "spring.health.disk.threshold"
+)
+
+// registerHealth 注册 /healthz 和 /readyz 两个健康检查接口以及内置的健康指示器，
+// 可以通过 spring.health.enabled=false 关闭。
+func (app *App) registerHealth() {
+
+	if enabled, _ := strconv.ParseBool(app.c.p.Get(HealthEnabled, conf.Def("true"))); !enabled {
+		return
+	}
+
+	threshold, err := conf.ParseDataSize(app.c.p.Get(HealthDiskThreshold, conf.Def("10MB")))
+	if err != nil {
+		threshold = 10 * conf.MegaByte
+	}
+	path := app.c.p.Get(HealthDiskPath, conf.Def("."))
+	app.Object(health.NewDiskIndicator(path, uint64(threshold))).Name("disk-health-indicator").Export((*health.Indicator)(nil))
+
+	config := new(configIndicator)
+	app.Object(config).Name("config-health-indicator").Export((*health.Indicator)(nil))
+	app.Listen(config.onChanged)
+
+	app.router.GetMapping("/healthz", func(ctx web.Context) {
+		var indicators []health.Indicator
+		for _, i := range app.HealthIndicators {
+			if l, ok := i.(health.LivenessIndicator); ok && l.Liveness() {
+				indicators = append(indicators, i)
+			}
+		}
+		writeHealth(ctx, health.Aggregate(ctx.Context(), indicators))
+	})
+
+	app.router.GetMapping("/readyz", func(ctx web.Context) {
+		r := health.Aggregate(ctx.Context(), app.HealthIndicators)
+		if !app.isReady() {
+			r.Status = health.StatusDown
+		}
+		writeHealth(ctx, r)
+	})
+}
+
+func writeHealth(ctx web.Context, r health.Report) {
+	if r.Status == health.StatusUp {
+		ctx.SetStatus(http.StatusOK)
+	} else {
+		ctx.SetStatus(http.StatusServiceUnavailable)
+	}
+	ctx.JSON(r)
+}
+
+// isReady 返回应用是否已经就绪，即发布了 ReadyEvent 并且还没有开始关闭。
+func (app *App) isReady() bool {
+	app.readyMutex.RLock()
+	defer app.readyMutex.RUnlock()
+	return app.ready
+}
+
+func (app *App) setReady(ready bool) {
+	app.readyMutex.Lock()
+	defer app.readyMutex.Unlock()
+	app.ready = ready
+}
+
+// configIndicator 配置的健康指示器，汇报最近一次配置变更的时间和属性。
+type configIndicator struct {
+	mu      sync.RWMutex
+	changed time.Time
+	keys    []string
+}
+
+func (c *configIndicator) Name() string {
+	return "config"
+}
+
+func (c *configIndicator) Health(ctx context.Context) health.Health {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.changed.IsZero() {
+		return health.Up(nil)
+	}
+	return health.Up(map[string]interface{}{
+		"lastChanged": c.changed.Format(time.RFC3339),
+		"keys":        c.keys,
+	})
+}
+
+func (c *configIndicator) onChanged(ctx context.Context, e ConfigChangedEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changed = time.Now()
+	c.keys = e.Keys
+}
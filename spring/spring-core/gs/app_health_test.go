@@ -0,0 +1,77 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/health"
+	"github.com/go-spring/spring-core/web"
+)
+
+func TestApp_Health(t *testing.T) {
+
+	os.Clearenv()
+	app := gs.NewApp()
+	app.Object(health.NewIndicator("db", func(ctx context.Context) health.Health {
+		return health.Up(nil)
+	})).Export((*health.Indicator)(nil))
+
+	var router web.Router
+	app.Provide(func(r web.Router) bool {
+		router = r
+		return true
+	})
+
+	call := func(path string) (int, string) {
+		for _, m := range router.Mappers() {
+			if m.Path() != path {
+				continue
+			}
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+			ctx := web.NewBaseContext(path, m.Handler(), r, &web.BufferedResponseWriter{ResponseWriter: w})
+			m.Handler().Invoke(ctx)
+			return w.Code, w.Body.String()
+		}
+		return 0, ""
+	}
+
+	errCh := startApp(app)
+
+	code, body := call("/healthz")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, body, `{"status":"UP"}`)
+
+	code, body = call("/readyz")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Matches(t, body, `"db":\{"status":"UP"\}`)
+	assert.Matches(t, body, `"config":\{"status":"UP"\}`)
+
+	assert.Nil(t, app.Publish(context.Background(), gs.ConfigChangedEvent{Keys: []string{"a"}}))
+	_, body = call("/readyz")
+	assert.Matches(t, body, `"config":\{"status":"UP","details":\{"keys":\["a"\],"lastChanged":".*"\}\}`)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-spring/spring-base/log"
+)
+
+// HookPoint 应用启动过程中执行回调的时机。
+type HookPoint string
+
+const (
+	BeforeRefresh = HookPoint("before-refresh") // 容器刷新之前，此时 bean 还未创建
+	AfterWiring   = HookPoint("after-wiring")   // 所有单例 bean 注入完成之后
+	BeforeServing = HookPoint("before-serving") // 启动 Lifecycle 和 Web 服务器之前
+	Ready         = HookPoint("ready")          // 发布 ReadyEvent 之后，全部成功时应用才就绪
+)
+
+// AfterWiringHook 需要在所有单例 bean 注入完成之后执行检查的 bean 实现此接口，
+// 例如检查数据库表结构，返回错误时应用启动失败。
+type AfterWiringHook interface {
+	AfterWiring(ctx context.Context) error
+}
+
+// BeforeServingHook 需要在应用开始接收流量之前执行准备工作的 bean 实现此接口，
+// 返回错误时应用启动失败。
+type BeforeServingHook interface {
+	BeforeServing(ctx context.Context) error
+}
+
+// ReadyHook 需要预热的 bean 实现此接口，例如预加载缓存。所有 ReadyHook 都返回
+// 成功之前 /readyz 接口返回失败，返回错误的 ReadyHook 每隔
+// spring.application.readiness.retry-interval 重试一次，直到成功或者应用关闭。
+type ReadyHook interface {
+	Ready(ctx context.Context) error
+}
+
+// startupHook 启动过程中执行的回调函数。
+type startupHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// OnStartup 注册应用启动过程中 point 时机执行的回调函数，同一时机的回调函数先于
+// 实现了对应接口的 bean 执行，并且按照注册的顺序执行。BeforeRefresh 时 bean 还
+// 未创建，只能通过这种方式注册。
+func (app *App) OnStartup(point HookPoint, fn func(ctx context.Context) error) {
+	if app.hooks == nil {
+		app.hooks = make(map[HookPoint][]startupHook)
+	}
+	h := startupHook{name: fmt.Sprintf("%s hook #%d", point, len(app.hooks[point])+1), fn: fn}
+	app.hooks[point] = append(app.hooks[point], h)
+}
+
+// collectHooks 返回 point 时机需要执行的回调函数，包括通过 OnStartup 注册的函数
+// 以及实现了对应接口并且已经完成注入的 bean ，必须在容器刷新之后、清理之前调用。
+func (app *App) collectHooks(point HookPoint) []startupHook {
+	hooks := append([]startupHook(nil), app.hooks[point]...)
+	if point == BeforeRefresh {
+		return hooks
+	}
+	for _, b := range app.c.beans {
+		if !b.Wired() {
+			continue
+		}
+		var fn func(ctx context.Context) error
+		switch v := b.Interface(); point {
+		case AfterWiring:
+			if h, ok := v.(AfterWiringHook); ok {
+				fn = h.AfterWiring
+			}
+		case BeforeServing:
+			if h, ok := v.(BeforeServingHook); ok {
+				fn = h.BeforeServing
+			}
+		case Ready:
+			if h, ok := v.(ReadyHook); ok {
+				fn = h.Ready
+			}
+		}
+		if fn != nil {
+			hooks = append(hooks, startupHook{name: b.String(), fn: fn})
+		}
+	}
+	return hooks
+}
+
+// runHooks 依次执行 point 时机的回调函数，遇到错误时立即返回。
+func (app *App) runHooks(ctx context.Context, point HookPoint) error {
+	for _, h := range app.collectHooks(point) {
+		if err := h.fn(ctx); err != nil {
+			return fmt.Errorf("%s error: %w", h.name, err)
+		}
+	}
+	return nil
+}
+
+// awaitReady 在后台执行所有的 ReadyHook ，全部成功之后应用才进入就绪状态，没有
+// ReadyHook 时应用立即就绪。
+func (app *App) awaitReady() {
+	hooks := app.collectHooks(Ready)
+	if len(hooks) == 0 {
+		app.setReady(true)
+		return
+	}
+	interval := app.ReadinessRetryInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	app.c.Go(func(ctx context.Context) {
+		for len(hooks) > 0 {
+			var failed []startupHook
+			for _, h := range hooks {
+				if err := h.fn(ctx); err != nil {
+					log.Warnf("%s not ready: %v", h.name, err)
+					failed = append(failed, h)
+				}
+			}
+			if hooks = failed; len(hooks) == 0 {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+		select {
+		case <-app.exitChan: // 应用已经开始关闭
+		default:
+			app.setReady(true)
+			log.Info("application is ready")
+		}
+	})
+}
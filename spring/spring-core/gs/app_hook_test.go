@@ -0,0 +1,132 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/web"
+)
+
+type warmupBean struct {
+	r     *shutdownRecorder
+	mu    sync.Mutex
+	tries int
+}
+
+func (b *warmupBean) AfterWiring(ctx context.Context) error {
+	b.r.add("bean after-wiring")
+	return nil
+}
+
+func (b *warmupBean) BeforeServing(ctx context.Context) error {
+	b.r.add("bean before-serving")
+	return nil
+}
+
+func (b *warmupBean) Ready(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tries++; b.tries < 3 {
+		return errors.New("cache not loaded")
+	}
+	return nil
+}
+
+func (b *warmupBean) Tries() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tries
+}
+
+func TestApp_StartupHooks(t *testing.T) {
+
+	t.Run("ready", func(t *testing.T) {
+		os.Clearenv()
+		r := new(shutdownRecorder)
+		app := gs.NewApp()
+		app.Property("spring.application.readiness.retry-interval", "20ms")
+		for _, point := range []gs.HookPoint{gs.BeforeRefresh, gs.AfterWiring, gs.BeforeServing, gs.Ready} {
+			point := point
+			app.OnStartup(point, func(ctx context.Context) error {
+				r.add(string(point))
+				return nil
+			})
+		}
+		app.Listen(func(ctx context.Context, e gs.StartedEvent) { r.add("started") })
+		warmup := &warmupBean{r: r}
+		app.Object(warmup)
+
+		var router web.Router
+		app.Provide(func(r web.Router) bool {
+			router = r
+			return true
+		})
+		readyz := func() int {
+			for _, m := range router.Mappers() {
+				if m.Path() == "/readyz" {
+					req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+					w := httptest.NewRecorder()
+					ctx := web.NewBaseContext("/readyz", m.Handler(), req, &web.BufferedResponseWriter{ResponseWriter: w})
+					m.Handler().Invoke(ctx)
+					return w.Code
+				}
+			}
+			return 0
+		}
+
+		errCh := startApp(app)
+
+		assert.Equal(t, readyz(), http.StatusServiceUnavailable)
+		assert.Eventually(t, func() bool {
+			return readyz() == http.StatusOK
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, warmup.Tries(), 3)
+		assert.Equal(t, r.list(), []string{
+			"before-refresh",
+			"after-wiring",
+			"bean after-wiring",
+			"before-serving",
+			"bean before-serving",
+			"started",
+			"ready",
+		})
+
+		app.ShutDown("run test end")
+		assert.Nil(t, <-errCh)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.OnStartup(gs.AfterWiring, func(ctx context.Context) error {
+			return errors.New("schema mismatch")
+		})
+		err := app.Run()
+		assert.Equal(t, gs.ExitCode(err), gs.ExitStartupError)
+		assert.Error(t, err, "after-wiring hook #1 error: schema mismatch")
+	})
+}
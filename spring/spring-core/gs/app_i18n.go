@@ -0,0 +1,107 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/web/i18n"
+)
+
+// messagesConfig 国际化消息的配置，属性前缀为 spring.messages 。Locales 用于
+// 指定额外的语言，本地文件系统中的 {basename}_{locale} 文件会被自动发现。
+type messagesConfig struct {
+	Enabled       bool     `value:"${enabled:=true}"`
+	Basename      string   `value:"${basename:=messages}"`
+	DefaultLocale string   `value:"${default-locale:=}"`
+	Locales       []string `value:"${locales:=}"`
+}
+
+// registerMessageSource 从配置文件目录加载 {basename}.{ext} 以及所有语言的
+// {basename}_{locale}.{ext} 文件，注册名为 message-source 的 *i18n.MessageSource
+// 对象，可以通过 spring.messages.enabled=false 关闭。
+func (app *App) registerMessageSource(e *configuration) error {
+
+	var cfg messagesConfig
+	if err := app.c.p.Bind(&cfg, conf.Key("spring.messages")); err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+
+	s := i18n.NewMessageSource(cfg.DefaultLocale)
+	locales := append([]string{""}, cfg.Locales...)
+	locales = append(locales, app.discoverLocales(cfg.Basename, e.ConfigExtensions)...)
+
+	loaded := make(map[string]bool)
+	for _, locale := range locales {
+		locale = i18n.NormalizeLocale(locale)
+		if loaded[locale] {
+			continue
+		}
+		loaded[locale] = true
+		name := cfg.Basename
+		if locale != "" {
+			name += "_" + strings.Replace(locale, "-", "_", -1)
+		}
+		for _, ext := range e.ConfigExtensions {
+			resources, err := app.loadResource(name + ext)
+			if err != nil {
+				return err
+			}
+			for i, resource := range resources {
+				p, err := readResource(resource)
+				if err != nil {
+					for _, r := range resources[i+1:] {
+						closeResource(r)
+					}
+					return err
+				}
+				s.Add(locale, p)
+			}
+		}
+	}
+
+	app.Object(s).Name("message-source")
+	return nil
+}
+
+// discoverLocales 在本地文件系统的配置文件目录中查找 {basename}_{locale} 文件，
+// 返回文件名中的语言。
+func (app *App) discoverLocales(basename string, extensions []string) []string {
+	var ret []string
+	for _, locator := range app.sources.locators {
+		l, ok := locator.(*defaultResourceLocator)
+		if !ok {
+			continue
+		}
+		for _, location := range l.configLocations {
+			for _, ext := range extensions {
+				pattern := filepath.Join(location, basename+"_*"+ext)
+				matches, _ := filepath.Glob(pattern)
+				for _, m := range matches {
+					name := strings.TrimSuffix(filepath.Base(m), ext)
+					ret = append(ret, strings.TrimPrefix(name, basename+"_"))
+				}
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/web/i18n"
+)
+
+type messageUser struct {
+	Messages *i18n.MessageSource `autowire:""`
+}
+
+func TestApp_MessageSource(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "messages")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"messages.properties":       "error.not-found=not found: {0}\napp.name=demo\n",
+		"messages_zh.properties":    "error.not-found=未找到：{0}\n",
+		"messages_zh_TW.properties": "error.not-found=找不到：{0}\n",
+		"i18n_fr.properties":        "error.not-found=introuvable : {0}\n",
+	} {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		assert.Nil(t, err)
+	}
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	app := gs.NewApp()
+	app.Property("spring.messages.default-locale", "zh_CN")
+	user := new(messageUser)
+	app.Object(user)
+
+	errCh := startApp(app)
+
+	s := user.Messages
+	assert.NotNil(t, s)
+	assert.Equal(t, s.Locales(), []string{"zh", "zh-TW"})
+
+	msg, err := s.Message("zh-TW", "error.not-found", "/a")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "找不到：/a")
+
+	msg, err = s.Message("en-US", "error.not-found", "/a")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "未找到：/a")
+
+	msg, err = s.Message("zh-TW", "app.name")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "demo")
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/log"
+)
+
+// SpringConfigImport 配置文件中导入其他配置的指令，多个导入项使用逗号分隔，例如
+// file:./extra/,optional:file:/etc/app/shared.yaml 。file: 前缀可以省略，相对
+// 路径相对于声明导入的配置文件所在的目录，以 / 结尾或者指向目录的导入项加载该目录
+// 下的 application 文件以及激活的 profile 文件，optional: 前缀表示导入项不存在
+// 时忽略。被导入的属性优先于声明导入的配置文件中的属性。
+const SpringConfigImport = "spring.config.import"
+
+const (
+	importOptional = "optional:"
+	importFile     = "file:"
+)
+
+// loadConfigResource 加载配置资源中的属性并递归处理其中的导入指令，stack 是正在
+// 加载的配置文件链，用于检测循环导入。
+func (app *App) loadConfigResource(e *configuration, resource Resource, ret *conf.Properties, origins map[string]string, stack []string) error {
+
+	p, err := readResource(resource)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range p.Keys() {
+		ret.Set(key, p.Get(key))
+		origins[key] = resource.Name()
+	}
+
+	s := p.Get(SpringConfigImport)
+	if s == "" {
+		return nil
+	}
+
+	stack = append(stack, resourcePath(resource))
+	for _, location := range strings.Split(s, ",") {
+		if location = strings.TrimSpace(location); location == "" {
+			continue
+		}
+		resources, err := app.importResources(e, resource, location)
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			for i, path := range stack {
+				if path == resourcePath(r) {
+					closeResource(r)
+					chain := append(stack[i:], path)
+					return fmt.Errorf("circular config import %s", strings.Join(chain, " -> "))
+				}
+			}
+			if err = app.loadConfigResource(e, r, ret, origins, stack); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importResources 返回导入项 location 对应的配置资源。
+func (app *App) importResources(e *configuration, from Resource, location string) ([]Resource, error) {
+
+	optional := strings.HasPrefix(location, importOptional)
+	path := strings.TrimPrefix(location, importOptional)
+
+	if i := strings.Index(path, ":"); i > 0 && !strings.HasPrefix(path, importFile) && !filepath.IsAbs(path) {
+		if optional {
+			log.Warnf("ignore unsupported config import %q", location)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unsupported config import %q", location)
+	}
+
+	path = strings.TrimPrefix(path, importFile)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(resourcePath(from)), path)
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		if optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("config import %q not found", location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return []Resource{file}, nil
+	}
+
+	var filenames []string
+	for _, ext := range e.ConfigExtensions {
+		filenames = append(filenames, "application"+ext)
+	}
+	for _, profile := range e.ActiveProfiles {
+		for _, ext := range e.ConfigExtensions {
+			filenames = append(filenames, "application-"+profile+ext)
+		}
+	}
+
+	var resources []Resource
+	for _, filename := range filenames {
+		file, err := os.Open(filepath.Join(path, filename))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			for _, r := range resources {
+				closeResource(r)
+			}
+			return nil, err
+		}
+		resources = append(resources, file)
+	}
+	return resources, nil
+}
+
+// readResource 读取配置资源中的属性，读取之后关闭资源。
+func readResource(resource Resource) (*conf.Properties, error) {
+	b, err := ioutil.ReadAll(resource)
+	closeResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	return conf.Bytes(b, filepath.Ext(resource.Name()))
+}
+
+func closeResource(resource Resource) {
+	if c, ok := resource.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// resourcePath 返回资源名称对应的绝对路径，无法转换时返回资源名称。
+func resourcePath(resource Resource) string {
+	if path, err := filepath.Abs(resource.Name()); err == nil {
+		return path
+	}
+	return resource.Name()
+}
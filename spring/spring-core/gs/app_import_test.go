@@ -0,0 +1,87 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+func TestApp_ConfigImport(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "config-import")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(file string, s string) {
+		assert.Nil(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+		assert.Nil(t, ioutil.WriteFile(file, []byte(s), 0644))
+	}
+
+	write(dir+"/application.properties", ""+
+		"spring.config.import=file:./extra/,optional:file:./missing.yaml,optional:configserver:\n"+
+		"spring.datasource.url=mysql://app\n"+
+		"spring.application.name=app\n")
+	write(dir+"/extra/application.properties", ""+
+		"spring.config.import=../shared/db.properties\n"+
+		"spring.datasource.url=mysql://extra\n")
+	write(dir+"/shared/db.properties", "spring.datasource.max-open=20\n")
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	app := gs.NewApp()
+	cfg := new(relaxedConfig)
+	app.Object(cfg)
+
+	errCh := startApp(app)
+	assert.Equal(t, cfg.URL, "mysql://extra")
+	assert.Equal(t, cfg.Name, "app")
+	assert.Equal(t, cfg.MaxOpen, 20)
+
+	p := app.Properties()
+	assert.Equal(t, p.Origin("spring.datasource.url"), filepath.Join(dir, "extra/application.properties"))
+	assert.Equal(t, p.Origin("spring.datasource.max-open"), filepath.Join(dir, "shared/db.properties"))
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+func TestApp_ConfigImportError(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "config-import")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	file := filepath.Join(dir, "application.properties")
+
+	assert.Nil(t, ioutil.WriteFile(file, []byte("spring.config.import=configserver:\n"), 0644))
+	assert.Error(t, gs.NewApp().Run(), "unsupported config import \"configserver:\"")
+
+	assert.Nil(t, ioutil.WriteFile(file, []byte("spring.config.import=extra.properties\n"), 0644))
+	assert.Error(t, gs.NewApp().Run(), "config import \"extra.properties\" not found")
+
+	extra := filepath.Join(dir, "extra.properties")
+	assert.Nil(t, ioutil.WriteFile(extra, []byte("spring.config.import=application.properties\n"), 0644))
+	assert.Error(t, gs.NewApp().Run(), "circular config import .*application.properties -> .*extra.properties -> .*application.properties")
+}
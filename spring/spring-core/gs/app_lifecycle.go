@@ -0,0 +1,81 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-spring/spring-base/log"
+)
+
+// Lifecycle 需要在应用启动时开始工作、在应用关闭时停止工作的 bean 实现此接口，
+// 例如 MQ 消费者。应用启动时按照 Phase 从小到大依次调用 Start ，关闭时按照相
+// 反的顺序依次调用 Stop ，Phase 相同时按照 bean 的注册顺序。实现此接口的 bean
+// 会被自动发现，不需要导出 Lifecycle 接口。
+type Lifecycle interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Phase() int
+}
+
+type byPhase []Lifecycle
+
+func (b byPhase) Len() int           { return len(b) }
+func (b byPhase) Less(i, j int) bool { return b[i].Phase() < b[j].Phase() }
+func (b byPhase) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// collectLifecycles 收集所有实现了 Lifecycle 接口并且已经完成注入的 bean ，必须在容器刷新
+// 之后、清理之前调用。
+func (app *App) collectLifecycles() []Lifecycle {
+	var ret []Lifecycle
+	for _, b := range app.c.beans {
+		if !b.Wired() {
+			continue
+		}
+		if l, ok := b.Interface().(Lifecycle); ok {
+			ret = append(ret, l)
+		}
+	}
+	sort.Stable(byPhase(ret))
+	return ret
+}
+
+// startLifecycles 按照 Phase 从小到大启动 Lifecycle ，某个 Lifecycle 启动失败
+// 时逆序停止已经启动的 Lifecycle 并返回错误。
+func (app *App) startLifecycles(ctx context.Context, lifecycles []Lifecycle) error {
+	for _, l := range lifecycles {
+		if err := l.Start(ctx); err != nil {
+			app.stopLifecycles(ctx)
+			return fmt.Errorf("start lifecycle %T error: %w", l, err)
+		}
+		app.lifecycles = append(app.lifecycles, l)
+	}
+	return nil
+}
+
+// stopLifecycles 按照启动的相反顺序停止已经启动的 Lifecycle 。
+func (app *App) stopLifecycles(ctx context.Context) {
+	for i := len(app.lifecycles) - 1; i >= 0; i-- {
+		l := app.lifecycles[i]
+		if err := l.Stop(ctx); err != nil {
+			log.Errorf("stop lifecycle %T error: %v", l, err)
+		}
+	}
+	app.lifecycles = nil
+}
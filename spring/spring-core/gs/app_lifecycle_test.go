@@ -0,0 +1,92 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type phaseBean struct {
+	name  string
+	phase int
+	err   error
+	r     *shutdownRecorder
+}
+
+func (b *phaseBean) Start(ctx context.Context) error {
+	b.r.add("start " + b.name)
+	return b.err
+}
+
+func (b *phaseBean) Stop(ctx context.Context) error {
+	b.r.add("stop " + b.name)
+	return nil
+}
+
+func (b *phaseBean) Phase() int {
+	return b.phase
+}
+
+func TestApp_Lifecycle(t *testing.T) {
+
+	run := func(err error) ([]string, error) {
+		os.Clearenv()
+		r := new(shutdownRecorder)
+		app := gs.NewApp()
+		app.Object(&phaseBean{name: "consumer", phase: 2, r: r, err: err}).Name("consumer")
+		app.Object(&phaseBean{name: "server", phase: 1, r: r}).Name("server")
+		app.Object(&phaseBean{name: "cache", phase: 1, r: r}).Name("cache")
+		app.Listen(func(ctx context.Context, e gs.StartedEvent) { r.add("started") })
+		errCh := startApp(app)
+		select {
+		case err = <-errCh:
+			return r.list(), err
+		default:
+		}
+		app.ShutDown("run test end")
+		err = <-errCh
+		return r.list(), err
+	}
+
+	phases, err := run(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, phases, []string{
+		"start server",
+		"start cache",
+		"start consumer",
+		"started",
+		"stop consumer",
+		"stop cache",
+		"stop server",
+	})
+
+	phases, err = run(errors.New("connect error"))
+	assert.Error(t, err, "start lifecycle \\*gs_test.phaseBean error: connect error")
+	assert.Equal(t, phases, []string{
+		"start server",
+		"start cache",
+		"start consumer",
+		"stop cache",
+		"stop server",
+	})
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/log"
+)
+
+// loggingConfig 日志的配置，属性前缀为 spring.logging 。Levels 的每一项是
+// 包路径=级别 的形式，Appenders 可以是 console 和 file ，file 输出到 File
+// 指定的文件。Pattern 和 Appenders 都为空时不改变日志的输出方式。
+type loggingConfig struct {
+	Level     string   `value:"${level:=}"`
+	Levels    []string `value:"${levels:=}"`
+	Pattern   string   `value:"${pattern:=}"`
+	Appenders []string `value:"${appenders:=}"`
+	File      string   `value:"${file.path:=}"`
+}
+
+// LoggerInfo /loggers 接口返回的日志级别信息。
+type LoggerInfo struct {
+	Level    string            `json:"level"`
+	Packages map[string]string `json:"packages,omitempty"`
+}
+
+// configureLogging 根据 spring.logging 属性配置日志的级别和输出方式，属性变化
+// 时重新设置日志级别，输出方式只在启动时设置。
+func (app *App) configureLogging(p *conf.Properties) error {
+
+	var cfg loggingConfig
+	if err := p.Bind(&cfg, conf.Key("spring.logging")); err != nil {
+		return err
+	}
+
+	if cfg.Pattern != "" || len(cfg.Appenders) > 0 {
+		o, err := loggingOutput(cfg)
+		if err != nil {
+			return err
+		}
+		log.SetOutput(o)
+	}
+
+	defaultLevel := log.GetLevel()
+	if err := applyLogLevels(cfg, defaultLevel); err != nil {
+		return err
+	}
+
+	app.Listen(func(ctx context.Context, e ConfigChangedEvent) {
+		if !loggingChanged(e.Keys) {
+			return
+		}
+		app.sources.mu.Lock()
+		p := app.sources.current
+		app.sources.mu.Unlock()
+		var cfg loggingConfig
+		err := p.Bind(&cfg, conf.Key("spring.logging"))
+		if err == nil {
+			err = applyLogLevels(cfg, defaultLevel)
+		}
+		if err != nil {
+			log.Errorf("refresh log levels error: %v", err)
+		}
+	})
+	return nil
+}
+
+func loggingChanged(keys []string) bool {
+	for _, k := range keys {
+		if strings.HasPrefix(k, "spring.logging.level") {
+			return true
+		}
+	}
+	return false
+}
+
+// loggingOutput 创建 Appenders 对应的日志输出。
+func loggingOutput(cfg loggingConfig) (log.Output, error) {
+	appenders := cfg.Appenders
+	if len(appenders) == 0 {
+		appenders = []string{"console"}
+	}
+	var writers []io.Writer
+	for _, a := range appenders {
+		switch a {
+		case "console":
+			writers = append(writers, os.Stdout)
+		case "file":
+			if cfg.File == "" {
+				return nil, fmt.Errorf("spring.logging.file.path is required by file appender")
+			}
+			f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, err
+			}
+			writers = append(writers, f)
+		default:
+			return nil, fmt.Errorf("unknown log appender %q", a)
+		}
+	}
+	return log.PatternOutput(cfg.Pattern, io.MultiWriter(writers...)), nil
+}
+
+// applyLogLevels 设置全局和包级别的日志级别，没有设置全局级别时使用 defaultLevel 。
+func applyLogLevels(cfg loggingConfig, defaultLevel log.Level) error {
+	level := defaultLevel
+	if cfg.Level != "" {
+		var err error
+		if level, err = log.ParseLevel(cfg.Level); err != nil {
+			return err
+		}
+	}
+	levels := make(map[string]log.Level)
+	for _, s := range cfg.Levels {
+		ss := strings.SplitN(s, "=", 2)
+		if len(ss) != 2 {
+			return fmt.Errorf("invalid package log level %q, want a value like pkg=debug", s)
+		}
+		l, err := log.ParseLevel(ss[1])
+		if err != nil {
+			return err
+		}
+		levels[strings.TrimSpace(ss[0])] = l
+	}
+	log.SetLevel(level)
+	log.SetPackageLevels(levels)
+	return nil
+}
+
+// loggerInfo 返回当前的日志级别。
+func loggerInfo() LoggerInfo {
+	info := LoggerInfo{Level: log.GetLevel().String()}
+	for pkg, l := range log.PackageLevels() {
+		if info.Packages == nil {
+			info.Packages = make(map[string]string)
+		}
+		info.Packages[pkg] = l.String()
+	}
+	return info
+}
+
+// handleLoggers GET 返回当前的日志级别，POST 通过 level 参数修改全局的日志级别，
+// 同时指定 package 参数时修改包级别的日志级别，此时 level 为空表示删除该包的设置。
+func handleLoggers(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		pkg, s := r.FormValue("package"), r.FormValue("level")
+		if pkg != "" && s == "" {
+			levels := log.PackageLevels()
+			delete(levels, pkg)
+			log.SetPackageLevels(levels)
+		} else {
+			level, err := log.ParseLevel(s)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if pkg == "" {
+				log.SetLevel(level)
+			} else {
+				levels := log.PackageLevels()
+				levels[pkg] = level
+				log.SetPackageLevels(levels)
+			}
+		}
+	}
+	writeJSON(w, loggerInfo())
+}
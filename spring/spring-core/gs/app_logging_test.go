@@ -0,0 +1,94 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-core/gs"
+)
+
+func TestApp_Logging(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "logging")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer log.Reset()
+
+	file := dir + "/application.properties"
+	write := func(s string) {
+		tmp := file + ".tmp"
+		assert.Nil(t, ioutil.WriteFile(tmp, []byte("spring.config.watch-interval=20ms\n"+s), 0644))
+		assert.Nil(t, os.Rename(tmp, file))
+	}
+	write("spring.logging.level=warn\nspring.logging.levels=github.com/go-spring/spring-core/gs_test=debug\n")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	assert.Nil(t, l.Close())
+
+	logFile := dir + "/app.log"
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	app := gs.NewApp()
+	app.Property("spring.logging.pattern", "%l %m")
+	app.Property("spring.logging.appenders", "file")
+	app.Property("spring.logging.file.path", logFile)
+	app.Property("spring.admin.enabled", true)
+	app.Property("spring.admin.port", port)
+
+	errCh := startApp(app)
+
+	assert.Equal(t, log.GetLevel(), log.WarnLevel)
+	assert.Equal(t, log.PackageLevels(), map[string]log.Level{
+		"github.com/go-spring/spring-core/gs_test": log.DebugLevel,
+	})
+	log.Debug("debug from test")
+	b, err := ioutil.ReadFile(logFile)
+	assert.Nil(t, err)
+	assert.Matches(t, string(b), "(?m)^DEBUG debug from test$")
+
+	write("spring.logging.level=error\n")
+	assert.Eventually(t, func() bool {
+		return log.GetLevel() == log.ErrorLevel && len(log.PackageLevels()) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/loggers", port)
+	resp, err := http.PostForm(url, map[string][]string{"package": {"github.com/foo"}, "level": {"trace"}})
+	assert.Nil(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Nil(t, resp.Body.Close())
+	assert.Equal(t, string(body), "{\n  \"level\": \"error\",\n  \"packages\": {\n    \"github.com/foo\": \"trace\"\n  }\n}")
+
+	resp, err = http.PostForm(url, map[string][]string{"level": {"verbose"}})
+	assert.Nil(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+	assert.Nil(t, resp.Body.Close())
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// EnvironmentPostProcessor 在所有属性源合并之后、bean 注入之前修改属性，远程配置、
+// 密钥服务以及云平台元数据等集成可以在这里解密属性或者派生新的属性。配置文件热加载
+// 或者远程配置变化之后重新合并的属性同样会经过 EnvironmentPostProcessor 处理。
+type EnvironmentPostProcessor interface {
+	PostProcessEnvironment(p *conf.Properties) error
+}
+
+// EnvironmentPostProcessorFunc 函数形式的 EnvironmentPostProcessor 。
+type EnvironmentPostProcessorFunc func(p *conf.Properties) error
+
+func (f EnvironmentPostProcessorFunc) PostProcessEnvironment(p *conf.Properties) error {
+	return f(p)
+}
+
+// postProcessor 通过 AddEnvironmentPostProcessor 注册的 EnvironmentPostProcessor 。
+type postProcessor struct {
+	order int
+	pp    EnvironmentPostProcessor
+}
+
+// AddEnvironmentPostProcessor 注册 EnvironmentPostProcessor ，按照 order 从小到大
+// 依次执行，order 相同时按照注册的顺序。需要在应用启动之前调用，通常由库在 init
+// 函数中通过 gs.AddEnvironmentPostProcessor 注册。
+func (app *App) AddEnvironmentPostProcessor(order int, pp EnvironmentPostProcessor) {
+	app.sources.mu.Lock()
+	defer app.sources.mu.Unlock()
+	app.sources.processors = append(app.sources.processors, postProcessor{order: order, pp: pp})
+	sort.SliceStable(app.sources.processors, func(i, j int) bool {
+		return app.sources.processors[i].order < app.sources.processors[j].order
+	})
+}
+
+// postProcessEnvironment 依次执行所有的 EnvironmentPostProcessor ，被修改的属性的
+// 来源记为 post-processor 加上处理器的类型，调用时需要持有 app.sources.mu 锁。
+func (app *App) postProcessEnvironment(p *conf.Properties, origins map[string]string) error {
+	for _, x := range app.sources.processors {
+		old := p.Copy()
+		if err := x.pp.PostProcessEnvironment(p); err != nil {
+			return fmt.Errorf("environment post-processor %T error: %w", x.pp, err)
+		}
+		for _, key := range diffProperties(old, p) {
+			if p.Has(key) {
+				origins[key] = fmt.Sprintf("post-processor:%T", x.pp)
+			} else {
+				delete(origins, key)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type secretDecoder struct{}
+
+func (d *secretDecoder) PostProcessEnvironment(p *conf.Properties) error {
+	for _, key := range p.Keys() {
+		if v := p.Get(key); strings.HasPrefix(v, "secret:") {
+			if err := p.Set(key, strings.ToUpper(strings.TrimPrefix(v, "secret:"))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestApp_EnvironmentPostProcessor(t *testing.T) {
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Property("db.host", "127.0.0.1")
+		app.Property("db.password", "secret:abc")
+		app.AddEnvironmentPostProcessor(2, new(secretDecoder))
+		app.AddEnvironmentPostProcessor(1, gs.EnvironmentPostProcessorFunc(func(p *conf.Properties) error {
+			return p.Set("db.url", "secret:mysql://"+p.Get("db.host"))
+		}))
+
+		var cfg struct {
+			URL      string `value:"${db.url}"`
+			Password string `value:"${db.password}"`
+		}
+		app.Object(&cfg)
+
+		errCh := startApp(app)
+		assert.Equal(t, cfg.URL, "MYSQL://127.0.0.1")
+		assert.Equal(t, cfg.Password, "ABC")
+
+		p := app.Properties()
+		assert.Equal(t, p.Origin("db.host"), gs.PropertySourceCode)
+		assert.Equal(t, p.Origin("db.password"), "post-processor:*gs_test.secretDecoder")
+		assert.Equal(t, p.Origin("db.url"), "post-processor:*gs_test.secretDecoder")
+
+		app.ShutDown("run test end")
+		assert.Nil(t, <-errCh)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.AddEnvironmentPostProcessor(0, gs.EnvironmentPostProcessorFunc(func(p *conf.Properties) error {
+			return errors.New("vault unreachable")
+		}))
+		err := app.Run()
+		assert.Error(t, err, "environment post-processor gs.EnvironmentPostProcessorFunc error: vault unreachable")
+	})
+}
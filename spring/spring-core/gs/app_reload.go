@@ -0,0 +1,186 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/log"
+)
+
+// SpringConfigWatchInterval 轮询配置文件的间隔时间，例如 10s ，为空时不监听配置
+// 文件的变化。
+const SpringConfigWatchInterval = "spring.config.watch-interval"
+
+// propertySources 保存各个来源的属性，任何一个来源发生变化时按照优先级重新合并。
+type propertySources struct {
+	refreshMu   sync.Mutex // 串行化合并、重新绑定和发布事件的整个刷新过程
+	mu          sync.Mutex
+	config      *configuration     // 加载配置文件使用的配置
+	locators    []ResourceLocator  // 查找配置文件的定位器
+	code        *conf.Properties   // 代码中设置的属性
+	files       *conf.Properties   // 配置文件中的属性
+	fileOrigins map[string]string  // 配置文件中属性的来源
+	env         *conf.Properties   // 环境变量和命令行参数
+	cmd         *conf.Properties   // 命令行参数
+	custom      []customSource     // 自定义的属性源
+	processors  []postProcessor    // 合并之后修改属性的处理器
+	random      *conf.RandomValues // 随机属性，重新合并属性时保持不变
+	current     *conf.Properties   // 合并之后的属性
+}
+
+// mergeProperties 按照 orderedSources 返回的优先级顺序合并所有属性源，后合并的
+// 优先，因此命令行参数的优先级最高，然后执行 EnvironmentPostProcessor ，同时记录
+// 每个属性的来源，调用时需要持有 app.sources.mu 锁。
+func (app *App) mergeProperties() (*conf.Properties, error) {
+	sources, err := app.orderedSources()
+	if err != nil {
+		return nil, err
+	}
+	p := conf.New()
+	origins := make(map[string]string)
+	for _, source := range sources {
+		source.merge(p, origins)
+	}
+	if err = app.postProcessEnvironment(p, origins); err != nil {
+		return nil, err
+	}
+	app.origins = origins
+	return p, nil
+}
+
+// refreshProperties 重新合并所有来源的属性，存在变化时先重新绑定 Refreshable 的
+// bean ，然后发布 PropertiesReboundEvent 和 ConfigChangedEvent 事件。配置文件和
+// 远程配置源可能同时触发刷新，整个过程持有 app.sources.refreshMu 锁，保证较旧的
+// 属性不会在较新的属性之后被绑定。
+func (app *App) refreshProperties(ctx context.Context) {
+
+	app.sources.refreshMu.Lock()
+	defer app.sources.refreshMu.Unlock()
+
+	app.sources.mu.Lock()
+	p, err := app.mergeProperties()
+	if err != nil {
+		app.sources.mu.Unlock()
+		log.Errorf("merge properties error: %v", err)
+		return
+	}
+	keys := diffProperties(app.sources.current, p)
+	if len(keys) > 0 {
+		app.sources.current = p
+	}
+	app.sources.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	log.Infof("properties changed: %v", keys)
+	rebound, err := app.c.refreshProperties(p, keys)
+	if err != nil {
+		log.Errorf("refresh beans error: %v", err)
+	}
+	for _, b := range rebound {
+		e := PropertiesReboundEvent{Prefix: b.prefix, Bean: b.v.Interface()}
+		if err = app.Publish(ctx, e); err != nil {
+			log.Errorf("publish properties rebound event error: %v", err)
+		}
+	}
+	if err = app.Publish(ctx, ConfigChangedEvent{Keys: keys}); err != nil {
+		log.Errorf("publish config changed event error: %v", err)
+	}
+}
+
+// watchConfigFiles 按照 spring.config.watch-interval 设置的间隔重新加载配置文件，
+// 配置文件中的属性发生变化时重新合并属性。
+func (app *App) watchConfigFiles() error {
+
+	app.sources.mu.Lock()
+	s := app.sources.current.Get(SpringConfigWatchInterval)
+	app.sources.mu.Unlock()
+	if s == "" {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", SpringConfigWatchInterval, err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("%s should be positive but %s", SpringConfigWatchInterval, s)
+	}
+
+	app.c.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			files, origins, err := app.loadProperties(app.sources.config)
+			if err != nil {
+				log.Errorf("reload config files error: %v", err)
+				continue
+			}
+			app.sources.mu.Lock()
+			app.sources.files = files
+			app.sources.fileOrigins = origins
+			app.sources.mu.Unlock()
+			app.refreshProperties(ctx)
+		}
+	})
+	return nil
+}
+
+// diffProperties 返回新增、删除以及值发生变化的属性名，结果按照字母顺序排列。
+func diffProperties(old, new *conf.Properties) []string {
+	oldMap, newMap := toStringMap(old), toStringMap(new)
+	if reflect.DeepEqual(oldMap, newMap) {
+		return nil
+	}
+	var keys []string
+	for k, v := range newMap {
+		if ov, ok := oldMap[k]; !ok || ov != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range oldMap {
+		if _, ok := newMap[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toStringMap(p *conf.Properties) map[string]string {
+	m := make(map[string]string)
+	if p != nil {
+		for _, k := range p.Keys() {
+			m[k] = p.Get(k)
+		}
+	}
+	return m
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/dync"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type reloadConfig struct {
+	Host    string     `value:"${db.host}"`
+	Port    int        `value:"${db.port:=3306}"`
+	Context gs.Context `autowire:""`
+}
+
+func TestApp_HotReload(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "hot-reload")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := dir + "/application.properties"
+	write := func(s string) {
+		// 先写临时文件再重命名，避免读到写了一半的配置文件。
+		tmp := file + ".tmp"
+		assert.Nil(t, ioutil.WriteFile(tmp, []byte("spring.config.watch-interval=20ms\n"+s), 0644))
+		assert.Nil(t, os.Rename(tmp, file))
+	}
+	write("db.host=127.0.0.1\n")
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	app := gs.NewApp()
+	cfg := new(reloadConfig)
+	app.Object(cfg).Refreshable()
+
+	changed := make(chan []string, 1)
+	app.Listen(func(ctx context.Context, e gs.ConfigChangedEvent) {
+		changed <- e.Keys
+	})
+
+	errCh := startApp(app)
+	assert.Equal(t, cfg.Host, "127.0.0.1")
+	assert.Equal(t, cfg.Port, 3306)
+
+	write("db.host=10.0.0.1\ndb.port=3307\n")
+	assert.Equal(t, <-changed, []string{"db.host", "db.port"})
+	assert.Equal(t, cfg.Host, "10.0.0.1")
+	assert.Equal(t, cfg.Port, 3307)
+	assert.NotNil(t, cfg.Context)
+
+	// 绑定失败时保留旧值。
+	write("db.host=10.0.0.2\ndb.port=abc\n")
+	assert.Equal(t, <-changed, []string{"db.host", "db.port"})
+	assert.Equal(t, cfg.Host, "10.0.0.1")
+	assert.Equal(t, cfg.Port, 3307)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+type httpConfig struct {
+	Port    int           `value:"${port:=8080}"`
+	Timeout time.Duration `value:"${timeout:=1s}"`
+	Hosts   []string      `value:"${hosts:=}"`
+}
+
+func TestApp_PrefixBinding(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "prefix-binding")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := dir + "/application.properties"
+	write := func(s string) {
+		tmp := file + ".tmp"
+		assert.Nil(t, ioutil.WriteFile(tmp, []byte("spring.config.watch-interval=20ms\n"+s), 0644))
+		assert.Nil(t, os.Rename(tmp, file))
+	}
+	write("server.http.port=9090\nserver.http.hosts=a,b\nother=1\n")
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	app := gs.NewApp()
+	cfg := new(httpConfig)
+	app.Object(cfg).Prefix("server.http").Refreshable()
+	static := new(httpConfig)
+	app.Object(static).Name("static").Prefix("server.http")
+
+	rebound := make(chan gs.PropertiesReboundEvent, 1)
+	app.Listen(func(ctx context.Context, e gs.PropertiesReboundEvent) {
+		rebound <- e
+	})
+	changed := make(chan []string, 1)
+	app.Listen(func(ctx context.Context, e gs.ConfigChangedEvent) {
+		changed <- e.Keys
+	})
+
+	errCh := startApp(app)
+	assert.Equal(t, *cfg, httpConfig{Port: 9090, Timeout: time.Second, Hosts: []string{"a", "b"}})
+	assert.Equal(t, *static, *cfg)
+
+	write("server.http.port=9090\nserver.http.timeout=2s\nother=1\n")
+	e := <-rebound
+	assert.Equal(t, e.Prefix, "server.http")
+	assert.Same(t, e.Bean, cfg)
+	assert.Equal(t, <-changed, []string{"server.http.hosts", "server.http.timeout"})
+	assert.Equal(t, *cfg, httpConfig{Port: 9090, Timeout: 2 * time.Second})
+	assert.Equal(t, static.Timeout, time.Second)
+
+	write("server.http.port=9090\nserver.http.timeout=2s\nother=2\n")
+	assert.Equal(t, <-changed, []string{"other"})
+	assert.Equal(t, len(rebound), 0)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+type dynamicConfig struct {
+	Host    string      `value:"${db.host}"`
+	Name    dync.String `value:"${app.name:=demo}"`
+	Limiter struct {
+		Rate dync.Int64 `value:"${rate:=10}"`
+	} `value:"${limiter}"`
+}
+
+func TestApp_DynamicValues(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "dynamic-values")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := dir + "/application.properties"
+	write := func(s string) {
+		tmp := file + ".tmp"
+		assert.Nil(t, ioutil.WriteFile(tmp, []byte("spring.config.watch-interval=20ms\n"+s), 0644))
+		assert.Nil(t, os.Rename(tmp, file))
+	}
+	write("db.host=127.0.0.1\n")
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	app := gs.NewApp()
+	cfg := new(dynamicConfig)
+	app.Object(cfg)
+
+	changed := make(chan []string, 1)
+	app.Listen(func(ctx context.Context, e gs.ConfigChangedEvent) {
+		changed <- e.Keys
+	})
+
+	errCh := startApp(app)
+	assert.Equal(t, cfg.Name.Value(), "demo")
+	assert.Equal(t, cfg.Limiter.Rate.Value(), int64(10))
+
+	// 非 Refreshable 的 bean 只刷新动态属性。
+	write("db.host=10.0.0.1\napp.name=api\nlimiter.rate=100\n")
+	assert.Equal(t, <-changed, []string{"app.name", "db.host", "limiter.rate"})
+	assert.Equal(t, cfg.Host, "127.0.0.1")
+	assert.Equal(t, cfg.Name.Value(), "api")
+	assert.Equal(t, cfg.Limiter.Rate.Value(), int64(100))
+
+	// 绑定失败时保留原来的值。
+	write("db.host=10.0.0.1\napp.name=web\nlimiter.rate=abc\n")
+	assert.Equal(t, <-changed, []string{"app.name", "limiter.rate"})
+	assert.Equal(t, cfg.Name.Value(), "web")
+	assert.Equal(t, cfg.Limiter.Rate.Value(), int64(100))
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/log"
+)
+
+// RemotePropertySource 远程配置源，例如 Nacos 、 Apollo 、 Consul KV 和 etcd ，
+// 需要在 bootstrap 阶段通过 RemotePropertySource 方法注册。远程配置在 bean 注入之
+// 前加载，优先级高于配置文件、低于环境变量和命令行参数。
+type RemotePropertySource interface {
+
+	// Name 返回配置源的名称，用于记录属性的来源。
+	Name() string
+
+	// Load 加载全部的远程配置。
+	Load(ctx context.Context) (*conf.Properties, error)
+
+	// Watch 阻塞地监听远程配置的变化，变化时通过 onChange 传入最新的全部配置，
+	// ctx 结束时返回。
+	Watch(ctx context.Context, onChange func(p *conf.Properties)) error
+}
+
+// remoteRetryInterval Watch 返回错误之后重新监听的间隔时间。
+var remoteRetryInterval = 5 * time.Second
+
+// remoteState 保存远程配置源及其最近一次加载的配置，props 受 app.sources.mu 保护。
+type remoteState struct {
+	sources []RemotePropertySource
+	props   map[string]*conf.Properties
+}
+
+// loadRemoteProperties 加载所有远程配置源的配置。
+func (app *App) loadRemoteProperties(ctx context.Context) error {
+	if app.b == nil || len(app.b.remoteSources) == 0 {
+		return nil
+	}
+	app.remote.sources = app.b.remoteSources
+	app.remote.props = make(map[string]*conf.Properties)
+	for _, s := range app.remote.sources {
+		p, err := s.Load(ctx)
+		if err != nil {
+			return err
+		}
+		app.remote.props[s.Name()] = p
+	}
+	return nil
+}
+
+// watchRemoteProperties 在后台监听所有远程配置源的变化，Watch 返回错误时间隔一段
+// 时间之后重新监听，直到应用退出。
+func (app *App) watchRemoteProperties() {
+	for _, s := range app.remote.sources {
+		source := s
+		app.c.Go(func(ctx context.Context) {
+			for {
+				err := source.Watch(ctx, func(p *conf.Properties) {
+					app.onRemoteChanged(ctx, source.Name(), p)
+				})
+				if err != nil {
+					log.Errorf("watch remote property source %s error: %v", source.Name(), err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(remoteRetryInterval):
+				}
+			}
+		})
+	}
+}
+
+// onRemoteChanged 保存远程配置源最新的配置，然后重新合并所有来源的属性。
+func (app *App) onRemoteChanged(ctx context.Context, name string, p *conf.Properties) {
+	app.sources.mu.Lock()
+	app.remote.props[name] = p
+	app.sources.mu.Unlock()
+	app.refreshProperties(ctx)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type fakeRemoteSource struct {
+	changes chan *conf.Properties
+}
+
+func (s *fakeRemoteSource) Name() string {
+	return "fake"
+}
+
+func (s *fakeRemoteSource) Load(ctx context.Context) (*conf.Properties, error) {
+	return conf.Map(map[string]interface{}{"remote.name": "v1", "remote.old": "x"}), nil
+}
+
+func (s *fakeRemoteSource) Watch(ctx context.Context, onChange func(p *conf.Properties)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case p := <-s.changes:
+			onChange(p)
+		}
+	}
+}
+
+func TestApp_RemotePropertySource(t *testing.T) {
+
+	os.Clearenv()
+	app := gs.NewApp()
+	source := &fakeRemoteSource{changes: make(chan *conf.Properties)}
+	app.Bootstrap().RemotePropertySource(source)
+
+	var name string
+	app.Provide(func(ctx gs.Context) int {
+		name = ctx.Prop("remote.name")
+		return 0
+	})
+
+	changed := make(chan []string, 1)
+	app.Listen(func(ctx context.Context, e gs.ConfigChangedEvent) {
+		changed <- e.Keys
+	})
+
+	errCh := startApp(app)
+	assert.Equal(t, name, "v1")
+
+	source.changes <- conf.Map(map[string]interface{}{"remote.name": "v2", "remote.new": "y"})
+	assert.Equal(t, <-changed, []string{"remote.name", "remote.new", "remote.old"})
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/chrono"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/recorder"
+	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/log"
+)
+
+// SpringSchedulingEnabled 是否启动定时任务，默认启动。
+const SpringSchedulingEnabled = "spring.scheduling.enabled"
+
+// scheduledTaskConfig 定时任务的配置，属性前缀为 spring.scheduling.tasks.<name> ，
+// Schedule 不为空时覆盖注册时指定的调度计划。
+type scheduledTaskConfig struct {
+	Enabled  bool   `value:"${enabled:=true}"`
+	Schedule string `value:"${schedule:=}"`
+}
+
+// ScheduledTask 定时任务，按照 cron 表达式或者固定的时间间隔执行。上一次执行还
+// 没有结束时跳过本次执行，执行过程中发生的 panic 不会影响后续的执行。
+type ScheduledTask struct {
+	name    string
+	spec    string
+	fn      reflect.Value
+	mutex   sync.Mutex
+	running bool
+}
+
+// NewScheduledTask 创建定时任务，spec 是 cron 表达式 (参考 chrono.ParseCron)
+// 或者 30s 这样的时间间隔，可以通过 ${} 引用属性。fn 的形式为 func(context.Context)
+// 或者 func(context.Context) error ，通常是 bean 的方法。
+func NewScheduledTask(name string, spec string, fn interface{}) *ScheduledTask {
+	if name == "" {
+		panic(errors.New("scheduled task name can't be empty"))
+	}
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 1 || t.In(0) != contextType {
+		panic(errors.New("scheduled task should be func(context.Context) [error]"))
+	}
+	if t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		panic(errors.New("scheduled task should be func(context.Context) [error]"))
+	}
+	return &ScheduledTask{name: name, spec: spec, fn: reflect.ValueOf(fn)}
+}
+
+// Name 返回定时任务的名称。
+func (t *ScheduledTask) Name() string {
+	return t.name
+}
+
+// tryStart 标记任务开始执行，任务正在执行时返回 false 。
+func (t *ScheduledTask) tryStart() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.running {
+		return false
+	}
+	t.running = true
+	return true
+}
+
+func (t *ScheduledTask) done() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.running = false
+}
+
+func (t *ScheduledTask) invoke(ctx context.Context, onPanic func(interface{}, []byte)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			onPanic(r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	out := t.fn.Call([]reflect.Value{reflect.ValueOf(ctx)})
+	if len(out) > 0 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
+
+// intervalSchedule 按照固定时间间隔执行的调度计划。
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// parseSchedule 解析时间间隔或者 cron 表达式。
+func parseSchedule(spec string) (chrono.Schedule, error) {
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", spec)
+		}
+		return intervalSchedule(d), nil
+	}
+	return chrono.ParseCron(spec)
+}
+
+// Schedule 注册定时任务，参考 NewScheduledTask 的解释。
+func (app *App) Schedule(name string, spec string, fn interface{}) *ScheduledTask {
+	t := NewScheduledTask(name, spec, fn)
+	app.tasks = append(app.tasks, t)
+	return t
+}
+
+// startScheduler 启动所有的定时任务，包括通过 App.Schedule 注册的和 ScheduledTask
+// 类型的 bean ，任务在应用关闭时停止调度。
+func (app *App) startScheduler() error {
+
+	enabled, err := strconv.ParseBool(app.c.p.Get(SpringSchedulingEnabled, conf.Def("true")))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", SpringSchedulingEnabled, err)
+	}
+	if !enabled {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	tasks := append(append([]*ScheduledTask{}, app.tasks...), app.ScheduledTasks...)
+	for _, t := range tasks {
+		if names[t.name] {
+			return fmt.Errorf("duplicate scheduled task %q", t.name)
+		}
+		names[t.name] = true
+
+		var cfg scheduledTaskConfig
+		if err = app.c.p.Bind(&cfg, conf.Key("spring.scheduling.tasks."+t.name)); err != nil {
+			return err
+		}
+		if !cfg.Enabled {
+			log.Infof("scheduled task %s disabled", t.name)
+			continue
+		}
+
+		spec := t.spec
+		if cfg.Schedule != "" {
+			spec = cfg.Schedule
+		}
+		if spec, err = app.c.p.Resolve(spec); err != nil {
+			return fmt.Errorf("scheduled task %s: %w", t.name, err)
+		}
+		s, err := parseSchedule(spec)
+		if err != nil {
+			return fmt.Errorf("scheduled task %s: %w", t.name, err)
+		}
+
+		t := t
+		app.c.Go(func(ctx context.Context) { app.schedule(ctx, t, s) })
+		log.Infof("scheduled task %s started with %q", t.name, spec)
+	}
+	return nil
+}
+
+// schedule 按照调度计划触发任务，直到 ctx 结束。
+func (app *App) schedule(ctx context.Context, t *ScheduledTask, s chrono.Schedule) {
+	clock := chrono.GetClock(ctx)
+	for {
+		now := clock.Now()
+		next := s.Next(now)
+		if next.IsZero() {
+			return
+		}
+		timer := clock.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		if !t.tryStart() {
+			log.Warnf("scheduled task %s is still running, skip this execution", t.name)
+			continue
+		}
+		app.c.Go(func(ctx context.Context) {
+			defer t.done()
+			app.runTask(ctx, t)
+		})
+	}
+}
+
+// runTask 执行一次定时任务，每次执行使用新的 knife 缓存，录制模式下每次执行
+// 录制为一个后台任务会话。
+func (app *App) runTask(ctx context.Context, t *ScheduledTask) {
+
+	ctx, _ = knife.New(ctx)
+	record := recorder.RecordMode()
+	if record {
+		if err := recorder.StartRecord(ctx, fastdev.NewSessionID()); err != nil {
+			log.Errorf("scheduled task %s start record error: %v", t.name, err)
+			record = false
+		}
+	}
+
+	start := time.Now()
+	err := t.invoke(ctx, app.onPanic)
+	if err != nil {
+		log.Errorf("scheduled task %s failed after %s: %v", t.name, time.Since(start), err)
+	} else {
+		log.Debugf("scheduled task %s finished in %s", t.name, time.Since(start))
+	}
+
+	if !record {
+		return
+	}
+	result := "OK"
+	if err != nil {
+		result = err.Error()
+	}
+	_ = recorder.RecordTags(ctx, "job:"+t.name)
+	inbound := fastdev.AcquireAction()
+	inbound.Protocol = fastdev.JOB
+	inbound.SetRequest(t.name)
+	inbound.SetResponse(result)
+	if err = recorder.RecordInbound(ctx, inbound); err != nil {
+		fastdev.ReleaseAction(inbound)
+		log.Errorf("scheduled task %s record error: %v", t.name, err)
+	}
+	session, err := recorder.StopRecord(ctx)
+	if err != nil {
+		log.Errorf("scheduled task %s stop record error: %v", t.name, err)
+	}
+	session.Release()
+}
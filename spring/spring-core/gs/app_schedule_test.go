@@ -0,0 +1,148 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/fastdev"
+	"github.com/go-spring/spring-base/fastdev/recorder"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type counterTask struct {
+	mu      sync.Mutex
+	count   int
+	running int
+	overlap bool
+	delay   time.Duration
+}
+
+func (c *counterTask) Run(ctx context.Context) error {
+	c.mu.Lock()
+	c.count++
+	c.running++
+	if c.running > 1 {
+		c.overlap = true
+	}
+	c.mu.Unlock()
+	time.Sleep(c.delay)
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *counterTask) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+func (c *counterTask) Overlap() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.overlap
+}
+
+func TestApp_Schedule(t *testing.T) {
+
+	t.Run("run", func(t *testing.T) {
+		os.Clearenv()
+
+		// 会话在 Sink 返回之后归还对象池，因此需要在 Sink 中取出用到的数据。
+		type jobSession struct {
+			protocol string
+			tag      string
+			response string
+		}
+		var sessions []jobSession
+		var mu sync.Mutex
+		recorder.SetRecordMode(true)
+		recorder.SetSink(func(s *fastdev.Session) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sessions = append(sessions, jobSession{
+				protocol: s.Inbound.Protocol,
+				tag:      s.Tags[0],
+				response: s.Inbound.Response.Data(),
+			})
+			return nil
+		})
+		defer func() {
+			recorder.SetSink(nil)
+			recorder.SetRecordMode(false)
+		}()
+
+		fast := &counterTask{}
+		slow := &counterTask{delay: 120 * time.Millisecond}
+		disabled := &counterTask{}
+		panics := &counterTask{}
+
+		app := gs.NewApp()
+		app.Property("job.interval", "20ms")
+		app.Property("spring.scheduling.tasks.disabled.enabled", false)
+		app.Property("spring.scheduling.tasks.slow.schedule", "30ms")
+		app.Schedule("fast", "${job.interval}", fast.Run)
+		app.Schedule("slow", "1h", slow.Run)
+		app.Schedule("disabled", "10ms", disabled.Run)
+		app.Object(gs.NewScheduledTask("panic", "20ms", func(ctx context.Context) {
+			_ = panics.Run(ctx)
+			panic("boom")
+		}))
+
+		errCh := startApp(app)
+		assert.Eventually(t, func() bool {
+			return fast.Count() >= 5 && slow.Count() >= 2 && panics.Count() >= 5
+		}, 2*time.Second, 10*time.Millisecond)
+		app.ShutDown("run test end")
+		assert.Nil(t, <-errCh)
+
+		assert.False(t, slow.Overlap())
+		assert.Equal(t, disabled.Count(), 0)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.True(t, len(sessions) > 0)
+		for _, s := range sessions {
+			assert.Equal(t, s.protocol, fastdev.JOB)
+			if s.tag == "job:panic" {
+				assert.Equal(t, s.response, "panic: boom")
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		os.Clearenv()
+		app := gs.NewApp()
+		app.Schedule("bad", "* * *", func(ctx context.Context) {})
+		err := app.Run()
+		assert.Error(t, err, "scheduled task bad: invalid cron expression \"\\* \\* \\*\": expected 5 or 6 fields but 3")
+
+		app = gs.NewApp()
+		app.Schedule("dup", "1s", func(ctx context.Context) {})
+		app.Schedule("dup", "1s", func(ctx context.Context) {})
+		assert.Error(t, app.Run(), "duplicate scheduled task \"dup\"")
+
+		assert.Panic(t, func() { gs.NewScheduledTask("x", "1s", func() {}) }, "scheduled task should be func\\(context.Context\\) \\[error\\]")
+	})
+}
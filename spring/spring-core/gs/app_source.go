@@ -0,0 +1,239 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// 内置属性源的名称，远程属性源的名称为 remote: 加上 RemotePropertySource.Name() 。
+const (
+	PropertySourceRandom      = "random"          // random.* 形式的随机属性
+	PropertySourceCode        = originCode        // 代码中设置的属性
+	PropertySourceConfigFiles = "config-files"    // 配置文件中的属性
+	PropertySourceEnvironment = originEnvironment // 环境变量
+	PropertySourceCommandLine = originCommandLine // 命令行参数
+)
+
+// customSource 通过 AddPropertySourceBefore 或者 AddPropertySourceAfter 添加的
+// 属性源，relative 是作为参照的属性源名称。
+type customSource struct {
+	name     string
+	p        *conf.Properties
+	relative string
+	before   bool
+}
+
+// propertySource 参与合并的属性源，merge 将属性源中的属性合并到 p 中并记录来源。
+type propertySource struct {
+	name  string
+	merge func(p *conf.Properties, origins map[string]string)
+}
+
+// AddPropertySourceBefore 添加一个名为 name 的属性源，它的优先级紧挨着高于名为
+// relative 的属性源。需要在应用启动之前调用，relative 不存在时应用启动失败。
+func (app *App) AddPropertySourceBefore(relative string, name string, p *conf.Properties) {
+	app.addPropertySource(customSource{name: name, p: p, relative: relative, before: true})
+}
+
+// AddPropertySourceAfter 添加一个名为 name 的属性源，它的优先级紧挨着低于名为
+// relative 的属性源。需要在应用启动之前调用，relative 不存在时应用启动失败。
+func (app *App) AddPropertySourceAfter(relative string, name string, p *conf.Properties) {
+	app.addPropertySource(customSource{name: name, p: p, relative: relative})
+}
+
+func (app *App) addPropertySource(s customSource) {
+	app.sources.mu.Lock()
+	defer app.sources.mu.Unlock()
+	app.sources.custom = append(app.sources.custom, s)
+}
+
+// PropertySources 返回所有属性源的名称，按照优先级从高到低排列。
+func (app *App) PropertySources() ([]string, error) {
+	app.sources.mu.Lock()
+	defer app.sources.mu.Unlock()
+	sources, err := app.orderedSources()
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for i := len(sources) - 1; i >= 0; i-- {
+		ret = append(ret, sources[i].name)
+	}
+	return ret, nil
+}
+
+// orderedSources 返回按照优先级从低到高排列的属性源，后合并的属性源优先，调用时
+// 需要持有 app.sources.mu 锁。
+func (app *App) orderedSources() ([]propertySource, error) {
+
+	s := &app.sources
+	mergeFrom := func(src *conf.Properties, origin func(key string) string) func(*conf.Properties, map[string]string) {
+		return func(p *conf.Properties, origins map[string]string) {
+			if src == nil {
+				return
+			}
+			for _, key := range src.Keys() {
+				p.Set(key, src.Get(key))
+				origins[key] = origin(key)
+			}
+		}
+	}
+
+	if s.random == nil {
+		s.random = conf.NewRandomValues()
+	}
+
+	sources := []propertySource{
+		{
+			name: PropertySourceRandom,
+			merge: func(p *conf.Properties, origins map[string]string) {
+				p.SetRandomValues(s.random)
+			},
+		},
+		{
+			name:  PropertySourceCode,
+			merge: mergeFrom(s.code, func(string) string { return originCode }),
+		},
+		{
+			name:  PropertySourceConfigFiles,
+			merge: mergeFrom(s.files, func(key string) string { return s.fileOrigins[key] }),
+		},
+	}
+
+	for _, source := range app.remote.sources {
+		name := "remote:" + source.Name()
+		sources = append(sources, propertySource{
+			name:  name,
+			merge: mergeFrom(app.remote.props[source.Name()], func(string) string { return name }),
+		})
+	}
+
+	mergeEnv := mergeFrom(s.env, func(string) string { return originEnvironment })
+	sources = append(sources, propertySource{
+		name: PropertySourceEnvironment,
+		merge: func(p *conf.Properties, origins map[string]string) {
+			// 环境变量按照宽松规则覆盖已有的属性，例如 SPRING_DATASOURCE_MAX_OPEN
+			// 覆盖 spring.datasource.max-open ，不存在的属性在获取时再从环境变量中查找。
+			if s.config != nil && s.config.env != nil {
+				for _, key := range p.Keys() {
+					if v, ok := s.config.env.Lookup(key); ok {
+						p.Set(key, v)
+						origins[key] = originEnvironment
+					}
+				}
+				p.SetRelaxedEnv(s.config.env)
+			}
+			mergeEnv(p, origins)
+		},
+	})
+
+	sources = append(sources, propertySource{
+		name:  PropertySourceCommandLine,
+		merge: mergeFrom(s.cmd, func(string) string { return originCommandLine }),
+	})
+
+	for _, c := range s.custom {
+		index := -1
+		for i, source := range sources {
+			if source.name == c.relative {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("property source %q not found for %q", c.relative, c.name)
+		}
+		if c.before {
+			index++
+		}
+		name := c.name
+		source := propertySource{
+			name:  name,
+			merge: mergeFrom(c.p, func(string) string { return name }),
+		}
+		sources = append(sources, propertySource{})
+		copy(sources[index+1:], sources[index:])
+		sources[index] = source
+	}
+	return sources, nil
+}
+
+// EffectiveProperties 合并之后生效的属性以及每个属性的来源。
+type EffectiveProperties struct {
+	*conf.Properties
+	origins map[string]string
+	env     *conf.RelaxedEnv
+	random  *conf.RandomValues
+}
+
+// Properties 返回当前生效的属性，应用启动之前返回代码中设置的属性。
+func (app *App) Properties() *EffectiveProperties {
+	app.sources.mu.Lock()
+	defer app.sources.mu.Unlock()
+	if app.sources.current == nil {
+		origins := make(map[string]string)
+		for _, key := range app.c.p.Keys() {
+			origins[key] = originCode
+		}
+		return &EffectiveProperties{Properties: app.c.p, origins: origins}
+	}
+	p := &EffectiveProperties{
+		Properties: app.sources.current,
+		origins:    app.origins,
+		random:     app.sources.random,
+	}
+	if app.sources.config != nil {
+		p.env = app.sources.config.env
+	}
+	return p
+}
+
+// Origin 返回属性值的来源，即最终生效的属性源的名称，配置文件中的属性返回文件的
+// 路径，属性不存在时返回空字符串。
+func (p *EffectiveProperties) Origin(key string) string {
+	if origin, ok := p.origins[key]; ok {
+		return origin
+	}
+	if _, ok := p.env.Lookup(key); ok {
+		return originEnvironment
+	}
+	if _, ok := p.random.Lookup(key); ok {
+		return PropertySourceRandom
+	}
+	return ""
+}
+
+// Dump 按照 key 的字母顺序输出所有生效的属性值及其来源，敏感属性的值会被隐藏。
+func (p *EffectiveProperties) Dump(w io.Writer) error {
+	keys := p.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		v := p.Get(key)
+		if isSensitiveKey(key) {
+			v = "******"
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s # %s\n", key, v, p.Origin(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
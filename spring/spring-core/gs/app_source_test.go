@@ -0,0 +1,149 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type relaxedConfig struct {
+	MaxOpen int    `value:"${spring.datasource.max-open:=5}"`
+	URL     string `value:"${spring.datasource.url}"`
+	Name    string `value:"${spring.application.name:=demo}"`
+}
+
+func TestApp_RelaxedEnv(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "relaxed")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	data := "spring.datasource.url=mysql://file\nspring.datasource.max-open=1\n"
+	assert.Nil(t, ioutil.WriteFile(dir+"/application.properties", []byte(data), 0644))
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	gs.Setenv("SPRING_DATASOURCE_MAX_OPEN", "10")
+	gs.Setenv("spring_datasource_url", "mysql://env")
+	gs.Setenv("SPRING_APPLICATION_NAME", "relaxed")
+	app := gs.NewApp()
+	cfg := new(relaxedConfig)
+	app.Object(cfg)
+
+	errCh := startApp(app)
+	assert.Equal(t, cfg.MaxOpen, 10)
+	assert.Equal(t, cfg.URL, "mysql://env")
+	assert.Equal(t, cfg.Name, "relaxed")
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+func TestApp_CommandLine(t *testing.T) {
+
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"app",
+		"--spring.datasource.url=mysql://cmd",
+		"-spring.application.name", "cmd",
+		"--", "--spring.datasource.max-open=99",
+	}
+
+	os.Clearenv()
+	gs.Setenv("SPRING_DATASOURCE_URL", "mysql://env")
+	gs.Setenv("SPRING_DATASOURCE_MAX_OPEN", "10")
+	gs.Setenv("GS_SPRING_APPLICATION_NAME", "env")
+	app := gs.NewApp()
+	cfg := new(relaxedConfig)
+	app.Object(cfg)
+
+	errCh := startApp(app)
+	assert.Equal(t, cfg.URL, "mysql://cmd")
+	assert.Equal(t, cfg.Name, "cmd")
+	assert.Equal(t, cfg.MaxOpen, 10)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+func TestApp_PropertySources(t *testing.T) {
+
+	os.Clearenv()
+	gs.Setenv("SPRING_DATASOURCE_MAX_OPEN", "10")
+	app := gs.NewApp()
+	app.Property("spring.datasource.url", "mysql://code")
+	app.Property("spring.application.name", "code")
+
+	override := conf.New()
+	_ = override.Set("spring.application.name", "override")
+	_ = override.Set("spring.datasource.url", "mysql://override")
+	app.AddPropertySourceBefore(gs.PropertySourceEnvironment, "override", override)
+
+	defaults := conf.New()
+	_ = defaults.Set("spring.datasource.url", "mysql://defaults")
+	_ = defaults.Set("spring.datasource.user", "root")
+	app.AddPropertySourceAfter(gs.PropertySourceCode, "defaults", defaults)
+
+	sources, err := app.PropertySources()
+	assert.Nil(t, err)
+	assert.Equal(t, sources, []string{
+		gs.PropertySourceCommandLine,
+		"override",
+		gs.PropertySourceEnvironment,
+		gs.PropertySourceConfigFiles,
+		gs.PropertySourceCode,
+		"defaults",
+		gs.PropertySourceRandom,
+	})
+
+	cfg := new(relaxedConfig)
+	app.Object(cfg)
+
+	errCh := startApp(app)
+	assert.Equal(t, cfg.URL, "mysql://override")
+	assert.Equal(t, cfg.Name, "override")
+	assert.Equal(t, cfg.MaxOpen, 10)
+
+	p := app.Properties()
+	assert.Equal(t, p.Origin("spring.datasource.url"), "override")
+	assert.Equal(t, p.Origin("spring.datasource.user"), "defaults")
+	assert.Equal(t, p.Origin("spring.datasource.max-open"), gs.PropertySourceEnvironment)
+	assert.Equal(t, p.Origin("spring.datasource.password"), "")
+	assert.Equal(t, p.Origin("random.uuid"), gs.PropertySourceRandom)
+	assert.Equal(t, app.Properties().Get("random.uuid"), p.Get("random.uuid"))
+
+	var buf bytes.Buffer
+	assert.Nil(t, p.Dump(&buf))
+	assert.Matches(t, buf.String(), "spring.datasource.url=mysql://override # override\n")
+	assert.Matches(t, buf.String(), "spring.datasource.user=root # defaults\n")
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+func TestApp_PropertySourceNotFound(t *testing.T) {
+	app := gs.NewApp()
+	app.AddPropertySourceBefore("remote:consul", "override", conf.New())
+	assert.Error(t, app.Run(), "property source \"remote:consul\" not found for \"override\"")
+}
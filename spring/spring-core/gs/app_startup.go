@@ -0,0 +1,144 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/log"
+)
+
+// slowBeanCount 启动日志中列出的耗时最长的 bean 的数量。
+const slowBeanCount = 5
+
+// StartupPhase 应用启动阶段的耗时。
+type StartupPhase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// BeanTiming 单例 bean 的注入耗时，包括创建、属性绑定、依赖注入和初始化，Total
+// 包括注入依赖项的耗时，Self 不包括。
+type BeanTiming struct {
+	Name   string
+	Type   string
+	Source string
+	Total  time.Duration
+	Self   time.Duration
+}
+
+// StartupReport 应用启动的耗时报告，Beans 按照 Self 从大到小排列。
+type StartupReport struct {
+	Total  time.Duration
+	Phases []StartupPhase
+	Beans  []BeanTiming
+}
+
+// startupTimer 记录应用启动各个阶段的耗时。
+type startupTimer struct {
+	mu     sync.Mutex
+	start  time.Time
+	last   time.Time
+	phases []StartupPhase
+}
+
+func (t *startupTimer) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = time.Now()
+	t.last = t.start
+	t.phases = nil
+}
+
+// mark 结束名为 name 的阶段，该阶段从上一个阶段结束时开始。
+func (t *startupTimer) mark(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.phases = append(t.phases, StartupPhase{Name: name, Duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// beanTimings 返回单例 bean 的注入耗时，包括运行时注入的 bean 。
+func (c *container) beanTimings() []BeanTiming {
+	c.runtimeMu.Lock()
+	defer c.runtimeMu.Unlock()
+	return append([]BeanTiming{}, c.timings...)
+}
+
+// StartupReport 返回应用启动各个阶段以及每个单例 bean 的耗时。
+func (app *App) StartupReport() *StartupReport {
+	app.startup.mu.Lock()
+	r := &StartupReport{
+		Total:  app.startup.last.Sub(app.startup.start),
+		Phases: append([]StartupPhase{}, app.startup.phases...),
+	}
+	app.startup.mu.Unlock()
+	r.Beans = app.c.beanTimings()
+	sort.SliceStable(r.Beans, func(i, j int) bool {
+		return r.Beans[i].Self > r.Beans[j].Self
+	})
+	return r
+}
+
+// logStartup 打印各个启动阶段的耗时以及耗时最长的几个 bean 。
+func (app *App) logStartup() {
+	r := app.StartupReport()
+	var phases []string
+	for _, p := range r.Phases {
+		phases = append(phases, fmt.Sprintf("%s %v", p.Name, p.Duration))
+	}
+	log.Infof("application started in %v (%s)", r.Total, strings.Join(phases, ", "))
+	var beans []string
+	for i := 0; i < len(r.Beans) && i < slowBeanCount; i++ {
+		beans = append(beans, fmt.Sprintf("%s %v", r.Beans[i].Name, r.Beans[i].Self))
+	}
+	if len(beans) > 0 {
+		log.Infof("slowest beans: %s", strings.Join(beans, ", "))
+	}
+}
+
+// startupInfo 返回 /startup 接口的内容，耗时使用 1.5s 这样的字符串表示。
+func (app *App) startupInfo() map[string]interface{} {
+	r := app.StartupReport()
+	var phases []map[string]string
+	for _, p := range r.Phases {
+		phases = append(phases, map[string]string{
+			"name":     p.Name,
+			"duration": p.Duration.String(),
+		})
+	}
+	var beans []map[string]string
+	for _, b := range r.Beans {
+		beans = append(beans, map[string]string{
+			"name":   b.Name,
+			"type":   b.Type,
+			"source": b.Source,
+			"total":  b.Total.String(),
+			"self":   b.Self.String(),
+		})
+	}
+	return map[string]interface{}{
+		"total":  r.Total.String(),
+		"phases": phases,
+		"beans":  beans,
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type slowBean struct {
+	Dep *slowDep `autowire:""`
+}
+
+type slowDep struct{}
+
+func TestApp_StartupReport(t *testing.T) {
+
+	os.Clearenv()
+	app := gs.NewApp()
+	app.Object(new(slowBean)).Init(func(b *slowBean) { time.Sleep(20 * time.Millisecond) })
+	app.Object(new(slowDep)).Init(func(d *slowDep) { time.Sleep(50 * time.Millisecond) })
+
+	errCh := startApp(app)
+	assert.Eventually(t, func() bool {
+		return len(app.StartupReport().Phases) == 6
+	}, time.Second, 5*time.Millisecond)
+
+	r := app.StartupReport()
+	var phases []string
+	var sum time.Duration
+	for _, p := range r.Phases {
+		phases = append(phases, p.Name)
+		sum += p.Duration
+	}
+	assert.Equal(t, phases, []string{"prepare", "properties", "refresh", "lifecycles", "runners", "ready"})
+	assert.Equal(t, sum, r.Total)
+	assert.True(t, r.Total >= 70*time.Millisecond)
+
+	assert.True(t, len(r.Beans) >= 2)
+	assert.Equal(t, r.Beans[0].Name, "slowDep")
+	assert.True(t, r.Beans[0].Self >= 50*time.Millisecond)
+	assert.Equal(t, r.Beans[1].Name, "slowBean")
+	assert.True(t, r.Beans[1].Self >= 20*time.Millisecond && r.Beans[1].Self < 50*time.Millisecond)
+	assert.True(t, r.Beans[1].Total >= 70*time.Millisecond)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
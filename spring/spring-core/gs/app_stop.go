@@ -0,0 +1,83 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-spring/spring-base/log"
+)
+
+// stopHook 应用关闭时执行的清理函数。
+type stopHook struct {
+	order int
+	fn    func(ctx context.Context) error
+}
+
+// OnStop 注册应用关闭时执行的清理函数，例如刷新录制的流量、保存缓存快照、关闭
+// 连接等。清理函数在后台任务结束之后、销毁 bean 之前按照 order 从小到大依次执行，
+// order 相同时按照注册的顺序。ctx 携带 spring.application.shutdown-timeout 剩余
+// 的截止时间，超时之后剩余的清理函数不再执行。
+func (app *App) OnStop(order int, fn func(ctx context.Context) error) {
+	app.stopMutex.Lock()
+	defer app.stopMutex.Unlock()
+	app.stopHooks = append(app.stopHooks, stopHook{order: order, fn: fn})
+}
+
+// runStopHooks 依次执行清理函数，返回第一个错误，清理函数的 panic 会被当作错误。
+func (app *App) runStopHooks(ctx context.Context) error {
+
+	app.stopMutex.Lock()
+	hooks := app.stopHooks
+	app.stopHooks = nil
+	app.stopMutex.Unlock()
+
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].order < hooks[j].order
+	})
+
+	var ret error
+	for i, h := range hooks {
+		if err := ctx.Err(); err != nil {
+			err = fmt.Errorf("%d stop hooks skipped: %w", len(hooks)-i, err)
+			log.Error(err)
+			if ret == nil {
+				ret = err
+			}
+			break
+		}
+		if err := callStopHook(ctx, h); err != nil {
+			err = fmt.Errorf("stop hook (order %d) error: %w", h.order, err)
+			log.Error(err)
+			if ret == nil {
+				ret = err
+			}
+		}
+	}
+	return ret
+}
+
+func callStopHook(ctx context.Context, h stopHook) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h.fn(ctx)
+}
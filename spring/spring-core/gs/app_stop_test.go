@@ -0,0 +1,149 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+)
+
+type shutdownEvent struct {
+	name string
+	r    *shutdownRecorder
+}
+
+func (e *shutdownEvent) OnAppStart(ctx gs.Context) {}
+
+func (e *shutdownEvent) OnAppStop(ctx context.Context) {
+	e.r.add("stop " + e.name)
+}
+
+type shutdownBean struct {
+	r *shutdownRecorder
+}
+
+func (b *shutdownBean) Destroy() {
+	b.r.add("destroy")
+}
+
+func TestApp_Stop(t *testing.T) {
+
+	os.Clearenv()
+	r := new(shutdownRecorder)
+	app := gs.NewApp()
+	app.Property("spring.application.shutdown-timeout", "50ms")
+	app.Listen(func(ctx context.Context, e gs.StoppingEvent) {
+		r.add(string(e.Phase))
+	})
+	app.Object(&shutdownEvent{name: "a", r: r}).Name("a").Export((*gs.AppEvent)(nil))
+	app.Object(&shutdownEvent{name: "b", r: r}).Name("b").Export((*gs.AppEvent)(nil))
+	app.Object(&shutdownBean{r: r}).Destroy((*shutdownBean).Destroy)
+	app.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Second) // 模拟无法及时退出的后台任务
+	})
+
+	errCh := startApp(app)
+
+	app.ShutDown("run test end")
+	err := <-errCh
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, gs.ExitCode(err), gs.ExitShutdownError)
+	assert.Equal(t, r.phases, []string{
+		"started",
+		"stop b",
+		"stop a",
+		"traffic-stopped",
+		"tasks-drained",
+		"destroy",
+		"completed",
+	})
+}
+
+func TestApp_OnStop(t *testing.T) {
+
+	t.Run("order", func(t *testing.T) {
+		os.Clearenv()
+		r := new(shutdownRecorder)
+		app := gs.NewApp()
+		app.Listen(func(ctx context.Context, e gs.StoppingEvent) {
+			r.add(string(e.Phase))
+		})
+		app.Object(&shutdownBean{r: r}).Destroy((*shutdownBean).Destroy)
+		app.OnStop(2, func(ctx context.Context) error {
+			r.add("hook 2")
+			return nil
+		})
+		app.OnStop(1, func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			r.add("hook 1a")
+			return errors.New("flush error")
+		})
+		app.OnStop(1, func(ctx context.Context) error {
+			r.add("hook 1b")
+			panic("snapshot panic")
+		})
+
+		errCh := startApp(app)
+
+		app.ShutDown("run test end")
+		err := <-errCh
+		assert.Error(t, err, "stop hook \\(order 1\\) error: flush error")
+		assert.Equal(t, gs.ExitCode(err), gs.ExitShutdownError)
+		assert.Equal(t, r.phases, []string{
+			"started",
+			"traffic-stopped",
+			"tasks-drained",
+			"hook 1a",
+			"hook 1b",
+			"hook 2",
+			"destroy",
+			"completed",
+		})
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		os.Clearenv()
+		r := new(shutdownRecorder)
+		app := gs.NewApp()
+		app.Property("spring.application.shutdown-timeout", "50ms")
+		app.OnStop(1, func(ctx context.Context) error {
+			<-ctx.Done()
+			r.add("hook 1")
+			return nil
+		})
+		app.OnStop(2, func(ctx context.Context) error {
+			r.add("hook 2")
+			return nil
+		})
+
+		errCh := startApp(app)
+
+		app.ShutDown("run test end")
+		err := <-errCh
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Error(t, err, "1 stop hooks skipped")
+		assert.Equal(t, r.phases, []string{"hook 1"})
+	})
+}
@@ -17,12 +17,17 @@
 package gs_test
 
 import (
+	"context"
+	"io/ioutil"
 	"os"
+	"regexp"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-spring/spring-base/assert"
 	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
 )
 
 func startApplication(cfgLocation string, fn func(gs.Context)) *gs.App {
@@ -47,6 +52,22 @@ func startApplication(cfgLocation string, fn func(gs.Context)) *gs.App {
 	return app
 }
 
+// startApp 在后台启动应用并且等待 ReadyEvent 发布之后返回，此后读取 bean 的
+// 字段是安全的。应用启动失败时返回的 chan 中已经包含了启动的错误，否则在应用退出
+// 之后接收到 Run 的结果。
+func startApp(app *gs.App) <-chan error {
+	ready := make(chan struct{})
+	app.Listen(func(ctx context.Context, e gs.ReadyEvent) { close(ready) })
+	errCh := make(chan error, 1)
+	go func() { errCh <- app.Run() }()
+	select {
+	case <-ready:
+	case err := <-errCh:
+		errCh <- err
+	}
+	return errCh
+}
+
 func TestConfig(t *testing.T) {
 
 	t.Run("config via env", func(t *testing.T) {
@@ -80,4 +101,165 @@ func TestConfig(t *testing.T) {
 		})
 		defer app.ShutDown("run test end")
 	})
+
+	t.Run("multiple profiles", func(t *testing.T) {
+		os.Clearenv()
+		gs.Setenv("GS_SPRING_PROFILES_ACTIVE", "test, metrics")
+		app := gs.NewApp()
+		gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", "testdata/config/")
+		app.Object(&shutdownRecorder{}).Name("both").On(cond.OnProfile("test & metrics"))
+		app.Object(&shutdownRecorder{}).Name("not-prod").On(cond.OnProfile("!prod"))
+		app.Object(&shutdownRecorder{}).Name("prod-or-dev").On(cond.OnProfile("prod | dev"))
+		checked := false
+		type PandoraAware struct{}
+		app.Provide(func(ctx gs.Context) PandoraAware {
+			checked = true
+			assert.Equal(t, ctx.Prop("spring.application.name"), "metrics")
+			assert.Equal(t, ctx.Prop("metrics.enabled"), "true")
+			var r *shutdownRecorder
+			assert.Nil(t, ctx.Get(&r, "both"))
+			assert.Nil(t, ctx.Get(&r, "not-prod"))
+			assert.Error(t, ctx.Get(&r, "prod-or-dev"), "can't find bean")
+			return PandoraAware{}
+		})
+		errCh := startApp(app)
+		app.ShutDown("run test end")
+		assert.Nil(t, <-errCh)
+		assert.True(t, checked)
+	})
+}
+
+type shutdownRecorder struct {
+	mu     sync.Mutex
+	phases []string
+}
+
+func (r *shutdownRecorder) add(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.phases = append(r.phases, s)
+}
+
+func (r *shutdownRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.phases...)
+}
+
+type orderedRunner struct {
+	name string
+	fail bool
+	runs *[]string
+}
+
+func (r *orderedRunner) Run(ctx gs.Context) {
+	*r.runs = append(*r.runs, r.name)
+	if r.fail {
+		panic("oops")
+	}
+}
+
+func TestApp_Runners(t *testing.T) {
+
+	run := func(policy string) ([]string, error) {
+		os.Clearenv()
+		var runs []string
+		app := gs.NewApp()
+		app.Property("spring.application.runner.failure-policy", policy)
+		app.Object(&orderedRunner{name: "c", runs: &runs}).Name("c").Order(3).Export((*gs.AppRunner)(nil))
+		app.Object(&orderedRunner{name: "a", runs: &runs, fail: true}).Name("a").Order(1).Export((*gs.AppRunner)(nil))
+		app.Object(&orderedRunner{name: "b", runs: &runs}).Name("b").Order(2).Export((*gs.AppRunner)(nil))
+		errCh := startApp(app)
+		select {
+		case err := <-errCh:
+			return runs, err
+		default:
+		}
+		app.ShutDown("run test end")
+		return runs, <-errCh
+	}
+
+	runs, err := run("abort")
+	assert.Error(t, err, "runner \\*gs_test.orderedRunner failed: oops")
+	assert.Equal(t, runs, []string{"a"})
+
+	runs, err = run("continue")
+	assert.Nil(t, err)
+	assert.Equal(t, runs, []string{"a", "b", "c"})
+
+	_, err = run("retry")
+	assert.Error(t, err, "unknown runner failure policy \"retry\"")
+}
+
+func TestApp_RunMode(t *testing.T) {
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_RUN_MODE", "debug")
+	err := gs.NewApp().Run()
+	assert.Error(t, err, "invalid run mode \"debug\"")
+	assert.Equal(t, gs.ExitCode(err), gs.ExitStartupError)
+}
+
+func TestApp_FastDevTenant(t *testing.T) {
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_FASTDEV_TENANT", "order.prod")
+	err := gs.NewApp().Run()
+	assert.Error(t, err, "invalid tenant \"order.prod\"")
+	assert.Equal(t, gs.ExitCode(err), gs.ExitStartupError)
+}
+
+func TestApp_Banner(t *testing.T) {
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_BANNER_VISIBLE", "true")
+	app := gs.NewApp()
+	app.Property("spring.application.name", "demo")
+	app.Banner("${spring.application.name} ${spring.version} ${unknown:=x}")
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	assert.Nil(t, err)
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	errCh := startApp(app)
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+
+	os.Stdout = stdout
+	assert.Nil(t, w.Close())
+	b, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Matches(t, string(b), "demo "+regexp.QuoteMeta(gs.Version)+" x")
+}
+
+type validServer struct {
+	Port int `value:"${server.port}" validate:"min=1,max=65535"`
+}
+
+type validDB struct {
+	Host string `value:"${db.host:=}" validate:"required"`
+	Mode string `value:"${db.mode:=dev}" validate:"oneof=dev prod"`
+}
+
+func TestApp_Validate(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "validate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := dir + "/application.properties"
+	assert.Nil(t, ioutil.WriteFile(file, []byte("server.port=80000\n"), 0644))
+
+	os.Clearenv()
+	gs.Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	gs.Setenv("GS_DB_MODE", "test")
+	app := gs.NewApp()
+	app.Object(new(validServer))
+	app.Object(new(validDB))
+
+	err = app.Run()
+	assert.Error(t, err, "invalid properties: 3 errors occurred:")
+	assert.Error(t, err, `Port must be at most 65535 \(property "server.port" from `+file+`\)`)
+	assert.Error(t, err, `Host is required \(property "db.host"\)`)
+	assert.Error(t, err, `Mode must be one of \[dev prod\] \(property "db.mode" from environment\)`)
 }
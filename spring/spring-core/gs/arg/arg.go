@@ -205,6 +205,20 @@ func (r *argList) get(ctx Context, fileLine string) ([]reflect.Value, error) {
 		}
 	}
 
+	// 没有为可变参数绑定值时，如果可变参数是 bean 类型，则注入所有该类型的 bean 。
+	if variadic && len(r.args) == numIn-1 {
+		t := fnType.In(numIn - 1)
+		if util.IsBeanType(t.Elem()) {
+			v := reflect.New(t).Elem()
+			if err := ctx.Wire(v, "*?"); err != nil {
+				return nil, err
+			}
+			for i := 0; i < v.Len(); i++ {
+				result = append(result, v.Index(i))
+			}
+		}
+	}
+
 	return result, nil
 }
 
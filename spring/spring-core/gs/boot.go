@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/go-spring/spring-base/conf"
 	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-base/util"
 	"github.com/go-spring/spring-core/grpc"
@@ -89,6 +90,41 @@ func ShutDown(msg ...string) {
 	gApp.ShutDown(msg...)
 }
 
+// ShutdownWithError 参考 App.ShutdownWithError 的解释。
+func ShutdownWithError(err error) {
+	gApp.ShutdownWithError(err)
+}
+
+// Schedule 参考 App.Schedule 的解释。
+func Schedule(name string, spec string, fn interface{}) *ScheduledTask {
+	return app().Schedule(name, spec, fn)
+}
+
+// AddEnvironmentPostProcessor 参考 App.AddEnvironmentPostProcessor 的解释。
+func AddEnvironmentPostProcessor(order int, pp EnvironmentPostProcessor) {
+	app().AddEnvironmentPostProcessor(order, pp)
+}
+
+// OnStartup 参考 App.OnStartup 的解释。
+func OnStartup(point HookPoint, fn func(ctx context.Context) error) {
+	app().OnStartup(point, fn)
+}
+
+// OnStop 参考 App.OnStop 的解释。
+func OnStop(order int, fn func(ctx context.Context) error) {
+	app().OnStop(order, fn)
+}
+
+// Listen 参考 App.Listen 的解释。
+func Listen(fn interface{}) *EventListener {
+	return app().Listen(fn)
+}
+
+// Publish 参考 App.Publish 的解释。
+func Publish(ctx context.Context, event interface{}) error {
+	return gApp.Publish(ctx, event)
+}
+
 // Banner 参考 App.Banner 的解释。
 func Banner(banner string) {
 	gApp.Banner(banner)
@@ -109,6 +145,16 @@ func Property(key string, value interface{}) {
 	app().Property(key, value)
 }
 
+// AddPropertySourceBefore 参考 App.AddPropertySourceBefore 的解释。
+func AddPropertySourceBefore(relative string, name string, p *conf.Properties) {
+	app().AddPropertySourceBefore(relative, name, p)
+}
+
+// AddPropertySourceAfter 参考 App.AddPropertySourceAfter 的解释。
+func AddPropertySourceAfter(relative string, name string, p *conf.Properties) {
+	app().AddPropertySourceAfter(relative, name, p)
+}
+
 // Object 参考 Container.Object 的解释。
 func Object(i interface{}) *BeanDefinition {
 	return app().c.register(NewBean(reflect.ValueOf(i)))
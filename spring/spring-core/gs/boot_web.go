@@ -18,22 +18,42 @@ package gs
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/go-spring/spring-core/health"
 	"github.com/go-spring/spring-core/web"
 )
 
 func init() {
 	gInits = append(gInits, func(s *startup) {
 		if s.web {
-			Object(new(WebStarter)).Export((*AppEvent)(nil))
+			Object(new(WebStarter)).Export((*AppEvent)(nil), (*health.Indicator)(nil))
+			Object(RequestScopeFilter()).Name("request-scope-filter").Export((*web.Filter)(nil))
 		}
 	})
 }
 
+// RequestScopeFilter 为每个请求创建请求作用域，请求结束时销毁作用域中的 bean ，
+// 处理函数中可以通过 Context.GetWithCtx 获取请求作用域的 bean 。
+func RequestScopeFilter() web.Filter {
+	return web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {
+		_, end := WithRequestScope(ctx.Context())
+		defer end()
+		chain.Next(ctx)
+	})
+}
+
 // WebStarter Web 服务器启动器
 type WebStarter struct {
+	mu      sync.RWMutex
+	started bool
+	errs    map[string]string
+
+	App        *App         `autowire:""`
 	Containers []web.Server `autowire:""`
 	Filters    []web.Filter `autowire:"${web.server.filters:=*?}"`
 	Router     web.Router   `autowire:""`
@@ -41,8 +61,16 @@ type WebStarter struct {
 
 // OnAppStart 应用程序启动事件。
 func (starter *WebStarter) OnAppStart(ctx Context) {
+	if err := starter.checkContainers(); err != nil {
+		starter.App.ShutdownWithError(err)
+		return
+	}
 	for _, c := range starter.Containers {
-		c.AddFilter(starter.Filters...)
+		for _, f := range starter.Filters {
+			if servesOn(f, c) {
+				c.AddFilter(f)
+			}
+		}
 	}
 	for _, m := range starter.Router.Mappers() {
 		for _, c := range starter.getContainers(m) {
@@ -52,9 +80,48 @@ func (starter *WebStarter) OnAppStart(ctx Context) {
 	starter.startContainers(ctx)
 }
 
+// checkContainers 检查多个 web 服务器的名称和监听地址是否重复。
+func (starter *WebStarter) checkContainers() error {
+	names := make(map[string]bool)
+	addrs := make(map[string]bool)
+	for _, c := range starter.Containers {
+		cfg := c.Config()
+		if cfg.Name != "" {
+			if names[cfg.Name] {
+				return fmt.Errorf("duplicate web server name %q", cfg.Name)
+			}
+			names[cfg.Name] = true
+		}
+		addr := address(c)
+		if addrs[addr] {
+			return fmt.Errorf("duplicate web server address %q", addr)
+		}
+		addrs[addr] = true
+	}
+	return nil
+}
+
+// servesOn 返回 v 是否作用于服务器 c ，v 是通过 Servers 方法指定服务器名称的
+// Mapper 或者过滤器，没有指定服务器名称时作用于所有的服务器。
+func servesOn(v interface{}, c web.Server) bool {
+	s, ok := v.(interface{ Servers() []string })
+	if !ok || len(s.Servers()) == 0 {
+		return true
+	}
+	for _, name := range s.Servers() {
+		if name == c.Config().Name {
+			return true
+		}
+	}
+	return false
+}
+
 func (starter *WebStarter) getContainers(mapper *web.Mapper) []web.Server {
 	var ret []web.Server
 	for _, c := range starter.Containers {
+		if !servesOn(mapper, c) {
+			continue
+		}
 		if strings.HasPrefix(mapper.Path(), c.Config().BasePath) {
 			ret = append(ret, c)
 		}
@@ -63,16 +130,55 @@ func (starter *WebStarter) getContainers(mapper *web.Mapper) []web.Server {
 }
 
 func (starter *WebStarter) startContainers(ctx Context) {
+	starter.mu.Lock()
+	starter.started = true
+	starter.errs = make(map[string]string)
+	starter.mu.Unlock()
 	for i := range starter.Containers {
 		c := starter.Containers[i]
 		ctx.Go(func(_ context.Context) {
 			if err := c.Start(); err != nil && err != http.ErrServerClosed {
-				ShutDown(err.Error())
+				starter.mu.Lock()
+				starter.errs[address(c)] = err.Error()
+				starter.mu.Unlock()
+				starter.App.ShutdownWithError(fmt.Errorf("web server %s start error: %w", address(c), err))
 			}
 		})
 	}
 }
 
+func address(c web.Server) string {
+	cfg := c.Config()
+	return fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+}
+
+// Name 返回健康指示器的名称。
+func (starter *WebStarter) Name() string {
+	return "web"
+}
+
+// Health 返回 web 服务器的健康状态，服务器没有启动或者启动失败时不健康。
+func (starter *WebStarter) Health(ctx context.Context) health.Health {
+	starter.mu.RLock()
+	defer starter.mu.RUnlock()
+	if !starter.started {
+		return health.Down(errors.New("web servers not started"))
+	}
+	details := make(map[string]interface{})
+	for _, c := range starter.Containers {
+		addr := address(c)
+		if msg, ok := starter.errs[addr]; ok {
+			details[addr] = msg
+		} else {
+			details[addr] = health.StatusUp
+		}
+	}
+	if len(starter.errs) > 0 {
+		return health.Health{Status: health.StatusDown, Details: details}
+	}
+	return health.Up(details)
+}
+
 // OnAppStop 应用程序结束事件。
 func (starter *WebStarter) OnAppStop(ctx context.Context) {
 	for _, c := range starter.Containers {
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/web"
+)
+
+type fakeWebServer struct {
+	web.Server
+	cfg     web.ServerConfig
+	mappers []*web.Mapper
+	filters []web.Filter
+	stop    chan struct{}
+	err     error
+}
+
+func newFakeWebServer(name string, port int) *fakeWebServer {
+	cfg := web.ServerConfig{Name: name, Port: port, BasePath: "/"}
+	return &fakeWebServer{cfg: cfg, stop: make(chan struct{})}
+}
+
+func (s *fakeWebServer) Config() web.ServerConfig  { return s.cfg }
+func (s *fakeWebServer) Mappers() []*web.Mapper    { return s.mappers }
+func (s *fakeWebServer) AddMapper(m *web.Mapper)   { s.mappers = append(s.mappers, m) }
+func (s *fakeWebServer) AddFilter(f ...web.Filter) { s.filters = append(s.filters, f...) }
+
+func (s *fakeWebServer) Start() error {
+	if s.err != nil {
+		return s.err
+	}
+	<-s.stop
+	return http.ErrServerClosed
+}
+
+func (s *fakeWebServer) Stop(ctx context.Context) error {
+	close(s.stop)
+	return nil
+}
+
+func TestApp_MultipleWebServers(t *testing.T) {
+
+	os.Clearenv()
+	app := gs.NewApp()
+	public := newFakeWebServer("", 8080)
+	internal := newFakeWebServer("internal", 8081)
+	metrics := newFakeWebServer("metrics", 9091)
+	app.Object(public).Name("public-server").Export((*web.Server)(nil))
+	app.Object(internal).Name("internal-server").Export((*web.Server)(nil))
+	app.Object(metrics).Name("metrics-server").Export((*web.Server)(nil))
+	app.Object(new(gs.WebStarter)).Export((*gs.AppEvent)(nil))
+
+	noop := web.FuncFilter(func(ctx web.Context, chain web.FilterChain) { chain.Next(ctx) })
+	app.Object(web.ServerFilter(noop, "internal")).Export((*web.Filter)(nil))
+
+	app.GetMapping("/hello", func(ctx web.Context) {})
+	app.GetMapping("/config", func(ctx web.Context) {}).Server("internal")
+	app.GetMapping("/metrics", func(ctx web.Context) {}).Server("metrics", "internal")
+
+	errCh := startApp(app)
+
+	paths := func(s *fakeWebServer) []string {
+		var ret []string
+		for _, m := range s.mappers {
+			ret = append(ret, m.Path())
+		}
+		return ret
+	}
+	assert.Equal(t, paths(public), []string{"/hello", "/healthz", "/readyz"})
+	assert.Equal(t, paths(internal), []string{"/hello", "/config", "/metrics", "/healthz", "/readyz"})
+	assert.Equal(t, paths(metrics), []string{"/hello", "/metrics", "/healthz", "/readyz"})
+	assert.Equal(t, len(public.filters), 0)
+	assert.Equal(t, len(internal.filters), 1)
+
+	app.ShutDown("run test end")
+	assert.Nil(t, <-errCh)
+}
+
+func TestApp_WebServerStartError(t *testing.T) {
+
+	os.Clearenv()
+	app := gs.NewApp()
+	server := newFakeWebServer("", 8080)
+	server.err = errors.New("address already in use")
+	app.Object(server).Export((*web.Server)(nil))
+	app.Object(new(gs.WebStarter)).Export((*gs.AppEvent)(nil))
+
+	err := <-startApp(app)
+	assert.Error(t, err, "web server :8080 start error: address already in use")
+	assert.Equal(t, gs.ExitCode(err), gs.ExitRuntimeError)
+}
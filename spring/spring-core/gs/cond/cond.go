@@ -19,13 +19,14 @@ package cond
 
 import (
 	"errors"
+	"fmt"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"strconv"
 	"strings"
 
 	"github.com/go-spring/spring-base/conf"
-	"github.com/go-spring/spring-base/util"
 	"github.com/go-spring/spring-core/gs/internal"
 )
 
@@ -147,13 +148,171 @@ func (c *onSingleCandidate) Matches(ctx Context) (bool, error) {
 	return len(beans) == 1, err
 }
 
-// onExpression 基于表达式的 Condition 实现。
+// onExpression 基于表达式的 Condition 实现，表达式使用 Go 语法，可以通过
+// ${key} 或者 ${key:=def} (也可以简写为 ${key:def}) 的形式引用属性值，例如
+// ${a} == "x" && ${b:=0} != "0" 。属性值一律替换为 Go 字符串字面量，避免属性值
+// 中的内容被当作表达式的一部分求值。
 type onExpression struct {
 	expression string
 }
 
 func (c *onExpression) Matches(ctx Context) (bool, error) {
-	return false, util.UnimplementedMethod
+	expr, err := c.resolve(ctx)
+	if err != nil {
+		return false, err
+	}
+	ret, err := types.Eval(token.NewFileSet(), nil, token.NoPos, expr)
+	if err != nil {
+		return false, err
+	}
+	if ret.Value == nil || ret.Value.Kind() != constant.Bool {
+		return false, fmt.Errorf("expression %q is not a bool expression", c.expression)
+	}
+	return constant.BoolVal(ret.Value), nil
+}
+
+// resolve 将表达式中的属性引用替换为字符串字面量。
+func (c *onExpression) resolve(ctx Context) (string, error) {
+	var (
+		s   = c.expression
+		buf strings.Builder
+	)
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			buf.WriteString(s)
+			return buf.String(), nil
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("invalid expression %q", c.expression)
+		}
+		end += start
+		key, def, hasDef := s[start+2:end], "", false
+		if i := strings.Index(key, ":"); i >= 0 {
+			key, def, hasDef = key[:i], strings.TrimPrefix(key[i+1:], "="), true
+		}
+		var val string
+		switch {
+		case ctx.Has(key):
+			val = ctx.Prop(key)
+		case hasDef:
+			val = def
+		default:
+			return "", fmt.Errorf("property %q not exist", key)
+		}
+		buf.WriteString(s[:start])
+		buf.WriteString(strconv.Quote(val))
+		s = s[end+1:]
+	}
+}
+
+// onProfile 基于 profile 表达式的 Condition 实现，spring.profiles.active 属性可
+// 以使用逗号分隔多个 profile 。表达式由 profile 名称以及 !、
+// &、| 和括号组成，例如 dev 、 !prod 、 dev & cloud 、 (dev | test) & !cloud ，
+// 其中 & 的优先级高于 | 。
+type onProfile struct {
+	expression string
+}
+
+func (c *onProfile) Matches(ctx Context) (bool, error) {
+	active := make(map[string]bool)
+	for _, s := range strings.Split(ctx.Prop("spring.profiles.active"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			active[s] = true
+		}
+	}
+	p := &profileParser{expr: c.expression, active: active}
+	ok, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.skipSpace(); p.pos < len(p.expr) {
+		return false, fmt.Errorf("invalid profile expression %q", c.expression)
+	}
+	return ok, nil
+}
+
+// profileParser profile 表达式的递归下降解析器，解析的同时计算表达式的值。
+type profileParser struct {
+	expr   string
+	pos    int
+	active map[string]bool
+}
+
+func (p *profileParser) skipSpace() {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// peek 跳过空格之后返回下一个字符，没有字符时返回 0 。
+func (p *profileParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.expr) {
+		return p.expr[p.pos]
+	}
+	return 0
+}
+
+func (p *profileParser) parseOr() (bool, error) {
+	ret, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == '|' {
+		p.pos++
+		ok, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		ret = ret || ok
+	}
+	return ret, nil
+}
+
+func (p *profileParser) parseAnd() (bool, error) {
+	ret, err := p.parseNot()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == '&' {
+		p.pos++
+		ok, err := p.parseNot()
+		if err != nil {
+			return false, err
+		}
+		ret = ret && ok
+	}
+	return ret, nil
+}
+
+func (p *profileParser) parseNot() (bool, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		ok, err := p.parseNot()
+		return !ok, err
+	case '(':
+		p.pos++
+		ok, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ')' {
+			return false, fmt.Errorf("invalid profile expression %q", p.expr)
+		}
+		p.pos++
+		return ok, nil
+	}
+	start := p.pos
+	for p.pos < len(p.expr) && !strings.ContainsRune(" \t!&|()", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return false, fmt.Errorf("invalid profile expression %q", p.expr)
+	}
+	return p.active[p.expr[start:p.pos]], nil
 }
 
 // Operator 条件操作符，包含 Or、And、None 三种。
@@ -395,12 +554,13 @@ func (c *conditional) OnMatches(fn Matches) *conditional {
 	return c.On(&onMatches{fn: fn})
 }
 
-// OnProfile 返回一个以 spring.profile 属性值是否匹配为开始条件的计算式。
-func OnProfile(profile string) *conditional {
-	return New().OnProfile(profile)
+// OnProfile 返回一个以 onProfile 为开始条件的计算式。
+func OnProfile(expression string) *conditional {
+	return New().OnProfile(expression)
 }
 
-// OnProfile 添加一个 spring.profile 属性值是否匹配的条件。
-func (c *conditional) OnProfile(profile string) *conditional {
-	return c.OnProperty("spring.profiles.active", HavingValue(profile))
+// OnProfile 添加一个 onProfile 条件，expression 可以是单个 profile 也可以是
+// profile 表达式，例如 !prod 或者 dev & cloud 。
+func (c *conditional) OnProfile(expression string) *conditional {
+	return c.On(&onProfile{expression: expression})
 }
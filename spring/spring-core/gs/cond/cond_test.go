@@ -0,0 +1,81 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cond_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/gs/cond"
+)
+
+// propContext 只提供属性的 cond.Context 实现。
+type propContext struct {
+	p *conf.Properties
+}
+
+func (c *propContext) Has(key string) bool {
+	return c.p.Has(key)
+}
+
+func (c *propContext) Prop(key string, opts ...conf.GetOption) string {
+	return c.p.Get(key, opts...)
+}
+
+func (c *propContext) Find(selector cond.BeanSelector) ([]cond.BeanDefinition, error) {
+	return nil, nil
+}
+
+func TestOnExpression(t *testing.T) {
+
+	p := conf.New()
+	assert.Nil(t, p.Set("server.port", 8080))
+	assert.Nil(t, p.Set("server.mode", "prod"))
+	assert.Nil(t, p.Set("server.name", `a" || "b`))
+	assert.Nil(t, p.Set("server.debug", "true"))
+	assert.Nil(t, p.Set("server.code", "len(x)"))
+	ctx := &propContext{p}
+
+	for expr, expect := range map[string]bool{
+		`${server.port} == "8080" && ${server.mode} == "prod"`: true,
+		`${server.mode} != "prod"`:                             false,
+		`${server.workers:=4} == "4"`:                          true,
+		`${server.workers:4} == "4"`:                           true,
+		`${server.profile:=} == ""`:                            true,
+		`${server.debug} == "true"`:                            true,
+		`${server.name} == "a\" || \"b"`:                       true,
+		`${server.name} == "a"`:                                false,
+		`${server.code} == "len(x)"`:                           true,
+		`${server.workers:=1 || true} == "1 || true"`:          true,
+	} {
+		ok, err := cond.OnExpression(expr).Matches(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, ok, expect)
+	}
+
+	for expr, msg := range map[string]string{
+		`${server.port}`:          `expression "\${server.port}" is not a bool expression`,
+		`${server.debug}`:         `expression "\${server.debug}" is not a bool expression`,
+		`${server.none} == "x"`:   `property "server.none" not exist`,
+		`${server.port == "8080"`: `invalid expression "\${server.port == \\"8080\\""`,
+		`${server.port} > 1024`:   `mismatched types`,
+	} {
+		_, err := cond.OnExpression(expr).Matches(ctx)
+		assert.Error(t, err, msg)
+	}
+}
@@ -20,16 +20,18 @@ package gs
 
 import (
 	"bytes"
-	"container/list"
 	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-spring/spring-base/code"
 	"github.com/go-spring/spring-base/conf"
 	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-base/util"
@@ -40,6 +42,15 @@ import (
 	_ "github.com/go-spring/spring-core/gs/conf/toml"
 )
 
+// OverridePolicyKey 重复注册 bean 时的处理策略，重复是指类型和名称都相同。
+const OverridePolicyKey = "spring.main.bean-override-policy"
+
+const (
+	OverrideForbid = "forbid"             // 报告错误，默认策略
+	OverrideWarn   = "allow-with-warning" // 后注册的覆盖先注册的，并打印告警日志
+	OverrideAllow  = "allow"              // 后注册的覆盖先注册的
+)
+
 type refreshState int
 
 const (
@@ -64,6 +75,7 @@ type tempContainer struct {
 	beansByName     map[string][]*BeanDefinition
 	beansByType     map[reflect.Type][]*BeanDefinition
 	mapOfOnProperty map[string]interface{}
+	origins         map[string]string // 属性的来源，用于报告校验错误
 }
 
 // container 是 go-spring 框架的基石，实现了 Martin Fowler 在 << Inversion
@@ -79,7 +91,19 @@ type container struct {
 	cancel     context.CancelFunc
 	destroyers []func()
 	state      refreshState
-	wg         sync.WaitGroup
+	runtimeMu  sync.Mutex // 串行化容器刷新之后的注入
+	processors []BeanPostProcessor
+	deps       *dependencyGraph // 注入过程中形成的依赖关系
+	refreshes  []refreshBean    // 属性变化时需要重新绑定的 bean
+	dynamics   []dynamicValue   // 属性变化时需要就地刷新的动态属性
+	timings    []BeanTiming     // 单例 bean 的注入耗时
+	parent     *container       // 父容器
+	childMu    sync.Mutex
+	children   []*container // 子容器
+
+	// panicHandler 处理 Go 启动的 goroutine 中发生的 panic ，为空时只打印日志。
+	panicHandler func(r interface{}, stack []byte)
+	wg           sync.WaitGroup
 }
 
 // New 创建 IoC 容器。
@@ -88,6 +112,7 @@ func New() Container {
 	return &container{
 		ctx:    ctx,
 		cancel: cancel,
+		deps:   newDependencyGraph(),
 		tempContainer: &tempContainer{
 			p:               conf.New(),
 			beansByName:     make(map[string][]*BeanDefinition),
@@ -143,57 +168,27 @@ func (c *container) Object(i interface{}) *BeanDefinition {
 }
 
 // Provide 注册构造函数形式的 bean ，需要注意的是该方法在注入开始后就不能再调用了。
+// 构造函数的参数自动注入，bean 类型的参数注入 bean ，其他类型的参数绑定属性值；
+// 没有绑定值的 bean 类型可变参数 (例如 ...Option) 注入所有该类型的 bean 。
 func (c *container) Provide(ctor interface{}, args ...arg.Arg) *BeanDefinition {
 	return c.register(NewBean(ctor, args...))
 }
 
-// destroyer 保存具有销毁函数的 bean 以及销毁函数的调用顺序。
-type destroyer struct {
-	current *BeanDefinition
-	earlier []*BeanDefinition
-}
-
-func (d *destroyer) foundEarlier(b *BeanDefinition) bool {
-	for _, c := range d.earlier {
-		if c == b {
-			return true
-		}
-	}
-	return false
-}
-
-// after 添加一个需要在该 bean 的销毁函数执行之前调用销毁函数的 bean 。
-func (d *destroyer) after(b *BeanDefinition) {
-	if d.foundEarlier(b) {
-		return
-	}
-	d.earlier = append(d.earlier, b)
-}
-
-// getBeforeDestroyers 获取排在 i 前面的 destroyer，用于 sort.Triple 排序。
-func getBeforeDestroyers(destroyers *list.List, i interface{}) *list.List {
-	d := i.(*destroyer)
-	result := list.New()
-	for e := destroyers.Front(); e != nil; e = e.Next() {
-		c := e.Value.(*destroyer)
-		if d.foundEarlier(c.current) {
-			result.PushBack(c)
-		}
-	}
-	return result
-}
-
 // wiringStack 记录 bean 的注入路径。
 type wiringStack struct {
-	destroyers   *list.List
-	destroyerMap map[string]*destroyer
+	ctx          context.Context // 获取 request 作用域的 bean 时使用
+	destroyerMap map[string]*BeanDefinition
 	beans        []*BeanDefinition
+	edges        []string        // 注入路径上每个 bean 被依赖的方式
+	via          string          // 下一个入栈的 bean 被依赖的方式
+	invalids     util.Errors     // 属性校验错误
+	starts       []time.Time     // 注入路径上每个 bean 开始注入的时间
+	nested       []time.Duration // 注入路径上每个 bean 注入依赖项的耗时
 }
 
 func newWiringStack() *wiringStack {
 	return &wiringStack{
-		destroyers:   list.New(),
-		destroyerMap: make(map[string]*destroyer),
+		destroyerMap: make(map[string]*BeanDefinition),
 	}
 }
 
@@ -201,14 +196,51 @@ func newWiringStack() *wiringStack {
 func (s *wiringStack) pushBack(b *BeanDefinition) {
 	log.Tracef("push %s %s", b, getStatusString(b.status))
 	s.beans = append(s.beans, b)
+	s.edges = append(s.edges, s.via)
+	s.starts = append(s.starts, time.Now())
+	s.nested = append(s.nested, 0)
+	s.via = ""
 }
 
-// popBack 删除一个已经注入的 bean 。
-func (s *wiringStack) popBack() {
+// popBack 删除一个已经注入的 bean ，返回它的注入耗时，total 包括注入依赖项的耗
+// 时，self 不包括。
+func (s *wiringStack) popBack() (total, self time.Duration) {
 	n := len(s.beans)
 	b := s.beans[n-1]
+	total = time.Since(s.starts[n-1])
+	self = total - s.nested[n-1]
 	s.beans = s.beans[:n-1]
+	s.edges = s.edges[:n-1]
+	s.starts = s.starts[:n-1]
+	s.nested = s.nested[:n-1]
+	if n > 1 {
+		s.nested[n-2] += total
+	}
 	log.Tracef("pop %s %s", b, getStatusString(b.status))
+	return total, self
+}
+
+// circle 返回栈顶 bean 形成的循环依赖路径，例如 bean:"a" → field B →
+// bean:"b" → constructor arg → bean:"a" 。
+func (s *wiringStack) circle() string {
+	n := len(s.beans)
+	b := s.beans[n-1]
+	i := 0
+	for ; i < n-1; i++ {
+		if s.beans[i] == b {
+			break
+		}
+	}
+	var buf bytes.Buffer
+	for j := i; j < n; j++ {
+		if j > i && s.edges[j] != "" {
+			buf.WriteString(" → " + s.edges[j] + " → ")
+		} else if j > i {
+			buf.WriteString(" → ")
+		}
+		fmt.Fprintf(&buf, "bean:%q", s.beans[j].BeanName())
+	}
+	return buf.String()
 }
 
 // path 返回 bean 的注入路径。
@@ -219,54 +251,74 @@ func (s *wiringStack) path() (path string) {
 	return path[:len(path)-1]
 }
 
-// saveDestroyer 记录具有销毁函数的 bean ，因为可能有多个依赖，因此需要排重处理。
-func (s *wiringStack) saveDestroyer(b *BeanDefinition) *destroyer {
-	d, ok := s.destroyerMap[b.ID()]
-	if !ok {
-		d = &destroyer{current: b}
-		s.destroyerMap[b.ID()] = d
-	}
-	return d
+// saveDestroyer 记录具有销毁函数的 bean 。
+func (s *wiringStack) saveDestroyer(b *BeanDefinition) {
+	s.destroyerMap[b.ID()] = b
 }
 
-// sortDestroyers 对具有销毁函数的 bean 按照销毁函数的依赖顺序进行排序。
-func (s *wiringStack) sortDestroyers() []func() {
+// sortDestroyers 根据 bean 的依赖关系对具有销毁函数的 bean 进行排序，依赖者先于被
+// 依赖者销毁，没有依赖关系的 bean 按照 ID 排序，保证每次启动的销毁顺序都是一样的。
+func (s *wiringStack) sortDestroyers(g *dependencyGraph) ([]func(), error) {
 
-	destroy := func(v reflect.Value, f interface{}) func() {
-		return func() {
-			if f == nil {
-				v.Interface().(BeanDestroy).OnDestroy()
-			} else {
-				fnValue := reflect.ValueOf(f)
-				out := fnValue.Call([]reflect.Value{v})
-				if len(out) > 0 && !out[0].IsNil() {
-					log.Error(out[0].Interface().(error))
-				}
-			}
+	ids := make([]string, 0, len(s.destroyerMap))
+	for id := range s.destroyerMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var edges []util.Edge[string]
+	for _, id := range ids {
+		for _, b := range g.reachable(s.destroyerMap[id], s.destroyerMap) {
+			edges = append(edges, util.Edge[string]{From: id, To: b.ID()})
 		}
 	}
 
-	destroyers := list.New()
-	for _, d := range s.destroyerMap {
-		destroyers.PushBack(d)
+	sorted, err := util.TopoSort(ids, edges)
+	if err != nil {
+		return nil, err
 	}
-	destroyers = util.TripleSort(destroyers, getBeforeDestroyers)
 
 	var ret []func()
-	for e := destroyers.Front(); e != nil; e = e.Next() {
-		d := e.Value.(*destroyer).current
-		ret = append(ret, destroy(d.Value(), d.destroy))
+	for _, id := range sorted {
+		d := s.destroyerMap[id]
+		v := d.Value()
+		if d.target.IsValid() {
+			v = d.target // 被替换的 bean 仍然销毁原来的值
+		}
+		ret = append(ret, destroyFunc(v, d.destroy))
+	}
+	return ret, nil
+}
+
+// destroyFunc 返回执行 bean 销毁函数的闭包，f 为 nil 时调用 BeanDestroy 接口。
+func destroyFunc(v reflect.Value, f interface{}) func() {
+	return func() {
+		if f == nil {
+			v.Interface().(BeanDestroy).OnDestroy()
+		} else {
+			fnValue := reflect.ValueOf(f)
+			out := fnValue.Call([]reflect.Value{v})
+			if len(out) > 0 && !out[0].IsNil() {
+				log.Error(out[0].Interface().(error))
+			}
+		}
 	}
-	return ret
 }
 
 func (c *container) clear() {
+	if c.needRuntimeWiring() || c.hasChildren() {
+		return
+	}
 	c.tempContainer = nil
 }
 
 // Refresh 刷新容器的内容，对 bean 进行有效性判断以及完成属性绑定和依赖注入。
 func (c *container) Refresh(opts ...internal.RefreshOption) (err error) {
 
+	if c.state == Unrefreshed {
+		c.inheritProperties()
+	}
+
 	for key, f := range c.mapOfOnProperty {
 		t := reflect.TypeOf(f)
 		in := reflect.New(t.In(0)).Elem()
@@ -290,14 +342,25 @@ func (c *container) Refresh(opts ...internal.RefreshOption) (err error) {
 	c.Object(c).Export((*Context)(nil))
 	c.state = Refreshing
 
+	c.replaceBeans()
 	for _, b := range c.beans {
 		c.registerBean(b)
 	}
 
+	// 一次报告所有 bean 的解析错误，而不是遇到第一个错误就返回。
+	var errs util.Errors
 	for _, b := range c.beans {
-		if err = c.resolveBean(b); err != nil {
-			return err
-		}
+		errs.Append(c.resolveBean(b))
+	}
+	if err = errs.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	policy := c.p.Get(OverridePolicyKey, conf.Def(OverrideForbid))
+	switch policy {
+	case OverrideForbid, OverrideWarn, OverrideAllow:
+	default:
+		return fmt.Errorf("unknown bean override policy %q", policy)
 	}
 
 	beansById := make(map[string]*BeanDefinition)
@@ -307,25 +370,59 @@ func (c *container) Refresh(opts ...internal.RefreshOption) (err error) {
 				continue
 			}
 			if b.status != Resolved {
-				return fmt.Errorf("unexpected status %d", b.status)
+				errs.Append(fmt.Errorf("unexpected status %d", b.status))
+				continue
+			}
+			if b.scope != ScopeSingleton && b.f == nil {
+				errs.Append(fmt.Errorf("%s can't be %s scoped, only constructor bean can", b, b.scope))
+				continue
 			}
 			beanID := b.ID()
 			if d, ok := beansById[beanID]; ok {
-				return fmt.Errorf("found duplicate beans [%s] [%s]", b, d)
+				if policy == OverrideForbid {
+					errs.Append(fmt.Errorf("found duplicate beans [%s] [%s]", b, d))
+					continue
+				}
+				if policy == OverrideWarn {
+					log.Warnf("bean [%s] is overridden by [%s]", d, b)
+				}
+				d.status = Deleted
 			}
 			beansById[beanID] = b
+			for _, alias := range b.aliases {
+				aliasID := b.typeName + ":" + alias
+				if d, ok := beansById[aliasID]; ok && d != b && d.status != Deleted {
+					errs.Append(fmt.Errorf("alias %q of [%s] conflicts with [%s]", alias, b, d))
+					continue
+				}
+				beansById[aliasID] = b
+			}
+		}
+		if err = errs.ErrorOrNil(); err != nil {
+			return err
 		}
 	}
 
 	stack := newWiringStack()
 
 	defer func() {
-		if err != nil || len(stack.beans) > 0 {
+		if len(stack.beans) > 0 {
 			err = fmt.Errorf("%s ↩\n%s", err, stack.path())
+		}
+		if err != nil {
 			log.Error(err)
 		}
 	}()
 
+	if err = c.collectPostProcessors(stack); err != nil {
+		return err
+	}
+
+	lazyAll, err := strconv.ParseBool(c.p.Get(LazyInitialization, conf.Def("false")))
+	if err != nil {
+		return util.Wrapf(err, code.FileLine(), "property %q", LazyInitialization)
+	}
+
 	// 按照 bean id 升序注入，保证注入过程始终一致。
 	{
 		var keys []string
@@ -335,13 +432,22 @@ func (c *container) Refresh(opts ...internal.RefreshOption) (err error) {
 		sort.Strings(keys)
 		for _, s := range keys {
 			b := beansById[s]
+			if b.scope != ScopeSingleton || c.isLazy(b, lazyAll) {
+				continue // 非单例 bean 和延迟初始化的 bean 在注入或者获取时才创建
+			}
 			if err = c.wireBean(b, stack); err != nil {
 				return err
 			}
 		}
 	}
 
-	c.destroyers = stack.sortDestroyers()
+	if err = c.invalidProperties(stack); err != nil {
+		return err
+	}
+
+	if c.destroyers, err = stack.sortDestroyers(c.deps); err != nil {
+		return err
+	}
 	c.state = Refreshed
 
 	cost := time.Now().Sub(start)
@@ -355,9 +461,60 @@ func (c *container) Refresh(opts ...internal.RefreshOption) (err error) {
 	return nil
 }
 
+// replaceBeans 删除被 Replace 的 bean 替换的 bean ，类型相同或者导出了相同接口
+// 的 bean 都会被替换。
+func (c *container) replaceBeans() {
+	for _, r := range c.beans {
+		if !r.replace {
+			continue
+		}
+		types := append([]reflect.Type{r.Type()}, r.exports...)
+		var replaced []*BeanDefinition
+		for _, b := range c.beans {
+			if b.replace || b.status == Deleted {
+				continue
+			}
+			if matchTypes(b, types) {
+				log.Debugf("%s replaced by %s", b, r)
+				b.status = Deleted
+				replaced = append(replaced, b)
+			}
+		}
+		if len(replaced) == 1 {
+			r.name = replaced[0].name
+			r.aliases = append(r.aliases, replaced[0].aliases...)
+		}
+	}
+}
+
+// matchTypes 返回 bean 的类型或者导出的接口是否属于 types 。
+func matchTypes(b *BeanDefinition, types []reflect.Type) bool {
+	for _, t := range types {
+		if b.Type() == t {
+			return true
+		}
+		for _, e := range b.exports {
+			if e == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dependencyGraph 返回单例 bean 之间的依赖关系图，包括运行时注入的 bean 。
+func (c *container) dependencyGraph() *DependencyGraph {
+	c.runtimeMu.Lock()
+	defer c.runtimeMu.Unlock()
+	return c.deps.export()
+}
+
 func (c *container) registerBean(b *BeanDefinition) {
 	log.Debugf("register %s name:%q type:%q %s", b.getClass(), b.BeanName(), b.Type(), b.FileLine())
 	c.beansByName[b.name] = append(c.beansByName[b.name], b)
+	for _, alias := range b.aliases {
+		c.beansByName[alias] = append(c.beansByName[alias], b)
+	}
 	c.beansByType[b.Type()] = append(c.beansByType[b.Type()], b)
 	for _, t := range b.exports {
 		log.Debugf("register %s name:%q type:%q %s", b.getClass(), b.BeanName(), t, b.FileLine())
@@ -368,7 +525,7 @@ func (c *container) registerBean(b *BeanDefinition) {
 // resolveBean 判断 bean 的有效性，如果 bean 是无效的则被标记为已删除。
 func (c *container) resolveBean(b *BeanDefinition) error {
 
-	if b.status >= Resolving {
+	if b.status == Deleted || b.status >= Resolving {
 		return nil
 	}
 
@@ -384,18 +541,13 @@ func (c *container) resolveBean(b *BeanDefinition) error {
 		if err != nil {
 			return err
 		}
-		n := len(parents)
-		if n > 1 {
-			msg := fmt.Sprintf("found %d parent beans, bean:%q type:%q [", n, selector, b.t.In(0))
-			for _, b := range parents {
-				msg += "( " + b.String() + " ), "
-			}
-			msg = msg[:len(msg)-2] + "]"
-			return errors.New(msg)
-		} else if n == 0 {
+		if len(parents) == 0 {
 			b.status = Deleted
 			return nil
 		}
+		if _, err = selectPrimary("parent beans", parents, selector, nil); err != nil {
+			return err
+		}
 	}
 
 	if b.cond != nil {
@@ -537,36 +689,31 @@ func (c *container) wireBean(b *BeanDefinition, stack *wiringStack) error {
 		return fmt.Errorf("bean:%q have been deleted", b.ID())
 	}
 
+	if n := len(stack.beans); n > 0 {
+		c.deps.add(stack.beans[n-1], b)
+	}
+
 	// 运行时 Get 或者 Wire 会出现下面这种情况。
 	if c.state == Refreshed && b.status == Wired {
 		return nil
 	}
 
-	haveDestroy := false
-
-	defer func() {
-		if haveDestroy {
-			stack.destroyers.Remove(stack.destroyers.Back())
-		}
-	}()
-
-	// 记录注入路径上的销毁函数及其执行的先后顺序。
-	if _, ok := b.Interface().(BeanDestroy); ok || b.destroy != nil {
-		haveDestroy = true
-		d := stack.saveDestroyer(b)
-		if i := stack.destroyers.Back(); i != nil {
-			d.after(i.Value.(*BeanDefinition))
-		}
-		stack.destroyers.PushBack(b)
+	// 记录具有销毁函数的 bean ，它们的销毁顺序由依赖关系决定。
+	if _, ok := b.Interface().(BeanDestroy); (ok || b.destroy != nil) && b.scope == ScopeSingleton {
+		stack.saveDestroyer(b)
 	}
 
 	stack.pushBack(b)
 
+	// 构造函数 bean 在构造完成之前又被依赖，说明存在循环依赖。如果依赖它的 bean
+	// 已经创建完成，那么是通过属性注入形成的循环依赖，此时注入的是尚未构造的值。
 	if b.status == Creating && b.f != nil {
 		prev := stack.beans[len(stack.beans)-2]
 		if prev.status == Creating {
-			return errors.New("found circle autowire")
+			const hint = "inject one of them by field instead of constructor arg, or get it later via gs.Context"
+			return fmt.Errorf("found circle autowire: %s, %s", stack.circle(), hint)
 		}
+		log.Warnf("found circle autowire: %s, the injected value isn't constructed yet", stack.circle())
 	}
 
 	if b.status >= Creating {
@@ -583,6 +730,7 @@ func (c *container) wireBean(b *BeanDefinition, stack *wiringStack) error {
 			return err
 		}
 		for _, d := range beans {
+			stack.via = "depends on"
 			err = c.wireBean(d, stack)
 			if err != nil {
 				return err
@@ -604,11 +752,26 @@ func (c *container) wireBean(b *BeanDefinition, stack *wiringStack) error {
 		}
 	}
 
-	err = c.wireBeanValue(v, t, stack)
+	err = c.wireBeanValue(v, t, b.prefix, stack)
 	if err != nil {
 		return err
 	}
 
+	if b.scope == ScopeSingleton {
+		if b.refresh {
+			c.refreshes = append(c.refreshes, refreshBean{v: v, prefix: b.prefix})
+		}
+		dynamics, err := collectDynamics(v, b.prefix)
+		if err != nil {
+			return err
+		}
+		c.dynamics = append(c.dynamics, dynamics...)
+	}
+
+	if err = c.beforeInit(b); err != nil {
+		return err
+	}
+
 	if b.init != nil {
 		fnValue := reflect.ValueOf(b.init)
 		out := fnValue.Call([]reflect.Value{b.Value()})
@@ -623,8 +786,22 @@ func (c *container) wireBean(b *BeanDefinition, stack *wiringStack) error {
 		}
 	}
 
+	if err = c.afterInit(b); err != nil {
+		return err
+	}
+
 	b.status = Wired
-	stack.popBack()
+	c.deps.addNode(b)
+	total, self := stack.popBack()
+	if b.scope == ScopeSingleton {
+		c.timings = append(c.timings, BeanTiming{
+			Name:   b.BeanName(),
+			Type:   b.TypeName(),
+			Source: b.FileLine(),
+			Total:  total,
+			Self:   self,
+		})
+	}
 	return nil
 }
 
@@ -642,6 +819,7 @@ func (a *argContext) Bind(v reflect.Value, tag string) error {
 }
 
 func (a *argContext) Wire(v reflect.Value, tag string) error {
+	a.stack.via = "constructor arg"
 	return a.c.wireByTag(v, tag, a.stack)
 }
 
@@ -683,8 +861,9 @@ func (c *container) getBeanValue(b *BeanDefinition, stack *wiringStack) (reflect
 	return v, nil
 }
 
-// wireBeanValue 对 v 进行属性绑定和依赖注入，v 在传入时应该是一个已经初始化的值。
-func (c *container) wireBeanValue(v reflect.Value, t reflect.Type, stack *wiringStack) error {
+// wireBeanValue 对 v 进行属性绑定和依赖注入，v 在传入时应该是一个已经初始化的值，
+// prefix 是属性绑定的前缀。
+func (c *container) wireBeanValue(v reflect.Value, t reflect.Type, prefix string, stack *wiringStack) error {
 
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -701,8 +880,33 @@ func (c *container) wireBeanValue(v reflect.Value, t reflect.Type, stack *wiring
 		typeName = t.String()
 	}
 
-	param := conf.BindParam{Type: t, Path: typeName}
-	return c.wireStruct(v, param, stack)
+	param := conf.BindParam{Type: t, Key: prefix, Path: typeName}
+	if err := c.wireStruct(v, param, stack); err != nil {
+		return err
+	}
+
+	// 容器刷新时收集所有 bean 的属性校验错误，然后一次性报告。
+	if err := conf.Validate(v, param); err != nil {
+		if c.state == Refreshed {
+			return err
+		}
+		stack.invalids.Append(err)
+	}
+	return nil
+}
+
+// invalidProperties 汇总所有 bean 的属性校验错误，并且补充属性的来源。
+func (c *container) invalidProperties(stack *wiringStack) error {
+	if stack.invalids.Len() == 0 {
+		return nil
+	}
+	for _, err := range stack.invalids.Errors() {
+		var e *conf.ValidateError
+		if errors.As(err, &e) && e.Key != "" {
+			e.Origin = c.origins[e.Key]
+		}
+	}
+	return fmt.Errorf("invalid properties: %w", &stack.invalids)
 }
 
 // wireStruct 对结构体进行依赖注入，需要注意的是这里不需要进行属性绑定。
@@ -727,6 +931,7 @@ func (c *container) wireStruct(v reflect.Value, opt conf.BindParam, stack *wirin
 			tag, ok = ft.Tag.Lookup("inject")
 		}
 		if ok {
+			stack.via = "field " + ft.Name
 			if err := c.wireByTag(fv, tag, stack); err != nil {
 				return fmt.Errorf("%q wired error: %w", fieldPath, err)
 			}
@@ -851,54 +1056,71 @@ func (c *container) getBean(v reflect.Value, tag wireTag, stack *wiringStack) er
 	}
 
 	if len(foundBeans) == 0 {
+		if c.parent != nil {
+			return c.parent.parentBean(v, tag)
+		}
 		if tag.nullable {
 			return nil
 		}
 		return fmt.Errorf("can't find bean, bean:%q type:%q", tag, t)
 	}
 
-	// 优先使用设置成主版本的 bean
-	var primaryBeans []*BeanDefinition
+	result, err := selectPrimary("beans", foundBeans, tag, t)
+	if err != nil {
+		return err
+	}
 
-	for _, b := range foundBeans {
-		if b.primary {
-			primaryBeans = append(primaryBeans, b)
-		}
+	// 确保找到的 bean 已经完成依赖注入。
+	val, err := c.scopedValue(result, stack)
+	if err != nil {
+		return err
 	}
 
-	if len(primaryBeans) > 1 {
-		msg := fmt.Sprintf("found %d primary beans, bean:%q type:%q [", len(primaryBeans), tag, t)
-		for _, b := range primaryBeans {
-			msg += "( " + b.String() + " ), "
-		}
-		msg = msg[:len(msg)-2] + "]"
-		return errors.New(msg)
+	v.Set(val)
+	return nil
+}
+
+// selectPrimary 从多个候选 bean 中选出唯一的 bean ，只有一个候选 bean 时直接返
+// 回它，否则返回唯一的主版本 bean ，没有或者有多个主版本 bean 时返回列出了所有候
+// 选 bean 的错误。
+func selectPrimary(kind string, beans []*BeanDefinition, selector interface{}, t reflect.Type) (*BeanDefinition, error) {
+
+	if len(beans) == 1 {
+		return beans[0], nil
 	}
 
-	if len(primaryBeans) == 0 && len(foundBeans) > 1 {
-		msg := fmt.Sprintf("found %d beans, bean:%q type:%q [", len(foundBeans), tag, t)
-		for _, b := range foundBeans {
-			msg += "( " + b.String() + " ), "
+	var primaryBeans []*BeanDefinition
+	for _, b := range beans {
+		if b.primary {
+			primaryBeans = append(primaryBeans, b)
 		}
-		msg = msg[:len(msg)-2] + "]"
-		return errors.New(msg)
 	}
 
-	var result *BeanDefinition
-	if len(primaryBeans) == 1 {
-		result = primaryBeans[0]
-	} else {
-		result = foundBeans[0]
+	switch len(primaryBeans) {
+	case 0:
+		const hint = "mark one of them as Primary() or select it by name"
+		return nil, candidatesError(kind, beans, selector, t, hint)
+	case 1:
+		return primaryBeans[0], nil
+	default:
+		const hint = "only one of them can be Primary()"
+		return nil, candidatesError("primary "+kind, primaryBeans, selector, t, hint)
 	}
+}
 
-	// 确保找到的 bean 已经完成依赖注入。
-	err := c.wireBean(result, stack)
-	if err != nil {
-		return err
+// candidatesError 返回找到多个候选 bean 的错误，错误信息中列出所有的候选 bean ，
+// t 为 nil 时不输出类型信息。
+func candidatesError(kind string, beans []*BeanDefinition, selector interface{}, t reflect.Type, hint string) error {
+	msg := fmt.Sprintf("found %d %s, bean:%q ", len(beans), kind, selector)
+	if t != nil {
+		msg += fmt.Sprintf("type:%q ", t)
 	}
-
-	v.Set(result.Value())
-	return nil
+	msg += "["
+	for _, b := range beans {
+		msg += "( " + b.String() + " ), "
+	}
+	msg = msg[:len(msg)-2] + "], " + hint
+	return errors.New(msg)
 }
 
 // filterBean 返回 tag 对应的 bean 在数组中的索引，找不到返回 -1。
@@ -916,12 +1138,12 @@ func filterBean(beans []*BeanDefinition, tag wireTag, t reflect.Type) (int, erro
 	}
 
 	if len(found) > 1 {
-		msg := fmt.Sprintf("found %d beans, bean:%q type:%q [", len(found), tag, t)
+		var candidates []*BeanDefinition
 		for _, i := range found {
-			msg += "( " + beans[i].String() + " ), "
+			candidates = append(candidates, beans[i])
 		}
-		msg = msg[:len(msg)-2] + "]"
-		return -1, errors.New(msg)
+		const hint = "select it by type and name, e.g. \"typeName:beanName\""
+		return -1, candidatesError("beans", candidates, tag, t, hint)
 	}
 
 	if len(found) > 0 {
@@ -1004,6 +1226,10 @@ func (c *container) collectBeans(v reflect.Value, tags []wireTag, stack *wiringS
 		beans = arr
 	}
 
+	if len(beans) == 0 && c.parent != nil {
+		return c.parent.parentBeans(v, tags)
+	}
+
 	if len(beans) == 0 {
 		if len(tags) == 0 {
 			return fmt.Errorf("no beans collected for %q", toWireString(tags))
@@ -1016,24 +1242,27 @@ func (c *container) collectBeans(v reflect.Value, tags []wireTag, stack *wiringS
 		return nil
 	}
 
+	values := make(map[*BeanDefinition]reflect.Value, len(beans))
 	for _, b := range beans {
-		if err := c.wireBean(b, stack); err != nil {
+		val, err := c.scopedValue(b, stack)
+		if err != nil {
 			return err
 		}
+		values[b] = val
 	}
 
 	var ret reflect.Value
 	switch t.Kind() {
 	case reflect.Slice:
-		sort.Sort(byOrder(beans))
+		sort.Stable(byOrder(beans))
 		ret = reflect.MakeSlice(t, 0, 0)
 		for _, b := range beans {
-			ret = reflect.Append(ret, b.Value())
+			ret = reflect.Append(ret, values[b])
 		}
 	case reflect.Map:
 		ret = reflect.MakeMap(t)
 		for _, b := range beans {
-			ret.SetMapIndex(reflect.ValueOf(b.name), b.Value())
+			ret.SetMapIndex(reflect.ValueOf(b.name), values[b])
 		}
 	}
 	v.Set(ret)
@@ -1043,16 +1272,38 @@ func (c *container) collectBeans(v reflect.Value, tags []wireTag, stack *wiringS
 // Close 关闭容器，此方法必须在 Refresh 之后调用。该方法会触发 ctx 的 Done 信
 // 号，然后等待所有 goroutine 结束，最后按照被依赖先销毁的原则执行所有的销毁函数。
 func (c *container) Close() {
+	_ = c.stopGoroutines(context.Background())
+	c.destroy()
+}
+
+// stopGoroutines 触发 ctx 的 Done 信号，然后等待所有 goroutine 结束，最多等待
+// 到 ctx 超时，超时时返回 ctx.Err() 。
+func (c *container) stopGoroutines(ctx context.Context) error {
 
 	c.cancel()
-	c.wg.Wait()
 
-	log.Info("goroutines exited")
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
 
+	select {
+	case <-done:
+		log.Info("goroutines exited")
+		return nil
+	case <-ctx.Done():
+		log.Warnf("wait goroutines exit error: %v", ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// destroy 按照依赖关系的逆序执行所有的销毁函数。
+func (c *container) destroy() {
+	c.closeChildren()
 	for _, f := range c.destroyers {
 		f()
 	}
-
 	log.Info("container closed")
 }
 
@@ -1064,7 +1315,11 @@ func (c *container) Go(fn func(ctx context.Context)) {
 		defer c.wg.Done()
 		defer func() {
 			if r := recover(); r != nil {
-				log.Panic(r)
+				if c.panicHandler != nil {
+					c.panicHandler(r, debug.Stack())
+				} else {
+					log.Panic(r)
+				}
 			}
 		}()
 		fn(c.ctx)
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BeanPostProcessor 在 bean 初始化前后对其进行处理，需要通过 Export 方法导出该接
+// 口。BeforeInit 在属性绑定和依赖注入完成之后、初始化函数执行之前调用；AfterInit
+// 在初始化函数执行之后调用，返回非 nil 的值时替换原来的 bean ，例如返回一个代理对
+// 象，替换的值必须可以赋值给 bean 的类型并且只能替换构造函数 bean 。
+// 注意 BeanPostProcessor 及其依赖的 bean 不会被 BeanPostProcessor 处理。
+type BeanPostProcessor interface {
+	BeforeInit(b *BeanDefinition) error
+	AfterInit(b *BeanDefinition) (interface{}, error)
+}
+
+// MethodInterceptor 方法拦截器，通过 inv.Proceed 调用下一个拦截器或者目标方法。
+type MethodInterceptor func(inv *Invocation) []interface{}
+
+// Invocation 一次被拦截的方法调用。
+type Invocation struct {
+	Bean   *BeanDefinition
+	Method string
+	Args   []interface{}
+
+	target reflect.Value
+	chain  []MethodInterceptor
+	index  int
+}
+
+// Target 返回被代理的 bean 对象。
+func (inv *Invocation) Target() interface{} {
+	return inv.target.Interface()
+}
+
+// Proceed 调用下一个拦截器，所有拦截器都调用过之后调用目标方法。
+func (inv *Invocation) Proceed() []interface{} {
+
+	if inv.index < len(inv.chain) {
+		f := inv.chain[inv.index]
+		inv.index++
+		return f(inv)
+	}
+
+	m := inv.target.MethodByName(inv.Method)
+	if !m.IsValid() {
+		panic(fmt.Errorf("method %s not found in %s", inv.Method, inv.target.Type()))
+	}
+
+	t := m.Type()
+	in := make([]reflect.Value, len(inv.Args))
+	for i, arg := range inv.Args {
+		if arg != nil {
+			in[i] = reflect.ValueOf(arg)
+			continue
+		}
+		if t.IsVariadic() && i >= t.NumIn()-1 {
+			in[i] = reflect.Zero(t.In(t.NumIn() - 1).Elem())
+		} else {
+			in[i] = reflect.Zero(t.In(i))
+		}
+	}
+
+	out := m.Call(in)
+	ret := make([]interface{}, len(out))
+	for i, v := range out {
+		ret[i] = v.Interface()
+	}
+	return ret
+}
+
+// Proxy 接口类型 bean 的方法拦截代理。由于 golang 无法在运行时生成类型，代理类型
+// 需要自己实现接口，并在每个方法中通过 Invoke 转发调用，例如：
+//
+//	type greeterProxy struct{ p *gs.Proxy }
+//
+//	func (g *greeterProxy) Greet(name string) string {
+//		return g.p.Invoke("Greet", name)[0].(string)
+//	}
+type Proxy struct {
+	bean         *BeanDefinition
+	target       reflect.Value
+	interceptors []MethodInterceptor
+}
+
+// NewProxy 返回 bean 的方法拦截代理，拦截器按照传入的顺序执行。
+func NewProxy(b *BeanDefinition, interceptors ...MethodInterceptor) *Proxy {
+	return &Proxy{
+		bean:         b,
+		target:       reflect.ValueOf(b.Interface()),
+		interceptors: interceptors,
+	}
+}
+
+// Target 返回被代理的 bean 对象。
+func (p *Proxy) Target() interface{} {
+	return p.target.Interface()
+}
+
+// Invoke 经过所有拦截器之后调用目标对象的方法，返回目标方法的所有返回值。
+func (p *Proxy) Invoke(method string, args ...interface{}) []interface{} {
+	inv := &Invocation{
+		Bean:   p.bean,
+		Method: method,
+		Args:   args,
+		target: p.target,
+		chain:  p.interceptors,
+	}
+	return inv.Proceed()
+}
+
+// collectPostProcessors 注入并收集所有的 BeanPostProcessor 对象。
+func (c *container) collectPostProcessors(stack *wiringStack) error {
+	var processors []BeanPostProcessor
+	v := reflect.ValueOf(&processors).Elem()
+	if err := c.autowire(v, []wireTag{parseWireTag("*?")}, stack); err != nil {
+		return err
+	}
+	c.processors = processors
+	return nil
+}
+
+// beforeInit 在 bean 的初始化函数执行之前调用所有的 BeanPostProcessor 对象。
+func (c *container) beforeInit(b *BeanDefinition) error {
+	for _, p := range c.processors {
+		if err := p.BeforeInit(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterInit 在 bean 的初始化函数执行之后应用方法拦截器，通过属性配置的重试和
+// 熔断拦截器在其他拦截器之前执行，然后调用所有的 BeanPostProcessor 对象，它们
+// 都可以替换原来的 bean 。
+func (c *container) afterInit(b *BeanDefinition) error {
+
+	if b.proxy != nil {
+		interceptors, err := c.configInterceptors(b)
+		if err != nil {
+			return err
+		}
+		p := NewProxy(b, append(interceptors, b.interceptors...)...)
+		if err := c.replaceBean(b, b.proxy(p)); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range c.processors {
+		i, err := p.AfterInit(b)
+		if err != nil {
+			return err
+		}
+		if i == nil {
+			continue
+		}
+		if err = c.replaceBean(b, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceBean 使用 i 替换 bean 的值，原来的值仍然用于执行销毁函数。
+func (c *container) replaceBean(b *BeanDefinition, i interface{}) error {
+	v := reflect.ValueOf(i)
+	if !v.IsValid() || !v.Type().AssignableTo(b.Type()) {
+		return fmt.Errorf("%s can't be replaced by %T", b, i)
+	}
+	if b.f == nil || !b.Value().CanSet() {
+		return fmt.Errorf("%s can't be replaced, only constructor bean can", b)
+	}
+	if !b.target.IsValid() {
+		b.target = reflect.ValueOf(b.Interface())
+	}
+	b.Value().Set(v)
+	return nil
+}
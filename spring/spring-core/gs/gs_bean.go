@@ -96,15 +96,25 @@ type BeanDefinition struct {
 	line int    // 注册点所在行数
 
 	name    string         // 名称
+	aliases []string       // 别名
 	status  beanStatus     // 状态
 	primary bool           // 是否为主版本
 	method  bool           // 是否为成员方法
 	cond    cond.Condition // 判断条件
 	order   int            // 收集时的顺序
+	scope   Scope          // 作用域
+	lazy    bool           // 是否延迟初始化
+	refresh bool           // 属性变化时是否重新绑定
+	prefix  string         // 属性绑定的前缀
+	replace bool           // 是否替换相同类型的其他 bean
 	init    interface{}    // 初始化函数
 	destroy interface{}    // 销毁函数
 	depends []BeanSelector // 间接依赖项
 	exports []reflect.Type // 导出的接口
+
+	proxy        func(p *Proxy) interface{} // 创建方法拦截代理
+	interceptors []MethodInterceptor        // 方法拦截器
+	target       reflect.Value              // 被替换之前的值
 }
 
 // Type 返回 bean 的类型。
@@ -164,7 +174,12 @@ func (d *BeanDefinition) String() string {
 	return fmt.Sprintf("%s name:%q %s", d.getClass(), d.name, d.FileLine())
 }
 
-// Match 测试 bean 的类型全限定名和 bean 的名称是否都匹配。
+// Aliases 返回 bean 的别名。
+func (d *BeanDefinition) Aliases() []string {
+	return d.aliases
+}
+
+// Match 测试 bean 的类型全限定名和 bean 的名称是否都匹配，bean 的名称可以是别名。
 func (d *BeanDefinition) Match(typeName string, beanName string) bool {
 
 	typeIsSame := false
@@ -175,6 +190,13 @@ func (d *BeanDefinition) Match(typeName string, beanName string) bool {
 	nameIsSame := false
 	if beanName == "" || d.name == beanName {
 		nameIsSame = true
+	} else {
+		for _, alias := range d.aliases {
+			if alias == beanName {
+				nameIsSame = true
+				break
+			}
+		}
 	}
 
 	return typeIsSame && nameIsSame
@@ -186,6 +208,13 @@ func (d *BeanDefinition) Name(name string) *BeanDefinition {
 	return d
 }
 
+// Alias 为 bean 添加别名，通过别名可以像通过名称一样选择 bean ，这样 bean 改名
+// 之后使用旧名称的注入标签仍然有效，starter 也可以同时提供通用的和具体的名称。
+func (d *BeanDefinition) Alias(aliases ...string) *BeanDefinition {
+	d.aliases = append(d.aliases, aliases...)
+	return d
+}
+
 // On 设置 bean 的 Condition。
 func (d *BeanDefinition) On(cond cond.Condition) *BeanDefinition {
 	d.cond = cond
@@ -198,6 +227,51 @@ func (d *BeanDefinition) Order(order int) *BeanDefinition {
 	return d
 }
 
+// Scope 设置 bean 的作用域，默认为 ScopeSingleton ，只有构造函数 bean 可以设置
+// 为其他作用域。
+func (d *BeanDefinition) Scope(scope Scope) *BeanDefinition {
+	d.scope = scope
+	return d
+}
+
+// Lazy 设置 bean 延迟初始化，即在第一次被注入或者获取时才创建。
+func (d *BeanDefinition) Lazy() *BeanDefinition {
+	d.lazy = true
+	return d
+}
+
+// Refreshable 设置 bean 在属性值发生变化时重新绑定 value 标签对应的字段，所有字段
+// 绑定成功之后才会替换旧值。字段是原地替换的，bean 需要自己保证并发读取的安全。
+func (d *BeanDefinition) Refreshable() *BeanDefinition {
+	d.refresh = true
+	return d
+}
+
+// Prefix 设置 bean 属性绑定的前缀，value 标签中的属性名都是相对于前缀的，例如
+// 前缀为 server.http 时 ${port:=8080} 绑定的是 server.http.port 属性。和
+// Refreshable 一起使用时，前缀下的属性发生变化之后重新绑定整个配置对象，然后发布
+// PropertiesReboundEvent 事件。
+func (d *BeanDefinition) Prefix(prefix string) *BeanDefinition {
+	d.prefix = prefix
+	return d
+}
+
+// Replace 设置 bean 替换容器中类型相同或者导出了相同接口的其他 bean ，被替换的
+// bean 只有一个时使用它的名称，因此按照名称注入的地方也会注入当前 bean ，通常用于
+// 在测试中使用 mock 对象替换真实的 bean 。
+func (d *BeanDefinition) Replace() *BeanDefinition {
+	d.replace = true
+	return d
+}
+
+// Intercept 为 bean 设置方法拦截器，bean 初始化之后使用 proxy 返回的代理对象替换
+// 原来的 bean ，因此只能用于返回接口类型的构造函数 bean 。
+func (d *BeanDefinition) Intercept(proxy func(p *Proxy) interface{}, interceptors ...MethodInterceptor) *BeanDefinition {
+	d.proxy = proxy
+	d.interceptors = append(d.interceptors, interceptors...)
+	return d
+}
+
 // DependsOn 设置 bean 的间接依赖项。
 func (d *BeanDefinition) DependsOn(selectors ...BeanSelector) *BeanDefinition {
 	d.depends = append(d.depends, selectors...)
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// NewChild 创建一个子容器，子容器继承父容器的属性和 bean ：子容器中设置的属性覆盖
+// 父容器中的同名属性，子容器中找不到的 bean 到父容器中查找，因此子容器可以定义自己
+// 的 bean 覆盖父容器中的 bean 。父容器在有子容器之后不再清理注册的 bean ，关闭时
+// 先关闭所有的子容器。子容器需要在父容器清理之前创建，例如在 AppRunner 或者 bean
+// 的初始化函数中创建。parent 可以是父容器的 Container 或者 Context 对象。
+func NewChild(parent interface{}) Container {
+	p, ok := parent.(*container)
+	if !ok {
+		panic(errors.New("parent should be created by gs.New or gs.NewApp"))
+	}
+	if p.tempContainer == nil {
+		panic(errors.New("parent container has been cleared"))
+	}
+	c := New().(*container)
+	c.ctx, c.cancel = context.WithCancel(p.ctx)
+	c.parent = p
+	p.childMu.Lock()
+	p.children = append(p.children, c)
+	p.childMu.Unlock()
+	return c
+}
+
+// hasChildren 返回容器是否有子容器。
+func (c *container) hasChildren() bool {
+	c.childMu.Lock()
+	defer c.childMu.Unlock()
+	return len(c.children) > 0
+}
+
+// closeChildren 按照创建顺序的逆序关闭所有的子容器。
+func (c *container) closeChildren() {
+	c.childMu.Lock()
+	children := c.children
+	c.children = nil
+	c.childMu.Unlock()
+	for i := len(children) - 1; i >= 0; i-- {
+		children[i].Close()
+	}
+}
+
+// inheritProperties 使用父容器的属性作为子容器属性的默认值。
+func (c *container) inheritProperties() {
+	if c.parent == nil {
+		return
+	}
+	p := c.parent.p.Copy()
+	for _, key := range c.p.Keys() {
+		_ = p.Set(key, c.p.Get(key))
+	}
+	c.p = p
+}
+
+// parentBean 在父容器中获取 tag 对应的 bean 。
+func (c *container) parentBean(v reflect.Value, tag wireTag) error {
+	stack := newWiringStack()
+	return c.runtimeWire(stack, func() error {
+		return c.getBean(v, tag, stack)
+	})
+}
+
+// parentBeans 在父容器中收集 tags 对应的 bean 。
+func (c *container) parentBeans(v reflect.Value, tags []wireTag) error {
+	stack := newWiringStack()
+	return c.runtimeWire(stack, func() error {
+		return c.collectBeans(v, tags, stack)
+	})
+}
@@ -42,6 +42,7 @@ type Context interface {
 	Prop(key string, opts ...conf.GetOption) string
 	Bind(i interface{}, opts ...conf.BindOption) error
 	Get(i interface{}, selectors ...BeanSelector) error
+	GetWithCtx(ctx context.Context, i interface{}, selectors ...BeanSelector) error
 	Wire(objOrCtor interface{}, ctorArgs ...arg.Arg) (interface{}, error)
 	Invoke(fn interface{}, args ...arg.Arg) ([]interface{}, error)
 	Go(fn func(ctx context.Context))
@@ -60,12 +61,16 @@ func (c *container) Bind(i interface{}, opts ...conf.BindOption) error {
 }
 
 // Find 查找符合条件的 bean 对象，注意该函数只能保证返回的 bean 是有效的，即未被
-// 标记为删除的，而不能保证已经完成属性绑定和依赖注入。
+// 标记为删除的，而不能保证已经完成属性绑定和依赖注入。子容器中找不到时到父容器
+// 中查找。
 func (c *container) Find(selector BeanSelector) ([]cond.BeanDefinition, error) {
 	beans, err := c.findBean(selector)
 	if err != nil {
 		return nil, err
 	}
+	if len(beans) == 0 && c.parent != nil {
+		return c.parent.Find(selector)
+	}
 	var ret []cond.BeanDefinition
 	for _, b := range beans {
 		ret = append(ret, b)
@@ -105,7 +110,9 @@ func (c *container) Get(i interface{}, selectors ...BeanSelector) error {
 	for _, s := range selectors {
 		tags = append(tags, toWireTag(s))
 	}
-	return c.autowire(v.Elem(), tags, stack)
+	return c.runtimeWire(stack, func() error {
+		return c.autowire(v.Elem(), tags, stack)
+	})
 }
 
 // Wire 如果传入的是 bean 对象，则对 bean 对象进行属性绑定和依赖注入，如果传入的
@@ -122,7 +129,14 @@ func (c *container) Wire(objOrCtor interface{}, ctorArgs ...arg.Arg) (interface{
 	}()
 
 	b := NewBean(objOrCtor, ctorArgs...)
-	err := c.wireBean(b, stack)
+	err := c.runtimeWire(stack, func() error {
+		if err := c.wireBean(b, stack); err != nil {
+			return err
+		}
+		delete(stack.destroyerMap, b.ID()) // Wire 的对象不由容器销毁
+		c.deps.remove(b)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +162,11 @@ func (c *container) Invoke(fn interface{}, args ...arg.Arg) ([]interface{}, erro
 		return nil, err
 	}
 
-	ret, err := r.Call(&argContext{c: c, stack: stack})
+	var ret []reflect.Value
+	err = c.runtimeWire(stack, func() error {
+		ret, err = r.Call(&argContext{c: c, stack: stack})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
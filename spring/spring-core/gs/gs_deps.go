@@ -0,0 +1,172 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// dependencyGraph 记录注入过程中形成的单例 bean 之间的依赖关系。
+type dependencyGraph struct {
+	nodes []*BeanDefinition
+	edges map[*BeanDefinition][]*BeanDefinition
+}
+
+func newDependencyGraph() *dependencyGraph {
+	return &dependencyGraph{edges: make(map[*BeanDefinition][]*BeanDefinition)}
+}
+
+// addNode 记录已经注入完成的单例 bean 。
+func (g *dependencyGraph) addNode(b *BeanDefinition) {
+	if b.scope == ScopeSingleton {
+		g.nodes = append(g.nodes, b)
+	}
+}
+
+// add 记录 from 依赖 to ，非单例 bean 每次都会创建新的实例，不记录它们的依赖关系。
+func (g *dependencyGraph) add(from, to *BeanDefinition) {
+	if from == to || from.scope != ScopeSingleton || to.scope != ScopeSingleton {
+		return
+	}
+	for _, b := range g.edges[from] {
+		if b == to {
+			return
+		}
+	}
+	g.edges[from] = append(g.edges[from], to)
+}
+
+// remove 删除 b 依赖其他 bean 的记录。
+func (g *dependencyGraph) remove(b *BeanDefinition) {
+	delete(g.edges, b)
+}
+
+// reachable 返回 b 直接依赖的或者经由不在 targets 中的 bean 间接依赖的、在 targets
+// 中的 bean 。
+func (g *dependencyGraph) reachable(b *BeanDefinition, targets map[string]*BeanDefinition) []*BeanDefinition {
+	var ret []*BeanDefinition
+	visited := map[*BeanDefinition]bool{b: true}
+	var visit func(from *BeanDefinition)
+	visit = func(from *BeanDefinition) {
+		for _, to := range g.edges[from] {
+			if visited[to] {
+				continue
+			}
+			visited[to] = true
+			if targets[to.ID()] == to {
+				ret = append(ret, to)
+				continue
+			}
+			visit(to)
+		}
+	}
+	visit(b)
+	return ret
+}
+
+// DependencyNode 依赖关系图中的 bean 。
+type DependencyNode struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+// DependencyEdge 依赖关系图中的边，表示 From 依赖 To 。
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DependencyGraph 单例 bean 之间的依赖关系图，节点和边都按照 ID 排序。
+type DependencyGraph struct {
+	Nodes []DependencyNode `json:"nodes"`
+	Edges []DependencyEdge `json:"edges"`
+}
+
+// export 导出依赖关系图，包括所有注入完成的单例 bean 以及它们之间的依赖关系。
+func (g *dependencyGraph) export() *DependencyGraph {
+
+	ret := &DependencyGraph{Nodes: []DependencyNode{}, Edges: []DependencyEdge{}}
+	nodes := make(map[*BeanDefinition]bool)
+	addNode := func(b *BeanDefinition) {
+		if nodes[b] {
+			return
+		}
+		nodes[b] = true
+		ret.Nodes = append(ret.Nodes, DependencyNode{
+			ID:     b.ID(),
+			Name:   b.BeanName(),
+			Type:   b.TypeName(),
+			Source: b.FileLine(),
+		})
+	}
+
+	for _, b := range g.nodes {
+		addNode(b)
+	}
+	for from, deps := range g.edges {
+		addNode(from)
+		for _, to := range deps {
+			addNode(to)
+			ret.Edges = append(ret.Edges, DependencyEdge{From: from.ID(), To: to.ID()})
+		}
+	}
+
+	sort.Slice(ret.Nodes, func(i, j int) bool {
+		return ret.Nodes[i].ID < ret.Nodes[j].ID
+	})
+	sort.Slice(ret.Edges, func(i, j int) bool {
+		if ret.Edges[i].From == ret.Edges[j].From {
+			return ret.Edges[i].To < ret.Edges[j].To
+		}
+		return ret.Edges[i].From < ret.Edges[j].From
+	})
+	return ret
+}
+
+// JSON 将依赖关系图以 JSON 格式写入 w 。
+func (g *DependencyGraph) JSON(w io.Writer) error {
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// DOT 将依赖关系图以 Graphviz 的 DOT 格式写入 w ，节点的标签是 bean 的名称和类型。
+func (g *DependencyGraph) DOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph beans {"); err != nil {
+		return err
+	}
+	for _, n := range g.Nodes {
+		if _, err := fmt.Fprintf(w, "\t%q [label=%q];\n", n.ID, n.Name+"\n"+n.Type); err != nil {
+			return err
+		}
+	}
+	for _, e := range g.Edges {
+		if _, err := fmt.Fprintf(w, "\t%q -> %q;\n", e.From, e.To); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
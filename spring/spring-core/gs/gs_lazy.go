@@ -0,0 +1,59 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+// LazyInitialization 为 true 时所有的构造函数 bean 都延迟初始化，对象 bean 已经
+// 创建好了，仍然会在容器刷新时完成注入。
+const LazyInitialization = "spring.main.lazy-initialization"
+
+// isLazy 返回 bean 是否延迟初始化。
+func (c *container) isLazy(b *BeanDefinition, lazyAll bool) bool {
+	return b.lazy || (lazyAll && b.f != nil)
+}
+
+// needRuntimeWiring 返回容器刷新之后是否还需要创建 bean ，例如存在非单例的 bean
+// 或者还没有创建的延迟初始化 bean ，此时刷新之后不能清理注册的 bean 。
+func (c *container) needRuntimeWiring() bool {
+	for _, b := range c.beans {
+		if b.status == Deleted {
+			continue
+		}
+		if b.scope != ScopeSingleton || !b.Wired() {
+			return true
+		}
+	}
+	return false
+}
+
+// runtimeWire 执行注入，容器刷新之后的注入是串行的，并且注入过程中新创建的 bean
+// 的销毁函数会先于已有的销毁函数执行。
+func (c *container) runtimeWire(stack *wiringStack, fn func() error) error {
+	if c.state != Refreshed {
+		return fn()
+	}
+	c.runtimeMu.Lock()
+	defer c.runtimeMu.Unlock()
+	if err := fn(); err != nil {
+		return err
+	}
+	destroyers, err := stack.sortDestroyers(c.deps)
+	if err != nil {
+		return err
+	}
+	c.destroyers = append(destroyers, c.destroyers...)
+	return nil
+}
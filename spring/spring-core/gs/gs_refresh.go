@@ -0,0 +1,237 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/util"
+)
+
+// refreshBean 属性变化时需要重新绑定的 bean 以及属性绑定的前缀。
+type refreshBean struct {
+	v      reflect.Value
+	prefix string
+}
+
+// matchPrefix 返回 keys 中是否存在前缀下的属性。
+func (b refreshBean) matchPrefix(keys []string) bool {
+	for _, key := range keys {
+		if key == b.prefix ||
+			strings.HasPrefix(key, b.prefix+".") ||
+			strings.HasPrefix(key, b.prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshProperties 使用新的属性列表重新绑定 Refreshable 的 bean 以及所有单例
+// bean 中的动态属性，如果容器还需要在运行时注入 bean ，那么后续的注入也使用新的属
+// 性列表。keys 是发生变化的属性，返回设置了前缀、前缀下的属性发生变化并且重新绑定
+// 成功的 bean 。
+func (c *container) refreshProperties(p *conf.Properties, keys []string) ([]refreshBean, error) {
+
+	c.runtimeMu.Lock()
+	defer c.runtimeMu.Unlock()
+
+	if c.tempContainer != nil {
+		c.p = p
+	}
+
+	var (
+		errs    util.Errors
+		rebound []refreshBean
+	)
+	for _, b := range c.refreshes {
+		if err := rebindValues(p, b.v, b.prefix); err != nil {
+			errs.Append(err)
+			continue
+		}
+		if b.prefix != "" && b.matchPrefix(keys) {
+			rebound = append(rebound, b)
+		}
+	}
+	for _, d := range c.dynamics {
+		if err := d.r.Refresh(p, d.param); err != nil {
+			errs.Append(fmt.Errorf("refresh %s error: %w", d.param.Path, err))
+		}
+	}
+	return rebound, errs.ErrorOrNil()
+}
+
+// dynamicValue 单例 bean 中的动态属性以及绑定时使用的参数。
+type dynamicValue struct {
+	r     conf.Refreshable
+	param conf.BindParam
+}
+
+// collectDynamics 收集 bean 中 value 标签对应的动态属性，包括嵌套结构体中的动态
+// 属性，属性变化时由容器就地刷新，prefix 是属性绑定的前缀。
+func collectDynamics(v reflect.Value, prefix string) ([]dynamicValue, error) {
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	t := v.Type()
+	typeName := t.Name()
+	if typeName == "" { // 简单类型没有名字
+		typeName = t.String()
+	}
+
+	var ret []dynamicValue
+	param := conf.BindParam{Type: t, Key: prefix, Path: typeName}
+	if err := collectDynamic(v, param, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func collectDynamic(v reflect.Value, opt conf.BindParam, ret *[]dynamicValue) error {
+
+	for i := 0; i < opt.Type.NumField(); i++ {
+		ft := opt.Type.Field(i)
+		fv := v.Field(i)
+
+		if !fv.CanInterface() {
+			fv = util.PatchValue(fv)
+			if !fv.CanInterface() {
+				continue
+			}
+		}
+
+		subParam := conf.BindParam{
+			Type: ft.Type,
+			Key:  opt.Key,
+			Path: opt.Path + "." + ft.Name,
+		}
+
+		tag, ok := ft.Tag.Lookup("value")
+		if !ok {
+			if ft.Anonymous && ft.Type.Kind() == reflect.Struct {
+				if err := collectDynamic(fv, subParam, ret); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := subParam.BindTag(tag); err != nil {
+			return err
+		}
+		if r, ok := conf.ToRefreshable(fv); ok {
+			*ret = append(*ret, dynamicValue{r: r, param: subParam})
+			continue
+		}
+		if ft.Type.Kind() == reflect.Struct {
+			if err := collectDynamic(fv, subParam, ret); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type rebindField struct {
+	field reflect.Value
+	value reflect.Value
+}
+
+// rebindValues 重新绑定结构体中 value 标签对应的字段，全部绑定成功之后才替换字段
+// 的值，避免出现部分字段更新的情况，prefix 是属性绑定的前缀。
+func rebindValues(p *conf.Properties, v reflect.Value, prefix string) error {
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	typeName := t.Name()
+	if typeName == "" { // 简单类型没有名字
+		typeName = t.String()
+	}
+
+	var fields []rebindField
+	param := conf.BindParam{Type: t, Key: prefix, Path: typeName}
+	if err := collectRebind(p, v, param, &fields); err != nil {
+		return err
+	}
+	for _, f := range fields {
+		f.field.Set(f.value)
+	}
+	return nil
+}
+
+func collectRebind(p *conf.Properties, v reflect.Value, opt conf.BindParam, fields *[]rebindField) error {
+
+	for i := 0; i < opt.Type.NumField(); i++ {
+		ft := opt.Type.Field(i)
+		fv := v.Field(i)
+
+		if !fv.CanInterface() {
+			fv = util.PatchValue(fv)
+			if !fv.CanInterface() {
+				continue
+			}
+		}
+
+		subParam := conf.BindParam{
+			Type: ft.Type,
+			Key:  opt.Key,
+			Path: opt.Path + "." + ft.Name,
+		}
+
+		tag, ok := ft.Tag.Lookup("value")
+		if !ok {
+			if ft.Anonymous && ft.Type.Kind() == reflect.Struct {
+				if err := collectRebind(p, fv, subParam, fields); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := subParam.BindTag(tag); err != nil {
+			return err
+		}
+		if _, ok = conf.ToRefreshable(fv); ok {
+			continue // 动态属性由容器就地刷新
+		}
+		if ft.Anonymous {
+			if err := collectRebind(p, fv, subParam, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		nv := reflect.New(ft.Type).Elem()
+		if err := conf.BindValue(p, nv, subParam); err != nil {
+			return fmt.Errorf("rebind %s error: %w", subParam.Path, err)
+		}
+		*fields = append(*fields, rebindField{field: fv, value: nv})
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/util"
+)
+
+// ErrCircuitOpen 熔断器打开时被拦截的方法返回的错误。
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// RetryConfig 重试拦截器的配置，Backoff 是第一次重试之前等待的时间，Multiplier
+// 大于 1 时等待时间按照指数增长，最多不超过 MaxBackoff (为 0 时不限制)。
+type RetryConfig struct {
+	Attempts   int           `value:"${attempts:=3}"`
+	Backoff    time.Duration `value:"${backoff:=100ms}"`
+	Multiplier float64       `value:"${multiplier:=1}"`
+	MaxBackoff time.Duration `value:"${max-backoff:=0}"`
+}
+
+func (cfg RetryConfig) backoff() util.Backoff {
+	if cfg.Multiplier <= 1 {
+		return util.ConstantBackoff(cfg.Backoff)
+	}
+	return func(n int) time.Duration {
+		d := float64(cfg.Backoff)
+		for i := 1; i < n; i++ {
+			d *= cfg.Multiplier
+			if cfg.MaxBackoff > 0 && d > float64(cfg.MaxBackoff) {
+				return cfg.MaxBackoff
+			}
+		}
+		return time.Duration(d)
+	}
+}
+
+// Retry 返回重试拦截器，方法的最后一个返回值是非 nil 的 error 时按照 cfg 重新
+// 执行后续的拦截器和目标方法，熔断器打开返回的 ErrCircuitOpen 不会重试。methods
+// 为空时拦截所有的方法。
+func Retry(cfg RetryConfig, methods ...string) MethodInterceptor {
+	backoff := cfg.backoff()
+	return func(inv *Invocation) []interface{} {
+		if !matchMethod(inv.Method, methods) {
+			return inv.Proceed()
+		}
+		var out []interface{}
+		index := inv.index
+		_ = util.Retry(context.Background(), cfg.Attempts, backoff, func(ctx context.Context) error {
+			inv.index = index
+			out = inv.Proceed()
+			return lastError(out)
+		}, util.RetryIf(func(err error) bool {
+			return !errors.Is(err, ErrCircuitOpen)
+		}))
+		return out
+	}
+}
+
+// CircuitBreakerConfig 熔断器的配置。在 Window 时间窗口内调用次数不少于
+// MinRequests 并且失败率不低于 FailureRate 时熔断器打开，打开 OpenTimeout
+// 时间之后进入半开状态，允许 HalfOpenProbes 次试探调用，全部成功时关闭，
+// 任何一次失败时重新打开。
+type CircuitBreakerConfig struct {
+	Window         time.Duration `value:"${window:=10s}"`
+	MinRequests    int           `value:"${min-requests:=20}"`
+	FailureRate    float64       `value:"${failure-rate:=0.5}"`
+	OpenTimeout    time.Duration `value:"${open-timeout:=30s}"`
+	HalfOpenProbes int           `value:"${half-open-probes:=1}"`
+}
+
+// CircuitState 熔断器的状态。
+type CircuitState int
+
+const (
+	CircuitClosed   = CircuitState(iota) // 关闭，正常调用
+	CircuitOpen                          // 打开，直接返回 ErrCircuitOpen
+	CircuitHalfOpen                      // 半开，允许少量的试探调用
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "half-open"
+	}
+}
+
+// CircuitBreaker 基于时间窗口内失败率的熔断器。
+type CircuitBreaker struct {
+	cfg         CircuitBreakerConfig
+	mutex       sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int // 半开状态下已经放行的试探调用次数
+	successes   int // 半开状态下成功的试探调用次数
+}
+
+// NewCircuitBreaker 创建熔断器。
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{cfg: cfg, windowStart: time.Now()}
+}
+
+// State 返回熔断器当前的状态。
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.update(time.Now())
+	return cb.state
+}
+
+// update 处理打开状态超时以及时间窗口滚动。
+func (cb *CircuitBreaker) update(now time.Time) {
+	switch cb.state {
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) >= cb.cfg.OpenTimeout {
+			cb.state = CircuitHalfOpen
+			cb.probes, cb.successes = 0, 0
+		}
+	case CircuitClosed:
+		if now.Sub(cb.windowStart) >= cb.cfg.Window {
+			cb.windowStart = now
+			cb.requests, cb.failures = 0, 0
+		}
+	}
+}
+
+// allow 返回是否放行本次调用。
+func (cb *CircuitBreaker) allow() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.update(time.Now())
+	switch cb.state {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if cb.probes >= cb.cfg.HalfOpenProbes {
+			return false
+		}
+		cb.probes++
+	}
+	return true
+}
+
+// record 记录一次调用的结果。
+func (cb *CircuitBreaker) record(failed bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	now := time.Now()
+	switch cb.state {
+	case CircuitHalfOpen:
+		if failed {
+			cb.open(now)
+			return
+		}
+		if cb.successes++; cb.successes >= cb.cfg.HalfOpenProbes {
+			cb.state = CircuitClosed
+			cb.windowStart = now
+			cb.requests, cb.failures = 0, 0
+		}
+	case CircuitClosed:
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.requests >= cb.cfg.MinRequests && float64(cb.failures) >= cb.cfg.FailureRate*float64(cb.requests) {
+			cb.open(now)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) open(now time.Time) {
+	cb.state = CircuitOpen
+	cb.openedAt = now
+}
+
+// Interceptor 返回熔断拦截器，熔断器打开时不调用目标方法，而是返回零值以及
+// ErrCircuitOpen ，因此被拦截的方法的最后一个返回值必须是 error 。methods 为
+// 空时拦截所有的方法。
+func (cb *CircuitBreaker) Interceptor(methods ...string) MethodInterceptor {
+	return func(inv *Invocation) []interface{} {
+		if !matchMethod(inv.Method, methods) {
+			return inv.Proceed()
+		}
+		if !cb.allow() {
+			return errorResult(inv, ErrCircuitOpen)
+		}
+		out := inv.Proceed()
+		cb.record(lastError(out) != nil)
+		return out
+	}
+}
+
+func matchMethod(method string, methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// lastError 返回方法的最后一个返回值中的 error 。
+func lastError(out []interface{}) error {
+	if n := len(out); n > 0 {
+		if err, ok := out[n-1].(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorResult 返回目标方法的零值返回值，最后一个返回值设置为 err 。
+func errorResult(inv *Invocation, err error) []interface{} {
+	m := inv.target.MethodByName(inv.Method)
+	if !m.IsValid() {
+		panic(fmt.Errorf("method %s not found in %s", inv.Method, inv.target.Type()))
+	}
+	t := m.Type()
+	if t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType {
+		panic(fmt.Errorf("method %s should return error as the last value", inv.Method))
+	}
+	out := make([]interface{}, t.NumOut())
+	for i := 0; i < t.NumOut()-1; i++ {
+		out[i] = reflect.Zero(t.Out(i)).Interface()
+	}
+	out[t.NumOut()-1] = err
+	return out
+}
+
+// configInterceptors 根据属性为 bean 的方法创建重试和熔断拦截器，属性前缀为
+// spring.aop.beans.<bean-name>.methods.<method> ，其下的 retry 和
+// circuit-breaker 分别对应 RetryConfig 和 CircuitBreakerConfig 。重试拦截器
+// 在熔断拦截器之前执行。
+func (c *container) configInterceptors(b *BeanDefinition) ([]MethodInterceptor, error) {
+	var retries, breakers []MethodInterceptor
+	t := reflect.TypeOf(b.Interface())
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i).Name
+		prefix := "spring.aop.beans." + b.BeanName() + ".methods." + method
+		if key := prefix + ".retry"; c.p.Has(key) {
+			var cfg RetryConfig
+			if err := c.p.Bind(&cfg, conf.Key(key)); err != nil {
+				return nil, err
+			}
+			retries = append(retries, Retry(cfg, method))
+		}
+		if key := prefix + ".circuit-breaker"; c.p.Has(key) {
+			var cfg CircuitBreakerConfig
+			if err := c.p.Bind(&cfg, conf.Key(key)); err != nil {
+				return nil, err
+			}
+			breakers = append(breakers, NewCircuitBreaker(cfg).Interceptor(method))
+		}
+	}
+	return append(retries, breakers...), nil
+}
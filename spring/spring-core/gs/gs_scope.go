@@ -0,0 +1,181 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/go-spring/spring-base/knife"
+)
+
+// Scope bean 的作用域。
+type Scope int
+
+const (
+	ScopeSingleton = Scope(iota) // 单例，容器中只有一个实例
+	ScopePrototype               // 原型，每次注入或者获取时都创建新的实例
+	ScopeRequest                 // 请求，同一个请求中只有一个实例
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeSingleton:
+		return "singleton"
+	case ScopePrototype:
+		return "prototype"
+	case ScopeRequest:
+		return "request"
+	default:
+		return fmt.Sprintf("scope(%d)", int(s))
+	}
+}
+
+// newInstance 复制 bean 的元数据用于创建非单例 bean 的新实例。
+func (d *BeanDefinition) newInstance() *BeanDefinition {
+	b := *d
+	b.v = reflect.New(d.v.Type()).Elem()
+	b.status = Resolved
+	return &b
+}
+
+// scopedValue 返回注入时使用的 bean 的值，单例 bean 返回其唯一的实例，原型 bean
+// 每次创建新的实例，请求 bean 返回当前请求中的实例。原型 bean 的销毁函数不会被
+// 调用，请求 bean 的销毁函数在请求结束时调用。单例 bean 的生命周期长于请求，
+// 因此不能注入请求 bean ，这种情况直接报错并指出注入的位置。
+func (c *container) scopedValue(b *BeanDefinition, stack *wiringStack) (reflect.Value, error) {
+	switch b.scope {
+	case ScopePrototype:
+		nb := b.newInstance()
+		if err := c.wireBean(nb, stack); err != nil {
+			return reflect.Value{}, err
+		}
+		return nb.Value(), nil
+	case ScopeRequest:
+		rs := getRequestScope(stack.ctx)
+		if rs == nil {
+			if n := len(stack.beans); n > 0 && stack.beans[n-1].scope == ScopeSingleton {
+				return reflect.Value{}, fmt.Errorf("request scoped %s can't be injected into singleton %s via %s, use GetWithCtx in request instead", b, stack.beans[n-1], stack.via)
+			}
+			return reflect.Value{}, fmt.Errorf("%s is request scoped, should get it in request", b)
+		}
+		return rs.get(b, func() (*BeanDefinition, error) {
+			nb := b.newInstance()
+			if err := c.wireBean(nb, stack); err != nil {
+				return nil, err
+			}
+			return nb, nil
+		})
+	}
+	if err := c.wireBean(b, stack); err != nil {
+		return reflect.Value{}, err
+	}
+	return b.Value(), nil
+}
+
+// GetWithCtx 和 Get 相同，但是可以获取 ctx 所在请求作用域中的 bean ，ctx 必须
+// 是 WithRequestScope 返回的对象。
+func (c *container) GetWithCtx(ctx context.Context, i interface{}, selectors ...BeanSelector) error {
+
+	if i == nil {
+		return errors.New("i can't be nil")
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr {
+		return errors.New("i must be pointer")
+	}
+
+	stack := newWiringStack()
+	stack.ctx = ctx
+
+	var tags []wireTag
+	for _, s := range selectors {
+		tags = append(tags, toWireTag(s))
+	}
+	return c.runtimeWire(stack, func() error {
+		return c.autowire(v.Elem(), tags, stack)
+	})
+}
+
+const requestScopeKey = "::gs.request-scope::"
+
+// requestScope 保存请求作用域中创建的 bean 。
+type requestScope struct {
+	mu    sync.Mutex
+	beans map[string]*BeanDefinition
+	order []*BeanDefinition
+}
+
+func getRequestScope(ctx context.Context) *requestScope {
+	if ctx == nil {
+		return nil
+	}
+	v, ok := knife.Get(ctx, requestScopeKey)
+	if !ok {
+		return nil
+	}
+	return v.(*requestScope)
+}
+
+func (rs *requestScope) get(b *BeanDefinition, create func() (*BeanDefinition, error)) (reflect.Value, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if nb, ok := rs.beans[b.ID()]; ok {
+		return nb.Value(), nil
+	}
+	nb, err := create()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	rs.beans[b.ID()] = nb
+	rs.order = append(rs.order, nb)
+	return nb.Value(), nil
+}
+
+// destroy 按照创建的相反顺序调用请求作用域中 bean 的销毁函数。
+func (rs *requestScope) destroy() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for i := len(rs.order) - 1; i >= 0; i-- {
+		b := rs.order[i]
+		if _, ok := b.Interface().(BeanDestroy); ok || b.destroy != nil {
+			destroyFunc(b.Value(), b.destroy)()
+		}
+	}
+	rs.beans, rs.order = nil, nil
+}
+
+// WithRequestScope 在 ctx 上创建请求作用域，请求结束时必须调用返回的 end 函数
+// 销毁作用域中的 bean 。ctx 上已经存在请求作用域时直接返回，此时 end 什么都不做。
+func WithRequestScope(ctx context.Context) (_ context.Context, end func()) {
+	if getRequestScope(ctx) != nil {
+		return ctx, func() {}
+	}
+	ctx, _ = knife.New(ctx)
+	rs := &requestScope{beans: make(map[string]*BeanDefinition)}
+	if err := knife.Set(ctx, requestScopeKey, rs); err != nil {
+		return ctx, func() {}
+	}
+	return ctx, func() {
+		rs.destroy()
+		knife.Delete(ctx, requestScopeKey)
+	}
+}
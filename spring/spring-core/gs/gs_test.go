@@ -17,6 +17,7 @@
 package gs_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image"
@@ -849,6 +850,163 @@ func TestApplicationContext_DependsOn(t *testing.T) {
 	})
 }
 
+func TestApplicationContext_Replace(t *testing.T) {
+
+	t.Run("same type", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero")
+		c.Object(&BeanZero{6}).Replace()
+		c.Object(new(BeanOne))
+		err := runTest(c, func(p gs.Context) {
+			var b *BeanZero
+			assert.Nil(t, p.Get(&b, "zero"))
+			assert.Equal(t, b.Int, 6)
+			var one *BeanOne
+			assert.Nil(t, p.Get(&one))
+			assert.Same(t, one.Zero, b)
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("exported interface", func(t *testing.T) {
+		c := gs.New()
+		c.Object(new(simpleGreeter)).Export((*greeter)(nil))
+		c.Object(&greeterProxy{}).Export((*greeter)(nil)).Replace()
+		err := runTest(c, func(p gs.Context) {
+			var g greeter
+			assert.Nil(t, p.Get(&g))
+			_, ok := g.(*greeterProxy)
+			assert.True(t, ok)
+		})
+		assert.Nil(t, err)
+	})
+}
+
+type aliasHolder struct {
+	Zero    *BeanZero `autowire:"primaryZero"`
+	Greeter greeter   `autowire:"defaultGreeter"`
+}
+
+func TestApplicationContext_Alias(t *testing.T) {
+
+	t.Run("inject", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero").Alias("primaryZero", "oldZero")
+		c.Object(&BeanZero{6}).Name("other")
+		c.Object(new(simpleGreeter)).Name("greeter").Alias("defaultGreeter")
+		h := new(aliasHolder)
+		c.Object(h)
+		var b *BeanZero
+		err := runTest(c, func(p gs.Context) {
+			assert.Nil(t, p.Get(&b, "oldZero"))
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, b.Int, 5)
+		assert.Same(t, h.Zero, b)
+		_, ok := h.Greeter.(*simpleGreeter)
+		assert.True(t, ok)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero").Alias("primaryZero")
+		c.Object(&BeanZero{6}).Replace()
+		err := runTest(c, func(p gs.Context) {
+			var b *BeanZero
+			assert.Nil(t, p.Get(&b, "primaryZero"))
+			assert.Equal(t, b.Int, 6)
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero")
+		c.Object(&BeanZero{6}).Name("other").Alias("zero")
+		err := c.Refresh()
+		assert.Error(t, err, "alias \"zero\" of .* conflicts with .*")
+	})
+}
+
+func TestApplicationContext_OverridePolicy(t *testing.T) {
+
+	for _, policy := range []string{gs.OverrideAllow, gs.OverrideWarn} {
+		t.Run(policy, func(t *testing.T) {
+			c := gs.New()
+			c.Property(gs.OverridePolicyKey, policy)
+			c.Object(&BeanZero{5}).Name("zero").Alias("primaryZero")
+			c.Object(&BeanZero{6}).Name("zero").Alias("primaryZero")
+			c.Object(new(BeanOne))
+			err := runTest(c, func(p gs.Context) {
+				var b *BeanZero
+				assert.Nil(t, p.Get(&b, "zero"))
+				assert.Equal(t, b.Int, 6)
+				var one *BeanOne
+				assert.Nil(t, p.Get(&one))
+				assert.Same(t, one.Zero, b)
+			})
+			assert.Nil(t, err)
+		})
+	}
+
+	t.Run("forbid", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero")
+		c.Object(&BeanZero{6}).Name("zero")
+		err := c.Refresh()
+		assert.Error(t, err, "found duplicate beans \\[.*gs_test.go:\\d+\\] \\[.*gs_test.go:\\d+\\]")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		c := gs.New()
+		c.Property(gs.OverridePolicyKey, "replace")
+		err := c.Refresh()
+		assert.Error(t, err, "unknown bean override policy \"replace\"")
+	})
+}
+
+func TestApplicationContext_Child(t *testing.T) {
+
+	t.Run("parent cleared", func(t *testing.T) {
+		parent := gs.New()
+		assert.Nil(t, parent.Refresh())
+		assert.Panic(t, func() { gs.NewChild(parent) }, "parent container has been cleared")
+	})
+
+	t.Run("inherit", func(t *testing.T) {
+		var calls []string
+		parent := gs.New()
+		parent.Property("app.name", "parent")
+		parent.Property("app.version", "1.0")
+		parent.Object(&BeanZero{5}).Destroy(func(*BeanZero) { calls = append(calls, "parent") })
+		parent.Object(new(simpleGreeter)).Export((*greeter)(nil))
+		child := gs.NewChild(parent)
+		assert.Nil(t, parent.Refresh())
+
+		child.Property("app.name", "child")
+		child.Object(new(BeanOne)).Destroy(func(*BeanOne) { calls = append(calls, "child") })
+		child.Object(&greeterProxy{}).Export((*greeter)(nil))
+		err := runTest(child, func(p gs.Context) {
+			assert.Equal(t, p.Prop("app.name"), "child")
+			assert.Equal(t, p.Prop("app.version"), "1.0")
+			var one *BeanOne
+			assert.Nil(t, p.Get(&one))
+			assert.Equal(t, one.Zero.Int, 5)
+			var g greeter
+			assert.Nil(t, p.Get(&g))
+			_, ok := g.(*greeterProxy)
+			assert.True(t, ok)
+			var ctx gs.Context
+			assert.Nil(t, p.Get(&ctx))
+			assert.Same(t, ctx, child)
+		})
+		assert.Nil(t, err)
+
+		parent.Close()
+		assert.Equal(t, calls, []string{"child", "parent"})
+	})
+}
+
 func TestApplicationContext_Primary(t *testing.T) {
 
 	t.Run("duplicate", func(t *testing.T) {
@@ -872,6 +1030,16 @@ func TestApplicationContext_Primary(t *testing.T) {
 		assert.Error(t, err, "duplicate beans ")
 	})
 
+	t.Run("duplicate all", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5})
+		c.Object(&BeanZero{6})
+		c.Object(new(BeanOne))
+		c.Object(new(BeanOne))
+		err := c.Refresh()
+		assert.Error(t, err, "^2 errors occurred:\n\t\\* found duplicate beans .*BeanZero.*\n\t\\* found duplicate beans .*BeanOne")
+	})
+
 	t.Run("not primary", func(t *testing.T) {
 		c := gs.New()
 		c.Object(&BeanZero{5})
@@ -900,6 +1068,38 @@ func TestApplicationContext_Primary(t *testing.T) {
 		})
 		assert.Nil(t, err)
 	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero_5")
+		c.Object(&BeanZero{6}).Name("zero_6")
+		c.Object(new(BeanOne))
+		err := c.Refresh()
+		assert.Error(t, err, `found 2 beans, bean:"" type:"\*gs_test.BeanZero" \[\( .*zero_5.* \), \( .*zero_6.* \)\], mark one of them as Primary\(\) or select it by name`)
+	})
+
+	t.Run("multiple primary", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero_5").Primary()
+		c.Object(&BeanZero{6}).Name("zero_6").Primary()
+		c.Object(new(BeanOne))
+		err := c.Refresh()
+		assert.Error(t, err, `found 2 primary beans, .*, only one of them can be Primary\(\)`)
+	})
+
+	t.Run("qualifier", func(t *testing.T) {
+		c := gs.New()
+		c.Object(&BeanZero{5}).Name("zero_5").Primary()
+		c.Object(&BeanZero{6}).Name("zero_6")
+		err := runTest(c, func(p gs.Context) {
+			var b *BeanZero
+			assert.Nil(t, p.Get(&b))
+			assert.Equal(t, b.Int, 5)
+			assert.Nil(t, p.Get(&b, "zero_6"))
+			assert.Equal(t, b.Int, 6)
+		})
+		assert.Nil(t, err)
+	})
 }
 
 type FuncObj struct {
@@ -1469,6 +1669,27 @@ func TestOptionConstructorArg(t *testing.T) {
 		})
 		assert.Nil(t, err)
 	})
+
+	t.Run("option beans", func(t *testing.T) {
+		c := gs.New()
+		c.Property("class_name", "二年级06班")
+		c.Property("class_floor", 6)
+		c.Property("president", "CaiYuanPei")
+		c.Provide(NewClassRoom)
+		c.Provide(withClassName, "${class_name}", "${class_floor}").Name("withClassName")
+		c.Provide(withStudents).Name("withStudents")
+		c.Object(&Student{}).Name("Student1")
+		c.Object(&Student{}).Name("Student2")
+		err := runTest(c, func(p gs.Context) {
+			var cls *ClassRoom
+			err := p.Get(&cls)
+			assert.Nil(t, err)
+			assert.Equal(t, cls.floor, 6)
+			assert.Equal(t, len(cls.students), 2)
+			assert.Equal(t, cls.className, "二年级06班")
+		})
+		assert.Nil(t, err)
+	})
 }
 
 type ServerInterface interface {
@@ -1807,7 +2028,20 @@ func TestApplicationContext_CircleAutowire(t *testing.T) {
 			return new(CircleC)
 		})
 		err := c.Refresh()
-		assert.Error(t, err, "found circle autowire")
+		assert.Error(t, err, `found circle autowire: bean:"CircleA" → constructor arg → bean:"CircleB" → constructor arg → bean:"CircleC" → constructor arg → bean:"CircleA", inject one of them by field`)
+	})
+
+	t.Run("field", func(t *testing.T) {
+		c := gs.New()
+		c.Provide(func(b *CircleB) *CircleA {
+			return new(CircleA)
+		})
+		c.Object(new(CircleB))
+		c.Provide(func(a *CircleA) *CircleC {
+			return new(CircleC)
+		})
+		err := c.Refresh()
+		assert.Error(t, err, `found circle autowire: bean:"CircleA" → constructor arg → bean:"CircleB" → field C → bean:"CircleC" → constructor arg → bean:"CircleA"`)
 	})
 }
 
@@ -2927,6 +3161,39 @@ func (t *table) OnDestroy() {
 	fmt.Println("table.OnDestroy")
 }
 
+type destroyMiddle struct {
+	Repo *destroyRepo `autowire:""`
+}
+
+type destroyRepo struct {
+	destroyed *[]string
+}
+
+func (r *destroyRepo) OnDestroy() {
+	*r.destroyed = append(*r.destroyed, "repo")
+}
+
+type destroyService struct {
+	Middle    *destroyMiddle `autowire:""`
+	destroyed *[]string
+}
+
+func (s *destroyService) OnDestroy() {
+	*s.destroyed = append(*s.destroyed, "service")
+}
+
+func TestDestroyIndirectDependence(t *testing.T) {
+	var destroyed []string
+	c := gs.New()
+	c.Object(&destroyService{destroyed: &destroyed})
+	c.Object(&destroyRepo{destroyed: &destroyed})
+	c.Object(new(destroyMiddle))
+	err := c.Refresh()
+	assert.Nil(t, err)
+	c.Close()
+	assert.Equal(t, destroyed, []string{"service", "repo"})
+}
+
 func TestDestroyDependence(t *testing.T) {
 	c := gs.New()
 	c.Object(new(memory))
@@ -2935,3 +3202,378 @@ func TestDestroyDependence(t *testing.T) {
 	err := c.Refresh()
 	assert.Nil(t, err)
 }
+
+func TestDefaultSpringContext_ConditionOnExpression(t *testing.T) {
+
+	c := gs.New()
+	c.Property("server.mode", "prod")
+	c.Object(&BeanZero{1}).Name("a").On(cond.OnExpression(`${server.mode} == "prod"`))
+	c.Object(&BeanZero{2}).Name("b").On(cond.OnExpression(`${server.mode} != "prod"`))
+	err := runTest(c, func(p gs.Context) {
+		var b *BeanZero
+		assert.Nil(t, p.Get(&b, "a"))
+		assert.Equal(t, b.Int, 1)
+		assert.Error(t, p.Get(&b, "b"), "can't find bean, bean:\"b\"")
+	})
+	assert.Nil(t, err)
+}
+
+// TestDefaultSpringContext_ConditionOverride 测试 starter 注册的默认 bean 可以
+// 被用户自定义的 bean 覆盖，与注册顺序无关。
+func TestDefaultSpringContext_ConditionOverride(t *testing.T) {
+	for _, userFirst := range []bool{true, false} {
+		c := gs.New()
+		if userFirst {
+			c.Object(&BeanZero{1}).Name("user")
+		}
+		c.Object(&BeanZero{2}).Name("default").On(cond.OnMissingBean((*BeanZero)(nil)))
+		if !userFirst {
+			c.Object(&BeanZero{1}).Name("user")
+		}
+		err := runTest(c, func(p gs.Context) {
+			var b *BeanZero
+			assert.Nil(t, p.Get(&b))
+			assert.Equal(t, b.Int, 1)
+		})
+		assert.Nil(t, err)
+	}
+}
+
+type scopedBean struct {
+	ID        int
+	destroyed *[]int
+}
+
+func (b *scopedBean) OnDestroy() {
+	*b.destroyed = append(*b.destroyed, b.ID)
+}
+
+type scopedHolder struct {
+	A *scopedBean `autowire:""`
+	B *scopedBean `autowire:""`
+}
+
+func TestDefaultSpringContext_Scope(t *testing.T) {
+
+	t.Run("prototype", func(t *testing.T) {
+		var (
+			n         int
+			destroyed []int
+		)
+		c := gs.New()
+		c.Provide(func() *scopedBean {
+			n++
+			return &scopedBean{ID: n, destroyed: &destroyed}
+		}).Scope(gs.ScopePrototype)
+		c.Object(new(scopedHolder))
+		err := runTest(c, func(p gs.Context) {
+			var h *scopedHolder
+			assert.Nil(t, p.Get(&h))
+			assert.Equal(t, []int{h.A.ID, h.B.ID}, []int{1, 2})
+			var b *scopedBean
+			assert.Nil(t, p.Get(&b))
+			assert.Equal(t, b.ID, 3)
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, destroyed)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		var (
+			n         int
+			destroyed []int
+		)
+		c := gs.New()
+		c.Provide(func() *scopedBean {
+			n++
+			return &scopedBean{ID: n, destroyed: &destroyed}
+		}).Scope(gs.ScopeRequest)
+		err := runTest(c, func(p gs.Context) {
+
+			var b *scopedBean
+			assert.Error(t, p.Get(&b), "is request scoped, should get it in request")
+
+			ctx, end := gs.WithRequestScope(context.Background())
+			var b1, b2 *scopedBean
+			assert.Nil(t, p.GetWithCtx(ctx, &b1))
+			assert.Nil(t, p.GetWithCtx(ctx, &b2))
+			assert.True(t, b1 == b2)
+			end()
+			assert.Equal(t, destroyed, []int{1})
+
+			ctx, end = gs.WithRequestScope(context.Background())
+			assert.Nil(t, p.GetWithCtx(ctx, &b1))
+			assert.Equal(t, b1.ID, 2)
+			end()
+			assert.Equal(t, destroyed, []int{1, 2})
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("request into singleton", func(t *testing.T) {
+		type Holder struct {
+			Req *scopedBean `autowire:""`
+		}
+		c := gs.New()
+		c.Provide(func() *scopedBean { return &scopedBean{} }).Scope(gs.ScopeRequest)
+		c.Object(new(Holder))
+		err := c.Refresh()
+		assert.Error(t, err, `"Holder.Req" wired error: request scoped .* can't be injected into singleton .*Holder.* via field Req`)
+	})
+
+	t.Run("object bean", func(t *testing.T) {
+		c := gs.New()
+		c.Object(new(scopedBean)).Scope(gs.ScopePrototype)
+		assert.Error(t, c.Refresh(), "can't be prototype scoped, only constructor bean can")
+	})
+}
+
+type lazyHolder struct {
+	Context gs.Context `autowire:""`
+}
+
+func TestDefaultSpringContext_Lazy(t *testing.T) {
+
+	run := func(t *testing.T, lazyAll bool) {
+		var (
+			n         int
+			destroyed []int
+		)
+		c := gs.New()
+		if lazyAll {
+			c.Property(gs.LazyInitialization, true)
+		}
+		b := c.Provide(func() *scopedBean {
+			n++
+			return &scopedBean{ID: n, destroyed: &destroyed}
+		})
+		if !lazyAll {
+			b.Lazy()
+		}
+		h := new(lazyHolder)
+		c.Object(h)
+		assert.Nil(t, c.Refresh())
+		assert.Equal(t, n, 0)
+
+		var b1, b2 *scopedBean
+		assert.Nil(t, h.Context.Get(&b1))
+		assert.Nil(t, h.Context.Get(&b2))
+		assert.True(t, b1 == b2)
+		assert.Equal(t, n, 1)
+
+		c.Close()
+		assert.Equal(t, destroyed, []int{1})
+	}
+
+	t.Run("bean", func(t *testing.T) {
+		run(t, false)
+	})
+
+	t.Run("global", func(t *testing.T) {
+		run(t, true)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := gs.New()
+		c.Property(gs.LazyInitialization, "maybe")
+		err := c.Refresh()
+		assert.Error(t, err, "property \"spring.main.lazy-initialization\"\n.*invalid syntax")
+	})
+
+	t.Run("injected", func(t *testing.T) {
+		c := gs.New()
+		c.Provide(func() *BeanZero { return &BeanZero{5} }).Lazy()
+		c.Object(&BeanOne{})
+		err := runTest(c, func(p gs.Context) {
+			var b *BeanOne
+			assert.Nil(t, p.Get(&b))
+			assert.Equal(t, b.Zero.Int, 5)
+		})
+		assert.Nil(t, err)
+	})
+}
+
+type greeter interface {
+	Greet(name string) (string, error)
+}
+
+type simpleGreeter struct{}
+
+func (g *simpleGreeter) Greet(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("name is empty")
+	}
+	return "hello " + name, nil
+}
+
+type greeterProxy struct{ p *gs.Proxy }
+
+func (g *greeterProxy) Greet(name string) (string, error) {
+	out := g.p.Invoke("Greet", name)
+	err, _ := out[1].(error)
+	return out[0].(string), err
+}
+
+type recordProcessor struct {
+	before []string
+	after  []string
+}
+
+func (p *recordProcessor) BeforeInit(b *gs.BeanDefinition) error {
+	p.before = append(p.before, b.BeanName())
+	return nil
+}
+
+func (p *recordProcessor) AfterInit(b *gs.BeanDefinition) (interface{}, error) {
+	p.after = append(p.after, b.BeanName())
+	return nil, nil
+}
+
+func TestDefaultSpringContext_AOP(t *testing.T) {
+
+	t.Run("post processor", func(t *testing.T) {
+		p := new(recordProcessor)
+		c := gs.New()
+		c.Object(p).Export((*gs.BeanPostProcessor)(nil))
+		c.Object(&BeanZero{5}).Init(func(b *BeanZero) {
+			assert.Equal(t, p.before, []string{"container", "BeanZero"})
+			assert.Equal(t, p.after, []string{"container"})
+		})
+		assert.Nil(t, c.Refresh())
+		assert.Equal(t, p.before, []string{"container", "BeanZero"})
+		assert.Equal(t, p.after, []string{"container", "BeanZero"})
+	})
+
+	t.Run("intercept", func(t *testing.T) {
+		var calls []string
+		logging := func(inv *gs.Invocation) []interface{} {
+			calls = append(calls, fmt.Sprintf("%s(%v)", inv.Method, inv.Args[0]))
+			return inv.Proceed()
+		}
+		upper := func(inv *gs.Invocation) []interface{} {
+			out := inv.Proceed()
+			out[0] = strings.ToUpper(out[0].(string))
+			return out
+		}
+		c := gs.New()
+		c.Provide(func() greeter { return new(simpleGreeter) }).Intercept(func(p *gs.Proxy) interface{} {
+			return &greeterProxy{p}
+		}, logging, upper)
+		err := runTest(c, func(p gs.Context) {
+			var g greeter
+			assert.Nil(t, p.Get(&g))
+			s, err := g.Greet("go-spring")
+			assert.Nil(t, err)
+			assert.Equal(t, s, "HELLO GO-SPRING")
+			_, err = g.Greet("")
+			assert.Error(t, err, "name is empty")
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, calls, []string{"Greet(go-spring)", "Greet()"})
+	})
+
+	t.Run("replace object bean", func(t *testing.T) {
+		c := gs.New()
+		c.Object(new(simpleGreeter)).Intercept(func(p *gs.Proxy) interface{} {
+			return &greeterProxy{p}
+		})
+		assert.Error(t, c.Refresh(), "can't be replaced by \\*gs_test.greeterProxy")
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		f := &flakyGreeter{fails: 2}
+		c := gs.New()
+		c.Provide(func() greeter { return f }).Intercept(func(p *gs.Proxy) interface{} {
+			return &greeterProxy{p}
+		}, gs.Retry(gs.RetryConfig{Attempts: 3, Backoff: time.Millisecond, Multiplier: 2}))
+		err := runTest(c, func(p gs.Context) {
+			var g greeter
+			assert.Nil(t, p.Get(&g))
+			s, err := g.Greet("go-spring")
+			assert.Nil(t, err)
+			assert.Equal(t, s, "hello go-spring")
+			assert.Equal(t, f.calls, 3)
+			f.calls, f.fails = 0, 5
+			_, err = g.Greet("go-spring")
+			assert.Error(t, err, "flaky error 3")
+			assert.Equal(t, f.calls, 3)
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("circuit breaker", func(t *testing.T) {
+		f := &flakyGreeter{fails: 100}
+		cb := gs.NewCircuitBreaker(gs.CircuitBreakerConfig{
+			Window:         time.Minute,
+			MinRequests:    4,
+			FailureRate:    0.5,
+			OpenTimeout:    50 * time.Millisecond,
+			HalfOpenProbes: 1,
+		})
+		c := gs.New()
+		c.Provide(func() greeter { return f }).Intercept(func(p *gs.Proxy) interface{} {
+			return &greeterProxy{p}
+		}, cb.Interceptor("Greet"))
+		err := runTest(c, func(p gs.Context) {
+			var g greeter
+			assert.Nil(t, p.Get(&g))
+			for i := 0; i < 4; i++ {
+				_, err := g.Greet("go-spring")
+				assert.Error(t, err, "flaky error")
+			}
+			assert.Equal(t, cb.State(), gs.CircuitOpen)
+			_, err := g.Greet("go-spring")
+			assert.Equal(t, err, gs.ErrCircuitOpen)
+			assert.Equal(t, f.calls, 4)
+
+			time.Sleep(60 * time.Millisecond)
+			assert.Equal(t, cb.State(), gs.CircuitHalfOpen)
+			_, err = g.Greet("go-spring")
+			assert.Error(t, err, "flaky error 5")
+			assert.Equal(t, cb.State(), gs.CircuitOpen)
+
+			time.Sleep(60 * time.Millisecond)
+			f.fails = 0
+			s, err := g.Greet("go-spring")
+			assert.Nil(t, err)
+			assert.Equal(t, s, "hello go-spring")
+			assert.Equal(t, cb.State(), gs.CircuitClosed)
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		f := &flakyGreeter{fails: 100}
+		c := gs.New()
+		c.Property("spring.aop.beans.flaky.methods.Greet.retry.attempts", 2)
+		c.Property("spring.aop.beans.flaky.methods.Greet.retry.backoff", "1ms")
+		c.Property("spring.aop.beans.flaky.methods.Greet.circuit-breaker.min-requests", 3)
+		c.Property("spring.aop.beans.flaky.methods.Greet.circuit-breaker.failure-rate", 1)
+		c.Provide(func() greeter { return f }).Name("flaky").Intercept(func(p *gs.Proxy) interface{} {
+			return &greeterProxy{p}
+		})
+		err := runTest(c, func(p gs.Context) {
+			var g greeter
+			assert.Nil(t, p.Get(&g))
+			_, err := g.Greet("go-spring")
+			assert.Error(t, err, "flaky error 2")
+			_, err = g.Greet("go-spring")
+			assert.Equal(t, err, gs.ErrCircuitOpen)
+			assert.Equal(t, f.calls, 3)
+		})
+		assert.Nil(t, err)
+	})
+}
+
+type flakyGreeter struct {
+	fails int
+	calls int
+}
+
+func (g *flakyGreeter) Greet(name string) (string, error) {
+	g.calls++
+	if g.calls <= g.fails {
+		return "", fmt.Errorf("flaky error %d", g.calls)
+	}
+	return "hello " + name, nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gstest 提供基于 IoC 容器的集成测试工具，每个测试使用独立的容器，可以使用
+// mock 对象替换真实的 bean ，然后将依赖注入到测试结构体中，测试结束时自动关闭容器。
+// 例如：
+//
+//	func TestService(t *testing.T) {
+//		c := gstest.New(t)
+//		gstest.MockBean[Repo](c, &mockRepo{})
+//		var s struct {
+//			Service *Service `autowire:""`
+//		}
+//		c.Wire(&s)
+//		...
+//	}
+package gstest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/arg"
+)
+
+var (
+	setupMutex sync.Mutex
+	setups     []func(c *Container)
+)
+
+// Setup 注册测试包中公共的 bean 和属性，每个测试创建容器时都会执行一遍，通常在
+// init 函数或者 TestMain 函数中调用。
+func Setup(fn func(c *Container)) {
+	setupMutex.Lock()
+	defer setupMutex.Unlock()
+	setups = append(setups, fn)
+}
+
+// Container 测试使用的 IoC 容器。
+type Container struct {
+	t testing.TB
+	c gs.Container
+}
+
+// New 创建测试使用的 IoC 容器并执行 Setup 注册的函数，测试结束时自动关闭容器。
+func New(t testing.TB) *Container {
+	c := &Container{t: t, c: gs.New()}
+	t.Cleanup(c.c.Close)
+	setupMutex.Lock()
+	fns := append([]func(c *Container){}, setups...)
+	setupMutex.Unlock()
+	for _, fn := range fns {
+		fn(c)
+	}
+	return c
+}
+
+// Property 参考 Container.Property 的解释。
+func (c *Container) Property(key string, value interface{}) {
+	c.c.Property(key, value)
+}
+
+// Object 参考 Container.Object 的解释。
+func (c *Container) Object(i interface{}) *gs.BeanDefinition {
+	return c.c.Object(i)
+}
+
+// Provide 参考 Container.Provide 的解释。
+func (c *Container) Provide(ctor interface{}, args ...arg.Arg) *gs.BeanDefinition {
+	return c.c.Provide(ctor, args...)
+}
+
+// MockBean 使用 mock 对象替换类型为 T 的 bean ，T 可以是接口类型，此时 mock 以
+// 该接口导出，也可以是具体的类型，mock 是否符合 T 由编译器检查。
+func MockBean[T any](c *Container, mock T) *gs.BeanDefinition {
+	b := c.c.Object(mock).Replace()
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() == reflect.Interface {
+		return b.Export((*T)(nil))
+	}
+	return b
+}
+
+// Wire 刷新容器，然后对测试结构体进行属性绑定和依赖注入，tests 必须是结构体指针，
+// 刷新失败时测试立即结束。
+func (c *Container) Wire(tests ...interface{}) {
+	c.t.Helper()
+	for _, test := range tests {
+		c.c.Object(test)
+	}
+	if err := c.c.Refresh(); err != nil {
+		c.t.Fatal(err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gstest_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs/gstest"
+)
+
+type Repo interface {
+	Find(id int) string
+}
+
+type dbRepo struct{}
+
+func (r *dbRepo) Find(id int) string { return "db" }
+
+type mockRepo struct{ calls int }
+
+func (r *mockRepo) Find(id int) string {
+	r.calls++
+	return "mock"
+}
+
+type Service struct {
+	Repo   Repo   `autowire:""`
+	ByName Repo   `autowire:"repo"`
+	Prefix string `value:"${service.prefix:=svc}"`
+}
+
+func (s *Service) Get(id int) string {
+	return s.Prefix + ":" + s.Repo.Find(id)
+}
+
+func init() {
+	gstest.Setup(func(c *gstest.Container) {
+		c.Object(&dbRepo{}).Name("repo").Export((*Repo)(nil))
+		c.Object(new(Service))
+	})
+}
+
+func TestContainer(t *testing.T) {
+
+	t.Run("real", func(t *testing.T) {
+		c := gstest.New(t)
+		var s struct {
+			Service *Service `autowire:""`
+		}
+		c.Wire(&s)
+		assert.Equal(t, s.Service.Get(1), "svc:db")
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		c := gstest.New(t)
+		c.Property("service.prefix", "test")
+		m := &mockRepo{}
+		gstest.MockBean[Repo](c, m)
+		var s struct {
+			Service *Service `autowire:""`
+			Repo    Repo     `autowire:"repo"`
+		}
+		c.Wire(&s)
+		assert.Equal(t, s.Service.Get(1), "test:mock")
+		assert.Equal(t, s.Service.ByName, Repo(m))
+		assert.Equal(t, s.Repo, Repo(m))
+		assert.Equal(t, m.calls, 1)
+	})
+
+	t.Run("same type", func(t *testing.T) {
+		c := gstest.New(t)
+		m := &Service{}
+		gstest.MockBean(c, m)
+		var s struct {
+			Service *Service `autowire:""`
+		}
+		c.Wire(&s)
+		assert.Same(t, s.Service, m)
+		assert.Equal(t, s.Service.Get(1), "svc:db")
+	})
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// DiskIndicator 磁盘空间健康指示器，可用空间低于阈值时不健康。
+type DiskIndicator struct {
+	Path      string // 检查的路径
+	Threshold uint64 // 可用空间的最小字节数
+}
+
+// NewDiskIndicator 创建磁盘空间健康指示器。
+func NewDiskIndicator(path string, threshold uint64) *DiskIndicator {
+	return &DiskIndicator{Path: path, Threshold: threshold}
+}
+
+// Name 返回组件的名称。
+func (d *DiskIndicator) Name() string {
+	return "disk"
+}
+
+// Health 返回磁盘空间的健康状态。
+func (d *DiskIndicator) Health(ctx context.Context) Health {
+	free, total, err := diskUsage(d.Path)
+	if err != nil {
+		return Health{
+			Status:  StatusUnknown,
+			Details: map[string]interface{}{"error": err.Error()},
+		}
+	}
+	details := map[string]interface{}{
+		"path":      d.Path,
+		"free":      free,
+		"total":     total,
+		"threshold": d.Threshold,
+	}
+	if free < d.Threshold {
+		details["error"] = fmt.Sprintf("free space %d below threshold %d", free, d.Threshold)
+		return Health{Status: StatusDown, Details: details}
+	}
+	return Up(details)
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package health 提供应用的健康检查，应用通过实现 Indicator 接口的 bean 汇报
+// 各个组件的健康状态，gs 会把它们汇总为存活探针和就绪探针的结果。
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// Status 健康状态。
+type Status string
+
+const (
+	StatusUp      = Status("UP")      // 健康
+	StatusDown    = Status("DOWN")    // 不健康
+	StatusUnknown = Status("UNKNOWN") // 无法确定
+)
+
+// Health 单个组件的健康状态以及详细信息。
+type Health struct {
+	Status  Status                 `json:"status"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Up 返回健康的状态。
+func Up(details map[string]interface{}) Health {
+	return Health{Status: StatusUp, Details: details}
+}
+
+// Down 返回不健康的状态，err 作为详细信息中的 error 字段。
+func Down(err error) Health {
+	return Health{Status: StatusDown, Details: map[string]interface{}{"error": err.Error()}}
+}
+
+// Indicator 健康指示器，Name 是组件的名称，Health 返回组件当前的健康状态。
+type Indicator interface {
+	Name() string
+	Health(ctx context.Context) Health
+}
+
+// LivenessIndicator 同时参与存活探针的健康指示器，所有的健康指示器都参与就绪
+// 探针，只有影响进程存活的健康指示器 (例如死锁检测) 需要实现该接口。
+type LivenessIndicator interface {
+	Indicator
+	Liveness() bool
+}
+
+// IndicatorFunc 使用函数实现的健康指示器。
+type IndicatorFunc struct {
+	name string
+	fn   func(ctx context.Context) Health
+}
+
+// NewIndicator 使用函数创建健康指示器。
+func NewIndicator(name string, fn func(ctx context.Context) Health) *IndicatorFunc {
+	return &IndicatorFunc{name: name, fn: fn}
+}
+
+// Name 返回组件的名称。
+func (f *IndicatorFunc) Name() string {
+	return f.name
+}
+
+// Health 返回组件当前的健康状态。
+func (f *IndicatorFunc) Health(ctx context.Context) Health {
+	return f.fn(ctx)
+}
+
+// Report 健康检查的汇总结果。
+type Report struct {
+	Status     Status            `json:"status"`
+	Components map[string]Health `json:"components,omitempty"`
+}
+
+// Aggregate 调用所有的健康指示器并汇总结果，任何一个组件不健康则整体不健康，
+// 健康指示器发生 panic 时视为不健康。
+func Aggregate(ctx context.Context, indicators []Indicator) Report {
+	r := Report{Status: StatusUp}
+	if len(indicators) == 0 {
+		return r
+	}
+	r.Components = make(map[string]Health)
+	for _, i := range indicators {
+		h := check(ctx, i)
+		r.Components[i.Name()] = h
+		if h.Status != StatusUp && r.Status == StatusUp {
+			r.Status = h.Status
+		}
+		if h.Status == StatusDown {
+			r.Status = StatusDown
+		}
+	}
+	return r
+}
+
+func check(ctx context.Context, i Indicator) (h Health) {
+	defer func() {
+		if r := recover(); r != nil {
+			h = Down(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	return i.Health(ctx)
+}
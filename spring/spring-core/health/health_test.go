@@ -0,0 +1,72 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/health"
+)
+
+func TestAggregate(t *testing.T) {
+
+	ctx := context.Background()
+	assert.Equal(t, health.Aggregate(ctx, nil), health.Report{Status: health.StatusUp})
+
+	up := health.NewIndicator("db", func(ctx context.Context) health.Health {
+		return health.Up(map[string]interface{}{"version": "8.0"})
+	})
+	unknown := health.NewIndicator("cache", func(ctx context.Context) health.Health {
+		return health.Health{Status: health.StatusUnknown}
+	})
+	down := health.NewIndicator("mq", func(ctx context.Context) health.Health {
+		return health.Down(errors.New("connection refused"))
+	})
+	panics := health.NewIndicator("panic", func(ctx context.Context) health.Health {
+		panic("oops")
+	})
+
+	r := health.Aggregate(ctx, []health.Indicator{up, unknown})
+	assert.Equal(t, r.Status, health.StatusUnknown)
+
+	r = health.Aggregate(ctx, []health.Indicator{up, down, unknown})
+	assert.Equal(t, r.Status, health.StatusDown)
+	assert.Equal(t, r.Components["db"], health.Up(map[string]interface{}{"version": "8.0"}))
+	assert.Equal(t, r.Components["mq"].Details["error"], "connection refused")
+
+	r = health.Aggregate(ctx, []health.Indicator{panics})
+	assert.Equal(t, r.Status, health.StatusDown)
+	assert.Equal(t, r.Components["panic"].Details["error"], "panic: oops")
+}
+
+func TestDiskIndicator(t *testing.T) {
+
+	ctx := context.Background()
+	h := health.NewDiskIndicator(".", 0).Health(ctx)
+	assert.Equal(t, h.Status, health.StatusUp)
+	assert.Equal(t, h.Details["path"], ".")
+
+	h = health.NewDiskIndicator(".", math.MaxUint64).Health(ctx)
+	assert.Equal(t, h.Status, health.StatusDown)
+
+	h = health.NewDiskIndicator("/not/exist", 0).Health(ctx)
+	assert.Equal(t, h.Status, health.StatusUnknown)
+}
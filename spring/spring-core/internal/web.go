@@ -18,6 +18,7 @@ package internal
 
 // WebServerConfig Web 服务器配置，通常配合 web 服务器名称前缀一起使用。
 type WebServerConfig struct {
+	Name         string `value:"${name:=}"`            // 服务器名称
 	Host         string `value:"${host:=}"`            // 监听 IP
 	Port         int    `value:"${port:=8080}"`        // HTTP 端口
 	EnableSSL    bool   `value:"${ssl.enable:=false}"` // 是否启用 HTTPS
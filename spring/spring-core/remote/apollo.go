@@ -0,0 +1,146 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// Apollo 基于 Apollo 配置服务 HTTP 接口的远程配置源。properties 类型的命名空间
+// 中的每一项对应一个属性，其他类型的命名空间例如 app.yaml 按照扩展名解析其内容。
+type Apollo struct {
+	Address   string       // 配置服务的地址，例如 http://127.0.0.1:8080
+	AppID     string       // 应用的 appId
+	Cluster   string       // 集群名称，为空时使用 default
+	Namespace string       // 命名空间，为空时使用 application
+	Client    *http.Client // 为 nil 时使用 http.DefaultClient
+}
+
+type apolloConfig struct {
+	Configurations map[string]string `json:"configurations"`
+}
+
+type apolloNotification struct {
+	NamespaceName  string `json:"namespaceName"`
+	NotificationID int64  `json:"notificationId"`
+}
+
+func (c *Apollo) Name() string {
+	return "apollo:" + c.AppID + "/" + c.namespace()
+}
+
+func (c *Apollo) cluster() string {
+	if c.Cluster == "" {
+		return "default"
+	}
+	return c.Cluster
+}
+
+func (c *Apollo) namespace() string {
+	if c.Namespace == "" {
+		return "application"
+	}
+	return c.Namespace
+}
+
+func (c *Apollo) url(path string) string {
+	return strings.TrimSuffix(c.Address, "/") + path
+}
+
+func (c *Apollo) Load(ctx context.Context) (*conf.Properties, error) {
+
+	path := "/configs/" + url.PathEscape(c.AppID) + "/" +
+		url.PathEscape(c.cluster()) + "/" + url.PathEscape(c.namespace())
+
+	b, _, err := doRequest(ctx, c.Client, http.MethodGet, c.url(path), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var config apolloConfig
+	if err = json.Unmarshal(b, &config); err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(c.namespace())
+	if ext == "" || ext == ".properties" {
+		return fromMap(config.Configurations)
+	}
+	return parse(c.namespace(), []byte(config.Configurations["content"]))
+}
+
+// Watch 通过 Apollo 的通知接口监听命名空间的变化，收到通知之后重新加载配置。
+func (c *Apollo) Watch(ctx context.Context, onChange func(p *conf.Properties)) error {
+
+	id := int64(-1)
+	for {
+		b, err := json.Marshal([]apolloNotification{{
+			NamespaceName:  c.namespace(),
+			NotificationID: id,
+		}})
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Set("appId", c.AppID)
+		query.Set("cluster", c.cluster())
+		query.Set("notifications", string(b))
+
+		u := c.url("/notifications/v2?" + query.Encode())
+		b, _, err = doRequest(ctx, c.Client, http.MethodGet, u, nil, nil)
+		if err == errNotModified {
+			continue // 超时，配置没有变化
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		var notifications []apolloNotification
+		if err = json.Unmarshal(b, &notifications); err != nil {
+			return err
+		}
+
+		first := id < 0
+		for _, n := range notifications {
+			if n.NamespaceName == c.namespace() {
+				id = n.NotificationID
+			}
+		}
+
+		// 第一次请求只用于获取当前的通知 ID 。
+		if first {
+			continue
+		}
+
+		p, err := c.Load(ctx)
+		if err != nil {
+			return err
+		}
+		onChange(p)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// Consul 基于 Consul KV 的远程配置源，Prefix 下的每个 key 对应一个属性，例如
+// config/app/db/host 对应 db.host 属性。
+type Consul struct {
+	Address string       // Consul 地址，例如 http://127.0.0.1:8500
+	Prefix  string       // key 的前缀，例如 config/app
+	Token   string       // ACL token
+	Wait    string       // 阻塞查询的最长等待时间，例如 5m
+	Client  *http.Client // 为 nil 时使用 http.DefaultClient
+}
+
+type consulKV struct {
+	Key   string
+	Value string
+}
+
+func (c *Consul) Name() string {
+	return "consul:" + c.Prefix
+}
+
+func (c *Consul) Load(ctx context.Context) (*conf.Properties, error) {
+	p, _, err := c.load(ctx, "")
+	return p, err
+}
+
+// load 加载 Prefix 下的所有 key ，index 不为空时进行阻塞查询，返回最新的索引。
+func (c *Consul) load(ctx context.Context, index string) (*conf.Properties, string, error) {
+
+	prefix := strings.Trim(c.Prefix, "/")
+	query := url.Values{"recurse": {"true"}}
+	if index != "" {
+		query.Set("index", index)
+		if c.Wait != "" {
+			query.Set("wait", c.Wait)
+		}
+	}
+	u := fmt.Sprintf("%s/v1/kv/%s?%s", strings.TrimSuffix(c.Address, "/"), prefix, query.Encode())
+
+	header := http.Header{}
+	if c.Token != "" {
+		header.Set("X-Consul-Token", c.Token)
+	}
+
+	var kvs []consulKV
+	b, h, err := doRequest(ctx, c.Client, http.MethodGet, u, nil, header)
+	switch {
+	case err == errNotFound: // Prefix 下没有 key
+	case err != nil:
+		return nil, "", err
+	default:
+		if err = json.Unmarshal(b, &kvs); err != nil {
+			return nil, "", err
+		}
+	}
+
+	m := make(map[string]string)
+	for _, kv := range kvs {
+		if strings.HasSuffix(kv.Key, "/") {
+			continue // 目录
+		}
+		v, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		m[keyToProperty(prefix, kv.Key)] = string(v)
+	}
+
+	p, err := fromMap(m)
+	if err != nil {
+		return nil, "", err
+	}
+	return p, h.Get("X-Consul-Index"), nil
+}
+
+// Watch 通过阻塞查询监听 Prefix 下的 key 的变化。
+func (c *Consul) Watch(ctx context.Context, onChange func(p *conf.Properties)) error {
+	_, index, err := c.load(ctx, "")
+	if err != nil {
+		return err
+	}
+	for ctx.Err() == nil {
+		p, newIndex, err := c.load(ctx, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if newIndex != index {
+			index = newIndex
+			onChange(p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// Etcd 基于 etcd v3 HTTP 网关的远程配置源，Prefix 下的每个 key 对应一个属性，
+// 例如 /config/app/db/host 对应 db.host 属性。
+type Etcd struct {
+	Address string       // etcd 地址，例如 http://127.0.0.1:2379
+	Prefix  string       // key 的前缀，例如 /config/app
+	Client  *http.Client // 为 nil 时使用 http.DefaultClient
+}
+
+type etcdKV struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type etcdRangeResponse struct {
+	Header struct {
+		Revision string `json:"revision"`
+	} `json:"header"`
+	Kvs []etcdKV `json:"kvs"`
+}
+
+type etcdWatchResponse struct {
+	Result struct {
+		Events []json.RawMessage `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *Etcd) Name() string {
+	return "etcd:" + c.Prefix
+}
+
+func (c *Etcd) Load(ctx context.Context) (*conf.Properties, error) {
+	p, _, err := c.load(ctx)
+	return p, err
+}
+
+// rangeEnd 返回前缀查询的结束 key ，即前缀的最后一个字节加一。
+func rangeEnd(prefix string) string {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1])
+		}
+	}
+	return "\x00"
+}
+
+func (c *Etcd) url(path string) string {
+	return strings.TrimSuffix(c.Address, "/") + path
+}
+
+func (c *Etcd) keyRange() map[string]interface{} {
+	return map[string]interface{}{
+		"key":       base64.StdEncoding.EncodeToString([]byte(c.Prefix)),
+		"range_end": base64.StdEncoding.EncodeToString([]byte(rangeEnd(c.Prefix))),
+	}
+}
+
+// load 加载 Prefix 下的所有 key ，返回当前的版本号。
+func (c *Etcd) load(ctx context.Context) (*conf.Properties, int64, error) {
+
+	body, err := json.Marshal(c.keyRange())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	b, _, err := doRequest(ctx, c.Client, http.MethodPost, c.url("/v3/kv/range"), bytes.NewReader(body), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp etcdRangeResponse
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return nil, 0, err
+	}
+
+	m := make(map[string]string)
+	for _, kv := range resp.Kvs {
+		k, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, 0, err
+		}
+		v, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+		m[keyToProperty(c.Prefix, string(k))] = string(v)
+	}
+
+	p, err := fromMap(m)
+	if err != nil {
+		return nil, 0, err
+	}
+	revision, _ := strconv.ParseInt(resp.Header.Revision, 10, 64)
+	return p, revision, nil
+}
+
+// Watch 通过 etcd 的 watch 接口监听 Prefix 下的 key 的变化，收到变化事件之后重新
+// 加载全部的 key 。
+func (c *Etcd) Watch(ctx context.Context, onChange func(p *conf.Properties)) error {
+
+	_, revision, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	r := c.keyRange()
+	r["start_revision"] = strconv.FormatInt(revision+1, 10)
+	body, err := json.Marshal(map[string]interface{}{"create_request": r})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url("/v3/watch"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("POST %s: %d", c.url("/v3/watch"), resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var w etcdWatchResponse
+		if err = decoder.Decode(&w); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if w.Error != nil {
+			return fmt.Errorf("etcd watch error: %s", w.Error.Message)
+		}
+		if len(w.Result.Events) == 0 {
+			continue
+		}
+		p, _, err := c.load(ctx)
+		if err != nil {
+			return err
+		}
+		onChange(p)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+// Nacos 基于 Nacos Open API 的远程配置源，DataID 的扩展名决定配置内容的格式，
+// 例如 app.yaml 按照 yaml 格式解析，没有扩展名时按照 properties 格式解析。
+type Nacos struct {
+	Address string        // Nacos 地址，例如 http://127.0.0.1:8848
+	DataID  string        // 配置的 dataId
+	Group   string        // 配置的分组，为空时使用 DEFAULT_GROUP
+	Tenant  string        // 命名空间的 ID
+	Timeout time.Duration // 长轮询的超时时间，为 0 时使用 30 秒
+	Client  *http.Client  // 为 nil 时使用 http.DefaultClient
+}
+
+func (c *Nacos) Name() string {
+	return "nacos:" + c.group() + "/" + c.DataID
+}
+
+func (c *Nacos) group() string {
+	if c.Group == "" {
+		return "DEFAULT_GROUP"
+	}
+	return c.Group
+}
+
+func (c *Nacos) url(path string) string {
+	return strings.TrimSuffix(c.Address, "/") + path
+}
+
+func (c *Nacos) Load(ctx context.Context) (*conf.Properties, error) {
+	p, _, err := c.load(ctx)
+	return p, err
+}
+
+// load 加载配置内容，返回配置内容的 md5 值。
+func (c *Nacos) load(ctx context.Context) (*conf.Properties, string, error) {
+
+	query := url.Values{}
+	query.Set("dataId", c.DataID)
+	query.Set("group", c.group())
+	if c.Tenant != "" {
+		query.Set("tenant", c.Tenant)
+	}
+
+	u := c.url("/nacos/v1/cs/configs?" + query.Encode())
+	b, _, err := doRequest(ctx, c.Client, http.MethodGet, u, nil, nil)
+	switch {
+	case err == errNotFound: // 配置还没有发布
+	case err != nil:
+		return nil, "", err
+	}
+
+	p, err := parse(c.DataID, b)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := md5.Sum(b)
+	return p, hex.EncodeToString(sum[:]), nil
+}
+
+// Watch 通过 Nacos 的长轮询接口监听配置的变化，配置变化之后重新加载配置内容。
+func (c *Nacos) Watch(ctx context.Context, onChange func(p *conf.Properties)) error {
+
+	_, md5Sum, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Long-Pulling-Timeout", strconv.FormatInt(int64(timeout/time.Millisecond), 10))
+
+	for {
+		item := []string{c.DataID, c.group(), md5Sum}
+		if c.Tenant != "" {
+			item = append(item, c.Tenant)
+		}
+		form := url.Values{}
+		form.Set("Listening-Configs", strings.Join(item, "\x02")+"\x01")
+
+		u := c.url("/nacos/v1/cs/configs/listener")
+		b, _, err := doRequest(ctx, c.Client, http.MethodPost, u, strings.NewReader(form.Encode()), header)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue // 超时，配置没有变化
+		}
+
+		var p *conf.Properties
+		p, md5Sum, err = c.load(ctx)
+		if err != nil {
+			return err
+		}
+		onChange(p)
+	}
+}
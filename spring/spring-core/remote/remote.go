@@ -0,0 +1,104 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package remote 实现了常用配置中心的远程配置源，包括 Nacos 、 Apollo 、
+// Consul KV 和 etcd ，它们都基于配置中心的 HTTP 接口实现，不依赖额外的 SDK ，
+// 可以通过 gs.Bootstrap().RemotePropertySource 方法注册。
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-spring/spring-base/conf"
+)
+
+var (
+	errNotModified = errors.New("not modified") // 长轮询超时并且配置没有变化
+	errNotFound    = errors.New("not found")    // 配置不存在
+)
+
+// parse 根据配置文件的扩展名解析配置内容，没有扩展名时按照 properties 格式解析。
+func parse(name string, content []byte) (*conf.Properties, error) {
+	ext := filepath.Ext(name)
+	if ext == "" {
+		ext = ".properties"
+	}
+	return conf.Bytes(content, ext)
+}
+
+// fromMap 将 key 为属性名的 map 转换为属性列表。
+func fromMap(m map[string]string) (*conf.Properties, error) {
+	p := conf.New()
+	for k, v := range m {
+		if err := p.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// doRequest 发送 HTTP 请求并返回响应的内容，状态码不是 2xx 时返回错误，状态码为
+// 304 和 404 时分别返回 errNotModified 和 errNotFound 。
+func doRequest(ctx context.Context, client *http.Client, method, url string, body io.Reader, header http.Header) ([]byte, http.Header, error) {
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, resp.Header, errNotModified
+	case http.StatusNotFound:
+		return nil, resp.Header, errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(b))
+		return nil, nil, fmt.Errorf("%s %s: %d %s", method, url, resp.StatusCode, msg)
+	}
+	return b, resp.Header, nil
+}
+
+// keyToProperty 将配置中心中以 / 分隔的 key 转换为以 . 分隔的属性名。
+func keyToProperty(prefix, key string) string {
+	key = strings.TrimPrefix(key, prefix)
+	key = strings.Trim(key, "/")
+	return strings.ReplaceAll(key, "/", ".")
+}
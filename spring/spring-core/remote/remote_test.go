@@ -0,0 +1,175 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-core/remote"
+)
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestConsul(t *testing.T) {
+
+	index := make(chan string, 1)
+	index <- "1"
+	value := "127.0.0.1"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/v1/kv/config/app")
+		if r.URL.Query().Get("index") != "" {
+			w.Header().Set("X-Consul-Index", <-index)
+			value = "10.0.0.1"
+		} else {
+			w.Header().Set("X-Consul-Index", "1")
+		}
+		fmt.Fprintf(w, `[{"Key":"config/app/db/host","Value":%q},{"Key":"config/app/db/","Value":null}]`, b64(value))
+	}))
+	defer server.Close()
+
+	c := &remote.Consul{Address: server.URL, Prefix: "config/app"}
+	assert.Equal(t, c.Name(), "consul:config/app")
+
+	p, err := c.Load(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("db.host"), "127.0.0.1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan *conf.Properties, 1)
+	go func() {
+		_ = c.Watch(ctx, func(p *conf.Properties) { changed <- p })
+	}()
+	index <- "2"
+	assert.Equal(t, (<-changed).Get("db.host"), "10.0.0.1")
+	cancel()
+}
+
+func TestEtcd(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			var req map[string]string
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, req["key"], b64("/config/app"))
+			assert.Equal(t, req["range_end"], b64("/config/apq"))
+			fmt.Fprintf(w, `{"header":{"revision":"7"},"kvs":[{"key":%q,"value":%q}]}`, b64("/config/app/db/port"), b64("3306"))
+		case "/v3/watch":
+			fmt.Fprint(w, `{"result":{"created":true}}`)
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, `{"result":{"events":[{"kv":{}}]}}`)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	c := &remote.Etcd{Address: server.URL, Prefix: "/config/app"}
+	p, err := c.Load(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("db.port"), "3306")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan *conf.Properties, 1)
+	go func() {
+		_ = c.Watch(ctx, func(p *conf.Properties) { changed <- p })
+	}()
+	assert.Equal(t, (<-changed).Get("db.port"), "3306")
+	cancel()
+}
+
+func TestNacos(t *testing.T) {
+
+	content := "db:\n  host: 127.0.0.1\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nacos/v1/cs/configs":
+			assert.Equal(t, r.URL.Query().Get("dataId"), "app.yaml")
+			assert.Equal(t, r.URL.Query().Get("group"), "DEFAULT_GROUP")
+			fmt.Fprint(w, content)
+		case "/nacos/v1/cs/configs/listener":
+			assert.Equal(t, r.Header.Get("Long-Pulling-Timeout"), "100")
+			assert.Matches(t, r.FormValue("Listening-Configs"), "^app.yaml\x02DEFAULT_GROUP\x02[0-9a-f]{32}\x01$")
+			content = "db:\n  host: 10.0.0.1\n"
+			fmt.Fprint(w, "app.yaml%02DEFAULT_GROUP%01")
+		}
+	}))
+	defer server.Close()
+
+	c := &remote.Nacos{Address: server.URL, DataID: "app.yaml", Timeout: 100 * time.Millisecond}
+	assert.Equal(t, c.Name(), "nacos:DEFAULT_GROUP/app.yaml")
+
+	p, err := c.Load(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("db.host"), "127.0.0.1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan *conf.Properties)
+	go func() {
+		_ = c.Watch(ctx, func(p *conf.Properties) { changed <- p })
+	}()
+	assert.Equal(t, (<-changed).Get("db.host"), "10.0.0.1")
+	cancel()
+}
+
+func TestApollo(t *testing.T) {
+
+	notifications := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/configs/demo/default/application":
+			fmt.Fprintf(w, `{"configurations":{"db.host":"127.0.0.1","db.port":"%d"}}`, 3306+notifications)
+		case "/notifications/v2":
+			assert.Equal(t, r.URL.Query().Get("appId"), "demo")
+			notifications++
+			if notifications == 2 {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fmt.Fprintf(w, `[{"namespaceName":"application","notificationId":%d}]`, notifications)
+		}
+	}))
+	defer server.Close()
+
+	c := &remote.Apollo{Address: server.URL, AppID: "demo"}
+	assert.Equal(t, c.Name(), "apollo:demo/application")
+
+	p, err := c.Load(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("db.host"), "127.0.0.1")
+	assert.Equal(t, p.Get("db.port"), "3306")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan *conf.Properties)
+	go func() {
+		_ = c.Watch(ctx, func(p *conf.Properties) { changed <- p })
+	}()
+	assert.Equal(t, (<-changed).Get("db.port"), "3309")
+	cancel()
+}
@@ -17,6 +17,10 @@
 // Package validator 提供了参数校验器接口。
 package validator
 
+import (
+	"github.com/go-spring/spring-base/util"
+)
+
 // Validator 参数校验器接口。
 type Validator interface {
 	Validate(i interface{}) error
@@ -41,10 +45,11 @@ func (f funcValidator) Validate(i interface{}) error {
 	return f(i)
 }
 
-// Validate 参数校验。
+// Validate 参数校验，未初始化参数校验器时使用 util.Validate 根据 validate 标签
+// 进行校验。
 func Validate(i interface{}) error {
 	if v != nil {
 		return v.Validate(i)
 	}
-	return nil
+	return util.Validate(i)
 }
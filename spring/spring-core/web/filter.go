@@ -75,6 +75,28 @@ func (f *urlPatternFilter) URLPatterns() []string {
 	return f.s
 }
 
+// serverFilter 封装带服务器名称信息的过滤器。
+type serverFilter struct {
+	Filter
+	names []string
+}
+
+// ServerFilter 封装只作用于指定名称服务器的过滤器，没有封装的过滤器作用于所有服务器。
+func ServerFilter(f Filter, names ...string) *serverFilter {
+	return &serverFilter{Filter: f, names: names}
+}
+
+func (f *serverFilter) Servers() []string {
+	return f.names
+}
+
+func (f *serverFilter) URLPatterns() []string {
+	if p, ok := f.Filter.(interface{ URLPatterns() []string }); ok {
+		return p.URLPatterns()
+	}
+	return []string{"/*"}
+}
+
 // FilterChain 过滤器链条接口
 type FilterChain interface {
 
@@ -150,3 +150,13 @@ func TestFilterChain_Next(t *testing.T) {
 		{},
 	})
 }
+
+func TestServerFilter(t *testing.T) {
+	f := web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {})
+	s := web.ServerFilter(f.URLPatterns("/api/*"), "internal")
+	assert.Equal(t, s.Servers(), []string{"internal"})
+	assert.Equal(t, s.URLPatterns(), []string{"/api/*"})
+	s = web.ServerFilter(f, "internal", "metrics")
+	assert.Equal(t, s.Servers(), []string{"internal", "metrics"})
+	assert.Equal(t, s.URLPatterns(), []string{"/*"})
+}
@@ -90,3 +90,62 @@ func TestResolve(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, str, "@@ 你好，世界！  @@")
 }
+
+func TestMessageSource(t *testing.T) {
+
+	s := i18n.NewMessageSource("en_US")
+	s.Add("", conf.Map(map[string]interface{}{
+		"app.name": "demo",
+	}))
+	s.Add("en", conf.Map(map[string]interface{}{
+		"greeting": "hello, {0}",
+		"color":    "color",
+	}))
+	s.Add("en_GB", conf.Map(map[string]interface{}{
+		"color": "colour",
+	}))
+	s.Add("zh", conf.Map(map[string]interface{}{
+		"greeting": "{0}，你好，今天是{1}",
+	}))
+	assert.Equal(t, s.Locales(), []string{"en", "en-GB", "zh"})
+
+	msg, err := s.Message("en-GB", "color")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "colour")
+
+	msg, err = s.Message("en-GB", "greeting", "jim")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "hello, jim")
+
+	msg, err = s.Message("zh-CN", "greeting", "小明", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "小明，你好，今天是5")
+
+	msg, err = s.Message("zh-CN", "color")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "color")
+
+	msg, err = s.Message("fr", "app.name")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "demo")
+
+	msg, err = s.Message("en", "greeting")
+	assert.Nil(t, err)
+	assert.Equal(t, msg, "hello, {0}")
+
+	_, err = s.Message("en", "not-exist")
+	assert.Error(t, err, "no message found for key \"not-exist\" and locale \"en\"")
+
+	ctx, _ := knife.New(context.Background())
+	err = i18n.SetLanguage(ctx, "zh")
+	assert.Nil(t, err)
+	assert.Equal(t, s.GetMessage(ctx, "greeting", "a", "b"), "a，你好，今天是b")
+	assert.Equal(t, s.GetMessage(ctx, "not-exist"), "not-exist")
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	assert.Equal(t, i18n.AcceptLanguage(""), "")
+	assert.Equal(t, i18n.AcceptLanguage("zh_CN"), "zh-CN")
+	assert.Equal(t, i18n.AcceptLanguage("en-US,en;q=0.9"), "en-US")
+	assert.Equal(t, i18n.AcceptLanguage("en;q=0.5, fr;q=0.8, *"), "fr")
+}
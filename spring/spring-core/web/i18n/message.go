@@ -0,0 +1,201 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-spring/spring-base/cast"
+	"github.com/go-spring/spring-base/conf"
+	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-core/web"
+)
+
+// MessageSource 按照语言保存消息文本，查找消息时按照 zh-Hant-TW、zh-Hant、zh、
+// 默认语言、无语言的顺序逐级回退，消息文本中的 {0}、{1} 等占位符会被替换为对应
+// 位置的参数。
+type MessageSource struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}
+
+// NewMessageSource 返回默认语言为 defaultLocale 的 *MessageSource 对象。
+func NewMessageSource(defaultLocale string) *MessageSource {
+	return &MessageSource{
+		defaultLocale: NormalizeLocale(defaultLocale),
+		messages:      make(map[string]map[string]string),
+	}
+}
+
+// NormalizeLocale 将 zh_CN 这样的语言代码转换成 zh-CN 的形式。
+func NormalizeLocale(locale string) string {
+	return strings.Replace(strings.TrimSpace(locale), "_", "-", -1)
+}
+
+// DefaultLocale 返回默认语言。
+func (s *MessageSource) DefaultLocale() string {
+	return s.defaultLocale
+}
+
+// Add 添加 locale 语言的消息，已存在的消息会被覆盖，locale 为空时表示不区分语言
+// 的消息，是最后一级回退。
+func (s *MessageSource) Add(locale string, p *conf.Properties) {
+	locale = NormalizeLocale(locale)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.messages[locale]
+	if !ok {
+		m = make(map[string]string)
+		s.messages[locale] = m
+	}
+	for _, key := range p.Keys() {
+		m[key] = p.Get(key)
+	}
+}
+
+// Locales 返回已添加消息的语言列表，按照字母顺序排列。
+func (s *MessageSource) Locales() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var ret []string
+	for locale := range s.messages {
+		if locale != "" {
+			ret = append(ret, locale)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// fallbacks 返回 locale 语言的回退链。
+func (s *MessageSource) fallbacks(locale string) []string {
+	var ret []string
+	chain := func(locale string) {
+		for locale != "" {
+			ret = append(ret, locale)
+			i := strings.LastIndex(locale, "-")
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+	chain(NormalizeLocale(locale))
+	chain(s.defaultLocale)
+	return append(ret, "")
+}
+
+// Message 返回 locale 语言下 key 对应的消息，args 用于替换消息中的占位符，沿着
+// 回退链找不到消息时返回错误。
+func (s *MessageSource) Message(locale, key string, args ...interface{}) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, l := range s.fallbacks(locale) {
+		if m, ok := s.messages[l]; ok {
+			if msg, ok := m[key]; ok {
+				return formatMessage(msg, args), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no message found for key %q and locale %q", key, locale)
+}
+
+// GetMessage 使用上下文语言获取 key 对应的消息，找不到消息时返回 key 本身。
+func (s *MessageSource) GetMessage(ctx context.Context, key string, args ...interface{}) string {
+	var locale string
+	_, _ = knife.Fetch(ctx, languageKey, &locale)
+	msg, err := s.Message(locale, key, args...)
+	if err != nil {
+		return key
+	}
+	return msg
+}
+
+// formatMessage 将 msg 中的 {0}、{1} 等占位符替换为对应位置的参数，没有对应参数
+// 的占位符保持原样。
+func formatMessage(msg string, args []interface{}) string {
+	if len(args) == 0 {
+		return msg
+	}
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(msg, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(msg[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		i, err := strconv.Atoi(msg[start+1 : end])
+		if err != nil || i < 0 || i >= len(args) {
+			sb.WriteString(msg[:end+1])
+		} else {
+			sb.WriteString(msg[:start])
+			sb.WriteString(cast.ToString(args[i]))
+		}
+		msg = msg[end+1:]
+	}
+	sb.WriteString(msg)
+	return sb.String()
+}
+
+// AcceptLanguage 返回 Accept-Language 请求头中权重最高的语言，没有时返回空串。
+func AcceptLanguage(header string) string {
+	var (
+		best    string
+		bestQ   = -1.0
+		entries = strings.Split(header, ",")
+	)
+	for _, entry := range entries {
+		ss := strings.Split(strings.TrimSpace(entry), ";")
+		lang := strings.TrimSpace(ss[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range ss[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if f, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return NormalizeLocale(best)
+}
+
+// LanguageFilter 返回一个根据 Accept-Language 请求头设置上下文语言的过滤器。
+func LanguageFilter() web.Filter {
+	return web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {
+		if lang := AcceptLanguage(ctx.Header("Accept-Language")); lang != "" {
+			_ = SetLanguage(ctx.Context(), lang)
+		}
+		chain.Next(ctx)
+	})
+}
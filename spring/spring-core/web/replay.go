@@ -17,21 +17,24 @@
 package web
 
 import (
-	"github.com/go-spring/spring-base/fastdev"
-	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/fastdev/replayer"
 	"github.com/go-spring/spring-base/util"
 )
 
-// ReplaySessionID 流量回放模式下传递会话 ID 使用的 Header 。
-const ReplaySessionID = "REPLAY-SESSION-ID"
+// ReplaySessionID 流量回放模式下传递会话 ID 使用的 Header，可以修改为其他
+// 值，例如 X-Replay-Session 。
+var ReplaySessionID = replayer.DefaultSessionHeader
 
 // StartReplay 开始流量回放
 func StartReplay(ctx Context) {
+	if !replayer.ReplayMode() {
+		return
+	}
 	session := ctx.Header(ReplaySessionID)
 	if session == "" {
 		return
 	}
-	err := knife.Set(ctx.Context(), fastdev.ReplaySessionIDKey, session)
+	err := replayer.SetSessionID(ctx.Context(), session)
 	util.Panic(err).When(err != nil)
 }
 
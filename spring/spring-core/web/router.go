@@ -62,6 +62,7 @@ type Mapper struct {
 	path    string    // 路由地址
 	handler Handler   // 处理函数
 	swagger Operation // 描述文档
+	servers []string  // 服务器名称
 }
 
 // NewMapper Mapper 的构造函数
@@ -84,6 +85,17 @@ func (m *Mapper) Handler() Handler {
 	return m.handler
 }
 
+// Server 设置 Mapper 所属的服务器名称，不设置时 Mapper 注册到所有根路径匹配的服务器。
+func (m *Mapper) Server(names ...string) *Mapper {
+	m.servers = append(m.servers, names...)
+	return m
+}
+
+// Servers 返回 Mapper 所属的服务器名称。
+func (m *Mapper) Servers() []string {
+	return m.servers
+}
+
 // Operation 设置与 Mapper 绑定的 Operation 对象
 func (m *Mapper) Operation(op Operation) {
 	m.swagger = op